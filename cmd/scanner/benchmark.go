@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+)
+
+// benchmark measures one scan's throughput for --benchmark: how long each
+// project took and the API traffic sent between start and report
+type benchmark struct {
+	start time.Time
+	calls gitlab.CallStats // The clients' traffic when the scan started
+
+	mu        sync.Mutex
+	latencies []time.Duration // One per project scanned (not reused from a cache)
+}
+
+// newBenchmark starts measuring a scan whose clients (created with
+// gitlab.Config.Stats) have so far sent calls
+func newBenchmark(calls gitlab.CallStats) *benchmark {
+	return &benchmark{start: time.Now(), calls: calls}
+}
+
+// record notes how long one project took to scan
+func (b *benchmark) record(latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.latencies = append(b.latencies, latency)
+}
+
+// report writes the throughput since start, given the client's traffic now
+func (b *benchmark) report(w io.Writer, calls gitlab.CallStats) error {
+	elapsed := time.Since(b.start)
+	calls = calls.Sub(b.calls)
+
+	b.mu.Lock()
+	latencies := append([]time.Duration(nil), b.latencies...)
+	b.mu.Unlock()
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	retryRate := 0.0
+	if calls.Requests > 0 {
+		retryRate = 100 * float64(calls.Retries) / float64(calls.Requests)
+	}
+
+	_, err := fmt.Fprintf(w, `
+Benchmark
+=========
+Projects scanned: %d in %v (%.2f/s)
+Files fetched:    %d (%.2f/s)
+Downloaded:       %s
+API requests:     %d, %d retries (%.1f%%)
+Project latency:  p50 %v, p95 %v
+`,
+		len(latencies), elapsed.Round(time.Millisecond), perSecond(int64(len(latencies)), elapsed),
+		calls.FilesFetched, perSecond(calls.FilesFetched, elapsed),
+		formatBytes(calls.Bytes),
+		calls.Requests, calls.Retries, retryRate,
+		percentile(latencies, 50).Round(time.Millisecond), percentile(latencies, 95).Round(time.Millisecond))
+	return err
+}
+
+// perSecond returns n per second of elapsed
+func perSecond(n int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(n) / elapsed.Seconds()
+}
+
+// percentile returns the nearest-rank pth percentile of sorted, or 0 if it's empty
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// formatBytes renders n bytes with a binary unit, e.g. "1.5 MiB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/config"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/scanner"
+)
+
+// Run modes accepted by --mode
+const (
+	modeAuto   = "auto"
+	modeScan   = "scan"
+	modeSearch = "search"
+	modeBoth   = "both"
+
+	modeMergeRequest = "mr"
+)
+
+// resolveMode returns the mode a run uses. "auto" searches when --search or
+// --match-files-only is given or the --config file has searches, and scans
+// otherwise. A discovered config file never changes the mode.
+func resolveMode(base *SearchConfig) (string, error) {
+	switch base.Mode {
+	case modeAuto, "":
+		if base.SearchTerm != "" || base.MatchFilesOnly {
+			return modeSearch, nil
+		}
+		if base.ConfigFile != "" && !base.ConfigDiscovered {
+			// A config that fails to load is reported by search mode
+			cfg, err := config.LoadConfig(base.ConfigFile)
+			if err != nil || len(cfg.Searches) > 0 {
+				return modeSearch, nil
+			}
+		}
+		return modeScan, nil
+	case modeScan:
+		if base.SearchTerm != "" || base.MatchFilesOnly {
+			return "", fmt.Errorf("--mode scan can't be combined with --search or --match-files-only")
+		}
+		return modeScan, nil
+	case modeSearch, modeBoth, modeMergeRequest:
+		return base.Mode, nil
+	}
+	return "", fmt.Errorf("--mode must be auto, scan, search, both, or mr, got %q", base.Mode)
+}
+
+// validateBothConfig checks the flags that --mode both handles differently
+// from a plain scan; the scan flags themselves go through validateConfig
+func validateBothConfig(config *SearchConfig) error {
+	if config.ConfigFile == "" {
+		return fmt.Errorf("--mode both requires --config with searches")
+	}
+	if config.SearchTerm != "" || config.MatchFilesOnly {
+		return fmt.Errorf("--mode both runs the searches from --config and can't be combined with --search or --match-files-only")
+	}
+	if config.Watch != 0 {
+		return fmt.Errorf("--watch can't be combined with --mode both")
+	}
+	if config.DepReportPath != "" {
+		return fmt.Errorf("--dep-report can't be combined with --mode both")
+	}
+	if config.ListVersions {
+		return fmt.Errorf("--list-versions can't be combined with --mode both")
+	}
+	if config.PostHook != "" {
+		return fmt.Errorf("--post-hook can't be combined with --mode both")
+	}
+	if config.Incremental != "" {
+		return fmt.Errorf("--incremental can't be combined with --mode both")
+	}
+	if config.Benchmark {
+		return fmt.Errorf("--benchmark can't be combined with --mode both")
+	}
+	if config.Bucketed {
+		return fmt.Errorf("--bucketed can't be combined with --mode both")
+	}
+	return nil
+}
+
+// runBothMode scans for Python versions with the config's rules and runs
+// the config's searches in a single pass over the project list
+func runBothMode(searchConfig *SearchConfig, scanConfig *Config) {
+	err := validateBothConfig(searchConfig)
+	if err == nil {
+		err = validateConfig(scanConfig)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	searches, err := loadSearchesFromConfig(searchConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	registry, err := loadScanRegistry(scanConfig.ConfigFile, scanConfig.DisabledTags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("GitLab Python Version Scanner and Content Search\n")
+	fmt.Printf("================================================\n\n")
+	fmt.Printf("Scanning: %s\n", scanConfig.GitLabURL)
+	fmt.Printf("Rules: %d, searches: %d from %s\n", len(registry.ListEnabled()), len(searches), scanConfig.ConfigFile)
+	if len(scanConfig.LogFiles) > 0 {
+		fmt.Printf("Logging scan results to: %s\n", strings.Join(scanConfig.LogFiles, ", "))
+		fmt.Printf("Logging search results to: %s\n", strings.Join(searchLogPaths(scanConfig.LogFiles), ", "))
+	}
+	if scanConfig.SQLitePath != "" {
+		fmt.Printf("Writing scan results to SQLite: %s\n", scanConfig.SQLitePath)
+	}
+	fmt.Println()
+
+	trace, closeTrace, err := openTrace(scanConfig.TracePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer closeTrace()
+
+	client, identity, err := createClient(scanConfig.clientOptions(trace))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating GitLab client: %v\n", err)
+		os.Exit(1)
+	}
+
+	printClientInfo(client, identity)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := runBoth(ctx, client, scanConfig, registry, searches); err != nil {
+		if ctx.Err() != nil {
+			fmt.Fprintf(os.Stderr, "Scan interrupted\n")
+			os.Exit(130)
+		}
+		fmt.Fprintf(os.Stderr, "Scan failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runBoth lists the projects once and, for each project, detects its Python
+// version and then runs every search, fetching each file and tree listing
+// they share once (see gitlab.FileCache). Scan results go to the usual --log
+// and --sqlite outputs; search results go to a sibling of each --log file
+// (see searchLogPath) so neither log mixes the two result kinds.
+func runBoth(ctx context.Context, client *gitlab.Client, config *Config, registry *rules.Registry, searches []*SearchConfig) error {
+	projects, partial, err := listScanProjects(ctx, client, config)
+	if err != nil {
+		return err
+	}
+	if len(projects) == 0 {
+		fmt.Println("No projects found")
+		return nil
+	}
+
+	streamer := output.NewConsoleStreamer()
+	streamer.Verbose = config.Verbose
+	scanSinks := []output.ResultSink{streamer}
+	searchSinks := []output.ContentResultSink{streamer}
+	if len(config.LogFiles) > 0 {
+		logger, err := output.OpenMultiLogger(config.LogFiles)
+		if err != nil {
+			return fmt.Errorf("failed to create log file: %w", err)
+		}
+		defer logger.Close()
+		scanSinks = append(scanSinks, logger)
+
+		searchLogger, err := output.OpenMultiLogger(searchLogPaths(config.LogFiles))
+		if err != nil {
+			return fmt.Errorf("failed to create search log file: %w", err)
+		}
+		defer searchLogger.Close()
+		searchSinks = append(searchSinks, searchLogger)
+	}
+	if config.SQLitePath != "" {
+		db, err := output.NewSQLiteSink(config.SQLitePath)
+		if err != nil {
+			return fmt.Errorf("failed to open sqlite database: %w", err)
+		}
+		defer db.Close()
+		scanSinks = append(scanSinks, db)
+	}
+
+	stats := output.NewScanStatistics()
+	if partial != nil {
+		stats.ListingError = partial.Error()
+	}
+	stats.Normalize = output.Normalization(config.Normalize)
+	stats.ApprovedVersions = config.ApprovedVersions
+	stats.TargetVersion = config.TargetVersion
+	stats.AtRiskBelow = config.AtRiskBelow
+	stats.RequireExplicit = config.RequireExplicit
+	if config.OrgSummary {
+		stats.OrgSummary = true
+		stats.EOLAsOf = time.Now()
+	}
+
+	baseline, err := loadBaseline(config.BaselinePath)
+	if err != nil {
+		return err
+	}
+	stats.Baseline = baseline
+
+	contentScanners := make([]*scanner.ContentScanner, len(searches))
+	searchStats := make([]*output.ContentScanStatistics, len(searches))
+	for i, sc := range searches {
+		contentScanners[i] = newContentScanner(client, sc)
+		searchStats[i] = output.NewContentScanStatistics()
+	}
+
+	for _, sink := range scanSinks {
+		if err := sink.WriteHeader(config.GitLabURL, len(projects)); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+	}
+	for _, sc := range searches {
+		for _, sink := range searchSinks {
+			if err := sink.WriteContentHeader(config.GitLabURL, len(projects), sc.SearchTerm); err != nil {
+				return fmt.Errorf("failed to write header: %w", err)
+			}
+		}
+	}
+
+	inst := &instance{client: client}
+	owners := make([]*instance, len(projects))
+	for i := range owners {
+		owners[i] = inst
+	}
+	opts := newScanOptions(config)
+	refs, err := projectRefs(config, projects, owners, projectExists(ctx, []*instance{inst}))
+	if err != nil {
+		return err
+	}
+	opts.Refs = refs[inst]
+
+	// Concurrency is bounded by the client's shared slots
+	var wg sync.WaitGroup
+	var unscanned atomic.Int32 // Projects left out once --max-api-calls ran out or the scan was interrupted
+	for i, project := range projects {
+		wg.Add(1)
+		go func(index int, proj *gitlab.Project) {
+			defer wg.Done()
+
+			// Acquiring a slot only fails once the scan is interrupted
+			if err := client.Acquire(ctx); err != nil {
+				unscanned.Add(1)
+				return
+			}
+			defer client.Release()
+
+			if client.BudgetExhausted() {
+				unscanned.Add(1)
+				return
+			}
+			// The scan and the searches share each file they both read
+			projectCtx := gitlab.WithFileCache(ctx, gitlab.NewFileCache())
+			result := scanner.ScanProject(projectCtx, client, registry, proj, index+1, len(projects), opts)
+			found := make([]*output.ContentScanResult, len(contentScanners))
+			for j, cs := range contentScanners {
+				found[j] = cs.ScanProject(projectCtx, proj, index+1, len(projects))
+			}
+			// Requests refused by the budget would look like missing files,
+			// so none of the project's results are reported
+			if client.BudgetExhausted() {
+				unscanned.Add(1)
+				return
+			}
+
+			if baseline != nil {
+				baseline.Annotate(result)
+			}
+			stats.RecordResult(result)
+			if !config.hidesResult(result) {
+				for _, sink := range scanSinks {
+					if err := sink.WriteResult(result); err != nil {
+						warn(stats, "failed to write result for %s: %v", result.ProjectPath, err)
+					}
+				}
+			}
+
+			for j := range contentScanners {
+				searchStats[j].RecordResult(found[j])
+				for _, sink := range searchSinks {
+					if err := sink.WriteContentResult(found[j]); err != nil {
+						warn(searchStats[j], "failed to write search result for %s: %v", result.ProjectPath, err)
+					}
+				}
+			}
+		}(i, project)
+	}
+	wg.Wait()
+	if ctx.Err() != nil && unscanned.Load() > 0 {
+		warn(stats, "scan interrupted; %d of %d projects were not scanned", unscanned.Load(), len(projects))
+	}
+
+	for _, sink := range scanSinks {
+		if err := sink.WriteSummary(stats); err != nil {
+			return fmt.Errorf("failed to write summary: %w", err)
+		}
+	}
+	for j, sc := range searches {
+		if len(searches) > 1 {
+			fmt.Printf("\n--- Search: %q ---\n", sc.SearchTerm)
+		}
+		for _, sink := range searchSinks {
+			if err := sink.WriteContentSummary(searchStats[j]); err != nil {
+				return fmt.Errorf("failed to write summary: %w", err)
+			}
+		}
+	}
+
+	// The machine-readable line must be the last thing on stdout
+	if config.SummaryLine {
+		if err := streamer.PrintSummaryLine(stats); err != nil {
+			return fmt.Errorf("failed to print summary line: %w", err)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := budgetError(config.MaxAPICalls, int(unscanned.Load()), len(projects)); err != nil {
+		return err
+	}
+	return checkFailOn(config.FailOn, stats)
+}
+
+// searchLogPath names the log that receives search results alongside a scan
+// log, keeping the extension so the format is inferred the same way:
+// "results.json" becomes "results.search.json"
+func searchLogPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".search" + ext
+}
+
+// searchLogPaths applies searchLogPath to every --log path
+func searchLogPaths(paths []string) []string {
+	searchPaths := make([]string, len(paths))
+	for i, path := range paths {
+		searchPaths[i] = searchLogPath(path)
+	}
+	return searchPaths
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+)
+
+// browsePageSize is the number of results shown per page
+const browsePageSize = 20
+
+// runBrowseMode loads a JSON scan log and lets the user browse it with typed
+// commands. It is line-oriented rather than a full-screen terminal UI: it
+// reads one command per line from stdin and needs no terminal library, so
+// it works over pipes and in headless environments.
+func runBrowseMode(args []string) {
+	fs := flag.NewFlagSet("browse", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s browse <scan-log.json>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Browse a scan's JSON log (written with --log results.json) interactively:\n")
+		fmt.Fprintf(os.Stderr, "filter by version, source, or errors, search projects, and open a result's details.\n")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	scanLog, err := output.ReadScanLogFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", fs.Arg(0), err)
+		os.Exit(1)
+	}
+
+	if err := newExplorer(scanLog.Results, os.Stdout).run(os.Stdin); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// explorer is the state of an interactive browse over scan results
+type explorer struct {
+	results []*output.ScanResult
+	out     io.Writer
+
+	// Filters; empty values match everything
+	version    string // Version prefix, or "none" for undetected projects
+	source     string // Substring of the detection source
+	query      string // Substring of the project path
+	errorsOnly bool
+
+	shown []*output.ScanResult // Results passing the filters
+	page  int
+}
+
+func newExplorer(results []*output.ScanResult, out io.Writer) *explorer {
+	e := &explorer{results: results, out: out}
+	e.applyFilters()
+	return e
+}
+
+// run reads one command per line from in until "q" or end of input
+func (e *explorer) run(in io.Reader) error {
+	e.printPage()
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(e.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(e.out)
+			return scanner.Err()
+		}
+		if !e.handle(strings.TrimSpace(scanner.Text())) {
+			return nil
+		}
+	}
+}
+
+// handle runs a single command, returning false when the user quits
+func (e *explorer) handle(line string) bool {
+	cmd, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch cmd {
+	case "":
+		return true
+	case "q", "quit":
+		return false
+	case "?", "h", "help":
+		e.printHelp()
+		return true
+	case "n":
+		if (e.page+1)*browsePageSize < len(e.shown) {
+			e.page++
+		}
+	case "p":
+		if e.page > 0 {
+			e.page--
+		}
+	case "v":
+		e.version = arg
+		e.applyFilters()
+	case "s":
+		e.source = arg
+		e.applyFilters()
+	case "/":
+		e.query = arg
+		e.applyFilters()
+	case "e":
+		e.errorsOnly = !e.errorsOnly
+		e.applyFilters()
+	case "c":
+		e.version, e.source, e.query, e.errorsOnly = "", "", "", false
+		e.applyFilters()
+	default:
+		n, err := strconv.Atoi(cmd)
+		if err != nil || n < 1 || n > len(e.shown) {
+			fmt.Fprintf(e.out, "Unknown command %q (? for help)\n", line)
+			return true
+		}
+		e.printDetail(e.shown[n-1])
+		return true
+	}
+
+	e.printPage()
+	return true
+}
+
+// applyFilters recomputes the shown results and returns to the first page
+func (e *explorer) applyFilters() {
+	e.shown = e.shown[:0]
+	for _, r := range e.results {
+		if e.matches(r) {
+			e.shown = append(e.shown, r)
+		}
+	}
+	e.page = 0
+}
+
+func (e *explorer) matches(r *output.ScanResult) bool {
+	if e.errorsOnly && r.Error == nil {
+		return false
+	}
+	switch {
+	case e.version == "":
+	case e.version == "none":
+		if r.PythonVersion != "" {
+			return false
+		}
+	case r.PythonVersion != e.version && !strings.HasPrefix(r.PythonVersion, e.version+"."):
+		return false
+	}
+	if e.source != "" && !strings.Contains(r.DetectionSource, e.source) {
+		return false
+	}
+	if e.query != "" && !strings.Contains(strings.ToLower(r.ProjectPath), strings.ToLower(e.query)) {
+		return false
+	}
+	return true
+}
+
+// printPage writes the current page of the filtered results as a table
+func (e *explorer) printPage() {
+	start := e.page * browsePageSize
+	end := min(start+browsePageSize, len(e.shown))
+
+	fmt.Fprintf(e.out, "\n%d of %d projects%s\n", len(e.shown), len(e.results), e.filterSummary())
+	tw := tabwriter.NewWriter(e.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tPROJECT\tVERSION\tSOURCE")
+	for i := start; i < end; i++ {
+		r := e.shown[i]
+		version, source := resultColumns(r)
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", i+1, r.ProjectPath, version, source)
+	}
+	tw.Flush()
+
+	if len(e.shown) > browsePageSize {
+		pages := (len(e.shown) + browsePageSize - 1) / browsePageSize
+		fmt.Fprintf(e.out, "Page %d/%d (n/p to move)\n", e.page+1, pages)
+	}
+}
+
+// resultColumns returns the version and source columns of a result's row,
+// which for a failed scan are "error" and the error
+func resultColumns(r *output.ScanResult) (version, source string) {
+	switch {
+	case r.Error != nil:
+		return "error", r.Error.Error()
+	case r.PythonVersion == "":
+		return "-", r.DetectionSource
+	}
+	return r.PythonVersion, r.DetectionSource
+}
+
+// filterSummary describes the active filters, e.g. " [version 3.11, errors]"
+func (e *explorer) filterSummary() string {
+	var parts []string
+	if e.version != "" {
+		parts = append(parts, "version "+e.version)
+	}
+	if e.source != "" {
+		parts = append(parts, "source "+e.source)
+	}
+	if e.query != "" {
+		parts = append(parts, "matching "+e.query)
+	}
+	if e.errorsOnly {
+		parts = append(parts, "errors")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(parts, ", ") + "]"
+}
+
+// printDetail writes everything the log recorded about one result
+func (e *explorer) printDetail(r *output.ScanResult) {
+	fmt.Fprintln(e.out)
+	writeDetail(e.out, r)
+}
+
+// writeDetail writes a result's project followed by one line per recorded
+// field, e.g. "  Source:          pyproject.toml"
+func writeDetail(w io.Writer, r *output.ScanResult) {
+	fmt.Fprintf(w, "%s (%s)\n", r.ProjectPath, r.ProjectName)
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "  %-16s %s\n", label+":", value)
+		}
+	}
+	if r.Error != nil {
+		field("Error", r.Error.Error())
+	}
+	field("Version", r.PythonVersion)
+	field("Source", r.DetectionSource)
+	field("Requires", r.VersionMax)
+	field("Raw value", r.RawValue)
+	if r.Confidence > 0 {
+		field("Confidence", strconv.FormatFloat(r.Confidence, 'f', 2, 64))
+	}
+	field("Last commit", r.LastCommitID)
+	field("Classification", r.Classification)
+	field("Drift", r.Drift)
+	field("Expected", r.ExpectedVersion)
+	for _, cc := range r.CrossChecks {
+		field("Cross-check", fmt.Sprintf("%s from %s", cc.Version, cc.Source))
+	}
+	for _, d := range r.Diagnostics {
+		field("Diagnostic", d)
+	}
+	for _, w := range r.Warnings {
+		field("Warning", w)
+	}
+	for _, p := range r.ParseErrors {
+		field("Parse error", p)
+	}
+}
+
+func (e *explorer) printHelp() {
+	fmt.Fprint(e.out, `Commands:
+  n, p          next / previous page
+  v <version>   filter by version (3.11 also matches 3.11.x; "none" for undetected)
+  s <text>      filter by detection source, e.g. s pyproject
+  / <text>      search project paths
+  e             toggle showing only errors
+  c             clear all filters
+  <number>      show a result's details
+  q             quit
+`)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/parsers"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+)
+
+// ruleManifestVersion is bumped whenever a field of ruleManifest or
+// ruleDescription changes meaning or is removed; adding fields doesn't bump it
+const ruleManifestVersion = 1
+
+// ruleManifest is the JSON document describe-rules --format json writes.
+// It's a stable contract for tooling, so fields are only ever added.
+type ruleManifest struct {
+	ManifestVersion int               `json:"manifest_version"`
+	Rules           []ruleDescription `json:"rules"`
+}
+
+// ruleDescription describes one rule. Empty strings and zero sizes
+// mean the rule doesn't use that condition; Confidence is null when it
+// depends on what the rule matches.
+type ruleDescription struct {
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	Priority        int      `json:"priority"`
+	Enabled         bool     `json:"enabled"`
+	FilePattern     string   `json:"file_pattern"`
+	CaseInsensitive bool     `json:"case_insensitive"`
+	PathPattern     string   `json:"path_pattern"`
+	RequiredContent string   `json:"required_content"`
+	MaxFileSize     int64    `json:"max_file_size"`
+	Tags            []string `json:"tags"`
+	Confidence      *float64 `json:"confidence"`
+}
+
+// runDescribeRules writes a manifest of every built-in rule, or of the rules
+// a --config file scans with, and exits without contacting GitLab
+func runDescribeRules(args []string) {
+	fs := flag.NewFlagSet("describe-rules", flag.ExitOnError)
+	format := fs.String("format", "text", "Output format: text (a table for people) or json (a stable manifest for tooling)")
+	configFile := fs.String("config", "", "Describe the rules this config file scans with, including its custom rules, explicit_version_files, and confidence_overrides, instead of the built-ins")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s describe-rules [--format text|json] [--config FILE]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Describe every detection rule: name, priority, file pattern,\n")
+		fmt.Fprintf(os.Stderr, "required content, tags, and confidence where it doesn't vary by match.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	registry, err := loadScanRegistry(*configFile, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := describeRules(os.Stdout, registry, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// describeRules writes registry's rules to w in format ("text" or "json"),
+// in priority order
+func describeRules(w io.Writer, registry *rules.Registry, format string) error {
+	manifest := newRuleManifest(registry)
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(manifest)
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "PRIORITY\tNAME\tFILE\tCONFIDENCE\tTAGS")
+		for _, r := range manifest.Rules {
+			confidence := "varies"
+			if r.Confidence != nil {
+				confidence = strconv.FormatFloat(*r.Confidence, 'f', 2, 64)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", r.Priority, r.Name, r.FilePattern, confidence, strings.Join(r.Tags, ","))
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("--format must be text or json, got %q", format)
+	}
+}
+
+// newRuleManifest describes every rule in registry in priority order, ties
+// broken by name so the output is reproducible
+func newRuleManifest(registry *rules.Registry) ruleManifest {
+	list := registry.List()
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Priority != list[j].Priority {
+			return list[i].Priority < list[j].Priority
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	manifest := ruleManifest{ManifestVersion: ruleManifestVersion, Rules: []ruleDescription{}}
+	for _, rule := range list {
+		desc := ruleDescription{
+			Name:            rule.Name,
+			Description:     rule.Description,
+			Priority:        rule.Priority,
+			Enabled:         rule.Enabled,
+			FilePattern:     rule.Condition.FilePattern,
+			CaseInsensitive: rule.Condition.CaseInsensitive,
+			MaxFileSize:     rule.Condition.MaxFileSize,
+			Tags:            rule.Tags,
+		}
+		if desc.Tags == nil {
+			desc.Tags = []string{}
+		}
+		if rule.Condition.PathPattern != nil {
+			desc.PathPattern = rule.Condition.PathPattern.String()
+		}
+		if rule.Condition.RequiredContent != nil {
+			desc.RequiredContent = rule.Condition.RequiredContent.String()
+		}
+		if confidence, ok := parsers.StaticConfidence(rule.Name); ok {
+			desc.Confidence = &confidence
+		}
+		// A confidence_overrides entry replaces whatever the parser reports
+		if rule.Confidence > 0 {
+			confidence := rule.Confidence
+			desc.Confidence = &confidence
+		}
+		manifest.Rules = append(manifest.Rules, desc)
+	}
+	return manifest
+}
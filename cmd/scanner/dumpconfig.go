@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/config"
+)
+
+// runDumpConfig writes the configuration a run with these flags would use
+// and exits without contacting GitLab
+func runDumpConfig(base *SearchConfig) {
+	cfg, err := effectiveConfig(base)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := config.SaveConfig(cfg, base.DumpConfigPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote %d rules and %d searches to %s\n", len(cfg.Rules), len(cfg.Searches), base.DumpConfigPath)
+}
+
+// effectiveConfig resolves the rules and the searches from --config or
+// --search the same way a run would, with every override applied: the
+// config's rules and settings replace or extend the built-ins, disabled tags
+// show as disabled rules, and config-file searches carry the values
+// --config-search-defaults gave them. Disabled searches are left out.
+func effectiveConfig(base *SearchConfig) (*config.Config, error) {
+	registry, err := loadScanRegistry(base.ConfigFile, base.DisabledTags)
+	if err != nil {
+		return nil, err
+	}
+	cfg := config.FromRegistry(registry)
+
+	var searches []*SearchConfig
+	switch {
+	case base.ConfigFile != "":
+		loaded, err := loadSearchesFromConfig(base)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		searches = loaded
+	case base.SearchTerm != "":
+		searches = []*SearchConfig{singleSearch(base)}
+	}
+
+	for _, s := range searches {
+		cfg.Searches = append(cfg.Searches, searchConfigEntry(s))
+	}
+	return cfg, nil
+}
+
+// searchConfigEntry converts a resolved search back into its config file form.
+// Every field is written explicitly so the dump doesn't depend on defaults.
+func searchConfigEntry(s *SearchConfig) config.SearchConfigEntry {
+	caseSensitive, contextLines := s.CaseSensitive, s.ContextLines
+	return config.SearchConfigEntry{
+		Name:          s.SearchName,
+		Severity:      s.Severity,
+		SearchTerm:    s.SearchTerm,
+		IsRegex:       s.IsRegex,
+		Prefilter:     s.Prefilter,
+		VersionFilter: s.VersionFilter.String(),
+		VersionGroup:  s.VersionGroup,
+		CaseSensitive: &caseSensitive,
+		FilePatterns:  s.FilePatterns,
+		ContextLines:  &contextLines,
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/scanner"
+)
+
+// resultCache holds the results --incremental can reuse, keyed by project
+// path qualified with its instance (see output.ProjectKey). It starts with the previous scan's log and takes in every result of
+// this process's runs, so each --watch run reuses the one before it.
+type resultCache struct {
+	mu      sync.Mutex
+	results map[string]*output.ScanResult
+}
+
+// loadResultCache reads the last run of the JSON log at path. It must be
+// read before --log opens its files, since it's usually one of them and
+// they're truncated. A log that is missing or empty (the first scheduled
+// run) gives an empty cache, so every project is scanned.
+func loadResultCache(path string) (*resultCache, error) {
+	cache := &resultCache{results: make(map[string]*output.ScanResult)}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && info.Size() == 0) {
+		fmt.Printf("No previous scan in %s; scanning every project\n", path)
+		return cache, nil
+	}
+
+	scanLog, err := output.ReadScanLogFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous scan %s: %w", path, err)
+	}
+	for _, result := range scanLog.Results {
+		cache.results[output.ProjectKey(result.Instance, result.ProjectPath)] = result
+	}
+	return cache, nil
+}
+
+// reuse returns the cached result for project on inst, renumbered for this
+// run, if the commit a scan with opts would read is the one the cached
+// result was scanned at: the head of the project's configured ref, its
+// latest tag with AtLatestTag, or the head of its default branch. Checking costs a request
+// or two, far fewer than a scan. It returns nil when the project needs
+// scanning: it's new, its commit moved or can't be resolved, or the cached
+// scan of it failed, timed out, or recorded no commit.
+func (c *resultCache) reuse(ctx context.Context, inst *instance, project *gitlab.Project, opts scanner.VersionScanOptions, index, total int) *output.ScanResult {
+	c.mu.Lock()
+	prev, ok := c.results[output.ProjectKey(inst.name, project.PathWithNamespace)]
+	c.mu.Unlock()
+
+	if !ok || prev.Error != nil || prev.TimedOut || prev.CommitSHA == "" {
+		return nil
+	}
+
+	ref, pinned := opts.Refs[project.PathWithNamespace]
+	if opts.AtLatestTag && !pinned {
+		tag, err := inst.client.LatestTag(ctx, project.ID)
+		if err != nil {
+			return nil
+		}
+		ref = tag
+	}
+	if ref == "" {
+		ref = project.DefaultBranch
+	}
+	if ref == "" {
+		return nil
+	}
+	sha, err := inst.client.ResolveCommit(ctx, project.ID, ref)
+	if err != nil || sha != prev.CommitSHA {
+		return nil
+	}
+
+	result := *prev
+	result.ProjectName = project.Name
+	result.LastActivityAt = project.LastActivityAt
+	result.Index = index
+	result.TotalProjects = total
+	result.Cached = true
+	// Re-annotated against this run's --baseline, if any
+	result.Drift, result.ExpectedVersion = "", ""
+	return &result
+}
+
+// record keeps result for the next run to reuse
+func (c *resultCache) record(result *output.ScanResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[output.ProjectKey(result.Instance, result.ProjectPath)] = result
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/config"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+)
+
+// instance is one GitLab instance a scan covers: the --url instance, or
+// each of the instances in --config
+type instance struct {
+	name   string // Recorded on each result; "" for the --url instance
+	url    string // The instance and group, as --url takes them
+	client *gitlab.Client
+}
+
+// loadInstances returns the config's instances, or nil without a config
+func loadInstances(configFile string) ([]config.InstanceConfig, error) {
+	if configFile == "" {
+		return nil, nil
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.ValidateInstances(); err != nil {
+		return nil, fmt.Errorf("instances in %s: %w", configFile, err)
+	}
+	return cfg.Instances, nil
+}
+
+// connectInstances creates a client for each of config.Instances with its
+// own token, sharing the rest of config's client settings
+func connectInstances(config *Config, trace io.Writer) ([]*instance, error) {
+	instances := make([]*instance, 0, len(config.Instances))
+	for i := range config.Instances {
+		ic := &config.Instances[i]
+		token, err := ic.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		url := ic.GitLabURL()
+		fmt.Printf("Instance %s: %s\n", ic.Name, url)
+		opts := config.clientOptions(trace)
+		opts.GitLabURL, opts.Token = url, token
+		client, identity, err := createClient(opts)
+		if err != nil {
+			return nil, fmt.Errorf("instance %s: %w", ic.Name, err)
+		}
+		if !config.ListVersions {
+			printClientInfo(client, identity)
+		}
+		instances = append(instances, &instance{name: ic.Name, url: url, client: client})
+	}
+	return instances, nil
+}
+
+// listInstanceProjects lists the projects to scan on every instance,
+// returning each project's instance alongside it. Listing failures that
+// --best-effort let through are joined into partial ("" if none).
+func listInstanceProjects(ctx context.Context, instances []*instance, config *Config) (projects []*gitlab.Project, owners []*instance, partial string, err error) {
+	var failures []string
+	for _, inst := range instances {
+		found, listingErr, err := listScanProjects(ctx, inst.client, config)
+		if err != nil {
+			if inst.name != "" {
+				err = fmt.Errorf("instance %s: %w", inst.name, err)
+			}
+			return nil, nil, "", err
+		}
+		if listingErr != nil {
+			failure := listingErr.Error()
+			if inst.name != "" {
+				failure = inst.name + ": " + failure
+			}
+			failures = append(failures, failure)
+		}
+		for _, project := range found {
+			projects = append(projects, project)
+			owners = append(owners, inst)
+		}
+	}
+	return projects, owners, strings.Join(failures, "; "), nil
+}
+
+// projectExists returns a func reporting whether a project exists, for
+// checkProjectRefs: a bare path on any of instances, or an "instance:path"
+// key on the named instance
+func projectExists(ctx context.Context, instances []*instance) func(key string) (bool, error) {
+	return func(key string) (bool, error) {
+		name, path, qualified := strings.Cut(key, ":")
+		if !qualified {
+			path = key
+		}
+		for _, inst := range instances {
+			if qualified && inst.name != name {
+				continue
+			}
+			found, err := inst.client.ProjectExists(ctx, path)
+			if err != nil || found {
+				return found, err
+			}
+		}
+		return false, nil
+	}
+}
+
+// sharedPaths returns the keys of entries that are the bare path of a
+// project listed on more than one instance, sorted. A project_refs or
+// --baseline entry would apply to each of them, so they must be keyed
+// "instance:path" instead.
+func sharedPaths(entries map[string]string, projects []*gitlab.Project, owners []*instance) []string {
+	onInstances := make(map[string]map[*instance]bool)
+	for i, project := range projects {
+		path := project.PathWithNamespace
+		if onInstances[path] == nil {
+			onInstances[path] = make(map[*instance]bool)
+		}
+		onInstances[path][owners[i]] = true
+	}
+	var shared []string
+	for key := range entries {
+		if len(onInstances[key]) > 1 {
+			shared = append(shared, key)
+		}
+	}
+	sort.Strings(shared)
+	return shared
+}
+
+// instanceURLs names the instances a scan covers, for output headers
+func instanceURLs(instances []*instance) string {
+	urls := make([]string, len(instances))
+	for i, inst := range instances {
+		urls[i] = inst.url
+	}
+	return strings.Join(urls, ", ")
+}
+
+// instanceStats sums the API traffic of every instance's client
+func instanceStats(instances []*instance) gitlab.CallStats {
+	var total gitlab.CallStats
+	for _, inst := range instances {
+		stats := inst.client.Stats()
+		total.Requests += stats.Requests
+		total.Retries += stats.Retries
+		total.FilesFetched += stats.FilesFetched
+		total.Bytes += stats.Bytes
+	}
+	return total
+}
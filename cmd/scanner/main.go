@@ -2,11 +2,22 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/gbjohnso/gitlab-python-scanner/internal/config"
@@ -19,26 +30,191 @@ import (
 
 // Config holds the application configuration for Python version scanning
 type Config struct {
-	GitLabURL   string
-	Token       string
-	LogFile     string
-	Concurrency int
-	Timeout     int
+	GitLabURL    string
+	Token        string
+	ConfigFile   string // Rules to scan with instead of the built-ins (--mode scan or both)
+	LogFiles     []string
+	Concurrency  int
+	Timeout      int
+	DisabledTags []string
+	SummaryLine  bool
+	CrossCheck   bool
+	Normalize    string
+	WithMetadata bool
+
+	ApprovedVersions []string
+	OnlyNonApproved  bool
+	OnlyPython2      bool
+	BestEffort       bool
+	ProjectTimeout   int
+	Subdirs          []string
+	IgnorePaths      []string
+	OnlyPaths        []string
+	SQLitePath       string
+	Watch            time.Duration
+
+	PlausibleMajors   []int
+	MaxPlausibleMinor int
+	SubgroupDepth     int
+	ExcludeForks      bool
+	ForksOnly         bool
+	Topic             string
+	TracePath         string
+	LogMisses         bool
+	DepReportPath     string
+	TargetVersion     string
+	AtRiskBelow       string
+	RequireExplicit   bool
+	OrgSummary        bool
+	AtLatestTag       bool
+	Commit            string
+	MaxCandidates     int
+	BreakerThreshold  int
+	BreakerCooldown   time.Duration
+	MaxAPICalls       int
+	RampUp            time.Duration
+	TLSMinVersion     string
+	TLSCiphers        string
+	BaselinePath      string
+	ListConcurrency   int
+	PerPage           int
+	HeadOnly          bool
+	Incremental       string
+	IgnoreFile        bool
+	Explain           bool
+	Benchmark         bool
+	Bucketed          bool
+
+	// Authoritative ranks detections by confidence tier before priority
+	Authoritative      bool
+	ExplicitConfidence float64
+	InferredConfidence float64
+
+	// Instances from --config, scanned in place of --url
+	Instances []config.InstanceConfig
+
+	ListVersions      bool
+	WithCounts        bool
+	IncludeUndetected bool
+
+	DecayHalfLife time.Duration
+	Strict        bool
+	FailOn        string
+	Verbose       bool
+
+	PostHook        string
+	PostHookTimeout time.Duration
 }
 
 // SearchConfig holds the configuration for content string search
 type SearchConfig struct {
 	GitLabURL     string
 	Token         string
-	LogFile       string
+	LogFiles      []string
 	Concurrency   int
 	Timeout       int
 	SearchTerm    string
+	SearchName    string
+	Severity      string
 	IsRegex       bool
+	Prefilter     string
+	MaxFileSize   int64
+	MetaPrefilter bool
+	// VersionFilter keeps only matches whose captured version satisfies
+	// it; only config-file searches set it (version_filter)
+	VersionFilter output.VersionFilter
+	VersionGroup  string
+	Redact        bool
+	SearchWikis   bool
 	FilePatterns  []string
 	CaseSensitive bool
 	ContextLines  int
 	ConfigFile    string
+	// NoConfig turns off discovery of a .gitlab-seeker.yaml when
+	// --config isn't given
+	NoConfig bool
+	// ConfigDiscovered is set when ConfigFile was found by discovery
+	// rather than given with --config; it never selects search mode
+	ConfigDiscovered bool
+	// SearchDefaults applies CaseSensitive, ContextLines, and FilePatterns
+	// to config-file searches that don't set them
+	SearchDefaults bool
+	DisabledTags   []string
+	SummaryLine    bool
+	CrossCheck     bool
+	Normalize      string
+	WithMetadata   bool
+
+	ApprovedVersions []string
+	OnlyNonApproved  bool
+	OnlyPython2      bool
+	BestEffort       bool
+	MatchFilesOnly   bool
+	FirstMatch       bool
+	InFiles          []string
+	ProjectTimeout   int
+	Subdirs          []string
+	IgnorePaths      []string
+	OnlyPaths        []string
+	SQLitePath       string
+	Watch            time.Duration
+
+	PlausibleMajors   []int
+	MaxPlausibleMinor int
+	SubgroupDepth     int
+	ExcludeForks      bool
+	ForksOnly         bool
+	Topic             string
+	TracePath         string
+	LogMisses         bool
+	DepReportPath     string
+	TargetVersion     string
+	AtRiskBelow       string
+	RequireExplicit   bool
+	OrgSummary        bool
+	InputLog          string
+	DumpConfigPath    string
+	Mode              string
+	AtLatestTag       bool
+	Commit            string
+
+	// Project and MergeRequest select the merge request --mode mr checks
+	Project      string
+	MergeRequest int
+
+	MaxCandidates    int
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	MaxAPICalls      int
+	RampUp           time.Duration
+	TLSMinVersion    string
+	TLSCiphers       string
+	BaselinePath     string
+	ListConcurrency  int
+	PerPage          int
+	HeadOnly         bool
+	Incremental      string
+	IgnoreFile       bool
+	Explain          bool
+	Benchmark        bool
+	Bucketed         bool
+
+	// Authoritative ranks detections by confidence tier before priority
+	Authoritative      bool
+	ExplicitConfidence float64
+	InferredConfidence float64
+
+	ListVersions      bool
+	WithCounts        bool
+	IncludeUndetected bool
+
+	DecayHalfLife time.Duration
+	Strict        bool
+	FailOn        string
+	Verbose       bool
+
+	PostHook        string
+	PostHookTimeout time.Duration
 }
 
 // multiFlag allows a flag to be specified multiple times
@@ -50,10 +226,58 @@ func (m *multiFlag) Set(value string) error {
 	return nil
 }
 
+// intListFlag is a comma-separated list of integers, e.g. "2,3"
+type intListFlag []int
+
+func (l *intListFlag) String() string {
+	parts := make([]string, len(*l))
+	for i, n := range *l {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ",")
+}
+func (l *intListFlag) Set(value string) error {
+	var nums []int
+	for _, p := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return fmt.Errorf("invalid number %q", p)
+		}
+		nums = append(nums, n)
+	}
+	*l = nums
+	return nil
+}
+
 func main() {
+	// "parse" runs rules against a local file and never contacts GitLab
+	if len(os.Args) > 1 && os.Args[1] == "parse" {
+		runParseMode(os.Args[2:])
+		return
+	}
+
+	// "browse" explores a saved scan log with typed commands
+	if len(os.Args) > 1 && os.Args[1] == "browse" {
+		runBrowseMode(os.Args[2:])
+		return
+	}
+
+	// "tui" explores a saved scan log in a full-screen table
+	if len(os.Args) > 1 && os.Args[1] == "tui" {
+		runTUIMode(os.Args[2:])
+		return
+	}
+
+	// "describe-rules" lists the built-in rules for people or tooling
+	if len(os.Args) > 1 && os.Args[1] == "describe-rules" {
+		runDescribeRules(os.Args[2:])
+		return
+	}
+
 	// Check for explicit "search" subcommand (kept for backward compat)
 	if len(os.Args) > 1 && os.Args[1] == "search" {
 		searchConfig := parseSearchFlags(os.Args[2:])
+		discoverConfig(searchConfig)
 		runSearchMode(searchConfig)
 		return
 	}
@@ -66,21 +290,120 @@ func main() {
 
 	// Parse unified flags (includes both scan and search flags)
 	searchConfig := parseSearchFlags(args)
+	discoverConfig(searchConfig)
 
-	// If --search or --config is provided, run in search mode
-	if searchConfig.SearchTerm != "" || searchConfig.ConfigFile != "" {
+	// --dump-config writes the resolved configuration instead of running
+	if searchConfig.DumpConfigPath != "" {
+		runDumpConfig(searchConfig)
+		return
+	}
+
+	// --input-log re-renders a previous scan without contacting GitLab
+	if searchConfig.InputLog != "" {
+		runRenderMode(searchConfig)
+		return
+	}
+
+	mode, err := resolveMode(searchConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if mode == modeSearch {
 		runSearchMode(searchConfig)
 		return
 	}
+	if mode == modeMergeRequest {
+		runMergeRequestMode(searchConfig)
+		return
+	}
 
 	// Otherwise run in scan mode (Python version detection)
 	scanConfig := &Config{
-		GitLabURL:   searchConfig.GitLabURL,
-		Token:       searchConfig.Token,
-		LogFile:     searchConfig.LogFile,
-		Concurrency: searchConfig.Concurrency,
-		Timeout:     searchConfig.Timeout,
+		GitLabURL:    searchConfig.GitLabURL,
+		Token:        searchConfig.Token,
+		ConfigFile:   searchConfig.ConfigFile,
+		LogFiles:     searchConfig.LogFiles,
+		Concurrency:  searchConfig.Concurrency,
+		Timeout:      searchConfig.Timeout,
+		DisabledTags: searchConfig.DisabledTags,
+		SummaryLine:  searchConfig.SummaryLine,
+		CrossCheck:   searchConfig.CrossCheck,
+		Normalize:    searchConfig.Normalize,
+		WithMetadata: searchConfig.WithMetadata,
+
+		ApprovedVersions: searchConfig.ApprovedVersions,
+		OnlyNonApproved:  searchConfig.OnlyNonApproved,
+		OnlyPython2:      searchConfig.OnlyPython2,
+		BestEffort:       searchConfig.BestEffort,
+		ProjectTimeout:   searchConfig.ProjectTimeout,
+		Subdirs:          searchConfig.Subdirs,
+		IgnorePaths:      searchConfig.IgnorePaths,
+		OnlyPaths:        searchConfig.OnlyPaths,
+		SQLitePath:       searchConfig.SQLitePath,
+		Watch:            searchConfig.Watch,
+
+		PlausibleMajors:   searchConfig.PlausibleMajors,
+		MaxPlausibleMinor: searchConfig.MaxPlausibleMinor,
+		SubgroupDepth:     searchConfig.SubgroupDepth,
+		ExcludeForks:      searchConfig.ExcludeForks,
+		ForksOnly:         searchConfig.ForksOnly,
+		Topic:             searchConfig.Topic,
+		TracePath:         searchConfig.TracePath,
+		LogMisses:         searchConfig.LogMisses,
+		DepReportPath:     searchConfig.DepReportPath,
+		TargetVersion:     searchConfig.TargetVersion,
+		AtRiskBelow:       searchConfig.AtRiskBelow,
+		RequireExplicit:   searchConfig.RequireExplicit,
+		OrgSummary:        searchConfig.OrgSummary,
+		AtLatestTag:       searchConfig.AtLatestTag,
+		Commit:            searchConfig.Commit,
+		MaxCandidates:     searchConfig.MaxCandidates,
+		BreakerThreshold:  searchConfig.BreakerThreshold,
+		BreakerCooldown:   searchConfig.BreakerCooldown,
+		MaxAPICalls:       searchConfig.MaxAPICalls,
+		RampUp:            searchConfig.RampUp,
+		TLSMinVersion:     searchConfig.TLSMinVersion,
+		TLSCiphers:        searchConfig.TLSCiphers,
+		BaselinePath:      searchConfig.BaselinePath,
+		ListConcurrency:   searchConfig.ListConcurrency,
+		PerPage:           searchConfig.PerPage,
+		HeadOnly:          searchConfig.HeadOnly,
+		Incremental:       searchConfig.Incremental,
+		IgnoreFile:        searchConfig.IgnoreFile,
+		Explain:           searchConfig.Explain,
+		Benchmark:         searchConfig.Benchmark,
+		Bucketed:          searchConfig.Bucketed,
+
+		Authoritative:      searchConfig.Authoritative,
+		ExplicitConfidence: searchConfig.ExplicitConfidence,
+		InferredConfidence: searchConfig.InferredConfidence,
+
+		ListVersions:      searchConfig.ListVersions,
+		WithCounts:        searchConfig.WithCounts,
+		IncludeUndetected: searchConfig.IncludeUndetected,
+
+		DecayHalfLife: searchConfig.DecayHalfLife,
+		Strict:        searchConfig.Strict,
+		FailOn:        searchConfig.FailOn,
+		Verbose:       searchConfig.Verbose,
+
+		PostHook:        searchConfig.PostHook,
+		PostHookTimeout: searchConfig.PostHookTimeout,
+	}
+
+	if mode == modeBoth {
+		runBothMode(searchConfig, scanConfig)
+		return
+	}
+
+	instances, err := loadInstances(scanConfig.ConfigFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
+	scanConfig.Instances = instances
 
 	if err := validateConfig(scanConfig); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -88,23 +411,65 @@ func main() {
 		os.Exit(1)
 	}
 
-	fmt.Printf("GitLab Python Version Scanner\n")
-	fmt.Printf("==============================\n\n")
-	fmt.Printf("Scanning: %s\n", scanConfig.GitLabURL)
-	if scanConfig.LogFile != "" {
-		fmt.Printf("Logging to: %s\n", scanConfig.LogFile)
+	// --list-versions output is meant for scripts, so skip the banner
+	if !scanConfig.ListVersions {
+		fmt.Printf("GitLab Python Version Scanner\n")
+		fmt.Printf("==============================\n\n")
+		if len(scanConfig.Instances) == 0 {
+			fmt.Printf("Scanning: %s\n", scanConfig.GitLabURL)
+		} else {
+			fmt.Printf("Scanning %d instances from %s\n", len(scanConfig.Instances), scanConfig.ConfigFile)
+		}
+		if len(scanConfig.LogFiles) > 0 {
+			fmt.Printf("Logging to: %s\n", strings.Join(scanConfig.LogFiles, ", "))
+		}
+		if scanConfig.SQLitePath != "" {
+			fmt.Printf("Writing results to SQLite: %s\n", scanConfig.SQLitePath)
+		}
+		if scanConfig.DepReportPath != "" {
+			fmt.Printf("Writing dependency report to: %s\n", scanConfig.DepReportPath)
+		}
+		fmt.Println()
 	}
-	fmt.Println()
 
-	client, err := createClient(scanConfig.GitLabURL, scanConfig.Token, scanConfig.Timeout)
+	trace, closeTrace, err := openTrace(scanConfig.TracePath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating GitLab client: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	defer closeTrace()
 
-	printClientInfo(client)
+	var targets []*instance
+	if len(scanConfig.Instances) > 0 {
+		targets, err = connectInstances(scanConfig, trace)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating GitLab client: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		client, identity, err := createClient(scanConfig.clientOptions(trace))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating GitLab client: %v\n", err)
+			os.Exit(1)
+		}
+		if !scanConfig.ListVersions {
+			printClientInfo(client, identity)
+		}
+		targets = []*instance{{url: scanConfig.GitLabURL, client: client}}
+	}
 
-	if err := runScan(client, scanConfig); err != nil {
+	// Ctrl-C cancels the scan cleanly; a second Ctrl-C exits immediately
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := runScan(ctx, targets, scanConfig); err != nil {
+		if ctx.Err() != nil {
+			fmt.Fprintf(os.Stderr, "Scan interrupted\n")
+			os.Exit(130)
+		}
 		fmt.Fprintf(os.Stderr, "Scan failed: %v\n", err)
 		os.Exit(1)
 	}
@@ -117,9 +482,10 @@ func runSearchMode(searchConfig *SearchConfig) {
 		os.Exit(1)
 	}
 
-	// If a config file is provided, load searches from it
+	// If a config file is provided, load searches from it; --search
+	// overrides a discovered one
 	var searchConfigs []*SearchConfig
-	if searchConfig.ConfigFile != "" {
+	if searchConfig.ConfigFile != "" && !(searchConfig.ConfigDiscovered && searchConfig.SearchTerm != "") {
 		loaded, err := loadSearchesFromConfig(searchConfig)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
@@ -127,7 +493,7 @@ func runSearchMode(searchConfig *SearchConfig) {
 		}
 		searchConfigs = loaded
 	} else {
-		searchConfigs = []*SearchConfig{searchConfig}
+		searchConfigs = []*SearchConfig{singleSearch(searchConfig)}
 	}
 
 	fmt.Printf("GitLab Content Search\n")
@@ -138,18 +504,25 @@ func runSearchMode(searchConfig *SearchConfig) {
 	} else {
 		fmt.Printf("Searches: %d from config file\n", len(searchConfigs))
 	}
-	if searchConfig.LogFile != "" {
-		fmt.Printf("Logging to: %s\n", searchConfig.LogFile)
+	if len(searchConfig.LogFiles) > 0 {
+		fmt.Printf("Logging to: %s\n", strings.Join(searchConfig.LogFiles, ", "))
 	}
 	fmt.Println()
 
-	client, err := createClient(searchConfig.GitLabURL, searchConfig.Token, searchConfig.Timeout)
+	trace, closeTrace, err := openTrace(searchConfig.TracePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer closeTrace()
+
+	client, identity, err := createClient(searchConfig.clientOptions(trace))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating GitLab client: %v\n", err)
 		os.Exit(1)
 	}
 
-	printClientInfo(client)
+	printClientInfo(client, identity)
 
 	for _, sc := range searchConfigs {
 		if len(searchConfigs) > 1 {
@@ -162,6 +535,30 @@ func runSearchMode(searchConfig *SearchConfig) {
 	}
 }
 
+// discoverConfig sets the config file to a .gitlab-seeker.yaml (or .yml or
+// .json) found by walking up from the working directory when --config isn't
+// given. --no-config turns this off, and --input-log never reads a config.
+func discoverConfig(searchConfig *SearchConfig) {
+	if searchConfig.ConfigFile != "" || searchConfig.NoConfig || searchConfig.InputLog != "" {
+		return
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	home, _ := os.UserHomeDir()
+	found, err := config.Discover(wd, home)
+	if err != nil || found == "" {
+		return
+	}
+
+	// stderr, so --list-versions output stays clean
+	fmt.Fprintf(os.Stderr, "Using config file %s (--no-config to ignore)\n", found)
+	searchConfig.ConfigFile = found
+	searchConfig.ConfigDiscovered = true
+}
+
 // loadSearchesFromConfig loads search definitions from a YAML/JSON config file
 func loadSearchesFromConfig(base *SearchConfig) ([]*SearchConfig, error) {
 	cfg, err := config.LoadConfig(base.ConfigFile)
@@ -183,17 +580,48 @@ func loadSearchesFromConfig(base *SearchConfig) ([]*SearchConfig, error) {
 			continue
 		}
 
+		caseSensitive, contextLines, filePatterns := searchEntryFields(base, s)
+
+		var versionFilter output.VersionFilter
+		if s.VersionFilter != "" {
+			if base.MatchFilesOnly {
+				return nil, fmt.Errorf("search %s: version_filter compares file contents and can't be combined with --match-files-only", s.Name)
+			}
+			// Already validated by LoadConfig
+			versionFilter, err = output.ParseVersionFilter(s.VersionFilter)
+			if err != nil {
+				return nil, fmt.Errorf("search %s: %w", s.Name, err)
+			}
+		}
+
 		configs = append(configs, &SearchConfig{
 			GitLabURL:     base.GitLabURL,
 			Token:         base.Token,
-			LogFile:       base.LogFile,
+			LogFiles:      base.LogFiles,
 			Concurrency:   base.Concurrency,
 			Timeout:       base.Timeout,
 			SearchTerm:    s.SearchTerm,
+			SearchName:    s.Name,
+			Severity:      s.Severity,
 			IsRegex:       s.IsRegex,
-			FilePatterns:  s.FilePatterns,
-			CaseSensitive: s.CaseSensitive,
-			ContextLines:  s.ContextLines,
+			Prefilter:     s.Prefilter,
+			VersionFilter: versionFilter,
+			VersionGroup:  s.VersionGroup,
+			FilePatterns:  filePatterns,
+			CaseSensitive: caseSensitive,
+			ContextLines:  contextLines,
+			MaxFileSize:   base.MaxFileSize,
+			MetaPrefilter: base.MetaPrefilter,
+			Redact:        base.Redact,
+			SearchWikis:   base.SearchWikis,
+
+			MatchFilesOnly: base.MatchFilesOnly,
+			FirstMatch:     base.FirstMatch,
+			InFiles:        base.InFiles,
+			SubgroupDepth:  base.SubgroupDepth,
+			ExcludeForks:   base.ExcludeForks,
+			ForksOnly:      base.ForksOnly,
+			Topic:          base.Topic,
 		})
 	}
 
@@ -204,33 +632,222 @@ func loadSearchesFromConfig(base *SearchConfig) ([]*SearchConfig, error) {
 	return configs, nil
 }
 
-// createClient creates and tests a GitLab client connection
-func createClient(gitlabURL, token string, timeout int) (*gitlab.Client, error) {
+// singleSearch returns the search given by --search, named after its term
+// so that its results group the same way config-file searches do
+func singleSearch(base *SearchConfig) *SearchConfig {
+	search := *base
+	if search.SearchName == "" {
+		search.SearchName = search.SearchTerm
+	}
+	return &search
+}
+
+// searchEntryFields returns a config-file search's per-search settings,
+// falling back to the command line's values for fields the entry leaves
+// unset when --config-search-defaults is given
+func searchEntryFields(base *SearchConfig, s config.SearchConfigEntry) (caseSensitive bool, contextLines int, filePatterns []string) {
+	if base.SearchDefaults {
+		caseSensitive, contextLines, filePatterns = base.CaseSensitive, base.ContextLines, base.FilePatterns
+	}
+	if s.CaseSensitive != nil {
+		caseSensitive = *s.CaseSensitive
+	}
+	if s.ContextLines != nil {
+		contextLines = *s.ContextLines
+	}
+	if len(s.FilePatterns) > 0 {
+		filePatterns = s.FilePatterns
+	}
+	return caseSensitive, contextLines, filePatterns
+}
+
+// openTrace opens the --trace destination: "" disables tracing and "-" means
+// stderr. The returned close function is always safe to call.
+func openTrace(tracePath string) (io.Writer, func(), error) {
+	switch tracePath {
+	case "":
+		return nil, func() {}, nil
+	case "-":
+		return os.Stderr, func() {}, nil
+	}
+
+	f, err := os.Create(tracePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create trace file: %w", err)
+	}
+	return f, func() { f.Close() }, nil
+}
+
+// clientOptions are the connection settings createClient builds a client
+// from, shared by the scan and search configurations
+type clientOptions struct {
+	GitLabURL string
+	Token     string
+	Timeout   int // Seconds
+
+	// Concurrency is owned by the client and shared by every operation
+	// using it; ListConcurrency separately bounds the project listing's
+	// parallel page fetches, and PerPage and HeadOnly set how the listing
+	// pages through projects
+	Concurrency     int
+	ListConcurrency int
+	PerPage         int
+	HeadOnly        bool
+	RampUp          time.Duration
+
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	MaxAPICalls      int
+
+	// TLSMinVersion and TLSCiphers are the unparsed --tls-min-version and
+	// --tls-ciphers (see parseTLSFlags)
+	TLSMinVersion string
+	TLSCiphers    string
+
+	// A non-nil Trace receives one line per API call, leaving out file
+	// probes that found nothing unless LogMisses is set. Stats counts the
+	// traffic for --benchmark.
+	Trace     io.Writer
+	LogMisses bool
+	Stats     bool
+}
+
+// clientOptions returns the client settings of a scan, tracing to trace
+func (c *Config) clientOptions(trace io.Writer) clientOptions {
+	return clientOptions{
+		GitLabURL:        c.GitLabURL,
+		Token:            c.Token,
+		Timeout:          c.Timeout,
+		Concurrency:      c.Concurrency,
+		ListConcurrency:  c.ListConcurrency,
+		PerPage:          c.PerPage,
+		HeadOnly:         c.HeadOnly,
+		RampUp:           c.RampUp,
+		BreakerThreshold: c.BreakerThreshold,
+		BreakerCooldown:  c.BreakerCooldown,
+		MaxAPICalls:      c.MaxAPICalls,
+		TLSMinVersion:    c.TLSMinVersion,
+		TLSCiphers:       c.TLSCiphers,
+		Trace:            trace,
+		LogMisses:        c.LogMisses,
+		Stats:            c.Benchmark,
+	}
+}
+
+// clientOptions returns the client settings of a search, tracing to trace
+func (c *SearchConfig) clientOptions(trace io.Writer) clientOptions {
+	return clientOptions{
+		GitLabURL:        c.GitLabURL,
+		Token:            c.Token,
+		Timeout:          c.Timeout,
+		Concurrency:      c.Concurrency,
+		ListConcurrency:  c.ListConcurrency,
+		PerPage:          c.PerPage,
+		HeadOnly:         c.HeadOnly,
+		RampUp:           c.RampUp,
+		BreakerThreshold: c.BreakerThreshold,
+		BreakerCooldown:  c.BreakerCooldown,
+		MaxAPICalls:      c.MaxAPICalls,
+		TLSMinVersion:    c.TLSMinVersion,
+		TLSCiphers:       c.TLSCiphers,
+		Trace:            trace,
+		LogMisses:        c.LogMisses,
+	}
+}
+
+// createClient creates a GitLab client from opts and identifies the
+// authenticated user and instance.
+func createClient(opts clientOptions) (*gitlab.Client, *gitlab.Identity, error) {
+	minVersion, cipherSuites, err := parseTLSFlags(opts.TLSMinVersion, opts.TLSCiphers)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	gitlabConfig := &gitlab.Config{
-		GitLabURL: gitlabURL,
-		Token:     token,
-		Timeout:   time.Duration(timeout) * time.Second,
+		GitLabURL:   opts.GitLabURL,
+		Token:       opts.Token,
+		Timeout:     time.Duration(opts.Timeout) * time.Second,
+		Concurrency: opts.Concurrency,
+		RampUp:      opts.RampUp,
+		Trace:       opts.Trace,
+		TraceMisses: opts.LogMisses,
+
+		ListConcurrency: opts.ListConcurrency,
+		ListPerPage:     opts.PerPage,
+		ListHeadOnly:    opts.HeadOnly,
+
+		BreakerThreshold: opts.BreakerThreshold,
+		BreakerCooldown:  opts.BreakerCooldown,
+		MaxAPICalls:      opts.MaxAPICalls,
+		Stats:            opts.Stats,
+
+		TLSMinVersion:   minVersion,
+		TLSCipherSuites: cipherSuites,
 	}
 
 	client, err := gitlab.NewClient(gitlabConfig)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	fmt.Println("Testing GitLab connection...")
-	if err := client.TestConnection(); err != nil {
-		return nil, err
+	identity, err := client.Identify(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Catch a wrong or inaccessible group now rather than at the first listing page
+	if err := client.CheckGroupAccess(context.Background(), client.GetOrganization()); err != nil {
+		return nil, nil, err
 	}
 	fmt.Println("✓ Successfully connected to GitLab")
 	fmt.Println()
 
-	return client, nil
+	return client, identity, nil
+}
+
+// parseTLSFlags parses --tls-min-version and the comma-separated
+// --tls-ciphers, either of which may be empty for Go's defaults
+func parseTLSFlags(minVersion, ciphers string) (uint16, []uint16, error) {
+	var version uint16
+	if minVersion != "" {
+		v, err := gitlab.ParseTLSVersion(minVersion)
+		if err != nil {
+			return 0, nil, fmt.Errorf("--tls-min-version: %w", err)
+		}
+		version = v
+	}
+	if ciphers == "" {
+		return version, nil, nil
+	}
+	if version == tls.VersionTLS13 {
+		return 0, nil, fmt.Errorf("--tls-ciphers has no effect with --tls-min-version 1.3, whose cipher suites aren't configurable")
+	}
+	var names []string
+	for _, name := range strings.Split(ciphers, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	suites, err := gitlab.ParseCipherSuites(names)
+	if err != nil {
+		return 0, nil, fmt.Errorf("--tls-ciphers: %w", err)
+	}
+	return version, suites, nil
 }
 
 // printClientInfo prints the client connection details
-func printClientInfo(client *gitlab.Client) {
+func printClientInfo(client *gitlab.Client, identity *gitlab.Identity) {
 	fmt.Printf("GitLab Base URL: %s\n", client.GetBaseURL())
 	fmt.Printf("Organization: %s\n", client.GetOrganization())
+	if identity != nil {
+		version := identity.Version
+		if version == "" {
+			version = "(version unknown)"
+		}
+		fmt.Printf("Connected as %s to GitLab %s (advanced search: %s)\n",
+			identity.Username, version, identity.AdvancedSearchStatus())
+	}
 	fmt.Println()
 }
 
@@ -239,10 +856,13 @@ func runContentSearch(client *gitlab.Client, config *SearchConfig) error {
 	ctx := context.Background()
 
 	fmt.Println("Fetching projects...")
-	projects, err := client.ListAllProjects(ctx)
+	projects, err := client.ListAllProjects(ctx, config.Topic)
 	if err != nil {
 		return fmt.Errorf("failed to list projects: %w", err)
 	}
+	reportTopic(os.Stdout, len(projects), config.Topic)
+	projects = filterBySubgroupDepth(projects, client, config.SubgroupDepth)
+	projects = filterForks(projects, config.ExcludeForks, config.ForksOnly, os.Stdout)
 
 	if len(projects) == 0 {
 		fmt.Println("No projects found")
@@ -252,49 +872,55 @@ func runContentSearch(client *gitlab.Client, config *SearchConfig) error {
 	streamer := output.NewConsoleStreamer()
 	stats := output.NewContentScanStatistics()
 
-	var logger *output.FileLogger
-	if config.LogFile != "" {
-		logger, err = output.NewFileLogger(config.LogFile, output.FormatJSON)
+	sinks := []output.ContentResultSink{streamer}
+	if len(config.LogFiles) > 0 {
+		logger, err := output.OpenMultiLogger(config.LogFiles)
 		if err != nil {
 			return fmt.Errorf("failed to create log file: %w", err)
 		}
 		defer logger.Close()
+		sinks = append(sinks, logger)
 	}
 
-	if err := streamer.PrintContentHeader(config.GitLabURL, len(projects), config.SearchTerm); err != nil {
-		return fmt.Errorf("failed to print header: %w", err)
+	for _, sink := range sinks {
+		if err := sink.WriteContentHeader(config.GitLabURL, len(projects), config.SearchTerm); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
 	}
 
-	contentScanner := scanner.NewContentScanner(client, scanner.ContentSearchConfig{
-		SearchTerm:    config.SearchTerm,
-		IsRegex:       config.IsRegex,
-		FilePatterns:  config.FilePatterns,
-		CaseSensitive: config.CaseSensitive,
-		ContextLines:  config.ContextLines,
-	})
+	contentScanner := newContentScanner(client, config)
 
-	semaphore := make(chan struct{}, config.Concurrency)
 	var wg sync.WaitGroup
+	var unscanned atomic.Int32
 
 	for i, project := range projects {
 		wg.Add(1)
 		go func(index int, proj *gitlab.Project) {
 			defer wg.Done()
 
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+			// Concurrency is bounded by the client so it is shared across searches
+			if err := client.Acquire(ctx); err != nil {
+				unscanned.Add(1)
+				return
+			}
+			defer client.Release()
 
+			if client.BudgetExhausted() {
+				unscanned.Add(1)
+				return
+			}
 			result := contentScanner.ScanProject(ctx, proj, index+1, len(projects))
+			// Files refused by the budget would look like files without matches
+			if client.BudgetExhausted() {
+				unscanned.Add(1)
+				return
+			}
 
 			stats.RecordResult(result)
 
-			if err := streamer.StreamContentResult(result); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to stream result: %v\n", err)
-			}
-
-			if logger != nil {
-				if err := logger.LogContentResult(result); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to log result: %v\n", err)
+			for _, sink := range sinks {
+				if err := sink.WriteContentResult(result); err != nil {
+					warn(stats, "failed to write result for %s: %v", result.ProjectPath, err)
 				}
 			}
 		}(i, project)
@@ -302,86 +928,582 @@ func runContentSearch(client *gitlab.Client, config *SearchConfig) error {
 
 	wg.Wait()
 
-	if err := streamer.PrintContentSummary(stats); err != nil {
-		return fmt.Errorf("failed to print summary: %w", err)
+	for _, sink := range sinks {
+		if err := sink.WriteContentSummary(stats); err != nil {
+			return fmt.Errorf("failed to write summary: %w", err)
+		}
+	}
+
+	return budgetError(config.MaxAPICalls, int(unscanned.Load()), len(projects))
+}
+
+// newContentScanner returns a scanner for the search described by config
+func newContentScanner(client *gitlab.Client, config *SearchConfig) *scanner.ContentScanner {
+	return scanner.NewContentScanner(client, scanner.ContentSearchConfig{
+		SearchTerm:    config.SearchTerm,
+		SearchName:    config.SearchName,
+		Severity:      config.Severity,
+		IsRegex:       config.IsRegex,
+		Prefilter:     config.Prefilter,
+		VersionFilter: config.VersionFilter,
+		VersionGroup:  config.VersionGroup,
+		FilePatterns:  config.FilePatterns,
+		CaseSensitive: config.CaseSensitive,
+		ContextLines:  config.ContextLines,
+		MaxFileSize:   config.MaxFileSize,
+
+		MatchFilesOnly:    config.MatchFilesOnly,
+		FirstMatch:        config.FirstMatch,
+		MetadataPrefilter: config.MetaPrefilter,
+		InFiles:           config.InFiles,
+		Redact:            config.Redact,
+		SearchWikis:       config.SearchWikis,
+	})
+}
+
+// runScan orchestrates the scanning process. With --watch it repeats the scan
+// every config.Watch, appending each run to the same outputs, until ctx is
+// cancelled.
+func runScan(ctx context.Context, instances []*instance, config *Config) error {
+	state := &runState{ignoreFiles: scanner.NewIgnoreFileCache()}
+	// Read before --log truncates its files, which usually include this one
+	if config.Incremental != "" {
+		loaded, err := loadResultCache(config.Incremental)
+		if err != nil {
+			return err
+		}
+		state.results = loaded
+	}
+
+	// Outputs stay open across watch runs so each run appends a snapshot
+	streamer := output.NewConsoleStreamer()
+	streamer.Verbose = config.Verbose
+	// Redrawing earlier lines would garble a file or pipe
+	streamer.Bucketed = config.Bucketed && output.IsTerminal(os.Stdout)
+	if streamer.Bucketed {
+		streamer.Width = output.TerminalWidth(os.Stdout)
+	}
+	var sinks []output.ResultSink
+	// --list-versions prints its own output once the scan is done
+	if !config.ListVersions {
+		sinks = append(sinks, streamer)
+	}
+	if len(config.LogFiles) > 0 {
+		logger, err := output.OpenMultiLogger(config.LogFiles)
+		if err != nil {
+			return fmt.Errorf("failed to create log file: %w", err)
+		}
+		defer logger.Close()
+		sinks = append(sinks, logger)
+	}
+	var db *output.SQLiteSink
+	if config.SQLitePath != "" {
+		var err error
+		db, err = output.NewSQLiteSink(config.SQLitePath)
+		if err != nil {
+			return fmt.Errorf("failed to open sqlite database: %w", err)
+		}
+		defer db.Close()
+		sinks = append(sinks, db)
+	}
+
+	if config.Watch <= 0 {
+		return scanWithHook(ctx, instances, config, streamer, sinks, output.NewScanStatistics(), state)
+	}
+
+	for seq := 1; ; seq++ {
+		stats := output.NewScanStatistics()
+		stats.RunStarted = time.Now()
+		stats.RunID = newRunID(stats.RunStarted, seq)
+
+		fmt.Printf("=== Run %s ===\n", stats.RunID)
+		if db != nil {
+			db.SetRunID(stats.RunID)
+		}
+		for _, inst := range instances {
+			inst.client.ResetBudget()
+		}
+		if err := scanWithHook(ctx, instances, config, streamer, sinks, stats, state); err != nil {
+			if ctx.Err() != nil {
+				fmt.Println("Watch stopped")
+				return nil
+			}
+			// One failed run (e.g. GitLab briefly unavailable) shouldn't end the watch
+			fmt.Fprintf(os.Stderr, "Warning: run %s failed: %v\n", stats.RunID, err)
+		}
+
+		fmt.Printf("\nNext scan in %v (Ctrl-C to stop)\n\n", config.Watch)
+		select {
+		case <-ctx.Done():
+			fmt.Println("Watch stopped")
+			return nil
+		case <-time.After(config.Watch):
+		}
+	}
+}
+
+// newScanRegistry returns the rule registry for Python version detection,
+// with rules carrying any of disabledTags turned off
+func newScanRegistry(disabledTags []string) *rules.Registry {
+	registry := parsers.DefaultRegistry()
+	for _, tag := range disabledTags {
+		registry.DisableByTag(tag)
+	}
+	return registry
+}
+
+// loadScanRegistry returns the rules from configFile when it defines any,
+// otherwise the built-in rules, plus a rule for each of the config's
+// explicit_version_files and with its confidence_overrides applied. Rules
+// carrying disabledTags are turned off.
+func loadScanRegistry(configFile string, disabledTags []string) (*rules.Registry, error) {
+	if configFile == "" {
+		return newScanRegistry(disabledTags), nil
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	registry := parsers.DefaultRegistry()
+	if len(cfg.Rules) > 0 {
+		registry, err = cfg.ToRegistry(config.NewDefaultParserRegistry())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rules from %s: %w", configFile, err)
+		}
+	}
+	if err := parsers.RegisterExplicitVersionFiles(registry, cfg.Settings.ExplicitVersionFiles); err != nil {
+		return nil, fmt.Errorf("explicit_version_files in %s: %w", configFile, err)
+	}
+	if err := cfg.ApplyConfidenceOverrides(registry); err != nil {
+		return nil, fmt.Errorf("%s: %w", configFile, err)
+	}
+	for _, tag := range disabledTags {
+		registry.DisableByTag(tag)
+	}
+	return registry, nil
+}
+
+// loadProjectRefs returns the config's project_refs, or nil without a config
+func loadProjectRefs(configFile string) (map[string]string, error) {
+	if configFile == "" {
+		return nil, nil
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	for path, ref := range cfg.Settings.ProjectRefs {
+		if strings.TrimSpace(ref) == "" {
+			return nil, fmt.Errorf("project_refs in %s: no ref given for %s", configFile, path)
+		}
+	}
+	return cfg.Settings.ProjectRefs, nil
+}
+
+// commitSHAPattern matches an abbreviated or full SHA-1 or SHA-256 commit ID
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,64}$`)
+
+// projectRefs returns the refs to scan each instance's listed projects at
+// instead of their default branches, keyed by project path: the config's
+// project_refs, plus --commit for the only project listed. owners[i] is the
+// instance projects[i] is on. A project_refs entry keyed "instance:path"
+// (see output.ProjectKey) pins the path on that instance only and takes
+// precedence over the bare path. exists reports whether a pinned project
+// that wasn't listed exists at all (see checkProjectRefs).
+func projectRefs(config *Config, projects []*gitlab.Project, owners []*instance, exists func(key string) (bool, error)) (map[*instance]map[string]string, error) {
+	refs, err := loadProjectRefs(config.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	notes := io.Writer(os.Stdout)
+	if config.ListVersions {
+		notes = io.Discard
+	}
+	if err := checkProjectRefs(refs, projects, owners, exists, notes); err != nil {
+		return nil, err
+	}
+
+	if config.Commit != "" {
+		if len(projects) != 1 {
+			return nil, fmt.Errorf("--commit pins a single project, but %d were found; pin several with project_refs in --config", len(projects))
+		}
+		path := projects[0].PathWithNamespace
+		key := output.ProjectKey(owners[0].name, path)
+		for _, pinned := range []string{key, path} {
+			if ref, ok := refs[pinned]; ok && ref != config.Commit {
+				return nil, fmt.Errorf("--commit %s conflicts with project_refs, which pins %s at %s", config.Commit, pinned, ref)
+			}
+		}
+		if refs == nil {
+			refs = make(map[string]string, 1)
+		}
+		refs[key] = config.Commit
+	}
+
+	byInstance := make(map[*instance]map[string]string)
+	for i, project := range projects {
+		path := project.PathWithNamespace
+		ref, ok := refs[output.ProjectKey(owners[i].name, path)]
+		if !ok {
+			ref, ok = refs[path]
+		}
+		if !ok {
+			continue
+		}
+		if byInstance[owners[i]] == nil {
+			byInstance[owners[i]] = make(map[string]string)
+		}
+		byInstance[owners[i]][path] = ref
+	}
+	return byInstance, nil
+}
+
+// checkProjectRefs returns an error naming every project in refs that
+// doesn't exist, so a typo isn't silently scanned at its default branch, or
+// that is named by a bare path listed on more than one instance, which
+// would pin each of them. Pinned projects that exist but weren't listed,
+// e.g. because --topic or --exclude-forks filtered them out, aren't
+// scanned; a note naming them is written to w.
+func checkProjectRefs(refs map[string]string, projects []*gitlab.Project, owners []*instance, exists func(key string) (bool, error), w io.Writer) error {
+	if shared := sharedPaths(refs, projects, owners); len(shared) > 0 {
+		return fmt.Errorf("project_refs names projects on more than one instance by path alone: %s; key each as instance:path", strings.Join(shared, ", "))
 	}
 
+	listed := make(map[string]bool, len(projects))
+	for i, project := range projects {
+		listed[project.PathWithNamespace] = true
+		listed[output.ProjectKey(owners[i].name, project.PathWithNamespace)] = true
+	}
+	var missing, filtered []string
+	for key := range refs {
+		if listed[key] {
+			continue
+		}
+		found, err := exists(key)
+		if err != nil {
+			return fmt.Errorf("project_refs: checking %s: %w", key, err)
+		}
+		if found {
+			filtered = append(filtered, key)
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("project_refs names projects that weren't found: %s", strings.Join(missing, ", "))
+	}
+	if len(filtered) > 0 {
+		sort.Strings(filtered)
+		fmt.Fprintf(w, "Note: project_refs pins projects that were filtered out of the listing and won't be scanned: %s\n", strings.Join(filtered, ", "))
+	}
 	return nil
 }
 
-// runScan orchestrates the scanning process
-func runScan(client *gitlab.Client, config *Config) error {
-	ctx := context.Background()
+// loadBaseline reads the --baseline file, or returns nil if none was given
+func loadBaseline(path string) (output.Baseline, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return output.LoadBaseline(path)
+}
 
-	// List all projects
-	fmt.Println("Fetching projects...")
-	projects, err := client.ListAllProjects(ctx)
+// newScanOptions returns the per-project scan settings for config
+func newScanOptions(config *Config) scanner.VersionScanOptions {
+	return scanner.VersionScanOptions{
+		CrossCheck:     config.CrossCheck,
+		WithMetadata:   config.WithMetadata,
+		ProjectTimeout: time.Duration(config.ProjectTimeout) * time.Second,
+		Subdirs:        config.Subdirs,
+		IgnorePaths:    append(append([]string(nil), scanner.DefaultIgnorePaths...), config.IgnorePaths...),
+		OnlyPaths:      config.OnlyPaths,
+		Bounds: output.VersionBounds{
+			Majors:   config.PlausibleMajors,
+			MaxMinor: config.MaxPlausibleMinor,
+		},
+		Dependencies:  config.DepReportPath != "",
+		AtLatestTag:   config.AtLatestTag,
+		MaxCandidates: config.MaxCandidates,
+		DecayHalfLife: config.DecayHalfLife,
+		Strict:        config.Strict,
+		IgnoreFile:    config.IgnoreFile,
+		Explain:       config.Explain,
+		Tiers:         confidenceTiers(config),
+	}
+}
+
+// confidenceTiers returns the tiers --authoritative ranks detections by, or
+// nil without it
+func confidenceTiers(config *Config) *output.ConfidenceTiers {
+	if !config.Authoritative {
+		return nil
+	}
+	return &output.ConfidenceTiers{Explicit: config.ExplicitConfidence, Inferred: config.InferredConfidence}
+}
+
+// hidesResult reports whether --only-non-approved, --only-python2, or
+// --baseline keeps result out of the streamed output; the summary still
+// counts everything
+func (c *Config) hidesResult(result *output.ScanResult) bool {
+	if result.Drift == output.DriftConforming {
+		return true
+	}
+	// Projects without a detected version (including failed scans) aren't
+	// on a non-approved version, so --only-non-approved leaves them out too
+	if c.OnlyNonApproved && (result.PythonVersion == "" || output.IsApproved(result.PythonVersion, c.ApprovedVersions)) {
+		return true
+	}
+	return c.OnlyPython2 && !result.IsPython2
+}
+
+// warningRecorder is the statistics of a scan or search, which keep the
+// run's warnings for its summary
+type warningRecorder interface {
+	RecordWarning(warning string)
+}
+
+// warn prints a problem that doesn't stop the run and records it on stats,
+// so the summary shows the run wasn't clean
+func warn(stats warningRecorder, format string, args ...interface{}) {
+	warning := fmt.Sprintf(format, args...)
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	stats.RecordWarning(warning)
+}
+
+// validateListVersions checks the flags that shape --list-versions output
+func validateListVersions(listVersions, withCounts, includeUndetected, summaryLine bool) error {
+	if !listVersions {
+		if withCounts {
+			return fmt.Errorf("--with-counts requires --list-versions")
+		}
+		if includeUndetected {
+			return fmt.Errorf("--include-undetected requires --list-versions")
+		}
+		return nil
+	}
+	if summaryLine {
+		return fmt.Errorf("--list-versions can't be combined with --summary-line")
+	}
+	return nil
+}
+
+// minWatchInterval is the shortest accepted --watch interval
+const minWatchInterval = time.Minute
+
+// newRunID returns an identifier for the seq'th watch run, unique within the
+// process and sortable across processes
+func newRunID(started time.Time, seq int) string {
+	return fmt.Sprintf("%s-%d", started.UTC().Format("20060102T150405Z"), seq)
+}
+
+// filterBySubgroupDepth applies --subgroup-depth to a project listing and
+// reports how many nested projects were left out
+func filterBySubgroupDepth(projects []*gitlab.Project, client *gitlab.Client, depth int) []*gitlab.Project {
+	filtered := gitlab.FilterBySubgroupDepth(projects, client.GetOrganization(), depth)
+	if skipped := len(projects) - len(filtered); skipped > 0 {
+		fmt.Printf("Skipping %d projects deeper than %d subgroup level(s)\n", skipped, depth)
+	}
+	return filtered
+}
+
+// reportTopic writes to w how many projects were listed with --topic,
+// which GitLab filtered the listing by
+func reportTopic(w io.Writer, listed int, topic string) {
+	if topic != "" {
+		fmt.Fprintf(w, "%d projects have topic %q\n", listed, topic)
+	}
+}
+
+// filterForks applies --exclude-forks or --forks-only to a project listing
+// and reports to w how many projects were left out
+func filterForks(projects []*gitlab.Project, excludeForks, forksOnly bool, w io.Writer) []*gitlab.Project {
+	var filtered []*gitlab.Project
+	var kind string
+	switch {
+	case excludeForks:
+		filtered, kind = gitlab.FilterForks(projects, false), "forked"
+	case forksOnly:
+		filtered, kind = gitlab.FilterForks(projects, true), "non-fork"
+	default:
+		return projects
+	}
+	if skipped := len(projects) - len(filtered); skipped > 0 {
+		fmt.Fprintf(w, "Skipping %d %s projects\n", skipped, kind)
+	}
+	return filtered
+}
+
+// listScanProjects lists the projects to scan. With --best-effort a listing
+// that failed part way returns the projects found so far along with the
+// *gitlab.PartialListError describing the failure.
+func listScanProjects(ctx context.Context, client *gitlab.Client, config *Config) ([]*gitlab.Project, *gitlab.PartialListError, error) {
+	if !config.ListVersions {
+		fmt.Println("Fetching projects...")
+	}
+	var projects []*gitlab.Project
+	var partial *gitlab.PartialListError
+	var err error
+	if config.BestEffort {
+		projects, err = client.ListAllProjectsBestEffort(ctx, config.Topic)
+	} else {
+		projects, err = client.ListAllProjects(ctx, config.Topic)
+	}
+	if errors.As(err, &partial) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", partial)
+		fmt.Fprintf(os.Stderr, "Warning: continuing with %d projects; results are incomplete\n", len(projects))
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	if config.ListVersions {
+		projects = gitlab.FilterBySubgroupDepth(projects, client.GetOrganization(), config.SubgroupDepth)
+		return filterForks(projects, config.ExcludeForks, config.ForksOnly, io.Discard), partial, nil
+	}
+	reportTopic(os.Stdout, len(projects), config.Topic)
+	projects = filterBySubgroupDepth(projects, client, config.SubgroupDepth)
+	return filterForks(projects, config.ExcludeForks, config.ForksOnly, os.Stdout), partial, nil
+}
+
+// runState is what one --watch run hands to the next
+type runState struct {
+	results     *resultCache             // Results --incremental can reuse (nil without it)
+	ignoreFiles *scanner.IgnoreFileCache // Which projects have a .gitlab-seeker-ignore
+}
+
+// scanOnce lists the projects, scans each one, and writes the results and
+// summary to every sink. With cached results (--incremental), unchanged
+// projects reuse them instead of being scanned. state may be nil for a scan
+// that isn't repeated.
+func scanOnce(ctx context.Context, instances []*instance, config *Config, streamer *output.ConsoleStreamer, sinks []output.ResultSink, stats *output.ScanStatistics, state *runState) error {
+	if state == nil {
+		state = &runState{}
+	}
+	cache := state.results
+
+	projects, owners, partial, err := listInstanceProjects(ctx, instances, config)
 	if err != nil {
-		return fmt.Errorf("failed to list projects: %w", err)
+		return err
 	}
 
 	if len(projects) == 0 {
-		fmt.Println("No projects found")
+		if !config.ListVersions {
+			fmt.Println("No projects found")
+		}
 		return nil
 	}
 
-	// Initialize output handlers
-	streamer := output.NewConsoleStreamer()
-	stats := output.NewScanStatistics()
+	// Initialize statistics
+	stats.ListingError = partial
+	stats.Normalize = output.Normalization(config.Normalize)
+	stats.ApprovedVersions = config.ApprovedVersions
+	stats.TargetVersion = config.TargetVersion
+	stats.AtRiskBelow = config.AtRiskBelow
+	stats.RequireExplicit = config.RequireExplicit
+	if config.OrgSummary {
+		stats.OrgSummary = true
+		stats.EOLAsOf = time.Now()
+	}
 
-	var logger *output.FileLogger
-	if config.LogFile != "" {
-		logger, err = output.NewFileLogger(config.LogFile, output.FormatJSON)
-		if err != nil {
-			return fmt.Errorf("failed to create log file: %w", err)
-		}
-		defer logger.Close()
+	// Read on every run so --watch picks up baseline edits
+	baseline, err := loadBaseline(config.BaselinePath)
+	if err != nil {
+		return err
+	}
+	if shared := sharedPaths(baseline, projects, owners); len(shared) > 0 {
+		return fmt.Errorf("--baseline names projects on more than one instance by path alone: %s; key each as instance:path", strings.Join(shared, ", "))
+	}
+	stats.Baseline = baseline
 
-		if err := logger.WriteHeader(config.GitLabURL, len(projects)); err != nil {
-			return fmt.Errorf("failed to write log header: %w", err)
+	// Write headers
+	for _, sink := range sinks {
+		if err := sink.WriteHeader(instanceURLs(instances), len(projects)); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
 		}
 	}
 
-	// Print header
-	if err := streamer.PrintHeader(config.GitLabURL, len(projects)); err != nil {
-		return fmt.Errorf("failed to print header: %w", err)
+	registry, err := loadScanRegistry(config.ConfigFile, config.DisabledTags)
+	if err != nil {
+		return err
 	}
 
-	// Create rule registry for Python version detection
-	registry := parsers.DefaultRegistry()
+	opts := newScanOptions(config)
+	opts.IgnoreFiles = state.ignoreFiles
+	refs, err := projectRefs(config, projects, owners, projectExists(ctx, instances))
+	if err != nil {
+		return err
+	}
 
-	// Set up concurrency control
-	semaphore := make(chan struct{}, config.Concurrency)
+	// Each run's report covers only that run's projects
+	var inventory *output.DependencyInventory
+	if opts.Dependencies {
+		inventory = output.NewDependencyInventory()
+	}
+
+	var bench *benchmark
+	if config.Benchmark {
+		bench = newBenchmark(instanceStats(instances))
+	}
+
+	// Concurrency is bounded by the client's shared slots
 	var wg sync.WaitGroup
-	var mu sync.Mutex
+	var unscanned atomic.Int32 // Projects left out once --max-api-calls ran out or the scan was interrupted
 
 	// Scan each project concurrently
 	for i, project := range projects {
 		wg.Add(1)
 		go func(index int, proj *gitlab.Project) {
 			defer wg.Done()
+			inst := owners[index]
+			client := inst.client
+			opts := opts
+			opts.Refs = refs[inst]
+
+			// Acquire a slot from the client's shared pool; it only fails
+			// once the scan is interrupted
+			if err := client.Acquire(ctx); err != nil {
+				unscanned.Add(1)
+				return
+			}
+			var result *output.ScanResult
+			if cache != nil && !client.BudgetExhausted() {
+				result = cache.reuse(ctx, inst, proj, opts, index+1, len(projects))
+			}
+			if result == nil && !client.BudgetExhausted() {
+				started := time.Now()
+				result = scanner.ScanProject(ctx, client, registry, proj, index+1, len(projects), opts)
+				if bench != nil {
+					bench.record(time.Since(started))
+				}
+			}
+			client.Release()
+			// Probes refused by the budget would look like missing files
+			if client.BudgetExhausted() && (result == nil || !result.Cached) {
+				unscanned.Add(1)
+				return
+			}
+			result.Instance = inst.name
+			if baseline != nil {
+				baseline.Annotate(result)
+			}
 
-			// Acquire semaphore
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-
-			// Scan the project
-			result := scanProject(ctx, client, registry, proj, index+1, len(projects))
-
-			// Thread-safe result recording
-			mu.Lock()
 			stats.RecordResult(result)
-			mu.Unlock()
+			if cache != nil {
+				cache.record(result)
+			}
+			if inventory != nil {
+				inventory.Record(result.ProjectPath, result.Dependencies)
+			}
 
-			// Stream result to console
-			if err := streamer.StreamResult(result); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to stream result: %v\n", err)
+			if config.hidesResult(result) {
+				return
 			}
 
-			// Log result to file if logger is configured
-			if logger != nil {
-				if err := logger.LogResult(result); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to log result: %v\n", err)
+			// Stream result to every output
+			for _, sink := range sinks {
+				if err := sink.WriteResult(result); err != nil {
+					warn(stats, "failed to write result for %s: %v", result.ProjectPath, err)
 				}
 			}
 		}(i, project)
@@ -389,76 +1511,95 @@ func runScan(client *gitlab.Client, config *Config) error {
 
 	// Wait for all scans to complete
 	wg.Wait()
+	if ctx.Err() != nil && unscanned.Load() > 0 {
+		warn(stats, "scan interrupted; %d of %d projects were not scanned", unscanned.Load(), len(projects))
+	}
 
-	// Print summary
-	if err := streamer.PrintSummary(stats); err != nil {
-		return fmt.Errorf("failed to print summary: %w", err)
+	if inventory != nil {
+		if err := output.WriteDependencyReport(config.DepReportPath, inventory); err != nil {
+			return err
+		}
+		fmt.Printf("Dependency report: %d packages across %d projects written to %s\n",
+			len(inventory.Packages()), inventory.ProjectCount(), config.DepReportPath)
 	}
 
-	// Write summary to log
-	if logger != nil {
-		if err := logger.WriteSummary(stats); err != nil {
-			return fmt.Errorf("failed to write log summary: %w", err)
+	// Write summaries; an interrupted scan still summarizes what it finished
+	for _, sink := range sinks {
+		if err := sink.WriteSummary(stats); err != nil {
+			return fmt.Errorf("failed to write summary: %w", err)
 		}
 	}
 
-	return nil
-}
+	if bench != nil {
+		if err := bench.report(os.Stdout, instanceStats(instances)); err != nil {
+			return fmt.Errorf("failed to print benchmark: %w", err)
+		}
+	}
 
-// scanProject scans a single project for Python version information
-func scanProject(ctx context.Context, client *gitlab.Client, registry *rules.Registry, project *gitlab.Project, index, total int) *output.ScanResult {
-	result := &output.ScanResult{
-		ProjectName:   project.Name,
-		ProjectPath:   project.PathWithNamespace,
-		Index:         index,
-		TotalProjects: total,
+	// The machine-readable line must be the last thing on stdout
+	if config.SummaryLine {
+		if err := streamer.PrintSummaryLine(stats); err != nil {
+			return fmt.Errorf("failed to print summary line: %w", err)
+		}
+	}
+	if config.ListVersions {
+		if err := streamer.PrintVersionList(stats, config.WithCounts, config.IncludeUndetected); err != nil {
+			return fmt.Errorf("failed to print version list: %w", err)
+		}
 	}
 
-	// Get all enabled rules to determine which files to check
-	enabledRules := registry.ListEnabled()
-	if len(enabledRules) == 0 {
-		result.Error = fmt.Errorf("no enabled rules found")
-		return result
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := budgetError(config.MaxAPICalls, int(unscanned.Load()), len(projects)); err != nil {
+		return err
 	}
+	return checkFailOn(config.FailOn, stats)
+}
 
-	// Try each rule's file pattern until we find a match
-	// Rules are already sorted by priority (highest first)
-	for _, rule := range enabledRules {
-		filename := rule.Condition.FilePattern
+// budgetError reports the projects a run left out because --max-api-calls
+// ran out, or returns nil if it didn't
+func budgetError(maxAPICalls, unscanned, total int) error {
+	if unscanned == 0 {
+		return nil
+	}
+	return fmt.Errorf("API budget exhausted after %d calls (--max-api-calls); %d of %d projects were not scanned, so the results are partial",
+		maxAPICalls, unscanned, total)
+}
 
-		// Try to fetch the file from the project
-		content, err := client.GetRawFile(ctx, project.ID, filename, nil)
-		if err != nil {
-			// File not found or other error - try next rule
-			continue
-		}
+// failOnParseErrors is the --fail-on value that fails a scan in which a
+// rule's parser errored on any candidate file
+const failOnParseErrors = "parse-errors"
 
-		// Apply the rule to parse the file content
-		searchResult, err := rule.Apply(ctx, content, filename)
-		if err != nil {
-			// Parse error - try next rule
-			continue
-		}
+// checkFailOn returns an error if the finished scan hit the --fail-on condition
+func checkFailOn(failOn string, stats *output.ScanStatistics) error {
+	if failOn == failOnParseErrors && stats.ParseErrorCount > 0 {
+		return fmt.Errorf("%d candidate files failed to parse (--fail-on %s)", stats.ParseErrorCount, failOnParseErrors)
+	}
+	return nil
+}
 
-		// Check if we found a Python version
-		if searchResult != nil && searchResult.Found && searchResult.Version != "" {
-			result.PythonVersion = searchResult.Version
-			result.DetectionSource = searchResult.Source
-			return result
+// validatePathGlobs rejects globs given to flag (--ignore-path or
+// --only-path) that path.Match can't parse
+func validatePathGlobs(flag string, patterns []string) error {
+	for _, pattern := range patterns {
+		for _, segment := range strings.Split(pattern, "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("%s %q: %w", flag, pattern, err)
+			}
 		}
 	}
-
-	// No Python version found
-	return result
+	return nil
 }
 
 func parseScanFlags(args []string) *Config {
 	config := &Config{}
+	var logFiles multiFlag
 
 	fs := flag.NewFlagSet("scan", flag.ExitOnError)
 	fs.StringVar(&config.GitLabURL, "url", "", "GitLab URL including org/group (e.g., gitlab.com/myorg)")
 	fs.StringVar(&config.Token, "token", os.Getenv("GITLAB_TOKEN"), "GitLab API token (or set GITLAB_TOKEN env var)")
-	fs.StringVar(&config.LogFile, "log", "", "Path to log file (optional)")
+	fs.Var(&logFiles, "log", "Path to log file, format inferred from extension: .json, .csv, .txt (repeatable)")
 	fs.IntVar(&config.Concurrency, "concurrency", 5, "Number of concurrent scans")
 	fs.IntVar(&config.Timeout, "timeout", 30, "API timeout in seconds")
 
@@ -470,28 +1611,112 @@ func parseScanFlags(args []string) *Config {
 		fmt.Fprintf(os.Stderr, "\nExample:\n")
 		fmt.Fprintf(os.Stderr, "  %s --url gitlab.com/myorg --token abc123\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --url gitlab.com/myorg --token abc123 --log results.log\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --url gitlab.com/myorg --token abc123 --log results.json --log results.csv\n", os.Args[0])
 	}
 
 	fs.Parse(args)
+	config.LogFiles = logFiles
 	return config
 }
 
 func parseSearchFlags(args []string) *SearchConfig {
 	config := &SearchConfig{}
 	var filePatterns multiFlag
+	var inFiles multiFlag
+	var logFiles multiFlag
+	var disabledTags multiFlag
+	var approvedVersions string
+	var subdirs multiFlag
+	var ignorePaths multiFlag
+	var onlyPaths multiFlag
+	plausibleMajors := intListFlag(output.DefaultVersionBounds.Majors)
 
 	fs := flag.NewFlagSet("scanner", flag.ExitOnError)
 	fs.StringVar(&config.GitLabURL, "url", "", "GitLab URL including org/group (e.g., gitlab.com/myorg)")
 	fs.StringVar(&config.Token, "token", os.Getenv("GITLAB_TOKEN"), "GitLab API token (or set GITLAB_TOKEN env var)")
-	fs.StringVar(&config.LogFile, "log", "", "Path to log file (optional)")
-	fs.IntVar(&config.Concurrency, "concurrency", 5, "Number of concurrent operations")
+	fs.Var(&logFiles, "log", "Path to log file, format inferred from extension: .json, .csv, .txt (repeatable)")
+	fs.StringVar(&config.SQLitePath, "sqlite", "", "Also write scan results to a SQLite database at this path (scan mode only)")
+	fs.StringVar(&config.DepReportPath, "dep-report", "", "Write a cross-project requirements.txt dependency inventory to this path, format inferred from extension (scan mode only)")
+	fs.StringVar(&config.InputLog, "input-log", "", "Re-render a previous scan's JSON log (JSONL or JSON array) through the console, --log, and --sqlite outputs without contacting GitLab")
+	fs.IntVar(&config.Concurrency, "concurrency", 5, "Number of concurrent scan and search operations, shared by everything using the same GitLab client")
+	fs.IntVar(&config.Concurrency, "scan-concurrency", 5, "Alias for --concurrency")
+	fs.DurationVar(&config.RampUp, "ramp-up", 0, "Admit --concurrency workers one at a time over this long (e.g. 10s) instead of all at once when the scan starts (0 = no ramp)")
+	fs.StringVar(&config.TLSMinVersion, "tls-min-version", "", "Oldest TLS version to negotiate with GitLab: 1.2 or 1.3 (default: Go's, currently 1.2)")
+	fs.StringVar(&config.TLSCiphers, "tls-ciphers", "", "Comma-separated cipher suites to allow for TLS 1.2 and earlier, as Go names them (e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256); TLS 1.3 suites aren't configurable")
+	fs.IntVar(&config.ListConcurrency, "list-concurrency", 4, "Number of project listing pages fetched in parallel, independent of --concurrency (0 or 1 = serial)")
+	fs.IntVar(&config.PerPage, "per-page", 20, "Projects per listing page, up to 100 (0 = GitLab's default); larger pages mean fewer requests for big groups")
+	fs.BoolVar(&config.HeadOnly, "head-only", false, "For small groups: list projects with one unretried request of 100 per page, falling back to normal listing if there are more or it fails")
 	fs.IntVar(&config.Timeout, "timeout", 30, "API timeout in seconds")
+	fs.IntVar(&config.BreakerThreshold, "breaker-threshold", 10, "Fail API calls fast after this many consecutive network, timeout, rate-limit, or 5xx failures (0 = disabled)")
+	fs.DurationVar(&config.BreakerCooldown, "breaker-cooldown", 30*time.Second, "How long to fail fast once --breaker-threshold is reached before trying GitLab again")
+	fs.IntVar(&config.MaxAPICalls, "max-api-calls", 0, "Stop once this many API requests (retries included) have been made in a run, reporting the projects done so far (0 = unlimited)")
+	fs.StringVar(&config.TracePath, "trace", "", "Record every API call (method, URL, status, duration, retry) as JSON lines to this file, or \"-\" for stderr; tokens are redacted")
+	fs.BoolVar(&config.LogMisses, "log-misses", false, "Also trace file probes that found no file (404s, category \"miss\"), which --trace leaves out by default")
+	fs.DurationVar(&config.Watch, "watch", 0, "Re-run the scan at this interval (e.g. 15m) until interrupted, logging each run's summary with a run ID")
+	fs.IntVar(&config.ProjectTimeout, "project-timeout", 0, "Maximum seconds to spend on a single project's file operations (0 = no limit)")
 	fs.StringVar(&config.SearchTerm, "search", "", "String or pattern to search for (enables search mode)")
 	fs.BoolVar(&config.IsRegex, "regex", false, "Treat search term as a regex pattern")
-	fs.Var(&filePatterns, "file", "Filename glob pattern to restrict search (repeatable, e.g., --file '*.py')")
+	fs.StringVar(&config.Prefilter, "prefilter", "", "Literal every --regex match contains; files without it are skipped before regex matching")
+	fs.Int64Var(&config.MaxFileSize, "max-file-size", 0, "Skip files larger than this many bytes when searching file contents (0 = 1MB default)")
+	fs.BoolVar(&config.MetaPrefilter, "metadata-prefilter", false, "Fetch each file's metadata first and skip files over --max-file-size without downloading them (--regex searches)")
+	fs.Var(&filePatterns, "file", "Filename glob pattern to restrict search; prefix with ! to exclude, exclusions win (repeatable, e.g., --file '*.py' --file '!*_test.py')")
+	fs.BoolVar(&config.MatchFilesOnly, "match-files-only", false, "Match --search/--file against file paths instead of contents (no file fetches)")
+	fs.BoolVar(&config.Redact, "redact", false, "Mask matched text in search results and logs, keeping the first 4 characters of longer matches, so secrets found aren't copied into reports")
+	fs.BoolVar(&config.SearchWikis, "search-wikis", false, "Also search each project's wiki pages, reporting matches as wiki:<page-slug>")
+	fs.BoolVar(&config.FirstMatch, "first-match", false, "Stop searching each project at its first match and report only that, for presence checks (match counts are not totals)")
+	fs.Var(&inFiles, "in-file", "Search only this exact file path in each project, fetched directly without listing the tree (repeatable, e.g., --in-file Dockerfile)")
 	fs.BoolVar(&config.CaseSensitive, "case-sensitive", false, "Enable case-sensitive search (default: case-insensitive)")
 	fs.IntVar(&config.ContextLines, "context", 0, "Lines of context around each match")
-	fs.StringVar(&config.ConfigFile, "config", "", "Path to YAML/JSON config file with search definitions")
+	fs.StringVar(&config.ConfigFile, "config", "", "Path to YAML/JSON config file with search definitions (default: the nearest .gitlab-seeker.yaml, .yml, or .json up to the repository root or home directory)")
+	fs.BoolVar(&config.NoConfig, "no-config", false, "Don't look for a .gitlab-seeker.yaml config file when --config isn't given")
+	fs.StringVar(&config.Mode, "mode", modeAuto, "auto (search when --search/--match-files-only or a --config with searches is given, else scan), scan (use --config rules), search, both (scan with --config rules and run its searches in one pass), or mr (check one merge request's changes)")
+	fs.StringVar(&config.Project, "project", os.Getenv("CI_PROJECT_PATH"), "Project whose merge request --mode mr checks, e.g. myorg/app (or set CI_PROJECT_PATH, as GitLab CI does)")
+	fs.IntVar(&config.MergeRequest, "merge-request", mergeRequestIID(), "IID of the merge request --mode mr checks (or set CI_MERGE_REQUEST_IID, as merge request pipelines do)")
+	fs.StringVar(&config.DumpConfigPath, "dump-config", "", "Write the effective rules and searches after --config, --disable-tag, and other flags are applied to this path (.yaml or .json), then exit")
+	fs.BoolVar(&config.SearchDefaults, "config-search-defaults", false, "Use --case-sensitive, --context, and --file as defaults for --config searches that don't set them")
+	fs.BoolVar(&config.CrossCheck, "cross-check", false, "Keep probing lower-priority sources after a detection and report version disagreements")
+	fs.BoolVar(&config.BestEffort, "best-effort", false, "Scan the projects listed so far if a later listing page fails, and mark the summary incomplete")
+	fs.BoolVar(&config.WithMetadata, "with-metadata", false, "Record the detecting file's last commit and size (uses the slower file API instead of raw fetches)")
+	fs.StringVar(&approvedVersions, "approved-versions", "", "Comma-separated versions approved by policy, e.g. 3.11,3.12 (compared at major.minor)")
+	fs.StringVar(&config.AtRiskBelow, "at-risk-below", "", "Policy floor, e.g. 3.10; the summary leads with the Python projects on an older version, listing them apart from current ones")
+	fs.StringVar(&config.TargetVersion, "target-version", "", "Upgrade target, e.g. 3.12; the summary counts projects whose requires-python upper bound excludes it")
+	fs.BoolVar(&config.Verbose, "verbose", false, "Print the raw text each detected version was parsed from, e.g. >=3.10,<4.0")
+	fs.BoolVar(&config.RequireExplicit, "require-explicit", false, "List Python projects with no explicit version file (.python-version, runtime.txt) in the summary")
+	fs.BoolVar(&config.OrgSummary, "org-summary", false, "Lead the summary with a compliance score: the percentage of Python projects on a version that hasn't reached end of life")
+	fs.BoolVar(&config.OnlyNonApproved, "only-non-approved", false, "Stream only projects on a detected version outside --approved-versions; undetected and failed projects are left out too")
+	fs.BoolVar(&config.OnlyPython2, "only-python2", false, "Stream only projects detected on Python 2")
+	fs.StringVar(&config.Incremental, "incremental", "", "JSON log of a previous scan (usually the --log being written); projects whose commit is unchanged since reuse their logged result instead of being rescanned")
+	fs.StringVar(&config.BaselinePath, "baseline", "", "YAML file of expected versions (path: version); stream only projects that drift from it or aren't in it, and summarize conformance")
+	fs.StringVar(&config.Normalize, "normalize", "", "Bucket the version distribution by \"minor\" (3.11) or \"major\" (3); results keep the raw version")
+	fs.BoolVar(&config.ListVersions, "list-versions", false, "Print only the distinct detected versions, oldest first, one per line, instead of per-project results and the summary")
+	fs.BoolVar(&config.WithCounts, "with-counts", false, "With --list-versions, follow each version with its project count")
+	fs.BoolVar(&config.IncludeUndetected, "include-undetected", false, "With --list-versions, add an \"undetected\" line for projects with no version")
+	fs.BoolVar(&config.SummaryLine, "summary-line", false, "Print a final machine-readable line: SUMMARY total=N python=N undetected=N errors=N")
+	fs.Var(&plausibleMajors, "plausible-majors", "Comma-separated major versions a detection may have; others are discarded as false positives")
+	fs.IntVar(&config.MaxPlausibleMinor, "max-plausible-minor", output.DefaultVersionBounds.MaxMinor, "Largest minor version a detection may have; larger ones are discarded as false positives (0 = no limit)")
+	fs.IntVar(&config.SubgroupDepth, "subgroup-depth", -1, "Only include projects at most N subgroup levels below the --url group (0 = direct projects only, -1 = unlimited)")
+	fs.BoolVar(&config.ExcludeForks, "exclude-forks", false, "Skip projects forked from another project")
+	fs.BoolVar(&config.ForksOnly, "forks-only", false, "Only include projects forked from another project")
+	fs.StringVar(&config.Topic, "topic", "", "Only include projects tagged with this topic, e.g. python-service (filtered by GitLab, so other projects aren't listed)")
+	fs.StringVar(&config.Commit, "commit", "", "Scan the group's only project at this commit SHA, for reproducing an audit after its branch has moved (pin several projects with project_refs in --config)")
+	fs.BoolVar(&config.AtLatestTag, "at-latest-tag", false, "Scan each project's most recently updated tag instead of its default branch; detection sources are suffixed with @tag")
+	fs.DurationVar(&config.DecayHalfLife, "decay-confidence", 0, "Halve a detection's confidence for every half-life (e.g. 8760h for a year) since its file was last committed; records the raw confidence too (costs up to two extra requests per detection)")
+	fs.BoolVar(&config.Strict, "strict", false, "Record candidate files whose rule parser returned an error (not merely no version) as parse errors on the result and in the summary")
+	fs.StringVar(&config.PostHook, "post-hook", "", "Shell command to run after the scan's outputs are written, with the JSON report on stdin and SEEKER_STATUS, SEEKER_TOTAL, SEEKER_PYTHON, etc. in its environment")
+	fs.DurationVar(&config.PostHookTimeout, "post-hook-timeout", time.Minute, "Kill --post-hook if it runs longer than this")
+	fs.StringVar(&config.FailOn, "fail-on", "", "Exit non-zero when the scan hits this condition: \"parse-errors\" (requires --strict); with --mode mr, \"version-change\" or \"match\" (requires --search)")
+	fs.IntVar(&config.MaxCandidates, "max-candidates", 0, "Fetch at most N candidate files per project, highest-priority rules first; projects that hit the limit are reported (0 = no limit)")
+	fs.Var(&subdirs, "subdir", "Also probe for version files under this directory, e.g. services/api (repeatable)")
+	fs.Var(&ignorePaths, "ignore-path", "Never read candidate files matching this glob (** matches any directories), in addition to **/vendor/**, **/node_modules/**, **/.tox/**, and **/site-packages/** (repeatable)")
+	fs.Var(&onlyPaths, "only-path", "Only read candidate files matching this glob (** matches any directories), e.g. services/** in a monorepo; --ignore-path still applies (repeatable)")
+	fs.BoolVar(&config.IgnoreFile, "ignore-file", false, "Read each project's .gitlab-seeker-ignore (globs of candidate files to skip, one per line); costs one request per project")
+	fs.BoolVar(&config.Explain, "explain", false, "Print and log each project's decision trace: every candidate file checked, what its rule returned, and why the reported version won")
+	fs.BoolVar(&config.Authoritative, "authoritative", false, "Report the first detection in the highest confidence tier found (explicit, then inferred, then weak) instead of the first in priority order; probes every candidate file unless an explicit one is found")
+	fs.Float64Var(&config.ExplicitConfidence, "explicit-confidence", output.DefaultConfidenceTiers.Explicit, "Lowest confidence in the explicit tier for --authoritative")
+	fs.Float64Var(&config.InferredConfidence, "inferred-confidence", output.DefaultConfidenceTiers.Inferred, "Lowest confidence in the inferred tier for --authoritative; lower detections are only used when no other tier has one")
+	fs.BoolVar(&config.Bucketed, "bucketed", false, "On a terminal, group results into detected, undetected, and error lists with running counts, updated in place as results arrive (a flat stream otherwise)")
+	fs.BoolVar(&config.Benchmark, "benchmark", false, "Report throughput at the end of the scan: projects and files fetched per second, bytes downloaded, retry rate, and p50/p95 per-project latency")
+	fs.Var(&disabledTags, "disable-tag", "Disable built-in detection rules with this tag (repeatable, e.g., --disable-tag provisioning)")
 
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
@@ -505,19 +1730,149 @@ func parseSearchFlags(args []string) *SearchConfig {
 		fmt.Fprintf(os.Stderr, "  %s --url gitlab.com/myorg --token abc123 --search \"API_KEY\"\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --url gitlab.com/myorg --search \"password\\s*=\" --regex --file \"*.py\"\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --url gitlab.com/myorg --config content-search.yaml\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --url gitlab.com/myorg --config rules-and-searches.yaml --mode both\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --url gitlab.com/myorg --match-files-only --file \"*.env\"\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --url gitlab.com/myorg --search \"USER root\" --in-file Dockerfile\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s parse pyproject.toml   (test rules against a local file)\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s browse results.json    (browse a scan's JSON log interactively)\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s tui results.json       (explore it full-screen; built with -tags tui)\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s describe-rules --format json   (manifest of the built-in rules)\n", os.Args[0])
 	}
 
 	fs.Parse(args)
 	config.FilePatterns = filePatterns
+	config.InFiles = inFiles
+	config.LogFiles = logFiles
+	config.DisabledTags = disabledTags
+	config.Subdirs = subdirs
+	config.IgnorePaths = ignorePaths
+	config.OnlyPaths = onlyPaths
+	config.PlausibleMajors = plausibleMajors
+	config.ApprovedVersions = output.ParseApprovedVersions(approvedVersions)
 	return config
 }
 
 func validateConfig(config *Config) error {
-	if config.GitLabURL == "" {
-		return fmt.Errorf("--url is required")
+	if len(config.Instances) > 0 {
+		if config.GitLabURL != "" {
+			return fmt.Errorf("--url can't be combined with instances in --config, which name the instances to scan")
+		}
+	} else {
+		if config.GitLabURL == "" {
+			return fmt.Errorf("--url is required")
+		}
+		if config.Token == "" {
+			return fmt.Errorf("--token is required (or set GITLAB_TOKEN environment variable)")
+		}
 	}
-	if config.Token == "" {
-		return fmt.Errorf("--token is required (or set GITLAB_TOKEN environment variable)")
+	if _, err := output.ParseNormalization(config.Normalize); err != nil {
+		return fmt.Errorf("--normalize: %w", err)
+	}
+	if config.OnlyNonApproved && len(config.ApprovedVersions) == 0 {
+		return fmt.Errorf("--only-non-approved requires --approved-versions")
+	}
+	if config.TargetVersion != "" {
+		if _, err := output.ParseVersion(config.TargetVersion); err != nil {
+			return fmt.Errorf("--target-version: %w", err)
+		}
+	}
+	if config.AtRiskBelow != "" {
+		if _, err := output.ParseVersion(config.AtRiskBelow); err != nil {
+			return fmt.Errorf("--at-risk-below: %w", err)
+		}
+	}
+	if config.Commit != "" {
+		if !commitSHAPattern.MatchString(config.Commit) {
+			return fmt.Errorf("--commit must be a commit SHA (7 to 64 hex digits), got %q", config.Commit)
+		}
+		if config.AtLatestTag {
+			return fmt.Errorf("--commit and --at-latest-tag are mutually exclusive")
+		}
+	}
+	if config.SubgroupDepth < -1 {
+		return fmt.Errorf("--subgroup-depth must be -1 (unlimited) or greater")
+	}
+	if config.ExcludeForks && config.ForksOnly {
+		return fmt.Errorf("--exclude-forks and --forks-only are mutually exclusive")
+	}
+	if err := validatePathGlobs("--ignore-path", config.IgnorePaths); err != nil {
+		return err
+	}
+	if err := validatePathGlobs("--only-path", config.OnlyPaths); err != nil {
+		return err
+	}
+	if config.ListConcurrency < 0 {
+		return fmt.Errorf("--list-concurrency must not be negative")
+	}
+	if config.LogMisses && config.TracePath == "" {
+		return fmt.Errorf("--log-misses requires --trace")
+	}
+	if config.PerPage < 0 || config.PerPage > 100 {
+		return fmt.Errorf("--per-page must be between 0 (GitLab's default) and 100")
+	}
+	if config.BreakerThreshold < 0 {
+		return fmt.Errorf("--breaker-threshold must be 0 (disabled) or greater")
+	}
+	if config.BreakerCooldown < 0 {
+		return fmt.Errorf("--breaker-cooldown must not be negative")
+	}
+	if config.MaxAPICalls < 0 {
+		return fmt.Errorf("--max-api-calls must be 0 (unlimited) or greater")
+	}
+	if config.RampUp < 0 {
+		return fmt.Errorf("--ramp-up must not be negative")
+	}
+	if _, _, err := parseTLSFlags(config.TLSMinVersion, config.TLSCiphers); err != nil {
+		return err
+	}
+	if config.MaxPlausibleMinor < 0 {
+		return fmt.Errorf("--max-plausible-minor must be 0 or greater")
+	}
+	if config.MaxCandidates < 0 {
+		return fmt.Errorf("--max-candidates must be 0 (no limit) or greater")
+	}
+	if config.DecayHalfLife < 0 {
+		return fmt.Errorf("--decay-confidence must not be negative")
+	}
+	if config.Authoritative {
+		if config.ExplicitConfidence <= 0 || config.ExplicitConfidence > 1 {
+			return fmt.Errorf("--explicit-confidence must be greater than 0 and at most 1")
+		}
+		if config.InferredConfidence < 0 || config.InferredConfidence > config.ExplicitConfidence {
+			return fmt.Errorf("--inferred-confidence must be between 0 and --explicit-confidence")
+		}
+	}
+	if config.PostHook != "" && config.PostHookTimeout <= 0 {
+		return fmt.Errorf("--post-hook-timeout must be positive")
+	}
+	if config.Incremental != "" && config.DepReportPath != "" {
+		return fmt.Errorf("--dep-report can't be combined with --incremental (reused results have no dependency data)")
+	}
+	if config.FailOn != "" {
+		if config.FailOn != failOnParseErrors {
+			return fmt.Errorf("--fail-on must be %q, got %q", failOnParseErrors, config.FailOn)
+		}
+		if !config.Strict {
+			return fmt.Errorf("--fail-on %s requires --strict", failOnParseErrors)
+		}
+	}
+	if config.BaselinePath != "" {
+		if _, err := output.LoadBaseline(config.BaselinePath); err != nil {
+			return fmt.Errorf("--baseline: %w", err)
+		}
+	}
+	if err := validateListVersions(config.ListVersions, config.WithCounts, config.IncludeUndetected, config.SummaryLine); err != nil {
+		return err
+	}
+	if config.ListVersions && config.Watch != 0 {
+		return fmt.Errorf("--list-versions can't be combined with --watch")
+	}
+	if config.ListVersions && config.Benchmark {
+		return fmt.Errorf("--list-versions can't be combined with --benchmark")
+	}
+	// Shorter intervals would re-list the whole group back to back
+	if config.Watch != 0 && config.Watch < minWatchInterval {
+		return fmt.Errorf("--watch must be at least %v, got %v", minWatchInterval, config.Watch)
 	}
 	return nil
 }
@@ -529,6 +1884,160 @@ func validateSearchConfig(config *SearchConfig) error {
 	if config.Token == "" {
 		return fmt.Errorf("--token is required (or set GITLAB_TOKEN environment variable)")
 	}
+	if config.SQLitePath != "" {
+		return fmt.Errorf("--sqlite is only supported when scanning for Python versions")
+	}
+	if config.DepReportPath != "" {
+		return fmt.Errorf("--dep-report is only supported when scanning for Python versions")
+	}
+	if config.Watch != 0 {
+		return fmt.Errorf("--watch is only supported when scanning for Python versions")
+	}
+	if config.AtLatestTag {
+		return fmt.Errorf("--at-latest-tag is only supported when scanning for Python versions")
+	}
+	if config.Commit != "" {
+		return fmt.Errorf("--commit is only supported when scanning for Python versions")
+	}
+	if config.MaxCandidates != 0 {
+		return fmt.Errorf("--max-candidates is only supported when scanning for Python versions")
+	}
+	if config.OnlyPython2 {
+		return fmt.Errorf("--only-python2 is only supported when scanning for Python versions")
+	}
+	if config.BaselinePath != "" {
+		return fmt.Errorf("--baseline is only supported when scanning for Python versions")
+	}
+	if config.ListVersions || config.WithCounts || config.IncludeUndetected {
+		return fmt.Errorf("--list-versions is only supported when scanning for Python versions")
+	}
+	if config.DecayHalfLife != 0 {
+		return fmt.Errorf("--decay-confidence is only supported when scanning for Python versions")
+	}
+	if config.Strict || config.FailOn != "" {
+		return fmt.Errorf("--strict is only supported when scanning for Python versions")
+	}
+	if config.RequireExplicit {
+		return fmt.Errorf("--require-explicit is only supported when scanning for Python versions")
+	}
+	if config.OrgSummary {
+		return fmt.Errorf("--org-summary is only supported when scanning for Python versions")
+	}
+	if config.Incremental != "" {
+		return fmt.Errorf("--incremental is only supported when scanning for Python versions")
+	}
+	if config.Verbose {
+		return fmt.Errorf("--verbose is only supported when scanning for Python versions")
+	}
+	if len(config.IgnorePaths) > 0 {
+		return fmt.Errorf("--ignore-path is only supported when scanning for Python versions")
+	}
+	if len(config.OnlyPaths) > 0 {
+		return fmt.Errorf("--only-path is only supported when scanning for Python versions")
+	}
+	if config.IgnoreFile {
+		return fmt.Errorf("--ignore-file is only supported when scanning for Python versions")
+	}
+	if config.Explain {
+		return fmt.Errorf("--explain is only supported when scanning for Python versions")
+	}
+	if config.Authoritative {
+		return fmt.Errorf("--authoritative is only supported when scanning for Python versions")
+	}
+	if config.Benchmark {
+		return fmt.Errorf("--benchmark is only supported when scanning for Python versions")
+	}
+	if config.Bucketed {
+		return fmt.Errorf("--bucketed is only supported when scanning for Python versions")
+	}
+	if config.PostHook != "" {
+		return fmt.Errorf("--post-hook is only supported when scanning for Python versions")
+	}
+	if config.AtRiskBelow != "" {
+		return fmt.Errorf("--at-risk-below is only supported when scanning for Python versions")
+	}
+	if config.TargetVersion != "" {
+		return fmt.Errorf("--target-version is only supported when scanning for Python versions")
+	}
+	// The bounds flags default to output.DefaultVersionBounds; zero values
+	// are a config that never set them
+	if len(config.PlausibleMajors) > 0 && !slices.Equal(config.PlausibleMajors, output.DefaultVersionBounds.Majors) {
+		return fmt.Errorf("--plausible-majors is only supported when scanning for Python versions")
+	}
+	if config.MaxPlausibleMinor != 0 && config.MaxPlausibleMinor != output.DefaultVersionBounds.MaxMinor {
+		return fmt.Errorf("--max-plausible-minor is only supported when scanning for Python versions")
+	}
+	if config.SubgroupDepth < -1 {
+		return fmt.Errorf("--subgroup-depth must be -1 (unlimited) or greater")
+	}
+	if config.ExcludeForks && config.ForksOnly {
+		return fmt.Errorf("--exclude-forks and --forks-only are mutually exclusive")
+	}
+	if err := validatePathGlobs("--ignore-path", config.IgnorePaths); err != nil {
+		return err
+	}
+	if err := validatePathGlobs("--only-path", config.OnlyPaths); err != nil {
+		return err
+	}
+	if config.ListConcurrency < 0 {
+		return fmt.Errorf("--list-concurrency must not be negative")
+	}
+	if config.LogMisses && config.TracePath == "" {
+		return fmt.Errorf("--log-misses requires --trace")
+	}
+	if config.PerPage < 0 || config.PerPage > 100 {
+		return fmt.Errorf("--per-page must be between 0 (GitLab's default) and 100")
+	}
+	if config.BreakerThreshold < 0 {
+		return fmt.Errorf("--breaker-threshold must be 0 (disabled) or greater")
+	}
+	if config.BreakerCooldown < 0 {
+		return fmt.Errorf("--breaker-cooldown must not be negative")
+	}
+	if config.MaxAPICalls < 0 {
+		return fmt.Errorf("--max-api-calls must be 0 (unlimited) or greater")
+	}
+	if config.RampUp < 0 {
+		return fmt.Errorf("--ramp-up must not be negative")
+	}
+	if _, _, err := parseTLSFlags(config.TLSMinVersion, config.TLSCiphers); err != nil {
+		return err
+	}
+	if config.MaxFileSize < 0 {
+		return fmt.Errorf("--max-file-size must be 0 or greater")
+	}
+	if config.MetaPrefilter && config.MatchFilesOnly {
+		return fmt.Errorf("--metadata-prefilter has no effect with --match-files-only, which fetches no files")
+	}
+	if config.Redact && config.MatchFilesOnly {
+		return fmt.Errorf("--redact has no effect with --match-files-only, which matches only file paths")
+	}
+	if config.SearchWikis && config.MatchFilesOnly {
+		return fmt.Errorf("--search-wikis searches page contents and can't be combined with --match-files-only")
+	}
+	if config.MetaPrefilter && config.SearchTerm != "" && !config.IsRegex && len(config.InFiles) == 0 {
+		return fmt.Errorf("--metadata-prefilter requires --regex or --in-file (literal searches use the search API and fetch no files)")
+	}
+	if len(config.InFiles) > 0 {
+		if config.MatchFilesOnly {
+			return fmt.Errorf("--in-file searches file contents and can't be combined with --match-files-only")
+		}
+		if len(config.FilePatterns) > 0 {
+			return fmt.Errorf("--in-file names exact paths and can't be combined with --file")
+		}
+	}
+	if config.SearchDefaults && config.ConfigFile == "" {
+		return fmt.Errorf("--config-search-defaults requires --config")
+	}
+	if config.Prefilter != "" && !config.IsRegex {
+		return fmt.Errorf("--prefilter requires --regex (literal searches already prefilter on the term)")
+	}
+	if config.MatchFilesOnly {
+		if config.SearchTerm == "" && len(config.FilePatterns) == 0 {
+			return fmt.Errorf("--match-files-only requires --search or --file")
+		}
+		return nil
+	}
 	if config.SearchTerm == "" && config.ConfigFile == "" {
 		return fmt.Errorf("--search or --config is required")
 	}
@@ -1,9 +1,25 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/config"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/parsers"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/scanner"
 )
 
 func TestValidateConfig(t *testing.T) {
@@ -18,29 +34,282 @@ func TestValidateConfig(t *testing.T) {
 			config: &Config{
 				GitLabURL:   "gitlab.com/myorg",
 				Token:       "test-token",
-				LogFile:     "",
+				LogFiles:    nil,
 				Concurrency: 5,
 				Timeout:     30,
 			},
 			wantErr: false,
 		},
+		{
+			name: "Valid config with TLS requirements",
+			config: &Config{
+				GitLabURL:     "gitlab.com/myorg",
+				Token:         "test-token",
+				Concurrency:   5,
+				Timeout:       30,
+				TLSMinVersion: "1.2",
+				TLSCiphers:    "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Ciphers with TLS 1.3 minimum",
+			config: &Config{
+				GitLabURL:     "gitlab.com/myorg",
+				Token:         "test-token",
+				Concurrency:   5,
+				Timeout:       30,
+				TLSMinVersion: "1.3",
+				TLSCiphers:    "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+			},
+			wantErr: true,
+			errMsg:  "--tls-ciphers has no effect with --tls-min-version 1.3, whose cipher suites aren't configurable",
+		},
 		{
 			name: "Valid config with log file",
 			config: &Config{
 				GitLabURL:   "gitlab.com/myorg",
 				Token:       "test-token",
-				LogFile:     "results.log",
+				LogFiles:    []string{"results.log"},
 				Concurrency: 10,
 				Timeout:     60,
 			},
 			wantErr: false,
 		},
+		{
+			name: "Valid config with minor normalization",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Concurrency: 5,
+				Timeout:     30,
+				Normalize:   "minor",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Valid config with instances instead of a URL",
+			config: &Config{
+				Instances:   []config.InstanceConfig{{Name: "saas", URL: "gitlab.com", TokenEnv: "GITLAB_COM_TOKEN"}},
+				Concurrency: 5,
+				Timeout:     30,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Instances with a URL",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Instances:   []config.InstanceConfig{{Name: "saas", URL: "gitlab.com", TokenEnv: "GITLAB_COM_TOKEN"}},
+				Concurrency: 5,
+				Timeout:     30,
+			},
+			wantErr: true,
+			errMsg:  "--url can't be combined with instances in --config, which name the instances to scan",
+		},
+		{
+			name: "Invalid normalization",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Concurrency: 5,
+				Timeout:     30,
+				Normalize:   "patch",
+			},
+			wantErr: true,
+			errMsg:  "--normalize: invalid normalization \"patch\" (expected \"minor\" or \"major\")",
+		},
+		{
+			name: "Only non-approved without approved versions",
+			config: &Config{
+				GitLabURL:       "gitlab.com/myorg",
+				Token:           "test-token",
+				Concurrency:     5,
+				Timeout:         30,
+				OnlyNonApproved: true,
+			},
+			wantErr: true,
+			errMsg:  "--only-non-approved requires --approved-versions",
+		},
+		{
+			name: "Invalid target version",
+			config: &Config{
+				GitLabURL:     "gitlab.com/myorg",
+				Token:         "test-token",
+				Concurrency:   5,
+				Timeout:       30,
+				TargetVersion: "3.x",
+			},
+			wantErr: true,
+			errMsg:  "--target-version: invalid version \"3.x\"",
+		},
+		{
+			name: "Commit that isn't a SHA",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Concurrency: 5,
+				Timeout:     30,
+				Commit:      "main",
+			},
+			wantErr: true,
+			errMsg:  "--commit must be a commit SHA (7 to 64 hex digits), got \"main\"",
+		},
+		{
+			name: "Commit with latest tag",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Concurrency: 5,
+				Timeout:     30,
+				Commit:      "4f2c9e1",
+				AtLatestTag: true,
+			},
+			wantErr: true,
+			errMsg:  "--commit and --at-latest-tag are mutually exclusive",
+		},
+		{
+			name: "Invalid at-risk floor",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Concurrency: 5,
+				Timeout:     30,
+				AtRiskBelow: "three",
+			},
+			wantErr: true,
+			errMsg:  "--at-risk-below: invalid version \"three\"",
+		},
+		{
+			name: "Authoritative with default tiers",
+			config: &Config{
+				GitLabURL:          "gitlab.com/myorg",
+				Token:              "test-token",
+				Concurrency:        5,
+				Timeout:            30,
+				Authoritative:      true,
+				ExplicitConfidence: 0.9,
+				InferredConfidence: 0.6,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Authoritative with inverted tiers",
+			config: &Config{
+				GitLabURL:          "gitlab.com/myorg",
+				Token:              "test-token",
+				Concurrency:        5,
+				Timeout:            30,
+				Authoritative:      true,
+				ExplicitConfidence: 0.5,
+				InferredConfidence: 0.7,
+			},
+			wantErr: true,
+			errMsg:  "--inferred-confidence must be between 0 and --explicit-confidence",
+		},
+		{
+			name: "Valid watch interval",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Concurrency: 5,
+				Timeout:     30,
+				Watch:       15 * time.Minute,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Watch interval too short",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Concurrency: 5,
+				Timeout:     30,
+				Watch:       10 * time.Second,
+			},
+			wantErr: true,
+			errMsg:  "--watch must be at least 1m0s, got 10s",
+		},
+		{
+			name: "Fail on parse errors without strict",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Concurrency: 5,
+				Timeout:     30,
+				FailOn:      "parse-errors",
+			},
+			wantErr: true,
+			errMsg:  "--fail-on parse-errors requires --strict",
+		},
+		{
+			name: "Exclude forks with forks only",
+			config: &Config{
+				GitLabURL:    "gitlab.com/myorg",
+				Token:        "test-token",
+				Concurrency:  5,
+				Timeout:      30,
+				ExcludeForks: true,
+				ForksOnly:    true,
+			},
+			wantErr: true,
+			errMsg:  "--exclude-forks and --forks-only are mutually exclusive",
+		},
+		{
+			name: "Post hook without timeout",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Concurrency: 5,
+				Timeout:     30,
+				PostHook:    "true",
+			},
+			wantErr: true,
+			errMsg:  "--post-hook-timeout must be positive",
+		},
+		{
+			name: "Log misses without trace",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Concurrency: 5,
+				Timeout:     30,
+				LogMisses:   true,
+			},
+			wantErr: true,
+			errMsg:  "--log-misses requires --trace",
+		},
+		{
+			name: "Malformed ignore path",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Concurrency: 5,
+				Timeout:     30,
+				IgnorePaths: []string{"**/[gen/**"},
+			},
+			wantErr: true,
+			errMsg:  `--ignore-path "**/[gen/**": syntax error in pattern`,
+		},
+		{
+			name: "Malformed only path",
+			config: &Config{
+				GitLabURL:   "gitlab.com/myorg",
+				Token:       "test-token",
+				Concurrency: 5,
+				Timeout:     30,
+				OnlyPaths:   []string{"services/[api"},
+			},
+			wantErr: true,
+			errMsg:  `--only-path "services/[api": syntax error in pattern`,
+		},
 		{
 			name: "Missing GitLab URL",
 			config: &Config{
 				GitLabURL:   "",
 				Token:       "test-token",
-				LogFile:     "",
+				LogFiles:    nil,
 				Concurrency: 5,
 				Timeout:     30,
 			},
@@ -52,7 +321,7 @@ func TestValidateConfig(t *testing.T) {
 			config: &Config{
 				GitLabURL:   "gitlab.com/myorg",
 				Token:       "",
-				LogFile:     "",
+				LogFiles:    nil,
 				Concurrency: 5,
 				Timeout:     30,
 			},
@@ -64,7 +333,7 @@ func TestValidateConfig(t *testing.T) {
 			config: &Config{
 				GitLabURL:   "",
 				Token:       "",
-				LogFile:     "",
+				LogFiles:    nil,
 				Concurrency: 5,
 				Timeout:     30,
 			},
@@ -138,6 +407,16 @@ func TestParseFlags(t *testing.T) {
 			wantConc:    5,
 			wantTimeout: 30,
 		},
+		{
+			name:        "Repeated log flag",
+			args:        []string{"cmd", "--url", "gitlab.com/myorg", "--token", "abc123", "--log", "results.json", "--log", "results.csv"},
+			envToken:    "",
+			wantURL:     "gitlab.com/myorg",
+			wantToken:   "abc123",
+			wantLog:     "results.json,results.csv",
+			wantConc:    5,
+			wantTimeout: 30,
+		},
 		{
 			name:        "Custom concurrency and timeout",
 			args:        []string{"cmd", "--url", "gitlab.example.com/eng", "--token", "token123", "--concurrency", "20", "--timeout", "120"},
@@ -176,8 +455,8 @@ func TestParseFlags(t *testing.T) {
 			if config.Token != tt.wantToken {
 				t.Errorf("Token = %v, want %v", config.Token, tt.wantToken)
 			}
-			if config.LogFile != tt.wantLog {
-				t.Errorf("LogFile = %v, want %v", config.LogFile, tt.wantLog)
+			if strings.Join(config.LogFiles, ",") != tt.wantLog {
+				t.Errorf("LogFiles = %v, want %v", config.LogFiles, tt.wantLog)
 			}
 			if config.Concurrency != tt.wantConc {
 				t.Errorf("Concurrency = %v, want %v", config.Concurrency, tt.wantConc)
@@ -194,7 +473,7 @@ func TestConfigStruct(t *testing.T) {
 	config := &Config{
 		GitLabURL:   "gitlab.com/test",
 		Token:       "test-token",
-		LogFile:     "output.log",
+		LogFiles:    []string{"output.log"},
 		Concurrency: 8,
 		Timeout:     45,
 	}
@@ -205,8 +484,8 @@ func TestConfigStruct(t *testing.T) {
 	if config.Token != "test-token" {
 		t.Errorf("Token = %v, want test-token", config.Token)
 	}
-	if config.LogFile != "output.log" {
-		t.Errorf("LogFile = %v, want output.log", config.LogFile)
+	if len(config.LogFiles) != 1 || config.LogFiles[0] != "output.log" {
+		t.Errorf("LogFiles = %v, want [output.log]", config.LogFiles)
 	}
 	if config.Concurrency != 8 {
 		t.Errorf("Concurrency = %v, want 8", config.Concurrency)
@@ -297,6 +576,18 @@ func TestParseSearchFlags(t *testing.T) {
 	}
 }
 
+func TestParseSearchFlagsApprovedVersions(t *testing.T) {
+	config := parseSearchFlags([]string{"--url", "gitlab.com/org", "--approved-versions", "3.11, 3.12,", "--only-non-approved"})
+
+	want := []string{"3.11", "3.12"}
+	if strings.Join(config.ApprovedVersions, ",") != strings.Join(want, ",") {
+		t.Errorf("ApprovedVersions = %v, want %v", config.ApprovedVersions, want)
+	}
+	if !config.OnlyNonApproved {
+		t.Error("OnlyNonApproved = false, want true")
+	}
+}
+
 func TestValidateSearchConfig(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -324,18 +615,1608 @@ func TestValidateSearchConfig(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "missing search and config",
-			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok"},
+			name:    "prefilter with regex",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: `pass\w+`, IsRegex: true, Prefilter: "pass"},
+			wantErr: false,
+		},
+		{
+			name:    "prefilter without regex",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "pass", Prefilter: "pass"},
 			wantErr: true,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := validateSearchConfig(tt.config)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("validateSearchConfig() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
+		{
+			name:    "post hook in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", PostHook: "true"},
+			wantErr: true,
+		},
+		{
+			name:    "org summary in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", OrgSummary: true},
+			wantErr: true,
+		},
+		{
+			name:    "incremental in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", Incremental: "scan.json"},
+			wantErr: true,
+		},
+		{
+			name:    "ignore file in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", IgnoreFile: true},
+			wantErr: true,
+		},
+		{
+			name:    "explain in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", Explain: true},
+			wantErr: true,
+		},
+		{
+			name:    "authoritative in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", Authoritative: true},
+			wantErr: true,
+		},
+		{
+			name:    "benchmark in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", Benchmark: true},
+			wantErr: true,
+		},
+		{
+			name:    "bucketed in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", Bucketed: true},
+			wantErr: true,
+		},
+		{
+			name:    "ignore path in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", IgnorePaths: []string{"third_party/**"}},
+			wantErr: true,
+		},
+		{
+			name:    "only path in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", OnlyPaths: []string{"services/**"}},
+			wantErr: true,
+		},
+		{
+			name:    "sqlite in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", SQLitePath: "scan.db"},
+			wantErr: true,
+		},
+		{
+			name:    "metadata prefilter with regex",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: `pass\w+`, IsRegex: true, MetaPrefilter: true, MaxFileSize: 4096},
+			wantErr: false,
+		},
+		{
+			name:    "metadata prefilter with literal search",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "pass", MetaPrefilter: true},
+			wantErr: true,
+		},
+		{
+			name:    "metadata prefilter with match files only",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", MatchFilesOnly: true, FilePatterns: []string{"*.env"}, MetaPrefilter: true},
+			wantErr: true,
+		},
+		{
+			name:    "redact",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "AKIA", Redact: true},
+			wantErr: false,
+		},
+		{
+			name:    "redact with match files only",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", MatchFilesOnly: true, FilePatterns: []string{"*.env"}, Redact: true},
+			wantErr: true,
+		},
+		{
+			name:    "search wikis with match files only",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", MatchFilesOnly: true, FilePatterns: []string{"*.env"}, SearchWikis: true},
+			wantErr: true,
+		},
+		{
+			name:    "negative max file size",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "pass", MaxFileSize: -1},
+			wantErr: true,
+		},
+		{
+			name:    "negative ramp up",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "pass", RampUp: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "unknown TLS version",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "pass", TLSMinVersion: "1.4"},
+			wantErr: true,
+		},
+		{
+			name:    "at-risk floor in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "pass", AtRiskBelow: "3.10"},
+			wantErr: true,
+		},
+		{
+			name:    "target version in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "pass", TargetVersion: "3.12"},
+			wantErr: true,
+		},
+		{
+			name:    "plausible majors in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "pass", PlausibleMajors: []int{3}},
+			wantErr: true,
+		},
+		{
+			name:    "default plausible bounds in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "pass", PlausibleMajors: []int{2, 3}, MaxPlausibleMinor: 30},
+			wantErr: false,
+		},
+		{
+			name:    "max plausible minor in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "pass", MaxPlausibleMinor: 20},
+			wantErr: true,
+		},
+		{
+			name:    "config search defaults without config",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", SearchDefaults: true},
+			wantErr: true,
+		},
+		{
+			name:    "dep report in search mode",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "test", DepReportPath: "deps.json"},
+			wantErr: true,
+		},
+		{
+			name:    "in file with literal search",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "USER root", InFiles: []string{"Dockerfile"}, MetaPrefilter: true},
+			wantErr: false,
+		},
+		{
+			name:    "in file with file pattern",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "USER root", InFiles: []string{"Dockerfile"}, FilePatterns: []string{"*.py"}},
+			wantErr: true,
+		},
+		{
+			name:    "in file with match files only",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", SearchTerm: "Dockerfile", InFiles: []string{"Dockerfile"}, MatchFilesOnly: true},
+			wantErr: true,
+		},
+		{
+			name:    "match files only with file pattern",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", MatchFilesOnly: true, FilePatterns: []string{"*.env"}},
+			wantErr: false,
+		},
+		{
+			name:    "match files only without pattern or term",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok", MatchFilesOnly: true},
+			wantErr: true,
+		},
+		{
+			name:    "missing search and config",
+			config:  &SearchConfig{GitLabURL: "gitlab.com/org", Token: "tok"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSearchConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSearchConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseLocalFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pyproject.toml")
+	content := "[project]\nname = \"demo\"\nrequires-python = \">=3.11\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	registry, err := loadParseRegistry("")
+	if err != nil {
+		t.Fatalf("loadParseRegistry() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := parseLocalFile(&buf, registry, path); err != nil {
+		t.Fatalf("parseLocalFile() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"Matching rules: 1", "pyproject-toml (priority 10): Python 3.11", "Best match: Python 3.11"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestParseLocalFileNoMatchingRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "CHANGELOG.md")
+	if err := os.WriteFile(path, []byte("# hello"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := parseLocalFile(&buf, parsers.DefaultRegistry(), path); err != nil {
+		t.Fatalf("parseLocalFile() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "No enabled rules match") {
+		t.Errorf("expected no-match message, got:\n%s", buf.String())
+	}
+}
+
+func TestParseParseFlags(t *testing.T) {
+	config, err := parseParseFlags([]string{"--config", "rules.yaml", "Dockerfile"})
+	if err != nil {
+		t.Fatalf("parseParseFlags() error = %v", err)
+	}
+	if config.ConfigFile != "rules.yaml" {
+		t.Errorf("ConfigFile = %q, want rules.yaml", config.ConfigFile)
+	}
+	if config.FilePath != "Dockerfile" {
+		t.Errorf("FilePath = %q, want Dockerfile", config.FilePath)
+	}
+}
+
+func TestCheckFailOn(t *testing.T) {
+	stats := output.NewScanStatistics()
+	stats.RecordResult(&output.ScanResult{ProjectName: "service", ParseErrors: []string{"broken.cfg: parser error"}})
+	if err := checkFailOn("", stats); err != nil {
+		t.Errorf("checkFailOn() without --fail-on = %v, want nil", err)
+	}
+	if err := checkFailOn(failOnParseErrors, stats); err == nil {
+		t.Error("checkFailOn() = nil, want an error for the parse error")
+	}
+}
+
+func TestNewRunID(t *testing.T) {
+	started := time.Date(2024, 2, 6, 10, 30, 0, 0, time.UTC)
+
+	if got := newRunID(started, 3); got != "20240206T103000Z-3" {
+		t.Errorf("newRunID() = %q, want 20240206T103000Z-3", got)
+	}
+	if newRunID(started, 1) == newRunID(started, 2) {
+		t.Error("newRunID() returned the same ID for different runs")
+	}
+}
+
+func TestParseSearchFlagsPlausibleBounds(t *testing.T) {
+	config := parseSearchFlags([]string{"--url", "gitlab.com/org"})
+	if fmt.Sprint(config.PlausibleMajors) != "[2 3]" || config.MaxPlausibleMinor != 30 {
+		t.Errorf("defaults = %v / %d, want [2 3] / 30", config.PlausibleMajors, config.MaxPlausibleMinor)
+	}
+
+	config = parseSearchFlags([]string{"--plausible-majors", "3", "--max-plausible-minor", "20"})
+	if fmt.Sprint(config.PlausibleMajors) != "[3]" || config.MaxPlausibleMinor != 20 {
+		t.Errorf("overrides = %v / %d, want [3] / 20", config.PlausibleMajors, config.MaxPlausibleMinor)
+	}
+}
+
+func TestLoadSearchesFromConfigDefaults(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "searches.yaml")
+	content := `searches:
+  - name: inherits
+    search_term: TODO
+  - name: overrides
+    search_term: FIXME
+    case_sensitive: false
+    context_lines: 0
+    file_patterns: ["*.go"]
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	base := &SearchConfig{
+		ConfigFile:    configPath,
+		CaseSensitive: true,
+		ContextLines:  3,
+		FilePatterns:  []string{"*.py"},
+	}
+
+	// Without --config-search-defaults the CLI values are ignored
+	searches, err := loadSearchesFromConfig(base)
+	if err != nil {
+		t.Fatalf("loadSearchesFromConfig() error = %v", err)
+	}
+	if searches[0].CaseSensitive || searches[0].ContextLines != 0 || searches[0].FilePatterns != nil {
+		t.Errorf("without defaults got %+v", searches[0])
+	}
+
+	base.SearchDefaults = true
+	searches, err = loadSearchesFromConfig(base)
+	if err != nil {
+		t.Fatalf("loadSearchesFromConfig() error = %v", err)
+	}
+
+	inherits, overrides := searches[0], searches[1]
+	if !inherits.CaseSensitive || inherits.ContextLines != 3 || fmt.Sprint(inherits.FilePatterns) != "[*.py]" {
+		t.Errorf("unset fields should take CLI values, got case=%v context=%d files=%v",
+			inherits.CaseSensitive, inherits.ContextLines, inherits.FilePatterns)
+	}
+	// Explicit zero values in the entry win over the CLI
+	if overrides.CaseSensitive || overrides.ContextLines != 0 || fmt.Sprint(overrides.FilePatterns) != "[*.go]" {
+		t.Errorf("set fields should be kept, got case=%v context=%d files=%v",
+			overrides.CaseSensitive, overrides.ContextLines, overrides.FilePatterns)
+	}
+}
+
+func TestFilterForks(t *testing.T) {
+	projects := []*gitlab.Project{
+		{Name: "upstream"},
+		{Name: "fork", IsFork: true},
+		{Name: "other-fork", IsFork: true},
+	}
+
+	var buf bytes.Buffer
+	if got := filterForks(projects, true, false, &buf); len(got) != 1 || got[0].Name != "upstream" {
+		t.Errorf("filterForks(exclude) = %v, want [upstream]", got)
+	}
+	if buf.String() != "Skipping 2 forked projects\n" {
+		t.Errorf("filterForks(exclude) reported %q", buf.String())
+	}
+
+	buf.Reset()
+	if got := filterForks(projects, false, true, &buf); len(got) != 2 {
+		t.Errorf("filterForks(only) returned %d projects, want 2", len(got))
+	}
+	if buf.String() != "Skipping 1 non-fork projects\n" {
+		t.Errorf("filterForks(only) reported %q", buf.String())
+	}
+
+	buf.Reset()
+	if got := filterForks(projects, false, false, &buf); len(got) != 3 || buf.Len() != 0 {
+		t.Errorf("filterForks(neither) = %d projects, reported %q; want all 3, silently", len(got), buf.String())
+	}
+}
+
+func TestReportTopic(t *testing.T) {
+	var buf bytes.Buffer
+	reportTopic(&buf, 12, "python-service")
+	if buf.String() != "12 projects have topic \"python-service\"\n" {
+		t.Errorf("reportTopic() reported %q", buf.String())
+	}
+
+	buf.Reset()
+	if reportTopic(&buf, 12, ""); buf.Len() != 0 {
+		t.Errorf("reportTopic() without a topic reported %q", buf.String())
+	}
+}
+
+func TestValidateRenderConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *SearchConfig
+		wantErr bool
+	}{
+		{"no url or token needed", &SearchConfig{InputLog: "scan.json"}, false},
+		{"with search", &SearchConfig{InputLog: "scan.json", SearchTerm: "x"}, true},
+		{"with watch", &SearchConfig{InputLog: "scan.json", Watch: time.Hour}, true},
+		{"with dep report", &SearchConfig{InputLog: "scan.json", DepReportPath: "deps.json"}, true},
+		{"bad target version", &SearchConfig{InputLog: "scan.json", TargetVersion: "latest"}, true},
+		{"with at-risk floor", &SearchConfig{InputLog: "scan.json", AtRiskBelow: "3.10"}, false},
+		{"with commit", &SearchConfig{InputLog: "scan.json", Commit: "4f2c9e1"}, true},
+		{"with exclude forks", &SearchConfig{InputLog: "scan.json", ExcludeForks: true}, true},
+		{"with topic", &SearchConfig{InputLog: "scan.json", Topic: "python-service"}, true},
+		{"with ignore path", &SearchConfig{InputLog: "scan.json", IgnorePaths: []string{"third_party/**"}}, true},
+		{"with ignore file", &SearchConfig{InputLog: "scan.json", IgnoreFile: true}, true},
+		{"with explain", &SearchConfig{InputLog: "scan.json", Explain: true}, true},
+		{"with authoritative", &SearchConfig{InputLog: "scan.json", Authoritative: true}, true},
+		{"with benchmark", &SearchConfig{InputLog: "scan.json", Benchmark: true}, true},
+		{"with bucketed", &SearchConfig{InputLog: "scan.json", Bucketed: true}, true},
+		{"with post hook", &SearchConfig{InputLog: "scan.json", PostHook: "true"}, true},
+		{"with incremental", &SearchConfig{InputLog: "scan.json", Incremental: "scan.json"}, true},
+		{"list versions with counts", &SearchConfig{InputLog: "scan.json", ListVersions: true, WithCounts: true}, false},
+		{"with counts without list versions", &SearchConfig{InputLog: "scan.json", WithCounts: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRenderConfig(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("validateRenderConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateListVersions(t *testing.T) {
+	tests := []struct {
+		name                                                 string
+		listVersions, withCounts, includeUndetected, summary bool
+		wantErr                                              bool
+	}{
+		{"off", false, false, false, false, false},
+		{"list only", true, false, false, false, false},
+		{"list with counts and undetected", true, true, true, false, false},
+		{"counts without list", false, true, false, false, true},
+		{"undetected without list", false, false, true, false, true},
+		{"list with summary line", true, false, false, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateListVersions(tt.listVersions, tt.withCounts, tt.includeUndetected, tt.summary)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateListVersions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderScanLog(t *testing.T) {
+	dir := t.TempDir()
+	input := `{"type": "scan_started", "gitlab_url": "gitlab.com/org"}
+{"project_name": "a", "project_path": "org/a", "python_version": "3.12"}
+{"project_name": "b", "project_path": "org/b", "python_version": "3.8"}
+{"type": "scan_completed", "approved_versions": ["3.8"]}
+`
+	scanLog, err := output.ReadScanLog(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadScanLog() error = %v", err)
+	}
+
+	// --approved-versions replaces the log's own policy
+	csvPath := filepath.Join(dir, "out.csv")
+	config := &SearchConfig{
+		LogFiles:         []string{csvPath},
+		ApprovedVersions: []string{"3.12"},
+		OnlyNonApproved:  true,
+	}
+	if err := renderScanLog(config, scanLog); err != nil {
+		t.Fatalf("renderScanLog() error = %v", err)
+	}
+
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("failed to read csv: %v", err)
+	}
+	if !strings.Contains(string(data), "org/b") || strings.Contains(string(data), "org/a") {
+		t.Errorf("expected only the non-approved project, got:\n%s", data)
+	}
+}
+
+func TestEffectiveConfig(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "searches.yaml")
+	content := `settings:
+  explicit_version_files: [PYTHON_VERSION]
+searches:
+  - name: secrets
+    search_term: API_KEY
+  - name: off
+    search_term: TODO
+    enabled: false
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	base := &SearchConfig{
+		ConfigFile:     configPath,
+		SearchDefaults: true,
+		ContextLines:   2,
+		DisabledTags:   []string{"provisioning"},
+	}
+	cfg, err := effectiveConfig(base)
+	if err != nil {
+		t.Fatalf("effectiveConfig() error = %v", err)
+	}
+
+	// The dump must load back as a valid config
+	dumpPath := filepath.Join(dir, "effective.yaml")
+	if err := config.SaveConfig(cfg, dumpPath); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	loaded, err := config.LoadConfig(dumpPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if len(loaded.Searches) != 1 {
+		t.Fatalf("got %d searches, want only the enabled one", len(loaded.Searches))
+	}
+	search := loaded.Searches[0]
+	if search.Name != "secrets" || search.ContextLines == nil || *search.ContextLines != 2 {
+		t.Errorf("search = %+v, want secrets with the CLI's context_lines", search)
+	}
+
+	// The config's explicit_version_files adds a rule to the built-ins
+	if len(loaded.Rules) != parsers.DefaultRegistry().Count()+1 {
+		t.Errorf("got %d rules, want every built-in rule and the config's PYTHON_VERSION", len(loaded.Rules))
+	}
+	for _, rule := range loaded.Rules {
+		provisioning := false
+		for _, tag := range rule.Tags {
+			provisioning = provisioning || tag == "provisioning"
+		}
+		if rule.Enabled == nil || *rule.Enabled == provisioning {
+			t.Errorf("rule %s enabled = %v, want %v", rule.Name, rule.Enabled, !provisioning)
+		}
+	}
+}
+
+func TestSearchNameAndSeverity(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "searches.yaml")
+	content := `searches:
+  - name: aws-keys
+    search_term: AKIA
+    severity: critical
+  - name: todos
+    search_term: TODO
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	searches, err := loadSearchesFromConfig(&SearchConfig{ConfigFile: configPath})
+	if err != nil {
+		t.Fatalf("loadSearchesFromConfig() error = %v", err)
+	}
+	if searches[0].SearchName != "aws-keys" || searches[0].Severity != "critical" {
+		t.Errorf("first search = %q/%q, want aws-keys/critical", searches[0].SearchName, searches[0].Severity)
+	}
+	if searches[1].Severity != "" {
+		t.Errorf("second search severity = %q, want unset", searches[1].Severity)
+	}
+
+	// A lone --search is named after its term
+	base := &SearchConfig{SearchTerm: "password"}
+	if got := singleSearch(base).SearchName; got != "password" {
+		t.Errorf("singleSearch() name = %q, want password", got)
+	}
+	if base.SearchName != "" {
+		t.Error("singleSearch() modified its argument")
+	}
+}
+
+func TestContentSearchInFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/repository/tree"):
+			t.Error("--in-file should not list the repository tree")
+		case strings.HasSuffix(r.URL.Path, "/projects/1/repository/files/Dockerfile/raw"):
+			w.Write([]byte("FROM python:3.12\nUSER root\n"))
+		case strings.HasSuffix(r.URL.Path, "/projects/3/repository/files/Dockerfile/raw"):
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	cs := scanner.NewContentScanner(client, scanner.ContentSearchConfig{
+		SearchTerm: "user root",
+		InFiles:    []string{"Dockerfile"},
+	})
+
+	result := cs.ScanProject(context.Background(), &gitlab.Project{ID: 1, Name: "api", DefaultBranch: "main"}, 1, 3)
+	if result.Error != nil || len(result.Matches) != 1 {
+		t.Fatalf("got %d matches, error %v; want 1 match", len(result.Matches), result.Error)
+	}
+	if m := result.Matches[0]; m.FilePath != "Dockerfile" || m.LineNumber != 2 || m.Ref != "main" {
+		t.Errorf("match = %+v, want Dockerfile line 2 on main", m)
+	}
+
+	// A project without the file has no matches rather than an error
+	missing := cs.ScanProject(context.Background(), &gitlab.Project{ID: 2, Name: "docs"}, 2, 3)
+	if missing.Error != nil || len(missing.Matches) != 0 {
+		t.Errorf("missing file: got %d matches, error %v; want none", len(missing.Matches), missing.Error)
+	}
+
+	denied := cs.ScanProject(context.Background(), &gitlab.Project{ID: 3, Name: "private"}, 3, 3)
+	if denied.Error == nil {
+		t.Error("expected an error when the file can't be read")
+	}
+}
+
+func TestContentSearchVersionFilter(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "searches.yaml")
+	content := `version: "1.0"
+searches:
+  - name: old-django
+    search_term: '^django\s*==\s*(?P<ver>[\d.]+)'
+    is_regex: true
+    version_filter: "<4.0"
+    version_group: ver
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	searches, err := loadSearchesFromConfig(&SearchConfig{ConfigFile: configPath})
+	if err != nil {
+		t.Fatalf("loadSearchesFromConfig() error = %v", err)
+	}
+	if got := searches[0].VersionFilter.String(); got != "<4.0" || searches[0].VersionGroup != "ver" {
+		t.Fatalf("version filter = %q on group %q, want <4.0 on ver", got, searches[0].VersionGroup)
+	}
+	if _, err := loadSearchesFromConfig(&SearchConfig{ConfigFile: configPath, MatchFilesOnly: true}); err == nil {
+		t.Error("expected version_filter to be rejected with --match-files-only")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/projects/1/repository/files/requirements.txt/raw"):
+			w.Write([]byte("Django==3.2.18\nrequests==2.31.0\n"))
+		case strings.HasSuffix(r.URL.Path, "/projects/2/repository/files/requirements.txt/raw"):
+			w.Write([]byte("Django==4.2.7\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	search := searches[0]
+	search.InFiles = []string{"requirements.txt"}
+	cs := newContentScanner(client, search)
+
+	if result := cs.ScanProject(context.Background(), &gitlab.Project{ID: 1, Name: "legacy"}, 1, 2); len(result.Matches) != 1 {
+		t.Errorf("Django 3.2 project: got %d matches, error %v; want 1", len(result.Matches), result.Error)
+	}
+	if result := cs.ScanProject(context.Background(), &gitlab.Project{ID: 2, Name: "current"}, 2, 2); len(result.Matches) != 0 {
+		t.Errorf("Django 4.2 project: got %d matches, want none", len(result.Matches))
+	}
+}
+
+func TestContentSearchFirstMatch(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/repository/tree") {
+			var files []string
+			for i := 0; i < 20; i++ {
+				files = append(files, fmt.Sprintf(`{"path": "f%d.py", "name": "f%d.py", "type": "blob"}`, i, i))
+			}
+			fmt.Fprintf(w, "[%s]", strings.Join(files, ","))
+			return
+		}
+		fetches.Add(1)
+		w.Write([]byte("import os\nos.system('x')\n"))
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	cs := scanner.NewContentScanner(client, scanner.ContentSearchConfig{
+		SearchTerm: `os\.system`,
+		IsRegex:    true,
+		FirstMatch: true,
+	})
+
+	result := cs.ScanProject(context.Background(), &gitlab.Project{ID: 1, Name: "api"}, 1, 1)
+	if result.Error != nil || len(result.Matches) != 1 || !result.FirstMatchOnly {
+		t.Fatalf("got %d matches, FirstMatchOnly %v, error %v; want one first-match-only match",
+			len(result.Matches), result.FirstMatchOnly, result.Error)
+	}
+	if n := fetches.Load(); n > 3 {
+		t.Errorf("fetched %d of 20 files, want the search to stop after the first match", n)
+	}
+}
+
+func TestContentSearchWikis(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/projects/1/wikis"):
+			if r.URL.Query().Get("with_content") != "true" {
+				t.Errorf("wiki pages listed without with_content: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[{"slug": "home", "title": "Home", "format": "markdown", "content": "Welcome"},
+				{"slug": "deploy/notes", "title": "Notes", "format": "markdown", "content": "Steps:\nRuntime is python 3.8 on the old hosts\n"}]`))
+		case strings.HasSuffix(r.URL.Path, "/repository/files/runtime.txt/raw"):
+			w.Write([]byte("python-3.11.5\n"))
+		default:
+			// Project 2's wiki is disabled
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	cs := scanner.NewContentScanner(client, scanner.ContentSearchConfig{
+		SearchTerm:  `python.?3\.\d+`,
+		IsRegex:     true,
+		InFiles:     []string{"runtime.txt"},
+		SearchWikis: true,
+	})
+
+	result := cs.ScanProject(context.Background(), &gitlab.Project{ID: 1, Name: "api"}, 1, 2)
+	if result.Error != nil || len(result.Matches) != 2 {
+		t.Fatalf("got %d matches, error %v; want the file's and the wiki page's", len(result.Matches), result.Error)
+	}
+	if m := result.Matches[1]; m.FilePath != "wiki:deploy/notes" || m.LineNumber != 2 || m.MatchedText != "python 3.8" {
+		t.Errorf("wiki match = %+v, want python 3.8 on line 2 of wiki:deploy/notes", m)
+	}
+
+	if result := cs.ScanProject(context.Background(), &gitlab.Project{ID: 2, Name: "web"}, 2, 2); result.Error != nil || len(result.Matches) != 1 {
+		t.Errorf("project without a wiki: got %d matches, error %v; want the file's match only", len(result.Matches), result.Error)
+	}
+}
+
+func TestRenderScanLogOnlyPython2(t *testing.T) {
+	input := `{"project_name": "a", "project_path": "org/a", "python_version": "3.12"}
+{"project_name": "b", "project_path": "org/b", "python_version": "2.7.18"}
+`
+	scanLog, err := output.ReadScanLog(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadScanLog() error = %v", err)
+	}
+
+	logPath := filepath.Join(t.TempDir(), "out.json")
+	if err := renderScanLog(&SearchConfig{LogFiles: []string{logPath}, OnlyPython2: true}, scanLog); err != nil {
+		t.Fatalf("renderScanLog() error = %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if strings.Contains(string(data), `"project_path":"org/a"`) || !strings.Contains(string(data), `"is_python2":true`) {
+		t.Errorf("expected only the Python 2 project, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), `"python2_paths":["org/b"]`) {
+		t.Errorf("summary missing python2_paths:\n%s", data)
+	}
+}
+
+func TestValidateMergeRequestConfig(t *testing.T) {
+	valid := func() *SearchConfig {
+		return &SearchConfig{GitLabURL: "gitlab.com", Token: "tok", Project: "org/app", MergeRequest: 7}
+	}
+	tests := []struct {
+		name    string
+		modify  func(*SearchConfig)
+		wantErr string
+	}{
+		{name: "valid", modify: func(c *SearchConfig) {}},
+		{name: "no project", modify: func(c *SearchConfig) { c.Project = "" }, wantErr: "--mode mr requires --project (or CI_PROJECT_PATH)"},
+		{name: "no merge request", modify: func(c *SearchConfig) { c.MergeRequest = 0 }, wantErr: "--mode mr requires --merge-request (or CI_MERGE_REQUEST_IID)"},
+		{name: "with log", modify: func(c *SearchConfig) { c.LogFiles = []string{"mr.json"} }, wantErr: "--mode mr reports to the console and can't be combined with --log or --sqlite"},
+		{name: "fail on match without search", modify: func(c *SearchConfig) { c.FailOn = failOnMatch }, wantErr: "--fail-on match requires --search"},
+		{name: "fail on parse errors", modify: func(c *SearchConfig) { c.FailOn = failOnParseErrors }, wantErr: `--fail-on must be "version-change" or "match" with --mode mr, got "parse-errors"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid()
+			tt.modify(config)
+			err := validateMergeRequestConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateMergeRequestConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("validateMergeRequestConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckMergeRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/merge_requests/7/changes"):
+			w.Write([]byte(`{"iid": 7, "title": "Move to 3.12", "source_branch": "py312", "target_branch": "main",
+				"changes": [{"old_path": ".python-version", "new_path": ".python-version", "diff": "@@ -1 +1 @@\n-3.11\n+3.12 # FIXME\n"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw") && r.URL.Query().Get("ref") == "main":
+			w.Write([]byte("3.11\n"))
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			w.Write([]byte("3.12\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	config := &SearchConfig{Project: "org/app", MergeRequest: 7, SearchTerm: "FIXME"}
+
+	var buf bytes.Buffer
+	if err := checkMergeRequest(context.Background(), client, parsers.DefaultRegistry(), config, &buf); err != nil {
+		t.Fatalf("checkMergeRequest() error = %v", err)
+	}
+	for _, want := range []string{
+		"Merge request !7: Move to 3.12\n  py312 -> main, 1 files changed\n",
+		"Python version changes: 1\n  .python-version: 3.11 -> 3.12\n",
+		"Matches for \"FIXME\" in added lines: 1\n  .python-version:1: 3.12 # FIXME\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	config.FailOn = failOnVersionChange
+	err = checkMergeRequest(context.Background(), client, parsers.DefaultRegistry(), config, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "changes the Python version in 1 files") {
+		t.Errorf("checkMergeRequest() with --fail-on version-change error = %v", err)
+	}
+}
+
+func TestResolveMode(t *testing.T) {
+	dir := t.TempDir()
+	rulesOnly := filepath.Join(dir, "rules.yaml")
+	withSearches := filepath.Join(dir, "searches.yaml")
+	if err := os.WriteFile(rulesOnly, []byte("rules:\n  - name: pinned\n    match:\n      file_pattern: .python-version\n    parser:\n      type: simple_version\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := os.WriteFile(withSearches, []byte("searches:\n  - name: keys\n    search_term: AKIA\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	pinFiles := filepath.Join(dir, "pins.yaml")
+	if err := os.WriteFile(pinFiles, []byte("settings:\n  explicit_version_files: [PYTHON_VERSION]\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	instancesOnly := filepath.Join(dir, "instances.yaml")
+	if err := os.WriteFile(instancesOnly, []byte("instances:\n  - name: saas\n    url: https://gitlab.com\n    token_env: SAAS_TOKEN\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	refsOnly := filepath.Join(dir, "refs.yaml")
+	if err := os.WriteFile(refsOnly, []byte("settings:\n  project_refs:\n    org/api: release\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		config  *SearchConfig
+		want    string
+		wantErr bool
+	}{
+		{name: "no flags scans", config: &SearchConfig{}, want: modeScan},
+		{name: "search term searches", config: &SearchConfig{SearchTerm: "AKIA"}, want: modeSearch},
+		{name: "config with searches searches", config: &SearchConfig{ConfigFile: withSearches}, want: modeSearch},
+		{name: "discovered config with searches scans", config: &SearchConfig{ConfigFile: withSearches, ConfigDiscovered: true}, want: modeScan},
+		{name: "config with only rules scans", config: &SearchConfig{ConfigFile: rulesOnly}, want: modeScan},
+		{name: "config with only pin files scans", config: &SearchConfig{ConfigFile: pinFiles}, want: modeScan},
+		{name: "config with only instances scans", config: &SearchConfig{ConfigFile: instancesOnly}, want: modeScan},
+		{name: "config with only project refs scans", config: &SearchConfig{ConfigFile: refsOnly}, want: modeScan},
+		{name: "unreadable config searches", config: &SearchConfig{ConfigFile: filepath.Join(dir, "missing.yaml")}, want: modeSearch},
+		{name: "explicit both", config: &SearchConfig{Mode: "both", ConfigFile: withSearches}, want: modeBoth},
+		{name: "merge request", config: &SearchConfig{Mode: "mr", SearchTerm: "AKIA"}, want: modeMergeRequest},
+		{name: "explicit scan with search term", config: &SearchConfig{Mode: "scan", SearchTerm: "AKIA"}, wantErr: true},
+		{name: "unknown mode", config: &SearchConfig{Mode: "everything"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveMode(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveMode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadScanRegistryExplicitVersionFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pins.yaml")
+	if err := os.WriteFile(path, []byte("settings:\n  explicit_version_files: [PYTHON_VERSION]\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	registry, err := loadScanRegistry(path, []string{"version-file"})
+	if err != nil {
+		t.Fatalf("loadScanRegistry() error = %v", err)
+	}
+	if registry.Count() != parsers.DefaultRegistry().Count()+1 {
+		t.Errorf("Count() = %d, want the built-in rules plus one", registry.Count())
+	}
+	rule := registry.Get("explicit-version-file:PYTHON_VERSION")
+	if rule == nil {
+		t.Fatal("no rule for PYTHON_VERSION")
+	}
+	if rule.Enabled {
+		t.Error("PYTHON_VERSION rule enabled, want it disabled by --disable-tag version-file")
+	}
+}
+
+func TestLoadScanRegistryConfidenceOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "weights.yaml")
+	if err := os.WriteFile(path, []byte("settings:\n  confidence_overrides:\n    python-version-file: 0.85\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	registry, err := loadScanRegistry(path, nil)
+	if err != nil {
+		t.Fatalf("loadScanRegistry() error = %v", err)
+	}
+	result, err := registry.Get("python-version-file").Apply(context.Background(), []byte("3.12\n"), ".python-version")
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if result.Confidence != 0.85 {
+		t.Errorf("Confidence = %v, want the configured 0.85", result.Confidence)
+	}
+
+	typo := filepath.Join(dir, "typo.yaml")
+	if err := os.WriteFile(typo, []byte("settings:\n  confidence_overrides:\n    python-versions-file: 0.85\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := loadScanRegistry(typo, nil); err == nil {
+		t.Error("loadScanRegistry() with an unknown rule name succeeded, want an error")
+	}
+}
+
+func TestLoadProjectRefs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "refs.yaml")
+	if err := os.WriteFile(path, []byte("settings:\n  project_refs:\n    org/billing: develop\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	refs, err := loadProjectRefs(path)
+	if err != nil {
+		t.Fatalf("loadProjectRefs() error = %v", err)
+	}
+	if refs["org/billing"] != "develop" {
+		t.Errorf("refs = %v, want org/billing on develop", refs)
+	}
+
+	// org/archive exists but was filtered out of the listing
+	exists := func(path string) (bool, error) { return path == "org/archive", nil }
+	projects := []*gitlab.Project{{PathWithNamespace: "org/billing"}, {PathWithNamespace: "org/search"}}
+	inst := &instance{}
+	owners := []*instance{inst, inst}
+	var notes bytes.Buffer
+	if err := checkProjectRefs(refs, projects, owners, exists, &notes); err != nil || notes.Len() > 0 {
+		t.Errorf("checkProjectRefs() error = %v, notes %q", err, notes.String())
+	}
+	err = checkProjectRefs(map[string]string{"org/biling": "develop", "org/search": "main", "org/archive": "v1"}, projects, owners, exists, &notes)
+	if err == nil || !strings.Contains(err.Error(), "org/biling") || strings.Contains(err.Error(), "org/search") || strings.Contains(err.Error(), "org/archive") {
+		t.Errorf("checkProjectRefs() error = %v, want only org/biling reported", err)
+	}
+	notes.Reset()
+	if err := checkProjectRefs(map[string]string{"org/archive": "v1"}, projects, owners, exists, &notes); err != nil {
+		t.Errorf("checkProjectRefs() with a filtered project error = %v", err)
+	}
+	if !strings.Contains(notes.String(), "filtered out of the listing and won't be scanned: org/archive") {
+		t.Errorf("notes = %q, want org/archive noted as skipped", notes.String())
+	}
+
+	empty := filepath.Join(dir, "empty.yaml")
+	if err := os.WriteFile(empty, []byte("settings:\n  project_refs:\n    org/billing: \"\"\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := loadProjectRefs(empty); err == nil {
+		t.Error("loadProjectRefs() with an empty ref succeeded, want an error")
+	}
+
+	if refs, err := loadProjectRefs(""); refs != nil || err != nil {
+		t.Errorf("loadProjectRefs(\"\") = %v, %v, want nothing", refs, err)
+	}
+}
+
+func TestProjectRefsCommit(t *testing.T) {
+	inst := &instance{}
+	one := []*gitlab.Project{{PathWithNamespace: "org/billing"}}
+	refs, err := projectRefs(&Config{Commit: "4f2c9e1"}, one, []*instance{inst}, nil)
+	if err != nil || refs[inst]["org/billing"] != "4f2c9e1" {
+		t.Errorf("projectRefs() = %v, %v, want org/billing at 4f2c9e1", refs, err)
+	}
+
+	two := append(one, &gitlab.Project{PathWithNamespace: "org/search"})
+	if _, err := projectRefs(&Config{Commit: "4f2c9e1"}, two, []*instance{inst, inst}, nil); err == nil || !strings.Contains(err.Error(), "2 were found") {
+		t.Errorf("projectRefs() with two projects error = %v, want a single-project error", err)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "refs.yaml")
+	if err := os.WriteFile(path, []byte("settings:\n  project_refs:\n    org/billing: develop\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := projectRefs(&Config{ConfigFile: path, Commit: "4f2c9e1"}, one, []*instance{inst}, nil); err == nil {
+		t.Error("projectRefs() with --commit conflicting with project_refs succeeded, want an error")
+	}
+}
+
+func TestProjectRefsInstances(t *testing.T) {
+	saas, onprem := &instance{name: "saas"}, &instance{name: "onprem"}
+	// org/api is on both instances mid-migration
+	projects := []*gitlab.Project{{PathWithNamespace: "org/api"}, {PathWithNamespace: "org/api"}, {PathWithNamespace: "org/web"}}
+	owners := []*instance{saas, onprem, onprem}
+	writeRefs := func(refs string) *Config {
+		path := filepath.Join(t.TempDir(), "refs.yaml")
+		if err := os.WriteFile(path, []byte("settings:\n  project_refs:\n"+refs), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		return &Config{ConfigFile: path, ListVersions: true}
+	}
+	exists := func(key string) (bool, error) { return false, nil }
+
+	refs, err := projectRefs(writeRefs("    \"onprem:org/api\": develop\n    org/web: release\n"), projects, owners, exists)
+	if err != nil {
+		t.Fatalf("projectRefs() error = %v", err)
+	}
+	if refs[saas]["org/api"] != "" || refs[onprem]["org/api"] != "develop" || refs[onprem]["org/web"] != "release" {
+		t.Errorf("projectRefs() = %v, want org/api pinned on onprem only", refs)
+	}
+
+	if _, err := projectRefs(writeRefs("    org/api: develop\n"), projects, owners, exists); err == nil || !strings.Contains(err.Error(), "by path alone: org/api") {
+		t.Errorf("projectRefs() with a bare shared path error = %v, want it rejected", err)
+	}
+}
+
+func TestSearchLogPath(t *testing.T) {
+	tests := map[string]string{
+		"results.json":     "results.search.json",
+		"out/scan.csv":     "out/scan.search.csv",
+		"results":          "results.search",
+		"logs.v2/scan.txt": "logs.v2/scan.search.txt",
+	}
+	for in, want := range tests {
+		if got := searchLogPath(in); got != want {
+			t.Errorf("searchLogPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRunBoth(t *testing.T) {
+	var dockerfileFetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/groups/org/projects"):
+			w.Write([]byte(`[{"id": 1, "name": "api", "path_with_namespace": "org/api", "default_branch": "main"}]`))
+		case strings.HasSuffix(r.URL.Path, "/repository/commits/main"):
+			w.Write([]byte(`{"id": "4f2c9e1a7b3d5c8e9f0a1b2c3d4e5f6a7b8c9d0e"}`))
+		case strings.HasSuffix(r.URL.Path, "/repository/tree"):
+			w.Write([]byte(`[{"name": "Dockerfile", "path": "Dockerfile", "type": "blob"}]`))
+		case strings.HasSuffix(r.URL.Path, "/files/Dockerfile/raw"):
+			dockerfileFetches.Add(1)
+			w.Write([]byte("FROM python:3.11\nUSER root\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL + "/org", Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	logPath := filepath.Join(t.TempDir(), "results.json")
+	config := &Config{GitLabURL: server.URL + "/org", LogFiles: []string{logPath}}
+	searches := []*SearchConfig{{SearchTerm: "USER root", SearchName: "root-user", InFiles: []string{"Dockerfile"}}}
+	if err := runBoth(context.Background(), client, config, parsers.DefaultRegistry(), searches); err != nil {
+		t.Fatalf("runBoth() error = %v", err)
+	}
+
+	scanLog, err := output.ReadScanLogFile(logPath)
+	if err != nil {
+		t.Fatalf("ReadScanLogFile() error = %v", err)
+	}
+	if len(scanLog.Results) != 1 || scanLog.Results[0].PythonVersion != "3.11" {
+		t.Errorf("scan results = %+v, want org/api on 3.11", scanLog.Results)
+	}
+
+	data, err := os.ReadFile(searchLogPath(logPath))
+	if err != nil {
+		t.Fatalf("failed to read search log: %v", err)
+	}
+	if !strings.Contains(string(data), `"search_name":"root-user"`) || !strings.Contains(string(data), `"file_path":"Dockerfile"`) {
+		t.Errorf("search log missing the Dockerfile match:\n%s", data)
+	}
+
+	// The scan and the search both read the Dockerfile
+	if n := dockerfileFetches.Load(); n != 1 {
+		t.Errorf("fetched the Dockerfile %d times, want once", n)
+	}
+}
+
+func TestScanWithHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/groups/org/projects"):
+			w.Write([]byte(`[{"id": 1, "name": "api", "path_with_namespace": "org/api", "default_branch": "main"}]`))
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			w.Write([]byte("3.11\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL + "/org", Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	envPath, reportPath := filepath.Join(dir, "env"), filepath.Join(dir, "report.json")
+	config := &Config{
+		GitLabURL:       server.URL + "/org",
+		SubgroupDepth:   -1,
+		PostHook:        fmt.Sprintf("env | grep ^SEEKER_ > %s; cat > %s", envPath, reportPath),
+		PostHookTimeout: 10 * time.Second,
+	}
+	run := func(config *Config) error {
+		return scanWithHook(context.Background(), []*instance{{client: client}}, config, output.NewConsoleStreamer(), nil, output.NewScanStatistics(), nil)
+	}
+
+	if err := run(config); err != nil {
+		t.Fatalf("scanWithHook() error = %v", err)
+	}
+
+	env, err := os.ReadFile(envPath)
+	if err != nil {
+		t.Fatalf("hook didn't run: %v", err)
+	}
+	for _, want := range []string{"SEEKER_STATUS=pass", "SEEKER_EXIT_CODE=0", "SEEKER_TOTAL=1", "SEEKER_PYTHON=1", "SEEKER_ERRORS=0"} {
+		if !strings.Contains(string(env), want+"\n") {
+			t.Errorf("hook environment missing %s:\n%s", want, env)
+		}
+	}
+
+	report, err := os.Open(reportPath)
+	if err != nil {
+		t.Fatalf("failed to open report: %v", err)
+	}
+	defer report.Close()
+	entries, header, summary, err := output.ReadLog(report)
+	if err != nil {
+		t.Fatalf("ReadLog() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].PythonVersion != "3.11" || header == nil || summary == nil {
+		t.Errorf("report = %d entries, header %v, summary %v; want org/api on 3.11 with header and summary", len(entries), header, summary)
+	}
+
+	config.PostHook = "exit 3"
+	if err := run(config); err == nil || !strings.Contains(err.Error(), "post-hook failed") {
+		t.Errorf("scanWithHook() with a failing hook error = %v, want post-hook failed", err)
+	}
+
+	config.PostHook = "sleep 5"
+	config.PostHookTimeout = 100 * time.Millisecond
+	start := time.Now()
+	if err := run(config); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("scanWithHook() with a slow hook error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("slow hook took %v, want it killed at the timeout", elapsed)
+	}
+}
+
+func TestScanIncremental(t *testing.T) {
+	var apiHead atomic.Value
+	apiHead.Store("1111111111111111111111111111111111111111")
+	var apiFetches, webFetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/groups/org/projects"):
+			w.Write([]byte(`[{"id": 1, "name": "api", "path_with_namespace": "org/api", "default_branch": "main"},
+				{"id": 2, "name": "web", "path_with_namespace": "org/web", "default_branch": "main"}]`))
+		case strings.HasSuffix(r.URL.Path, "/projects/1/repository/commits/main"):
+			fmt.Fprintf(w, `{"id": %q}`, apiHead.Load())
+		case strings.HasSuffix(r.URL.Path, "/projects/2/repository/commits/main"):
+			w.Write([]byte(`{"id": "2222222222222222222222222222222222222222"}`))
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			if strings.Contains(r.URL.Path, "/projects/1/") {
+				apiFetches.Add(1)
+			} else {
+				webFetches.Add(1)
+			}
+			w.Write([]byte("3.11\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL + "/org", Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	logPath := filepath.Join(t.TempDir(), "scan.json")
+	config := &Config{GitLabURL: server.URL + "/org", SubgroupDepth: -1, Incremental: logPath}
+	// Each run is a separate process reading the log the last one wrote
+	run := func() *output.ScanStatistics {
+		cache, err := loadResultCache(logPath)
+		if err != nil {
+			t.Fatalf("loadResultCache() error = %v", err)
+		}
+		logger, err := output.NewFileLogger(logPath, output.FormatJSON)
+		if err != nil {
+			t.Fatalf("NewFileLogger() error = %v", err)
+		}
+		defer logger.Close()
+		stats := output.NewScanStatistics()
+		if err := scanOnce(context.Background(), []*instance{{client: client}}, config, output.NewConsoleStreamer(), []output.ResultSink{logger}, stats, &runState{results: cache}); err != nil {
+			t.Fatalf("scanOnce() error = %v", err)
+		}
+		return stats
+	}
+
+	// The first run has no previous log, so everything is scanned
+	if stats := run(); stats.CachedProjects != 0 || apiFetches.Load() != 1 || webFetches.Load() != 1 {
+		t.Fatalf("first run reused %d, fetched api %d and web %d times; want a full scan",
+			stats.CachedProjects, apiFetches.Load(), webFetches.Load())
+	}
+
+	apiHead.Store("3333333333333333333333333333333333333333")
+	stats := run()
+	if apiFetches.Load() != 2 || webFetches.Load() != 1 {
+		t.Errorf("second run fetched api %d and web %d times in total, want only api rescanned", apiFetches.Load(), webFetches.Load())
+	}
+	if stats.TotalProjects != 2 || stats.PythonProjects != 2 || stats.CachedProjects != 1 {
+		t.Errorf("second run stats = %d total, %d Python, %d cached; want 2, 2, 1",
+			stats.TotalProjects, stats.PythonProjects, stats.CachedProjects)
+	}
+
+	scanLog, err := output.ReadScanLogFile(logPath)
+	if err != nil {
+		t.Fatalf("ReadScanLogFile() error = %v", err)
+	}
+	for _, result := range scanLog.Results {
+		if want := result.ProjectPath == "org/web"; result.Cached != want || result.PythonVersion != "3.11" {
+			t.Errorf("%s logged as cached=%v on %q, want cached=%v on 3.11", result.ProjectPath, result.Cached, result.PythonVersion, want)
+		}
+	}
+}
+
+func TestScanBudget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/groups/org/projects"):
+			w.Write([]byte(`[{"id": 1, "name": "api", "path_with_namespace": "org/api", "default_branch": "main"},
+				{"id": 2, "name": "web", "path_with_namespace": "org/web", "default_branch": "main"}]`))
+		default:
+			// Every probe misses, so each project needs many calls
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL + "/org", Token: "test-token", MaxAPICalls: 3})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	logPath := filepath.Join(t.TempDir(), "scan.json")
+	logger, err := output.NewFileLogger(logPath, output.FormatJSON)
+	if err != nil {
+		t.Fatalf("NewFileLogger() error = %v", err)
+	}
+	config := &Config{GitLabURL: server.URL + "/org", SubgroupDepth: -1, MaxAPICalls: 3}
+	stats := output.NewScanStatistics()
+	err = scanOnce(context.Background(), []*instance{{client: client}}, config, output.NewConsoleStreamer(), []output.ResultSink{logger}, stats, nil)
+	logger.Close()
+	if err == nil || !strings.Contains(err.Error(), "API budget exhausted") || !strings.Contains(err.Error(), "2 of 2 projects") {
+		t.Fatalf("scanOnce() error = %v, want the budget exhausted with 2 of 2 projects unscanned", err)
+	}
+
+	// Projects whose probes were refused aren't reported as lacking Python
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if strings.Contains(string(data), `"project_path"`) || stats.TotalProjects != 0 {
+		t.Errorf("counted %d projects and logged results, want none:\n%s", stats.TotalProjects, data)
+	}
+}
+
+func TestBenchmarkReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("3.12\n"))
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL + "/org", Token: "test-token", Stats: true})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	// Traffic before the benchmark starts isn't counted
+	if _, err := client.GetRawFile(context.Background(), 1, ".python-version", nil); err != nil {
+		t.Fatalf("GetRawFile() error = %v", err)
+	}
+
+	bench := newBenchmark(client.Stats())
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetRawFile(context.Background(), 1, ".python-version", nil); err != nil {
+			t.Fatalf("GetRawFile() error = %v", err)
+		}
+	}
+	for _, ms := range []int{40, 10, 30, 20, 100} {
+		bench.record(time.Duration(ms) * time.Millisecond)
+	}
+
+	var buf bytes.Buffer
+	if err := bench.report(&buf, client.Stats()); err != nil {
+		t.Fatalf("report() error = %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{"Projects scanned: 5 in ", "Files fetched:    2 (", "Downloaded:       10 B", "API requests:     2, 0 retries (0.0%)", "p50 30ms, p95 100ms"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestScanInstances(t *testing.T) {
+	newInstance := func(name, project, version string) *instance {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/groups/org/projects"):
+				fmt.Fprintf(w, `[{"id": 1, "name": %q, "path_with_namespace": "org/%s", "default_branch": "main"}]`, project, project)
+			case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+				w.Write([]byte(version + "\n"))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL + "/org", Token: "test-token"})
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		return &instance{name: name, url: server.URL + "/org", client: client}
+	}
+	instances := []*instance{newInstance("saas", "billing", "3.11"), newInstance("onprem", "search", "3.9")}
+
+	logPath := filepath.Join(t.TempDir(), "scan.json")
+	logger, err := output.NewFileLogger(logPath, output.FormatJSON)
+	if err != nil {
+		t.Fatalf("NewFileLogger() error = %v", err)
+	}
+	config := &Config{SubgroupDepth: -1}
+	stats := output.NewScanStatistics()
+	err = scanOnce(context.Background(), instances, config, output.NewConsoleStreamerWithWriter(&bytes.Buffer{}), []output.ResultSink{logger}, stats, nil)
+	logger.Close()
+	if err != nil {
+		t.Fatalf("scanOnce() error = %v", err)
+	}
+
+	// One report covers both instances, each result naming its own
+	if stats.TotalProjects != 2 || stats.PythonProjects != 2 {
+		t.Errorf("counted %d projects (%d Python), want both instances' 2", stats.TotalProjects, stats.PythonProjects)
+	}
+	log, err := output.ReadScanLogFile(logPath)
+	if err != nil {
+		t.Fatalf("ReadScanLogFile() error = %v", err)
+	}
+	got := map[string]string{}
+	for _, result := range log.Results {
+		got[result.ProjectPath] = result.Instance + " " + result.PythonVersion
+	}
+	if got["org/billing"] != "saas 3.11" || got["org/search"] != "onprem 3.9" {
+		t.Errorf("results = %v, want org/billing on saas with 3.11 and org/search on onprem with 3.9", got)
+	}
+}
+
+func TestResultCacheReuse(t *testing.T) {
+	const head = "4f2c9e1a7b3d5c8e9f0a1b2c3d4e5f6a7b8c9d0e"
+	const release = "9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/repository/commits/main"):
+			fmt.Fprintf(w, `{"id": %q}`, head)
+		case strings.HasSuffix(r.URL.Path, "/repository/commits/release"):
+			fmt.Fprintf(w, `{"id": %q}`, release)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	project := &gitlab.Project{ID: 1, Name: "api", PathWithNamespace: "org/api", DefaultBranch: "main", LastActivityAt: "2026-10-01T10:00:00Z"}
+	tests := []struct {
+		name string
+		prev *output.ScanResult
+		opts scanner.VersionScanOptions
+		want bool
+	}{
+		{"unchanged", &output.ScanResult{ProjectPath: "org/api", PythonVersion: "3.11", CommitSHA: head}, scanner.VersionScanOptions{}, true},
+		{"unchanged without a version", &output.ScanResult{ProjectPath: "org/api", CommitSHA: head}, scanner.VersionScanOptions{}, true},
+		{"unchanged despite other activity", &output.ScanResult{ProjectPath: "org/api", PythonVersion: "3.11", CommitSHA: head, LastActivityAt: "2026-09-01T10:00:00Z"}, scanner.VersionScanOptions{}, true},
+		{"new commit", &output.ScanResult{ProjectPath: "org/api", PythonVersion: "3.11", CommitSHA: release}, scanner.VersionScanOptions{}, false},
+		{"pinned ref unchanged", &output.ScanResult{ProjectPath: "org/api", PythonVersion: "3.11", CommitSHA: release}, scanner.VersionScanOptions{Refs: map[string]string{"org/api": "release"}}, true},
+		{"logged without a commit", &output.ScanResult{ProjectPath: "org/api", PythonVersion: "3.11"}, scanner.VersionScanOptions{}, false},
+		{"previous scan failed", &output.ScanResult{ProjectPath: "org/api", CommitSHA: head, Error: fmt.Errorf("500")}, scanner.VersionScanOptions{}, false},
+		{"previous scan timed out", &output.ScanResult{ProjectPath: "org/api", CommitSHA: head, TimedOut: true}, scanner.VersionScanOptions{}, false},
+		{"not in the previous scan", &output.ScanResult{ProjectPath: "org/web", CommitSHA: head}, scanner.VersionScanOptions{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &resultCache{results: map[string]*output.ScanResult{tt.prev.ProjectPath: tt.prev}}
+			got := cache.reuse(context.Background(), &instance{client: client}, project, tt.opts, 3, 7)
+			if (got != nil) != tt.want {
+				t.Fatalf("reuse() = %+v, want reused %v", got, tt.want)
+			}
+			if got != nil && (!got.Cached || got.Index != 3 || got.TotalProjects != 7 || tt.prev.Cached) {
+				t.Errorf("reused result = %+v, want a cached copy renumbered 3/7", got)
+			}
+		})
+	}
+
+	// The same path on another instance is a different project
+	cache := &resultCache{results: make(map[string]*output.ScanResult)}
+	cache.record(&output.ScanResult{Instance: "saas", ProjectPath: "org/api", PythonVersion: "3.11", CommitSHA: head})
+	if got := cache.reuse(context.Background(), &instance{name: "onprem", client: client}, project, scanner.VersionScanOptions{}, 1, 1); got != nil {
+		t.Errorf("reuse() on onprem = %+v, want saas's result left alone", got)
+	}
+	if got := cache.reuse(context.Background(), &instance{name: "saas", client: client}, project, scanner.VersionScanOptions{}, 1, 1); got == nil {
+		t.Error("reuse() on saas = nil, want its cached result")
+	}
+}
+
+func TestExplorer(t *testing.T) {
+	results := []*output.ScanResult{
+		{ProjectName: "api", ProjectPath: "org/api", PythonVersion: "3.11.4", DetectionSource: "pyproject.toml", Warnings: []string{"setup.cfg: bad"}},
+		{ProjectName: "web", ProjectPath: "org/web", PythonVersion: "3.12", DetectionSource: ".python-version"},
+		{ProjectName: "docs", ProjectPath: "org/docs"},
+		{ProjectName: "broken", ProjectPath: "org/broken", Error: fmt.Errorf("403 Forbidden")},
+	}
+
+	var buf bytes.Buffer
+	e := newExplorer(results, &buf)
+	if err := e.run(strings.NewReader("v 3.11\n1\nc\ne\nq\nv none\n")); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"4 of 4 projects\n",
+		"1 of 4 projects [version 3.11]\n",
+		"  Warning:         setup.cfg: bad\n",
+		"1 of 4 projects [errors]\n",
+		"403 Forbidden",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	// "q" stops before the remaining commands
+	if strings.Contains(out, "[version none]") {
+		t.Errorf("commands after q were run:\n%s", out)
+	}
+
+	e.handle("c")
+	e.handle("v none")
+	if len(e.shown) != 2 {
+		t.Errorf("version none shows %d results, want the undetected and errored projects", len(e.shown))
+	}
+}
+
+func TestConfigHidesResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+		result *output.ScanResult
+		want   bool
+	}{
+		{"no filters", &Config{}, &output.ScanResult{PythonVersion: "3.11"}, false},
+		{"conforming to baseline", &Config{}, &output.ScanResult{PythonVersion: "3.11", Drift: output.DriftConforming}, true},
+		{"drifted from baseline", &Config{}, &output.ScanResult{PythonVersion: "3.9", Drift: output.DriftMismatch}, false},
+		{"untracked", &Config{}, &output.ScanResult{PythonVersion: "3.12", Drift: output.DriftUntracked}, false},
+		{"approved", &Config{OnlyNonApproved: true, ApprovedVersions: []string{"3.11"}}, &output.ScanResult{PythonVersion: "3.11.2"}, true},
+		{"non-approved", &Config{OnlyNonApproved: true, ApprovedVersions: []string{"3.11"}}, &output.ScanResult{PythonVersion: "3.9"}, false},
+		{"undetected with only non-approved", &Config{OnlyNonApproved: true, ApprovedVersions: []string{"3.11"}}, &output.ScanResult{}, true},
+		{"python 3 with only python2", &Config{OnlyPython2: true}, &output.ScanResult{PythonVersion: "3.11"}, true},
+		{"python 2 with only python2", &Config{OnlyPython2: true}, &output.ScanResult{PythonVersion: "2.7", IsPython2: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.hidesResult(tt.result); got != tt.want {
+				t.Errorf("hidesResult() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeRulesJSON(t *testing.T) {
+	registry := parsers.DefaultRegistry()
+
+	var buf bytes.Buffer
+	if err := describeRules(&buf, registry, "json"); err != nil {
+		t.Fatalf("describeRules() error = %v", err)
+	}
+
+	var manifest ruleManifest
+	if err := json.Unmarshal(buf.Bytes(), &manifest); err != nil {
+		t.Fatalf("manifest is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if manifest.ManifestVersion != ruleManifestVersion {
+		t.Errorf("ManifestVersion = %d, want %d", manifest.ManifestVersion, ruleManifestVersion)
+	}
+	if len(manifest.Rules) != registry.Count() {
+		t.Fatalf("got %d rules, want %d", len(manifest.Rules), registry.Count())
+	}
+
+	byName := make(map[string]ruleDescription)
+	for i, r := range manifest.Rules {
+		byName[r.Name] = r
+		if i > 0 && r.Priority < manifest.Rules[i-1].Priority {
+			t.Errorf("rule %s (priority %d) listed after priority %d", r.Name, r.Priority, manifest.Rules[i-1].Priority)
+		}
+	}
+
+	pv := byName["python-version-file"]
+	if pv.FilePattern != ".python-version" || pv.Confidence == nil || *pv.Confidence != 1.0 {
+		t.Errorf("python-version-file = %+v, want .python-version at confidence 1.0", pv)
+	}
+	if rt := byName["runtime-txt"]; rt.RequiredContent == "" {
+		t.Error("runtime-txt has no required_content, want its regex")
+	}
+	if ci := byName["gitlab-ci"]; ci.Confidence != nil {
+		t.Errorf("gitlab-ci confidence = %v, want null since it varies by match", *ci.Confidence)
+	}
+}
+
+func TestDescribeRulesConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scanner.yaml")
+	if err := os.WriteFile(path, []byte("settings:\n  confidence_overrides:\n    gitlab-ci: 0.7\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	registry, err := loadScanRegistry(path, nil)
+	if err != nil {
+		t.Fatalf("loadScanRegistry() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := describeRules(&buf, registry, "json"); err != nil {
+		t.Fatalf("describeRules() error = %v", err)
+	}
+	var manifest ruleManifest
+	if err := json.Unmarshal(buf.Bytes(), &manifest); err != nil {
+		t.Fatalf("manifest is not valid JSON: %v", err)
+	}
+	for _, r := range manifest.Rules {
+		if r.Name == "gitlab-ci" && (r.Confidence == nil || *r.Confidence != 0.7) {
+			t.Errorf("gitlab-ci confidence = %v, want the 0.7 override", r.Confidence)
+		}
+	}
+}
+
+func TestDescribeRulesText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := describeRules(&buf, parsers.DefaultRegistry(), "text"); err != nil {
+		t.Fatalf("describeRules() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"PRIORITY", "python-version-file", "varies"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := describeRules(&buf, parsers.DefaultRegistry(), "yaml"); err == nil {
+		t.Error("describeRules() with an unknown format succeeded, want an error")
 	}
 }
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/scanner"
+)
+
+// --fail-on conditions of --mode mr
+const (
+	failOnVersionChange = "version-change"
+	failOnMatch         = "match"
+)
+
+// mergeRequestIID returns the merge request CI_MERGE_REQUEST_IID names in a
+// merge request pipeline, or 0 outside one
+func mergeRequestIID() int {
+	iid, err := strconv.Atoi(os.Getenv("CI_MERGE_REQUEST_IID"))
+	if err != nil {
+		return 0
+	}
+	return iid
+}
+
+// validateMergeRequestConfig checks the flags --mode mr uses
+func validateMergeRequestConfig(config *SearchConfig) error {
+	if config.GitLabURL == "" {
+		return fmt.Errorf("--url is required")
+	}
+	if config.Token == "" {
+		return fmt.Errorf("--token is required (or set GITLAB_TOKEN environment variable)")
+	}
+	if config.Project == "" {
+		return fmt.Errorf("--mode mr requires --project (or CI_PROJECT_PATH)")
+	}
+	if config.MergeRequest <= 0 {
+		return fmt.Errorf("--mode mr requires --merge-request (or CI_MERGE_REQUEST_IID)")
+	}
+	if config.MatchFilesOnly || len(config.InFiles) > 0 || config.SearchWikis {
+		return fmt.Errorf("--mode mr searches the lines a merge request adds and can't be combined with --match-files-only, --in-file, or --search-wikis")
+	}
+	if len(config.LogFiles) > 0 || config.SQLitePath != "" {
+		return fmt.Errorf("--mode mr reports to the console and can't be combined with --log or --sqlite")
+	}
+	if config.Watch != 0 {
+		return fmt.Errorf("--watch can't be combined with --mode mr")
+	}
+	switch config.FailOn {
+	case "", failOnVersionChange:
+	case failOnMatch:
+		if config.SearchTerm == "" {
+			return fmt.Errorf("--fail-on %s requires --search", failOnMatch)
+		}
+	default:
+		return fmt.Errorf("--fail-on must be %q or %q with --mode mr, got %q", failOnVersionChange, failOnMatch, config.FailOn)
+	}
+	return nil
+}
+
+// runMergeRequestMode checks one merge request's proposed changes, for
+// merge request pipelines
+func runMergeRequestMode(config *SearchConfig) {
+	if err := validateMergeRequestConfig(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	trace, closeTrace, err := openTrace(config.TracePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer closeTrace()
+
+	client, _, err := createClient(config.clientOptions(trace))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to connect to GitLab: %v\n", err)
+		os.Exit(1)
+	}
+	registry, err := loadScanRegistry(config.ConfigFile, config.DisabledTags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := checkMergeRequest(context.Background(), client, registry, config, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// checkMergeRequest reports the Python version changes config's merge
+// request makes and, with --search, the matches in the lines it adds. It
+// returns an error if the --fail-on condition is hit.
+func checkMergeRequest(ctx context.Context, client *gitlab.Client, registry *rules.Registry, config *SearchConfig, w io.Writer) error {
+	mr, err := client.GetMergeRequestChanges(ctx, config.Project, config.MergeRequest)
+	if err != nil {
+		return fmt.Errorf("failed to get merge request !%d: %w", config.MergeRequest, err)
+	}
+
+	fmt.Fprintf(w, "Merge request !%d: %s\n", mr.IID, mr.Title)
+	fmt.Fprintf(w, "  %s -> %s, %d files changed\n", mr.SourceBranch, mr.TargetBranch, len(mr.Changes))
+	if mr.Overflow {
+		fmt.Fprintln(w, "  Warning: GitLab listed only some of the changed files; the rest weren't checked")
+	}
+
+	changes, err := scanner.MergeRequestVersionChanges(ctx, client, registry, config.Project, mr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nPython version changes: %d\n", len(changes))
+	for _, change := range changes {
+		fmt.Fprintf(w, "  %s: %s -> %s\n", change.Path, versionOrNone(change.Before), versionOrNone(change.After))
+	}
+
+	var matches []output.ContentMatchEntry
+	if config.SearchTerm != "" {
+		var skipped []string
+		matches, skipped, err = newContentScanner(client, config).SearchAddedLines(mr)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\nMatches for %q in added lines: %d\n", config.SearchTerm, len(matches))
+		for _, match := range matches {
+			fmt.Fprintf(w, "  %s:%d: %s\n", match.FilePath, match.LineNumber, match.LineContent)
+		}
+		for _, path := range skipped {
+			fmt.Fprintf(w, "  Not searched (diff too large): %s\n", path)
+		}
+	}
+
+	switch {
+	case config.FailOn == failOnVersionChange && len(changes) > 0:
+		return fmt.Errorf("merge request !%d changes the Python version in %d files (--fail-on %s)", mr.IID, len(changes), failOnVersionChange)
+	case config.FailOn == failOnMatch && len(matches) > 0:
+		return fmt.Errorf("merge request !%d adds %d lines matching %q (--fail-on %s)", mr.IID, len(matches), config.SearchTerm, failOnMatch)
+	}
+	return nil
+}
+
+// versionOrNone renders a version for a change, where "" means the file
+// declared none
+func versionOrNone(version string) string {
+	if version == "" {
+		return "(none)"
+	}
+	return version
+}
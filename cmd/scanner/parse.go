@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/config"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/parsers"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+)
+
+// ParseConfig holds the configuration for the parse subcommand
+type ParseConfig struct {
+	FilePath   string
+	ConfigFile string
+}
+
+// runParseMode parses a local file against the rule registry without contacting GitLab
+func runParseMode(args []string) {
+	parseConfig, err := parseParseFlags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	registry, err := loadParseRegistry(parseConfig.ConfigFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := parseLocalFile(os.Stdout, registry, parseConfig.FilePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Parse failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func parseParseFlags(args []string) (*ParseConfig, error) {
+	config := &ParseConfig{}
+
+	fs := flag.NewFlagSet("parse", flag.ExitOnError)
+	fs.StringVar(&config.ConfigFile, "config", "", "Path to YAML/JSON config file with rule definitions (default: built-in rules)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s parse [options] <file>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Run detection rules against a local file without contacting GitLab.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s parse pyproject.toml\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s parse --config my-rules.yaml Dockerfile\n", os.Args[0])
+	}
+
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return nil, fmt.Errorf("exactly one file path is required")
+	}
+	config.FilePath = fs.Arg(0)
+
+	return config, nil
+}
+
+// loadParseRegistry returns the built-in registry, or the rules from a config file if one is given
+func loadParseRegistry(configFile string) (*rules.Registry, error) {
+	if configFile == "" {
+		return parsers.DefaultRegistry(), nil
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Rules) == 0 {
+		return nil, fmt.Errorf("config file contains no rule definitions")
+	}
+
+	registry, err := cfg.ToRegistry(config.NewDefaultParserRegistry())
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.ApplyConfidenceOverrides(registry); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// parseLocalFile applies every rule matching the file to its content and
+// writes each rule's outcome followed by the best match
+func parseLocalFile(w io.Writer, registry *rules.Registry, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	filename := filepath.Base(path)
+	matchingRules := registry.FindMatchingRules(filename, path)
+
+	fmt.Fprintf(w, "Parsing: %s (%d bytes)\n", path, len(content))
+	fmt.Fprintf(w, "Matching rules: %d\n\n", len(matchingRules))
+
+	if len(matchingRules) == 0 {
+		fmt.Fprintf(w, "No enabled rules match %q\n", filename)
+		return nil
+	}
+
+	var best *rules.SearchResult
+	var bestRule *rules.SearchRule
+
+	for _, rule := range matchingRules {
+		result, err := rule.Apply(context.Background(), content, filename)
+		if err != nil {
+			fmt.Fprintf(w, "  %s (priority %d): error - %v\n", rule.Name, rule.Priority, err)
+			continue
+		}
+
+		if result == nil || !result.Found {
+			fmt.Fprintf(w, "  %s (priority %d): no match\n", rule.Name, rule.Priority)
+			continue
+		}
+
+		if !result.HasVersion() {
+			// e.g. requirements.txt with dependencies but no version comment
+			fmt.Fprintf(w, "  %s (priority %d): matched, but no Python version\n", rule.Name, rule.Priority)
+			continue
+		}
+
+		fmt.Fprintf(w, "  %s (priority %d): Python %s (confidence %.2f)\n",
+			rule.Name, rule.Priority, result.Version, result.Confidence)
+		if result.RawValue != "" {
+			fmt.Fprintf(w, "    raw value: %q\n", result.RawValue)
+		}
+
+		// Same selection as Registry.Execute: highest confidence, ties go to higher priority
+		if best == nil || result.Confidence > best.Confidence {
+			best = result
+			bestRule = rule
+		}
+	}
+
+	fmt.Fprintln(w)
+	if best == nil {
+		fmt.Fprintln(w, "Best match: none")
+		return nil
+	}
+
+	fmt.Fprintf(w, "Best match: Python %s from %s via %s (confidence %.2f)\n",
+		best.Version, best.Source, bestRule.Name, best.Confidence)
+	return nil
+}
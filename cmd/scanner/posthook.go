@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+)
+
+// postHook runs the --post-hook command once a scan's outputs are written.
+// It collects the run's JSON log in a temporary file, which the command
+// reads on stdin.
+type postHook struct {
+	command string
+	timeout time.Duration
+
+	reportPath string
+	report     *output.FileLogger
+}
+
+func newPostHook(command string, timeout time.Duration) (*postHook, error) {
+	file, err := os.CreateTemp("", "gitlab-seeker-report-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create post-hook report: %w", err)
+	}
+	file.Close()
+
+	report, err := output.NewFileLogger(file.Name(), output.FormatJSON)
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, fmt.Errorf("failed to create post-hook report: %w", err)
+	}
+	return &postHook{command: command, timeout: timeout, reportPath: file.Name(), report: report}, nil
+}
+
+// run executes the command through sh with the report on stdin and the
+// outcome in the environment (see postHookEnv). The command is killed if it
+// runs longer than the hook's timeout.
+func (h *postHook) run(config *Config, stats *output.ScanStatistics, scanErr error) error {
+	if err := h.report.Close(); err != nil {
+		return fmt.Errorf("failed to write post-hook report: %w", err)
+	}
+	report, err := os.Open(h.reportPath)
+	if err != nil {
+		return fmt.Errorf("failed to read post-hook report: %w", err)
+	}
+	defer report.Close()
+
+	// The hook still runs when the scan fails, so it isn't tied to the scan's context
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.command)
+	cmd.Stdin = report
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), postHookEnv(config, stats, scanErr)...)
+	killGroupOnCancel(cmd)
+	// Don't wait forever on anything that keeps stdout open after a kill
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("post-hook timed out after %v", h.timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("post-hook failed: %w", err)
+	}
+	return nil
+}
+
+// Close removes the report file
+func (h *postHook) Close() {
+	h.report.Close()
+	os.Remove(h.reportPath)
+}
+
+// postHookEnv returns the SEEKER_* variables describing a finished scan.
+// SEEKER_STATUS is "fail" (and SEEKER_EXIT_CODE 1) when the scanner will
+// exit non-zero, e.g. on --fail-on, with the reason in SEEKER_FAILURE.
+func postHookEnv(config *Config, stats *output.ScanStatistics, scanErr error) []string {
+	status, exitCode, failure := "pass", 0, ""
+	if scanErr != nil {
+		status, exitCode, failure = "fail", 1, scanErr.Error()
+	}
+
+	return []string{
+		"SEEKER_STATUS=" + status,
+		"SEEKER_EXIT_CODE=" + strconv.Itoa(exitCode),
+		"SEEKER_FAILURE=" + failure,
+		"SEEKER_GITLAB_URL=" + config.GitLabURL,
+		"SEEKER_RUN_ID=" + stats.RunID,
+		"SEEKER_LOG_FILES=" + strings.Join(config.LogFiles, ","),
+		"SEEKER_TOTAL=" + strconv.Itoa(stats.TotalProjects),
+		"SEEKER_PYTHON=" + strconv.Itoa(stats.PythonProjects),
+		"SEEKER_UNDETECTED=" + strconv.Itoa(stats.NonPythonProjects),
+		"SEEKER_ERRORS=" + strconv.Itoa(stats.ErrorCount),
+		"SEEKER_PARSE_ERRORS=" + strconv.Itoa(stats.ParseErrorCount),
+	}
+}
+
+// scanWithHook runs scanOnce and then, with --post-hook, the hook command.
+// An interrupted scan skips the hook. A hook failure fails an otherwise
+// successful scan; after a failed scan it's only reported as a warning.
+func scanWithHook(ctx context.Context, instances []*instance, config *Config, streamer *output.ConsoleStreamer, sinks []output.ResultSink, stats *output.ScanStatistics, state *runState) error {
+	if config.PostHook == "" {
+		return scanOnce(ctx, instances, config, streamer, sinks, stats, state)
+	}
+
+	hook, err := newPostHook(config.PostHook, config.PostHookTimeout)
+	if err != nil {
+		return err
+	}
+	defer hook.Close()
+
+	runSinks := append(sinks[:len(sinks):len(sinks)], hook.report)
+	err = scanOnce(ctx, instances, config, streamer, runSinks, stats, state)
+	if ctx.Err() != nil {
+		return err
+	}
+
+	if hookErr := hook.run(config, stats, err); hookErr != nil {
+		if err == nil {
+			return hookErr
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", hookErr)
+	}
+	return err
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+import "os/exec"
+
+// killGroupOnCancel leaves cmd's default cancellation, which kills only the
+// shell; WaitDelay still bounds the wait for anything it started
+func killGroupOnCancel(cmd *exec.Cmd) {}
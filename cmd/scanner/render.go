@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+)
+
+// runRenderMode re-emits a previous scan's JSON log through the usual
+// outputs without contacting GitLab
+func runRenderMode(config *SearchConfig) {
+	if err := validateRenderConfig(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	scanLog, err := output.ReadScanLogFile(config.InputLog)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", config.InputLog, err)
+		os.Exit(1)
+	}
+
+	if err := renderScanLog(config, scanLog); err != nil {
+		fmt.Fprintf(os.Stderr, "Render failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// renderScanLog writes scanLog to the console and any --log/--sqlite outputs.
+// Policy flags given on the command line replace the ones the log was written with.
+func renderScanLog(config *SearchConfig, scanLog *output.ScanLog) error {
+	streamer := output.NewConsoleStreamer()
+	streamer.Verbose = config.Verbose
+	var sinks []output.ResultSink
+	if !config.ListVersions {
+		sinks = append(sinks, streamer)
+	}
+	if len(config.LogFiles) > 0 {
+		logger, err := output.OpenMultiLogger(config.LogFiles)
+		if err != nil {
+			return fmt.Errorf("failed to create log file: %w", err)
+		}
+		defer logger.Close()
+		sinks = append(sinks, logger)
+	}
+	if config.SQLitePath != "" {
+		db, err := output.NewSQLiteSink(config.SQLitePath)
+		if err != nil {
+			return fmt.Errorf("failed to open sqlite database: %w", err)
+		}
+		defer db.Close()
+		// A --watch log's run keeps its ID
+		db.SetRunID(scanLog.Summary.RunID)
+		sinks = append(sinks, db)
+	}
+
+	if len(config.ApprovedVersions) > 0 {
+		scanLog.Summary.ApprovedVersions = config.ApprovedVersions
+	}
+	if config.TargetVersion != "" {
+		scanLog.Summary.TargetVersion = config.TargetVersion
+	}
+	if config.AtRiskBelow != "" {
+		scanLog.Summary.AtRiskBelow = config.AtRiskBelow
+	}
+	if config.RequireExplicit {
+		scanLog.Summary.RequireExplicit = true
+	}
+	if config.OrgSummary {
+		scanLog.Summary.OrgSummary = true
+	}
+	stats := scanLog.Statistics(output.Normalization(config.Normalize))
+	approved := stats.ApprovedVersions
+	if config.OnlyNonApproved && len(approved) == 0 {
+		return fmt.Errorf("--only-non-approved requires --approved-versions (the log has none)")
+	}
+
+	for _, sink := range sinks {
+		if err := sink.WriteHeader(scanLog.GitLabURL, len(scanLog.Results)); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+	}
+
+	for _, result := range scanLog.Results {
+		if config.OnlyNonApproved && (result.PythonVersion == "" || output.IsApproved(result.PythonVersion, approved)) {
+			continue
+		}
+		if config.OnlyPython2 && !result.IsPython2 {
+			continue
+		}
+		for _, sink := range sinks {
+			if err := sink.WriteResult(result); err != nil {
+				warn(stats, "failed to write result for %s: %v", result.ProjectPath, err)
+			}
+		}
+	}
+
+	for _, sink := range sinks {
+		if err := sink.WriteSummary(stats); err != nil {
+			return fmt.Errorf("failed to write summary: %w", err)
+		}
+	}
+
+	if config.SummaryLine {
+		if err := streamer.PrintSummaryLine(stats); err != nil {
+			return fmt.Errorf("failed to print summary line: %w", err)
+		}
+	}
+	if config.ListVersions {
+		if err := streamer.PrintVersionList(stats, config.WithCounts, config.IncludeUndetected); err != nil {
+			return fmt.Errorf("failed to print version list: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateRenderConfig rejects flags that only make sense against a live GitLab
+func validateRenderConfig(config *SearchConfig) error {
+	if config.SearchTerm != "" || config.ConfigFile != "" || config.MatchFilesOnly {
+		return fmt.Errorf("--input-log re-renders a Python version scan and can't be combined with a content search")
+	}
+	if config.Watch != 0 {
+		return fmt.Errorf("--watch can't be combined with --input-log")
+	}
+	if config.DepReportPath != "" {
+		return fmt.Errorf("--dep-report can't be combined with --input-log (the log has no dependency data)")
+	}
+	if config.BaselinePath != "" {
+		return fmt.Errorf("--baseline can't be combined with --input-log")
+	}
+	if config.DecayHalfLife != 0 {
+		return fmt.Errorf("--decay-confidence can't be combined with --input-log")
+	}
+	if config.Strict || config.FailOn != "" {
+		return fmt.Errorf("--strict can't be combined with --input-log")
+	}
+	if config.ExcludeForks || config.ForksOnly {
+		return fmt.Errorf("--exclude-forks and --forks-only can't be combined with --input-log (the log doesn't record forks)")
+	}
+	if config.Topic != "" {
+		return fmt.Errorf("--topic can't be combined with --input-log (the log doesn't record topics)")
+	}
+	if len(config.IgnorePaths) > 0 {
+		return fmt.Errorf("--ignore-path can't be combined with --input-log")
+	}
+	if config.IgnoreFile {
+		return fmt.Errorf("--ignore-file can't be combined with --input-log")
+	}
+	if config.Explain {
+		return fmt.Errorf("--explain can't be combined with --input-log (explanations recorded in the log are shown anyway)")
+	}
+	if config.Authoritative {
+		return fmt.Errorf("--authoritative can't be combined with --input-log")
+	}
+	if config.Benchmark {
+		return fmt.Errorf("--benchmark can't be combined with --input-log")
+	}
+	if config.Bucketed {
+		return fmt.Errorf("--bucketed can't be combined with --input-log")
+	}
+	if config.PostHook != "" {
+		return fmt.Errorf("--post-hook can't be combined with --input-log")
+	}
+	if config.Incremental != "" {
+		return fmt.Errorf("--incremental can't be combined with --input-log")
+	}
+	if config.Commit != "" {
+		return fmt.Errorf("--commit can't be combined with --input-log")
+	}
+	if err := validateListVersions(config.ListVersions, config.WithCounts, config.IncludeUndetected, config.SummaryLine); err != nil {
+		return err
+	}
+	if _, err := output.ParseNormalization(config.Normalize); err != nil {
+		return fmt.Errorf("--normalize: %w", err)
+	}
+	if config.TargetVersion != "" {
+		if _, err := output.ParseVersion(config.TargetVersion); err != nil {
+			return fmt.Errorf("--target-version: %w", err)
+		}
+	}
+	if config.AtRiskBelow != "" {
+		if _, err := output.ParseVersion(config.AtRiskBelow); err != nil {
+			return fmt.Errorf("--at-risk-below: %w", err)
+		}
+	}
+	return nil
+}
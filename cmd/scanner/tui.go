@@ -0,0 +1,274 @@
+//go:build tui
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+)
+
+// tuiVersionWidth is the width of the table's version column
+const tuiVersionWidth = 8
+
+// tuiHelp is the key reference shown on the status line
+const tuiHelp = "↑/↓ move  enter details  v version  s source  / search  e errors  c clear  q quit"
+
+// runTUIMode loads a JSON scan log and shows it as a full-screen table that
+// can be filtered and searched, with a detail pane for the selected result.
+// It's only built with -tags tui, so default builds carry no terminal
+// library; browse offers the same filters without one.
+func runTUIMode(args []string) {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s tui <scan-log.json>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Explore a scan's JSON log (written with --log results.json) in a full-screen table:\n")
+		fmt.Fprintf(os.Stderr, "filter by version, source, or errors, search projects, and open a result's details.\n")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	scanLog, err := output.ReadScanLogFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", fs.Arg(0), err)
+		os.Exit(1)
+	}
+
+	screen, err := tcell.NewScreen()
+	if err == nil {
+		err = screen.Init()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error starting the terminal UI: %v (browse works without a terminal)\n", err)
+		os.Exit(1)
+	}
+	defer screen.Fini()
+
+	newTUI(screen, scanLog.Results).run()
+}
+
+// tui is the state of the full-screen results table
+type tui struct {
+	screen tcell.Screen
+	e      *explorer // Filters and the results passing them
+
+	cursor int  // Index of the selected result in e.shown
+	top    int  // Index of the first result on screen
+	detail bool // Whether the detail pane is open
+
+	// A filter being typed; prompt is "" when the user isn't typing
+	prompt string
+	input  []rune
+	apply  func(string)
+}
+
+func newTUI(screen tcell.Screen, results []*output.ScanResult) *tui {
+	return &tui{screen: screen, e: newExplorer(results, io.Discard)}
+}
+
+// run draws the table and handles keys until the user quits
+func (t *tui) run() {
+	for {
+		t.draw()
+		switch ev := t.screen.PollEvent().(type) {
+		case nil:
+			return
+		case *tcell.EventResize:
+			t.screen.Sync()
+		case *tcell.EventKey:
+			if !t.handleKey(ev) {
+				return
+			}
+		}
+	}
+}
+
+// handleKey applies one key press, returning false when the user quits
+func (t *tui) handleKey(ev *tcell.EventKey) bool {
+	if t.prompt != "" {
+		t.handlePromptKey(ev)
+		return true
+	}
+
+	switch ev.Key() {
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		return false
+	case tcell.KeyUp:
+		t.move(-1)
+	case tcell.KeyDown:
+		t.move(1)
+	case tcell.KeyPgUp:
+		t.move(-t.tableHeight())
+	case tcell.KeyPgDn:
+		t.move(t.tableHeight())
+	case tcell.KeyHome:
+		t.move(-len(t.e.shown))
+	case tcell.KeyEnd:
+		t.move(len(t.e.shown))
+	case tcell.KeyEnter, tcell.KeyTab:
+		t.detail = !t.detail
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'q':
+			return false
+		case 'k':
+			t.move(-1)
+		case 'j':
+			t.move(1)
+		case 'v':
+			t.startPrompt("Version", t.e.version, func(s string) { t.e.version = s })
+		case 's':
+			t.startPrompt("Source", t.e.source, func(s string) { t.e.source = s })
+		case '/':
+			t.startPrompt("Search", t.e.query, func(s string) { t.e.query = s })
+		case 'e':
+			t.e.errorsOnly = !t.e.errorsOnly
+			t.filter()
+		case 'c':
+			t.e.version, t.e.source, t.e.query, t.e.errorsOnly = "", "", "", false
+			t.filter()
+		}
+	}
+	return true
+}
+
+// startPrompt begins typing a filter, starting from its current value
+func (t *tui) startPrompt(label, value string, apply func(string)) {
+	t.prompt, t.input, t.apply = label, []rune(value), apply
+}
+
+func (t *tui) handlePromptKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		t.prompt = ""
+	case tcell.KeyEnter:
+		t.apply(strings.TrimSpace(string(t.input)))
+		t.prompt = ""
+		t.filter()
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(t.input) > 0 {
+			t.input = t.input[:len(t.input)-1]
+		}
+	case tcell.KeyRune:
+		t.input = append(t.input, ev.Rune())
+	}
+}
+
+// filter reapplies the filters and selects the first result
+func (t *tui) filter() {
+	t.e.applyFilters()
+	t.cursor, t.top = 0, 0
+}
+
+// move moves the selection by delta rows, scrolling to keep it on screen
+func (t *tui) move(delta int) {
+	t.cursor = max(0, min(t.cursor+delta, len(t.e.shown)-1))
+	height := t.tableHeight()
+	switch {
+	case t.cursor < t.top:
+		t.top = t.cursor
+	case t.cursor >= t.top+height:
+		t.top = t.cursor - height + 1
+	}
+}
+
+// tableHeight is the number of result rows on screen: everything but the
+// title, column headings and status line, less the detail pane when open
+func (t *tui) tableHeight() int {
+	_, height := t.screen.Size()
+	rows := height - 3
+	if t.detail {
+		rows -= rows / 2
+	}
+	return max(rows, 1)
+}
+
+func (t *tui) draw() {
+	t.screen.Clear()
+	width, height := t.screen.Size()
+	bold := tcell.StyleDefault.Bold(true)
+
+	title := fmt.Sprintf("%d of %d projects%s", len(t.e.shown), len(t.e.results), t.e.filterSummary())
+	drawText(t.screen, 0, 0, width, bold, title)
+
+	projectWidth := len("PROJECT")
+	for _, r := range t.e.shown {
+		projectWidth = max(projectWidth, runewidth.StringWidth(r.ProjectPath))
+	}
+	projectWidth = min(projectWidth, width/2)
+	row := func(y int, style tcell.Style, project, version, source string) {
+		drawText(t.screen, 0, y, projectWidth, style, project)
+		drawText(t.screen, projectWidth+2, y, tuiVersionWidth, style, version)
+		drawText(t.screen, projectWidth+tuiVersionWidth+4, y, width-projectWidth-tuiVersionWidth-4, style, source)
+	}
+	row(1, bold, "PROJECT", "VERSION", "SOURCE")
+
+	tableHeight := t.tableHeight()
+	for i := 0; i < tableHeight && t.top+i < len(t.e.shown); i++ {
+		r := t.e.shown[t.top+i]
+		style := tcell.StyleDefault
+		if t.top+i == t.cursor {
+			style = style.Reverse(true)
+			drawText(t.screen, 0, 2+i, width, style, "")
+		}
+		version, source := resultColumns(r)
+		row(2+i, style, r.ProjectPath, version, strings.ReplaceAll(source, "\n", " "))
+	}
+
+	if t.detail && t.cursor < len(t.e.shown) {
+		paneTop := 2 + tableHeight
+		drawText(t.screen, 0, paneTop, width, bold, strings.Repeat("─", width))
+		var buf bytes.Buffer
+		writeDetail(&buf, t.e.shown[t.cursor])
+		lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+		for i, line := range lines {
+			if paneTop+1+i >= height-1 {
+				break
+			}
+			drawText(t.screen, 0, paneTop+1+i, width, tcell.StyleDefault, line)
+		}
+	}
+
+	status := tuiHelp
+	if t.prompt != "" {
+		status = t.prompt + ": " + string(t.input)
+	}
+	drawText(t.screen, 0, height-1, width, bold, status)
+	if t.prompt != "" {
+		t.screen.ShowCursor(runewidth.StringWidth(status), height-1)
+	} else {
+		t.screen.HideCursor()
+	}
+	t.screen.Show()
+}
+
+// drawText writes text at x, y, cut to width columns; the rest of the width
+// is filled with style so a selected row is highlighted end to end
+func drawText(screen tcell.Screen, x, y, width int, style tcell.Style, text string) {
+	end := x + width
+	for _, r := range text {
+		w := runewidth.RuneWidth(r)
+		if x+w > end {
+			break
+		}
+		if w == 0 {
+			continue
+		}
+		screen.SetContent(x, y, r, nil, style)
+		x += w
+	}
+	for ; x < end; x++ {
+		screen.SetContent(x, y, ' ', nil, style)
+	}
+}
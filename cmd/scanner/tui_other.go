@@ -0,0 +1,15 @@
+//go:build !tui
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// runTUIMode reports that this build has no terminal UI; it's built with
+// -tags tui so default builds carry no terminal library
+func runTUIMode(args []string) {
+	fmt.Fprintf(os.Stderr, "Error: this build has no terminal UI; rebuild with `go build -tags tui ./cmd/scanner`, or use %s browse\n", os.Args[0])
+	os.Exit(1)
+}
@@ -0,0 +1,91 @@
+//go:build tui
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+)
+
+// screenText returns the simulated screen's rows as text
+func screenText(t *testing.T, screen tcell.SimulationScreen) string {
+	t.Helper()
+	cells, width, _ := screen.GetContents()
+	var b strings.Builder
+	for i, cell := range cells {
+		if len(cell.Runes) > 0 {
+			b.WriteRune(cell.Runes[0])
+		}
+		if (i+1)%width == 0 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+func TestTUI(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(100, 20)
+
+	ui := newTUI(screen, []*output.ScanResult{
+		{ProjectPath: "org/api", ProjectName: "api", PythonVersion: "3.11.4", DetectionSource: ".python-version", RawValue: "3.11.4"},
+		{ProjectPath: "org/billing", ProjectName: "billing", PythonVersion: "3.9", DetectionSource: "pyproject.toml"},
+		{ProjectPath: "org/docs", ProjectName: "docs"},
+	})
+	key := func(k tcell.Key, r rune) {
+		ui.handleKey(tcell.NewEventKey(k, r, tcell.ModNone))
+		ui.draw()
+	}
+	typeText := func(s string) {
+		for _, r := range s {
+			key(tcell.KeyRune, r)
+		}
+	}
+
+	ui.draw()
+	if text := screenText(t, screen); !strings.Contains(text, "3 of 3 projects") || !strings.Contains(text, "org/billing") {
+		t.Fatalf("initial screen missing rows:\n%s", text)
+	}
+
+	// Filtering by version through the prompt
+	key(tcell.KeyRune, 'v')
+	typeText("3.11")
+	if text := screenText(t, screen); !strings.Contains(text, "Version: 3.11") {
+		t.Errorf("prompt not shown:\n%s", text)
+	}
+	key(tcell.KeyEnter, 0)
+	text := screenText(t, screen)
+	if !strings.Contains(text, "1 of 3 projects [version 3.11]") || strings.Contains(text, "org/billing") {
+		t.Errorf("version filter not applied:\n%s", text)
+	}
+
+	// The detail pane shows the selected result's fields
+	key(tcell.KeyEnter, 0)
+	if text := screenText(t, screen); !strings.Contains(text, "Raw value:") || !strings.Contains(text, "org/api (api)") {
+		t.Errorf("detail pane not shown:\n%s", text)
+	}
+
+	// Clearing the filters brings every project back
+	key(tcell.KeyRune, 'c')
+	key(tcell.KeyDown, 0)
+	key(tcell.KeyDown, 0)
+	if ui.cursor != 2 || ui.e.shown[ui.cursor].ProjectPath != "org/docs" {
+		t.Errorf("cursor = %d, want org/docs selected", ui.cursor)
+	}
+	key(tcell.KeyDown, 0)
+	if ui.cursor != 2 {
+		t.Errorf("cursor moved past the last result to %d", ui.cursor)
+	}
+
+	if ui.handleKey(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone)) {
+		t.Error("q did not quit")
+	}
+}
@@ -2,11 +2,15 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/parsers"
 	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
 	"gopkg.in/yaml.v3"
 )
@@ -40,6 +44,9 @@ type MatchConfig struct {
 	// FilePattern is a glob pattern to match filenames
 	FilePattern string `yaml:"file_pattern,omitempty" json:"file_pattern,omitempty"`
 
+	// CaseInsensitive matches FilePattern regardless of case
+	CaseInsensitive bool `yaml:"case_insensitive,omitempty" json:"case_insensitive,omitempty"`
+
 	// PathPattern is a regex to match file paths
 	PathPattern string `yaml:"path_pattern,omitempty" json:"path_pattern,omitempty"`
 
@@ -68,20 +75,39 @@ type SearchConfigEntry struct {
 	// Description provides human-readable information
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
 
+	// Severity is a free-form triage label (e.g. "high") recorded on every
+	// match this search produces
+	Severity string `yaml:"severity,omitempty" json:"severity,omitempty"`
+
 	// SearchTerm is the string or regex pattern to search for
 	SearchTerm string `yaml:"search_term" json:"search_term"`
 
 	// IsRegex indicates whether SearchTerm is a regex pattern
 	IsRegex bool `yaml:"is_regex,omitempty" json:"is_regex,omitempty"`
 
-	// CaseSensitive enables case-sensitive matching
-	CaseSensitive bool `yaml:"case_sensitive,omitempty" json:"case_sensitive,omitempty"`
+	// Prefilter is a literal every regex match contains; files without it
+	// are skipped before regex matching
+	Prefilter string `yaml:"prefilter,omitempty" json:"prefilter,omitempty"`
+
+	// VersionFilter reports only matches whose captured version satisfies
+	// this comparison, e.g. "<4.0" to find outdated pins (requires is_regex)
+	VersionFilter string `yaml:"version_filter,omitempty" json:"version_filter,omitempty"`
+
+	// VersionGroup names the capture group holding the version for
+	// VersionFilter; empty means the first capture group
+	VersionGroup string `yaml:"version_group,omitempty" json:"version_group,omitempty"`
+
+	// CaseSensitive enables case-sensitive matching. Unset (nil) entries can
+	// take the command line's value with --config-search-defaults.
+	CaseSensitive *bool `yaml:"case_sensitive,omitempty" json:"case_sensitive,omitempty"`
 
-	// FilePatterns restricts search to files matching these glob patterns
+	// FilePatterns restricts search to files matching these glob patterns;
+	// patterns prefixed with "!" exclude matching files instead
 	FilePatterns []string `yaml:"file_patterns,omitempty" json:"file_patterns,omitempty"`
 
-	// ContextLines is the number of context lines around each match
-	ContextLines int `yaml:"context_lines,omitempty" json:"context_lines,omitempty"`
+	// ContextLines is the number of context lines around each match. Like
+	// CaseSensitive, nil means unset so that an explicit 0 can be told apart.
+	ContextLines *int `yaml:"context_lines,omitempty" json:"context_lines,omitempty"`
 
 	// MaxMatches limits the number of matches per project (0 = unlimited)
 	MaxMatches int `yaml:"max_matches,omitempty" json:"max_matches,omitempty"`
@@ -101,6 +127,10 @@ type Config struct {
 	// Searches defines content search configurations
 	Searches []SearchConfigEntry `yaml:"searches,omitempty" json:"searches,omitempty"`
 
+	// Instances lists the GitLab instances one scan covers, each with its
+	// own token, in place of --url
+	Instances []InstanceConfig `yaml:"instances,omitempty" json:"instances,omitempty"`
+
 	// Settings contains global configuration
 	Settings SettingsConfig `yaml:"settings,omitempty" json:"settings,omitempty"`
 }
@@ -112,6 +142,18 @@ type SettingsConfig struct {
 
 	// DefaultPriority sets the default priority for rules
 	DefaultPriority int `yaml:"default_priority,omitempty" json:"default_priority,omitempty"`
+
+	// ExplicitVersionFiles names extra .python-version-style pin files to
+	// read, in addition to the built-in ones (e.g. "python-version")
+	ExplicitVersionFiles []string `yaml:"explicit_version_files,omitempty" json:"explicit_version_files,omitempty"`
+
+	// ConfidenceOverrides replaces the confidence of every detection made by
+	// the named rules, e.g. {"requirements-txt-dependencies": 0.3}
+	ConfidenceOverrides map[string]float64 `yaml:"confidence_overrides,omitempty" json:"confidence_overrides,omitempty"`
+
+	// ProjectRefs maps project paths to the branch (or tag) to scan instead
+	// of their default branch, e.g. {"org/billing": "develop"}
+	ProjectRefs map[string]string `yaml:"project_refs,omitempty" json:"project_refs,omitempty"`
 }
 
 // LoadConfig loads a configuration file (YAML or JSON) from the given path
@@ -189,7 +231,9 @@ func SaveConfig(config *Config, path string) error {
 }
 
 // ToRegistry converts a Config into a rules.Registry
-// This allows loading rules from configuration files
+// This allows loading rules from configuration files.
+// Every rule is converted even after one fails, so the returned error lists
+// all invalid rules at once.
 func (c *Config) ToRegistry(parserRegistry ParserRegistry) (*rules.Registry, error) {
 	registry := rules.NewRegistry()
 
@@ -205,17 +249,23 @@ func (c *Config) ToRegistry(parserRegistry ParserRegistry) (*rules.Registry, err
 	}
 
 	// Convert each rule config to a SearchRule
+	var errs []error
 	for i, ruleConfig := range c.Rules {
 		rule, err := ruleConfig.ToSearchRule(parserRegistry, defaultEnabled, defaultPriority)
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert rule %d (%s): %w", i, ruleConfig.Name, err)
+			errs = append(errs, fmt.Errorf("rules[%d]: %w", i, err))
+			continue
 		}
 
 		if err := registry.Register(rule); err != nil {
-			return nil, fmt.Errorf("failed to register rule %s: %w", ruleConfig.Name, err)
+			errs = append(errs, fmt.Errorf("rules[%d]: failed to register rule %q: %w", i, ruleConfig.Name, err))
 		}
 	}
 
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%d of %d rules are invalid:\n%w", len(errs), len(c.Rules), errors.Join(errs...))
+	}
+
 	return registry, nil
 }
 
@@ -257,12 +307,21 @@ func (rc *RuleConfig) ToSearchRule(parserRegistry ParserRegistry, defaultEnabled
 	if rc.Match.FilePattern != "" {
 		builder.FilePattern(rc.Match.FilePattern)
 	}
+	builder.CaseInsensitive(rc.Match.CaseInsensitive)
 
+	// Patterns are checked here so the error names the config field and
+	// pattern rather than the builder's generic message
 	if rc.Match.PathPattern != "" {
+		if _, err := regexp.Compile(rc.Match.PathPattern); err != nil {
+			return nil, fmt.Errorf("rule %q: invalid path_pattern %q: %w", rc.Name, rc.Match.PathPattern, err)
+		}
 		builder.PathPattern(rc.Match.PathPattern)
 	}
 
 	if rc.Match.RequiredContent != "" {
+		if _, err := regexp.Compile(rc.Match.RequiredContent); err != nil {
+			return nil, fmt.Errorf("rule %q: invalid required_content %q: %w", rc.Name, rc.Match.RequiredContent, err)
+		}
 		builder.RequiredContent(rc.Match.RequiredContent)
 	}
 
@@ -273,12 +332,16 @@ func (rc *RuleConfig) ToSearchRule(parserRegistry ParserRegistry, defaultEnabled
 	// Get parser function from registry
 	parser, err := parserRegistry.GetParser(rc.Parser.Type, rc.Parser.Config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get parser: %w", err)
+		return nil, fmt.Errorf("rule %q: failed to get parser: %w", rc.Name, err)
 	}
 	builder.Parser(parser)
 
 	// Build and return
-	return builder.Build()
+	rule, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", rc.Name, err)
+	}
+	return rule, nil
 }
 
 // FromRegistry converts a rules.Registry to a Config
@@ -302,8 +365,9 @@ func FromRegistry(registry *rules.Registry) *Config {
 			Enabled:     &rule.Enabled,
 			Tags:        rule.Tags,
 			Match: MatchConfig{
-				FilePattern: rule.Condition.FilePattern,
-				MaxFileSize: rule.Condition.MaxFileSize,
+				FilePattern:     rule.Condition.FilePattern,
+				CaseInsensitive: rule.Condition.CaseInsensitive,
+				MaxFileSize:     rule.Condition.MaxFileSize,
 			},
 			// Note: Parser type and config cannot be easily reverse-engineered
 			// from the ParserFunc, so we leave it empty
@@ -327,19 +391,46 @@ func FromRegistry(registry *rules.Registry) *Config {
 	return config
 }
 
+// ApplyConfidenceOverrides sets the confidence of each rule named in
+// settings.confidence_overrides. An unknown rule name or a value outside
+// (0, 1] is an error, so a typo can't silently leave the default in place.
+func (c *Config) ApplyConfidenceOverrides(registry *rules.Registry) error {
+	names := make([]string, 0, len(c.Settings.ConfidenceOverrides))
+	for name := range c.Settings.ConfidenceOverrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		confidence := c.Settings.ConfidenceOverrides[name]
+		if confidence <= 0 || confidence > 1 {
+			return fmt.Errorf("confidence_overrides: %s: confidence must be greater than 0 and at most 1, got %v", name, confidence)
+		}
+		rule := registry.Get(name)
+		if rule == nil {
+			return fmt.Errorf("confidence_overrides: no rule named %q", name)
+		}
+		rule.Confidence = confidence
+	}
+	return nil
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	if c.Version == "" {
 		return fmt.Errorf("config version is required")
 	}
 
-	if len(c.Rules) == 0 && len(c.Searches) == 0 {
-		return fmt.Errorf("at least one rule or search is required")
+	if len(c.Rules) == 0 && len(c.Searches) == 0 && len(c.Instances) == 0 {
+		return fmt.Errorf("at least one rule, search, or instance is required")
 	}
 
 	if err := c.validateSearches(); err != nil {
 		return err
 	}
+	if err := c.ValidateInstances(); err != nil {
+		return err
+	}
 
 	return c.validateRules()
 }
@@ -357,15 +448,41 @@ func (c *Config) validateSearches() error {
 		if search.SearchTerm == "" {
 			return fmt.Errorf("search %s: search_term is required", search.Name)
 		}
+		var pattern *regexp.Regexp
 		if search.IsRegex {
-			if _, err := regexp.Compile(search.SearchTerm); err != nil {
+			var err error
+			if pattern, err = regexp.Compile(search.SearchTerm); err != nil {
 				return fmt.Errorf("search %s: invalid regex search_term: %w", search.Name, err)
 			}
 		}
+		if search.Prefilter != "" && !search.IsRegex {
+			return fmt.Errorf("search %s: prefilter requires is_regex", search.Name)
+		}
+		if err := validateVersionFilter(search, pattern); err != nil {
+			return fmt.Errorf("search %s: %w", search.Name, err)
+		}
 	}
 	return nil
 }
 
+// validateVersionFilter checks a search's version_filter and version_group;
+// pattern is its compiled search_term, nil for literal searches
+func validateVersionFilter(search SearchConfigEntry, pattern *regexp.Regexp) error {
+	if search.VersionFilter == "" {
+		if search.VersionGroup != "" {
+			return fmt.Errorf("version_group requires version_filter")
+		}
+		return nil
+	}
+	if pattern == nil {
+		return fmt.Errorf("version_filter requires is_regex")
+	}
+	if _, err := output.ParseVersionFilter(search.VersionFilter); err != nil {
+		return err
+	}
+	return parsers.CheckVersionGroup(pattern, search.VersionGroup)
+}
+
 func (c *Config) validateRules() error {
 	if len(c.Rules) == 0 {
 		return nil
@@ -386,12 +503,12 @@ func (c *Config) validateRules() error {
 		}
 		if rule.Match.PathPattern != "" {
 			if _, err := regexp.Compile(rule.Match.PathPattern); err != nil {
-				return fmt.Errorf("rule %s: invalid path_pattern: %w", rule.Name, err)
+				return fmt.Errorf("rule %s: invalid path_pattern %q: %w", rule.Name, rule.Match.PathPattern, err)
 			}
 		}
 		if rule.Match.RequiredContent != "" {
 			if _, err := regexp.Compile(rule.Match.RequiredContent); err != nil {
-				return fmt.Errorf("rule %s: invalid required_content: %w", rule.Name, err)
+				return fmt.Errorf("rule %s: invalid required_content %q: %w", rule.Name, rule.Match.RequiredContent, err)
 			}
 		}
 		if rule.Parser.Type == "" {
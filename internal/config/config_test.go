@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
@@ -354,6 +355,96 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "regex search with prefilter",
+			config: &Config{
+				Version: "1.0",
+				Searches: []SearchConfigEntry{
+					{Name: "imports", SearchTerm: `from\s+old\s+import`, IsRegex: true, Prefilter: "old"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "prefilter on literal search",
+			config: &Config{
+				Version: "1.0",
+				Searches: []SearchConfigEntry{
+					{Name: "todo", SearchTerm: "TODO", Prefilter: "TODO"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "version filter on named group",
+			config: &Config{
+				Version: "1.0",
+				Searches: []SearchConfigEntry{
+					{Name: "old-django", SearchTerm: `^django==(?P<ver>[\d.]+)`, IsRegex: true, VersionFilter: "<4.0", VersionGroup: "ver"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "version filter on first group",
+			config: &Config{
+				Version: "1.0",
+				Searches: []SearchConfigEntry{
+					{Name: "old-django", SearchTerm: `^django==([\d.]+)`, IsRegex: true, VersionFilter: "<4.0"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "version filter on literal search",
+			config: &Config{
+				Version: "1.0",
+				Searches: []SearchConfigEntry{
+					{Name: "old-django", SearchTerm: "django==", VersionFilter: "<4.0"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "version filter without capture group",
+			config: &Config{
+				Version: "1.0",
+				Searches: []SearchConfigEntry{
+					{Name: "old-django", SearchTerm: `^django==[\d.]+`, IsRegex: true, VersionFilter: "<4.0"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "version filter on unknown group",
+			config: &Config{
+				Version: "1.0",
+				Searches: []SearchConfigEntry{
+					{Name: "old-django", SearchTerm: `^django==(?P<v>[\d.]+)`, IsRegex: true, VersionFilter: "<4.0", VersionGroup: "ver"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid version filter",
+			config: &Config{
+				Version: "1.0",
+				Searches: []SearchConfigEntry{
+					{Name: "old-django", SearchTerm: `^django==([\d.]+)`, IsRegex: true, VersionFilter: "4.0"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "version group without filter",
+			config: &Config{
+				Version: "1.0",
+				Searches: []SearchConfigEntry{
+					{Name: "old-django", SearchTerm: `^django==(?P<ver>[\d.]+)`, IsRegex: true, VersionGroup: "ver"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -443,6 +534,53 @@ func TestConfigToRegistry(t *testing.T) {
 	}
 }
 
+func TestApplyConfidenceOverrides(t *testing.T) {
+	newRegistry := func() *rules.Registry {
+		registry := rules.NewRegistry()
+		registry.MustRegister(rules.NewRuleBuilder("requirements").
+			FilePattern("requirements.txt").
+			Parser(func(content []byte, filename string) (*rules.SearchResult, error) {
+				return &rules.SearchResult{Found: true, Detection: rules.Detection{Version: "3.11", Confidence: 0.6}}, nil
+			}).
+			MustBuild())
+		return registry
+	}
+
+	tests := []struct {
+		name      string
+		overrides map[string]float64
+		wantErr   string
+		want      float64
+	}{
+		{name: "override", overrides: map[string]float64{"requirements": 0.2}, want: 0.2},
+		{name: "none", want: 0},
+		{name: "unknown rule", overrides: map[string]float64{"requirement": 0.2}, wantErr: `no rule named "requirement"`},
+		{name: "above one", overrides: map[string]float64{"requirements": 1.5}, wantErr: "at most 1"},
+		{name: "zero", overrides: map[string]float64{"requirements": 0}, wantErr: "greater than 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newRegistry()
+			cfg := &Config{Settings: SettingsConfig{ConfidenceOverrides: tt.overrides}}
+
+			err := cfg.ApplyConfidenceOverrides(registry)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ApplyConfidenceOverrides() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyConfidenceOverrides() error = %v", err)
+			}
+			if got := registry.Get("requirements").Confidence; got != tt.want {
+				t.Errorf("Confidence = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFromRegistry(t *testing.T) {
 	// Create a registry with some rules
 	registry := rules.NewRegistry()
@@ -459,7 +597,7 @@ func TestFromRegistry(t *testing.T) {
 			MaxFileSize: 1024,
 		},
 		Parser: func(content []byte, filename string) (*rules.SearchResult, error) {
-			return &rules.SearchResult{Found: true, Version: "1.0"}, nil
+			return &rules.SearchResult{Found: true, Detection: rules.Detection{Version: "1.0"}}, nil
 		},
 	}
 
@@ -489,6 +627,44 @@ func TestFromRegistry(t *testing.T) {
 	}
 }
 
+func TestConfigToRegistryCollectsRuleErrors(t *testing.T) {
+	rule := func(name, pathPattern, requiredContent string) RuleConfig {
+		return RuleConfig{
+			Name:   name,
+			Match:  MatchConfig{FilePattern: "*.txt", PathPattern: pathPattern, RequiredContent: requiredContent},
+			Parser: ParserConfig{Type: "simple_version"},
+		}
+	}
+	config := &Config{
+		Version: "1.0",
+		Rules: []RuleConfig{
+			rule("good", "", ""),
+			rule("bad-path", "[unclosed", ""),
+			rule("bad-content", "", "python(3"),
+		},
+	}
+
+	_, err := config.ToRegistry(NewDefaultParserRegistry())
+	if err == nil {
+		t.Fatal("ToRegistry() succeeded, want an error")
+	}
+
+	// Both invalid rules are reported, each naming its field and pattern
+	msg := err.Error()
+	for _, want := range []string{
+		"2 of 3 rules are invalid",
+		`rules[1]: rule "bad-path": invalid path_pattern "[unclosed"`,
+		`rules[2]: rule "bad-content": invalid required_content "python(3"`,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error missing %q:\n%s", want, msg)
+		}
+	}
+	if strings.Contains(msg, `"good"`) {
+		t.Errorf("error mentions the valid rule:\n%s", msg)
+	}
+}
+
 func TestRuleConfigToSearchRule(t *testing.T) {
 	ruleConfig := RuleConfig{
 		Name:        "test-rule",
@@ -498,6 +674,7 @@ func TestRuleConfigToSearchRule(t *testing.T) {
 		Tags:        []string{"test"},
 		Match: MatchConfig{
 			FilePattern:     "*.txt",
+			CaseInsensitive: true,
 			PathPattern:     "^/test/.*",
 			RequiredContent: "version",
 			MaxFileSize:     1024,
@@ -538,6 +715,10 @@ func TestRuleConfigToSearchRule(t *testing.T) {
 		t.Errorf("Expected file pattern '*.txt', got %s", rule.Condition.FilePattern)
 	}
 
+	if !rule.Condition.CaseInsensitive || !rule.Matches("NOTES.TXT", "/test/NOTES.TXT") {
+		t.Error("Expected case-insensitive file pattern to match NOTES.TXT")
+	}
+
 	if rule.Condition.MaxFileSize != 1024 {
 		t.Errorf("Expected max file size 1024, got %d", rule.Condition.MaxFileSize)
 	}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DiscoveryNames are the file names Discover looks for in each directory,
+// in order of preference
+var DiscoveryNames = []string{".gitlab-seeker.yaml", ".gitlab-seeker.yml", ".gitlab-seeker.json"}
+
+// Discover walks up from dir looking for a config file named in
+// DiscoveryNames and returns the first one found, or "" if there is none.
+// The walk stops after a repository root (a directory containing .git),
+// stopDir (normally the user's home directory), or the filesystem root.
+func Discover(dir, stopDir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if stopDir != "" {
+		if stopDir, err = filepath.Abs(stopDir); err != nil {
+			return "", err
+		}
+	}
+
+	for {
+		for _, name := range DiscoveryNames {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+				return candidate, nil
+			}
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", nil
+		}
+		parent := filepath.Dir(dir)
+		if dir == stopDir || parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
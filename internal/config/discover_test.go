@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	repo := filepath.Join(home, "repo")
+	nested := filepath.Join(repo, "services", "api")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(path string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte("version: \"1.0\"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Nothing to find up to the stop directory
+	if got, err := Discover(nested, home); err != nil || got != "" {
+		t.Errorf("Discover() = %q, %v; want none", got, err)
+	}
+
+	// Found by walking up, preferring .yaml over .json
+	write(filepath.Join(repo, ".gitlab-seeker.json"))
+	write(filepath.Join(repo, ".gitlab-seeker.yaml"))
+	if got, _ := Discover(nested, home); got != filepath.Join(repo, ".gitlab-seeker.yaml") {
+		t.Errorf("Discover() = %q, want the repo's .gitlab-seeker.yaml", got)
+	}
+
+	// A config above the stop directory is never reached
+	os.Remove(filepath.Join(repo, ".gitlab-seeker.json"))
+	os.Remove(filepath.Join(repo, ".gitlab-seeker.yaml"))
+	write(filepath.Join(root, ".gitlab-seeker.yml"))
+	if got, _ := Discover(nested, home); got != "" {
+		t.Errorf("Discover() = %q, want none above the stop directory", got)
+	}
+
+	// Nor is one above a repository root
+	write(filepath.Join(home, ".gitlab-seeker.yml"))
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Discover(nested, ""); got != "" {
+		t.Errorf("Discover() = %q, want none above the repository root", got)
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// InstanceConfig is one GitLab instance a scan covers, for organizations
+// whose projects are split across instances (e.g. gitlab.com and a
+// self-hosted one mid-migration). Tokens are never written in the config:
+// each instance names where to read its own from.
+type InstanceConfig struct {
+	// Name identifies the instance on every result, e.g. "saas"
+	Name string `yaml:"name" json:"name"`
+
+	// URL is the instance's base URL, e.g. "https://gitlab.example.com"
+	URL string `yaml:"url" json:"url"`
+
+	// Group is the group to scan (empty = every project the token can see)
+	Group string `yaml:"group,omitempty" json:"group,omitempty"`
+
+	// TokenEnv names the environment variable holding the instance's token
+	TokenEnv string `yaml:"token_env,omitempty" json:"token_env,omitempty"`
+
+	// TokenFile is a file holding the instance's token
+	TokenFile string `yaml:"token_file,omitempty" json:"token_file,omitempty"`
+}
+
+// GitLabURL returns the instance's URL including its group, as --url takes it
+func (i *InstanceConfig) GitLabURL() string {
+	if i.Group == "" {
+		return i.URL
+	}
+	return strings.TrimSuffix(i.URL, "/") + "/" + strings.Trim(i.Group, "/")
+}
+
+// Token reads the instance's token from its token_env or token_file
+func (i *InstanceConfig) Token() (string, error) {
+	var token string
+	if i.TokenEnv != "" {
+		token = os.Getenv(i.TokenEnv)
+		if token == "" {
+			return "", fmt.Errorf("instance %s: environment variable %s is not set", i.Name, i.TokenEnv)
+		}
+	} else {
+		data, err := os.ReadFile(i.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("instance %s: failed to read token file: %w", i.Name, err)
+		}
+		token = strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("instance %s: token file %s is empty", i.Name, i.TokenFile)
+		}
+	}
+	return token, nil
+}
+
+// ValidateInstances checks that every instance has a unique name, a URL,
+// and exactly one token source
+func (c *Config) ValidateInstances() error {
+	names := make(map[string]bool)
+	for i, instance := range c.Instances {
+		if instance.Name == "" {
+			return fmt.Errorf("instance %d: name is required", i)
+		}
+		if names[instance.Name] {
+			return fmt.Errorf("duplicate instance name: %s", instance.Name)
+		}
+		names[instance.Name] = true
+		if instance.URL == "" {
+			return fmt.Errorf("instance %s: url is required", instance.Name)
+		}
+		if (instance.TokenEnv == "") == (instance.TokenFile == "") {
+			return fmt.Errorf("instance %s: exactly one of token_env or token_file is required", instance.Name)
+		}
+	}
+	return nil
+}
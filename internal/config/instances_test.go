@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstanceConfigGitLabURL(t *testing.T) {
+	tests := []struct {
+		instance InstanceConfig
+		want     string
+	}{
+		{InstanceConfig{URL: "https://gitlab.example.com", Group: "eng"}, "https://gitlab.example.com/eng"},
+		{InstanceConfig{URL: "gitlab.com/", Group: "/myorg/team/"}, "gitlab.com/myorg/team"},
+		{InstanceConfig{URL: "https://gitlab.example.com"}, "https://gitlab.example.com"},
+	}
+	for _, tt := range tests {
+		if got := tt.instance.GitLabURL(); got != tt.want {
+			t.Errorf("GitLabURL() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestInstanceConfigToken(t *testing.T) {
+	t.Setenv("ONPREM_TOKEN", "env-token")
+	fromEnv := InstanceConfig{Name: "onprem", TokenEnv: "ONPREM_TOKEN"}
+	if token, err := fromEnv.Token(); err != nil || token != "env-token" {
+		t.Errorf("Token() = %q, %v, want env-token", token, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("failed to write token: %v", err)
+	}
+	fromFile := InstanceConfig{Name: "saas", TokenFile: path}
+	if token, err := fromFile.Token(); err != nil || token != "file-token" {
+		t.Errorf("Token() = %q, %v, want file-token", token, err)
+	}
+
+	unset := InstanceConfig{Name: "saas", TokenEnv: "SEEKER_TEST_UNSET_TOKEN"}
+	if _, err := unset.Token(); err == nil || !strings.Contains(err.Error(), "SEEKER_TEST_UNSET_TOKEN is not set") {
+		t.Errorf("Token() error = %v, want the unset variable named", err)
+	}
+}
+
+func TestValidateInstances(t *testing.T) {
+	tests := []struct {
+		name      string
+		instances []InstanceConfig
+		wantErr   string
+	}{
+		{"valid", []InstanceConfig{{Name: "saas", URL: "gitlab.com", TokenEnv: "A"}, {Name: "onprem", URL: "gitlab.example.com", TokenFile: "t"}}, ""},
+		{"missing name", []InstanceConfig{{URL: "gitlab.com", TokenEnv: "A"}}, "name is required"},
+		{"duplicate name", []InstanceConfig{{Name: "saas", URL: "gitlab.com", TokenEnv: "A"}, {Name: "saas", URL: "gitlab.com", TokenEnv: "B"}}, "duplicate instance name"},
+		{"missing url", []InstanceConfig{{Name: "saas", TokenEnv: "A"}}, "url is required"},
+		{"no token source", []InstanceConfig{{Name: "saas", URL: "gitlab.com"}}, "exactly one of token_env or token_file"},
+		{"two token sources", []InstanceConfig{{Name: "saas", URL: "gitlab.com", TokenEnv: "A", TokenFile: "t"}}, "exactly one of token_env or token_file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Config{Instances: tt.instances}).ValidateInstances()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateInstances() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateInstances() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
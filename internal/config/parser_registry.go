@@ -30,6 +30,10 @@ func NewDefaultParserRegistry() *DefaultParserRegistry {
 		return rule.Parser, nil
 	})
 
+	registry.RegisterParser("python_version_file", func(config map[string]interface{}) (rules.ParserFunc, error) {
+		return parsers.ParsePythonVersionFile, nil
+	})
+
 	registry.RegisterParser("regex", createRegexParser)
 	registry.RegisterParser("simple_version", createSimpleVersionParser)
 	registry.RegisterParser("string_search", createStringSearchParser)
@@ -99,11 +103,13 @@ func createRegexParser(config map[string]interface{}) (rules.ParserFunc, error)
 		}
 
 		return &rules.SearchResult{
-			Found:      true,
-			Version:    strings.TrimSpace(version),
-			Source:     filename,
-			Confidence: confidence,
-			RawValue:   version,
+			Found: true,
+			Detection: rules.Detection{
+				Version:    strings.TrimSpace(version),
+				Source:     filename,
+				Confidence: confidence,
+			},
+			RawValue: version,
 		}, nil
 	}, nil
 }
@@ -135,11 +141,13 @@ func createSimpleVersionParser(config map[string]interface{}) (rules.ParserFunc,
 		}
 
 		return &rules.SearchResult{
-			Found:      true,
-			Version:    version,
-			Source:     filename,
-			Confidence: confidence,
-			RawValue:   version,
+			Found: true,
+			Detection: rules.Detection{
+				Version:    version,
+				Source:     filename,
+				Confidence: confidence,
+			},
+			RawValue: version,
 		}, nil
 	}, nil
 }
@@ -131,6 +131,7 @@ func TestDefaultParserRegistry_ListParserTypes(t *testing.T) {
 
 	expectedTypes := map[string]bool{
 		"pyproject_toml":  true,
+		"python_version_file": true,
 		"regex":           true,
 		"simple_version":  true,
 		"string_search":   true,
@@ -154,10 +155,12 @@ func TestDefaultParserRegistry_RegisterParser(t *testing.T) {
 	customParser := func(config map[string]interface{}) (ParserFunc, error) {
 		return func(content []byte, filename string) (*SearchResult, error) {
 			return &SearchResult{
-				Found:      true,
-				Version:    "custom",
-				Source:     filename,
-				Confidence: 0.5,
+				Found: true,
+				Detection: rules.Detection{
+					Version:    "custom",
+					Source:     filename,
+					Confidence: 0.5,
+				},
 			}, nil
 		}, nil
 	}
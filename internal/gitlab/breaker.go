@@ -0,0 +1,111 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	apperrors "github.com/gbjohnso/gitlab-python-scanner/internal/errors"
+)
+
+// ErrCircuitOpen is wrapped by every error returned while the circuit
+// breaker is refusing calls
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitBreaker fails calls fast once GitLab looks completely unavailable.
+// After threshold consecutive retryable failures (network errors, timeouts,
+// rate limits and 5xx responses) across all requests, calls are refused for
+// cooldown. Once the cooldown passes calls go through again: a success
+// closes the circuit and another retryable failure reopens it immediately.
+// It is safe for concurrent use.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	failures  int       // Consecutive retryable failures
+	lastErr   error     // Most recent retryable failure
+	openUntil time.Time // Calls are refused until this time
+}
+
+// NewCircuitBreaker returns a breaker that opens after threshold consecutive
+// retryable failures and stays open for cooldown
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow returns an error wrapping ErrCircuitOpen while the circuit is open
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	remaining := b.openUntil.Sub(b.now())
+	if remaining <= 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: GitLab appears unavailable after %d consecutive failures (last: %v); failing fast for another %s",
+		ErrCircuitOpen, b.failures, b.lastErr, remaining.Round(time.Second))
+}
+
+// Record updates the breaker with the outcome of one API attempt. Any
+// response that isn't retryable, including a 404, shows GitLab is reachable
+// and resets the failure count.
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil || !apperrors.IsRetryable(err) {
+		b.failures = 0
+		b.lastErr = nil
+		b.openUntil = time.Time{}
+		return
+	}
+
+	b.failures++
+	b.lastErr = err
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// retry runs fn with backoff, consulting the API budget and circuit
+// breaker (if any) before every attempt so that neither a spent budget nor
+// an outage costs each caller a full retry budget. Attempts after the first
+// are counted as retries in the client's stats.
+func (c *Client) retry(ctx context.Context, config *apperrors.RetryConfig, fn func() error) error {
+	if c.breaker == nil && c.budget == nil && c.stats == nil {
+		return apperrors.RetryWithBackoff(ctx, config, fn)
+	}
+	attempts := 0
+	return apperrors.RetryWithBackoff(ctx, config, func() error {
+		if attempts++; attempts > 1 && c.stats != nil {
+			c.stats.retries.Add(1)
+		}
+		if c.budget != nil {
+			if err := c.budget.allow(); err != nil {
+				return err
+			}
+		}
+		if c.breaker != nil {
+			if err := c.breaker.Allow(); err != nil {
+				return err
+			}
+		}
+		err := fn()
+		// A refused request comes back classified as a retryable network error
+		if c.budget != nil && c.budget.refused.Load() && apperrors.IsRetryable(err) {
+			return c.budget.exhausted()
+		}
+		if c.breaker != nil {
+			c.breaker.Record(err)
+		}
+		return err
+	})
+}
@@ -0,0 +1,87 @@
+package gitlab
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apperrors "github.com/gbjohnso/gitlab-python-scanner/internal/errors"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker(3, time.Minute)
+	b.now = func() time.Time { return now }
+
+	outage := apperrors.NewNetworkError(stderrors.New("connection refused"))
+	notFound := apperrors.NewNotFoundError("project")
+
+	// Non-retryable errors mean GitLab answered, so they reset the count
+	b.Record(outage)
+	b.Record(outage)
+	b.Record(notFound)
+	b.Record(outage)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() after interrupted failures = %v, want nil", err)
+	}
+
+	b.Record(outage)
+	b.Record(outage)
+	err := b.Allow()
+	if !stderrors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow() after 3 consecutive failures = %v, want ErrCircuitOpen", err)
+	}
+
+	// After the cooldown calls are allowed again; a failure reopens at once
+	now = now.Add(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() after cooldown = %v, want nil", err)
+	}
+	b.Record(outage)
+	if err := b.Allow(); !stderrors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow() after failed probe = %v, want ErrCircuitOpen", err)
+	}
+
+	// A success after the cooldown closes the circuit
+	now = now.Add(time.Minute)
+	b.Record(nil)
+	b.Record(outage)
+	if err := b.Allow(); err != nil {
+		t.Errorf("Allow() after successful probe = %v, want nil", err)
+	}
+}
+
+func TestClientCircuitBreakerFailsFast(t *testing.T) {
+	// A closed server refuses connections, like GitLab during an outage
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	gitlabClient, err := gitlab.NewClient("test-token", gitlab.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create go-gitlab client: %v", err)
+	}
+	c := &Client{
+		client:  gitlabClient,
+		baseURL: server.URL,
+		timeout: 5 * time.Second,
+		breaker: NewCircuitBreaker(1, time.Minute),
+	}
+
+	// The first call's failure opens the circuit, which also stops its retries
+	if _, err := c.LatestTag(context.Background(), 42); !stderrors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("first LatestTag() error = %v, want ErrCircuitOpen", err)
+	}
+
+	start := time.Now()
+	_, err = c.LatestTag(context.Background(), 42)
+	if !stderrors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second LatestTag() error = %v, want ErrCircuitOpen", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("second LatestTag() took %v, want an immediate failure", elapsed)
+	}
+}
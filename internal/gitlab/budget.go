@@ -0,0 +1,78 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// ErrBudgetExhausted is wrapped by every error returned once a client has
+// sent all the requests Config.MaxAPICalls allows
+var ErrBudgetExhausted = errors.New("API budget exhausted")
+
+// callBudget counts the requests a client sends, including go-gitlab's own
+// retries, and refuses any past max. It is safe for concurrent use.
+type callBudget struct {
+	max     int64
+	calls   atomic.Int64
+	refused atomic.Bool // Whether any request has been refused
+}
+
+// allow returns an error wrapping ErrBudgetExhausted once every allowed
+// request has been sent, so callers stop before building another one
+func (b *callBudget) allow() error {
+	if b.calls.Load() >= b.max {
+		b.refused.Store(true)
+		return b.exhausted()
+	}
+	return nil
+}
+
+// take counts one request, refusing it if the budget is spent
+func (b *callBudget) take() error {
+	if b.calls.Add(1) > b.max {
+		b.calls.Add(-1)
+		b.refused.Store(true)
+		return b.exhausted()
+	}
+	return nil
+}
+
+// exhausted returns the error for a refused request
+func (b *callBudget) exhausted() error {
+	return fmt.Errorf("%w: all %d allowed API calls have been made", ErrBudgetExhausted, b.max)
+}
+
+// budgetTransport is an http.RoundTripper that sends requests only while
+// its budget allows
+type budgetTransport struct {
+	base   http.RoundTripper
+	budget *callBudget
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.budget.take(); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// BudgetExhausted reports whether a request has been refused because
+// Config.MaxAPICalls was reached. It is always false without a limit.
+func (c *Client) BudgetExhausted() bool {
+	return c.budget != nil && c.budget.refused.Load()
+}
+
+// ResetBudget allows another Config.MaxAPICalls requests, e.g. for each run
+// of a repeated scan
+func (c *Client) ResetBudget() {
+	if c.budget != nil {
+		c.budget.calls.Store(0)
+		c.budget.refused.Store(false)
+	}
+}
@@ -0,0 +1,76 @@
+package gitlab
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClientBudget(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if strings.Contains(r.URL.Path, "/projects/2/") {
+			// Always unavailable, so go-gitlab retries it itself
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("3.12\n"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&Config{GitLabURL: server.URL + "/org", Token: "test-token", MaxAPICalls: 2})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetRawFile(context.Background(), 1, ".python-version", nil); err != nil {
+			t.Fatalf("call %d within the budget: %v", i+1, err)
+		}
+	}
+	if client.BudgetExhausted() {
+		t.Error("BudgetExhausted() = true before any call was refused")
+	}
+
+	_, err = client.GetRawFile(context.Background(), 1, ".python-version", nil)
+	if !stderrors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("call past the budget error = %v, want ErrBudgetExhausted", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server saw %d requests, want 2", got)
+	}
+	if !client.BudgetExhausted() {
+		t.Error("BudgetExhausted() = false after a refused call")
+	}
+
+	// A retry the budget refuses ends the call rather than being retried
+	client.ResetBudget()
+	if client.BudgetExhausted() {
+		t.Error("BudgetExhausted() = true after ResetBudget")
+	}
+	requests.Store(0)
+	client.budget.max = 1
+	_, err = client.GetRawFile(context.Background(), 2, ".python-version", nil)
+	if !stderrors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("refused retry error = %v, want ErrBudgetExhausted", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+}
+
+func TestClientWithoutBudget(t *testing.T) {
+	client, err := NewClient(&Config{GitLabURL: "gitlab.com/org", Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client.budget != nil || client.BudgetExhausted() {
+		t.Error("a client without MaxAPICalls has a budget")
+	}
+	client.ResetBudget() // No-op
+}
@@ -2,14 +2,18 @@ package gitlab
 
 import (
 	"context"
+	"encoding/base64"
 	stderrors "errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	apperrors "github.com/gbjohnso/gitlab-python-scanner/internal/errors"
+	"github.com/hashicorp/go-retryablehttp"
 	"github.com/xanzy/go-gitlab"
 )
 
@@ -19,6 +23,15 @@ type Client struct {
 	baseURL      string
 	organization string
 	timeout      time.Duration
+	slots        chan struct{}   // Bounds concurrent work shared by all callers (nil = unbounded)
+	breaker      *CircuitBreaker // Fails calls fast during an outage (nil = disabled)
+	budget       *callBudget     // Caps the requests sent (nil = unlimited)
+	stats        *callStats      // Counts the traffic sent (nil = not counted)
+	ramp         *rampUp         // Admits slots gradually (nil = all at once)
+
+	listConcurrency int  // Project listing pages fetched in parallel (<= 1 = serial)
+	listPerPage     int  // Projects per listing page for ListAllProjects (0 = GitLab default)
+	listHeadOnly    bool // ListAllProjects tries a single unretried page first
 }
 
 // Config holds the configuration for creating a GitLab client
@@ -26,6 +39,59 @@ type Config struct {
 	GitLabURL string        // Full URL including org/group (e.g., "gitlab.com/myorg")
 	Token     string        // GitLab API token
 	Timeout   time.Duration // API timeout duration
+
+	// Concurrency bounds the number of concurrent operations across every
+	// caller sharing this client. Zero or negative means unbounded.
+	Concurrency int
+
+	// RampUp admits the Concurrency slots one at a time over this long,
+	// starting from the first Acquire, to avoid a burst of requests when a
+	// scan starts. Zero admits them all at once; it has no effect without
+	// Concurrency.
+	RampUp time.Duration
+
+	// ListConcurrency is the number of project listing pages fetched in
+	// parallel. It is separate from Concurrency because listing is a
+	// handful of page calls while scanning is thousands of file fetches.
+	// Zero or one lists serially.
+	ListConcurrency int
+
+	// ListPerPage is the page size ListAllProjects requests (0 = GitLab's
+	// default of 20, capped at 100). Larger pages mean fewer round-trips.
+	ListPerPage int
+
+	// ListHeadOnly makes ListAllProjects try one unretried page of 100
+	// first, for groups known to be small (see ListProjectsOptions.HeadOnly)
+	ListHeadOnly bool
+
+	// Trace, if set, receives one JSON line per API call (see TraceTransport)
+	Trace io.Writer
+
+	// TraceMisses also traces file probes that found no file, which are
+	// left out by default (see TraceMiss)
+	TraceMisses bool
+
+	// BreakerThreshold is the number of consecutive retryable failures
+	// after which calls fail fast for BreakerCooldown (see CircuitBreaker).
+	// Zero or negative disables the breaker.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+
+	// MaxAPICalls caps the requests the client sends, retries included;
+	// past it every call fails with ErrBudgetExhausted. Zero or negative
+	// means unlimited.
+	MaxAPICalls int
+
+	// Stats counts the requests, retries, files and bytes the client sends
+	// and receives, for Client.Stats
+	Stats bool
+
+	// TLSMinVersion is the oldest TLS version the client negotiates (see
+	// ParseTLSVersion); zero, or anything older than TLS 1.2, means Go's
+	// default. TLSCipherSuites, if set, restricts TLS 1.2 and earlier to
+	// those suites (see ParseCipherSuites).
+	TLSMinVersion   uint16
+	TLSCipherSuites []uint16
 }
 
 // NewClient creates a new GitLab API client with authentication
@@ -49,7 +115,44 @@ func NewClient(config *Config) (*Client, error) {
 	}
 
 	// Create the go-gitlab client
-	gitlabClient, err := gitlab.NewClient(config.Token, gitlab.WithBaseURL(baseURL))
+	options := []gitlab.ClientOptionFunc{gitlab.WithBaseURL(baseURL)}
+	var transport http.RoundTripper
+	if config.TLSMinVersion != 0 || len(config.TLSCipherSuites) > 0 {
+		// Innermost, so every layer below sends through it
+		transport = newTLSTransport(config.TLSMinVersion, config.TLSCipherSuites)
+	}
+	var hooks []retryablehttp.RequestLogHook
+	if config.Trace != nil {
+		trace := NewTraceTransport(baseTransport(transport), config.Trace)
+		trace.LogMisses = config.TraceMisses
+		transport = trace
+		hooks = append(hooks, trace.RecordAttempt)
+	}
+	var stats *callStats
+	if config.Stats {
+		stats = &callStats{}
+		base := baseTransport(transport)
+		transport = &statsTransport{base: base, stats: stats}
+		hooks = append(hooks, stats.RecordAttempt)
+	}
+	if len(hooks) > 0 {
+		options = append(options, gitlab.WithRequestLogHook(func(logger retryablehttp.Logger, req *http.Request, attempt int) {
+			for _, hook := range hooks {
+				hook(logger, req, attempt)
+			}
+		}))
+	}
+	var budget *callBudget
+	if config.MaxAPICalls > 0 {
+		budget = &callBudget{max: int64(config.MaxAPICalls)}
+		// Outside the trace and stats, so refused requests aren't counted as sent
+		base := baseTransport(transport)
+		transport = &budgetTransport{base: base, budget: budget}
+	}
+	if transport != nil {
+		options = append(options, gitlab.WithHTTPClient(&http.Client{Transport: transport}))
+	}
+	gitlabClient, err := gitlab.NewClient(config.Token, options...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
 	}
@@ -65,11 +168,44 @@ func NewClient(config *Config) (*Client, error) {
 		baseURL:      baseURL,
 		organization: organization,
 		timeout:      timeout,
+		budget:       budget,
+		stats:        stats,
+
+		listConcurrency: config.ListConcurrency,
+		listPerPage:     config.ListPerPage,
+		listHeadOnly:    config.ListHeadOnly,
+	}
+
+	if config.Concurrency > 0 {
+		client.slots = make(chan struct{}, config.Concurrency)
+		if config.RampUp > 0 && config.Concurrency > 1 {
+			client.ramp = &rampUp{duration: config.RampUp, held: config.Concurrency - 1}
+			for i := 0; i < client.ramp.held; i++ {
+				client.slots <- struct{}{}
+			}
+		}
+	}
+
+	if config.BreakerThreshold > 0 {
+		cooldown := config.BreakerCooldown
+		if cooldown <= 0 {
+			cooldown = 30 * time.Second // default cooldown
+		}
+		client.breaker = NewCircuitBreaker(config.BreakerThreshold, cooldown)
 	}
 
 	return client, nil
 }
 
+// baseTransport returns transport for another layer to wrap, or a fresh
+// default transport if there's none yet
+func baseTransport(transport http.RoundTripper) http.RoundTripper {
+	if transport == nil {
+		return http.DefaultTransport.(*http.Transport).Clone()
+	}
+	return transport
+}
+
 // parseGitLabURL extracts the base URL and organization/group from a GitLab URL
 // Examples:
 //   - "gitlab.com/myorg" -> "https://gitlab.com", "myorg"
@@ -128,7 +264,7 @@ func (c *Client) TestConnectionWithContext(ctx context.Context) error {
 	}
 
 	var lastResp *gitlab.Response
-	err := apperrors.RetryWithBackoff(ctx, retryConfig, func() error {
+	err := c.retry(ctx, retryConfig, func() error {
 		// Try to get the current user to verify authentication
 		_, resp, err := c.client.Users.CurrentUser()
 		lastResp = resp
@@ -146,6 +282,188 @@ func (c *Client) TestConnectionWithContext(ctx context.Context) error {
 	return nil
 }
 
+// Identity describes the authenticated user and the GitLab instance the client is talking to
+type Identity struct {
+	Username string // Username of the authenticated user
+	Name     string // Display name of the authenticated user
+	IsAdmin  bool   // Whether the authenticated user is an instance administrator
+	Version  string // GitLab instance version (e.g., "16.8.1-ee"; "" = unknown)
+	Revision string // GitLab instance revision ("" = unknown)
+
+	// AdvancedSearch reports whether Elasticsearch-backed advanced search is enabled.
+	// nil means availability could not be determined (the settings API requires admin access).
+	AdvancedSearch *bool
+}
+
+// AdvancedSearchStatus returns "enabled", "disabled", or "unknown"
+func (i *Identity) AdvancedSearchStatus() string {
+	if i.AdvancedSearch == nil {
+		return "unknown"
+	}
+	if *i.AdvancedSearch {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// Identify verifies authentication like TestConnection and additionally reports
+// the authenticated username, the instance version, and whether advanced search
+// is available. Version and advanced search detection are best-effort and never
+// fail the call.
+func (c *Client) Identify(ctx context.Context) (*Identity, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("GitLab client is not initialized")
+	}
+
+	// Create a context with timeout
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// Configure retry for network failures
+	retryConfig := &apperrors.RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2.0,
+		ShouldRetry: func(err error) bool {
+			return apperrors.IsRetryable(err)
+		},
+	}
+
+	identity := &Identity{}
+	var lastResp *gitlab.Response
+
+	// The current user endpoint doubles as the authentication check
+	err := c.retry(ctx, retryConfig, func() error {
+		user, resp, err := c.client.Users.CurrentUser(gitlab.WithContext(ctx))
+		lastResp = resp
+		if err != nil {
+			return classifyGitLabError(err, resp)
+		}
+		identity.Username = user.Username
+		identity.Name = user.Name
+		identity.IsAdmin = user.IsAdmin
+		return nil
+	})
+	if err != nil {
+		return nil, c.formatUserError(err, lastResp)
+	}
+
+	// Some instances restrict /version, so a failure here just leaves it unknown
+	_ = c.retry(ctx, retryConfig, func() error {
+		version, resp, err := c.client.Version.GetVersion(gitlab.WithContext(ctx))
+		if err != nil {
+			return classifyGitLabError(err, resp)
+		}
+		identity.Version = version.Version
+		identity.Revision = version.Revision
+		return nil
+	})
+
+	// Application settings are admin-only, so a failure here just leaves the status unknown
+	if settings, _, err := c.client.Settings.GetSettings(gitlab.WithContext(ctx)); err == nil && settings != nil {
+		identity.AdvancedSearch = gitlab.Ptr(settings.ElasticsearchSearch)
+	}
+
+	return identity, nil
+}
+
+// CheckGroupAccess verifies that group exists and is readable with the client's
+// token, so a wrong group path fails up front instead of mid-scan. GitLab
+// answers 404 for private groups the token cannot see, so "not found" and
+// "not accessible" are reported together. An empty group always passes.
+func (c *Client) CheckGroupAccess(ctx context.Context, group string) error {
+	if c.client == nil {
+		return fmt.Errorf("GitLab client is not initialized")
+	}
+	if group == "" {
+		return nil
+	}
+
+	// Create a context with timeout
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// Configure retry for network failures
+	retryConfig := &apperrors.RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2.0,
+		ShouldRetry: func(err error) bool {
+			return apperrors.IsRetryable(err)
+		},
+	}
+
+	var lastResp *gitlab.Response
+	err := c.retry(ctx, retryConfig, func() error {
+		// Skip projects; only the group's existence and visibility matter
+		_, resp, err := c.client.Groups.GetGroup(group, &gitlab.GetGroupOptions{
+			WithProjects: gitlab.Ptr(false),
+		}, gitlab.WithContext(ctx))
+		lastResp = resp
+		if err != nil {
+			return classifyGitLabError(err, resp)
+		}
+		return nil
+	})
+	if err != nil {
+		var appErr *apperrors.AppError
+		if stderrors.As(err, &appErr) &&
+			(appErr.Type == apperrors.ErrorTypeNotFound || appErr.Type == apperrors.ErrorTypePermission) {
+			return fmt.Errorf("group '%s' not found or not accessible with this token", group)
+		}
+		return c.formatUserError(err, lastResp)
+	}
+
+	return nil
+}
+
+// ProjectExists reports whether the project at path (with namespace, e.g.
+// "myorg/billing") exists and is readable with the client's token. GitLab
+// answers 404 for private projects the token cannot see, so those are
+// reported as missing.
+func (c *Client) ProjectExists(ctx context.Context, path string) (bool, error) {
+	if c.client == nil {
+		return false, fmt.Errorf("GitLab client is not initialized")
+	}
+
+	// Create a context with timeout
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// Configure retry for network failures
+	retryConfig := &apperrors.RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2.0,
+		ShouldRetry: func(err error) bool {
+			return apperrors.IsRetryable(err)
+		},
+	}
+
+	var lastResp *gitlab.Response
+	err := c.retry(ctx, retryConfig, func() error {
+		_, resp, err := c.client.Projects.GetProject(path, nil, gitlab.WithContext(ctx))
+		lastResp = resp
+		if err != nil {
+			return classifyGitLabError(err, resp)
+		}
+		return nil
+	})
+	if err != nil {
+		var appErr *apperrors.AppError
+		if stderrors.As(err, &appErr) &&
+			(appErr.Type == apperrors.ErrorTypeNotFound || appErr.Type == apperrors.ErrorTypePermission) {
+			return false, nil
+		}
+		return false, c.formatUserError(err, lastResp)
+	}
+
+	return true, nil
+}
+
 // classifyGitLabError analyzes a GitLab API error and returns an appropriate AppError
 func classifyGitLabError(err error, resp *gitlab.Response) error {
 	if err == nil {
@@ -165,6 +483,13 @@ func classifyGitLabError(err error, resp *gitlab.Response) error {
 			return apperrors.NewRateLimitError(err)
 		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
 			return apperrors.NewNetworkError(err)
+		case http.StatusInternalServerError:
+			// Loaded instances return transient 500s, but some 500s are
+			// GitLab rejecting the request itself and would fail again
+			if isRequestRejection(err) {
+				return apperrors.ClassifyError(err)
+			}
+			return apperrors.NewNetworkError(err)
 		}
 	}
 
@@ -172,6 +497,33 @@ func classifyGitLabError(err error, resp *gitlab.Response) error {
 	return apperrors.ClassifyError(err)
 }
 
+// requestRejectionMarkers appear in the messages of 500 responses that GitLab
+// returns for a request it can't handle, rather than for server load
+var requestRejectionMarkers = []string{
+	"bad request",
+	"invalid",
+	"is missing",
+	"malformed",
+}
+
+// isRequestRejection reports whether a 500 error's message says the request
+// itself was at fault, so retrying it won't help. Only the message GitLab
+// sent is checked: err.Error() also holds the request URL, whose file path
+// could contain a marker (e.g. "invalid_input.py").
+func isRequestRejection(err error) bool {
+	var errResp *gitlab.ErrorResponse
+	if !stderrors.As(err, &errResp) {
+		return false
+	}
+	message := strings.ToLower(errResp.Message)
+	for _, marker := range requestRejectionMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
 // formatUserError formats an error for user-friendly display
 func (c *Client) formatUserError(err error, resp *gitlab.Response) error {
 	var appErr *apperrors.AppError
@@ -218,16 +570,53 @@ func (c *Client) GetTimeout() time.Duration {
 	return c.timeout
 }
 
+// GetConcurrency returns the shared concurrency limit, or 0 if unbounded
+func (c *Client) GetConcurrency() int {
+	return cap(c.slots)
+}
+
+// Acquire blocks until a concurrency slot is available on this client or ctx
+// is done. Every scan and search sharing the client draws from the same pool,
+// so back-to-back or parallel operations cannot overload the instance.
+// Each successful Acquire must be paired with a Release.
+func (c *Client) Acquire(ctx context.Context) error {
+	if c.slots == nil {
+		return nil
+	}
+	if c.ramp != nil {
+		c.ramp.start(c.slots)
+	}
+
+	select {
+	case c.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release returns a concurrency slot obtained with Acquire
+func (c *Client) Release() {
+	if c.slots == nil {
+		return
+	}
+	<-c.slots
+}
+
 // Project represents a GitLab project with relevant information
 type Project struct {
-	ID                int    // Project ID
-	Name              string // Project name
-	Path              string // Project path (URL slug)
-	PathWithNamespace string // Full path including group
-	WebURL            string // Web URL of the project
-	DefaultBranch     string // Default branch name (e.g., "main", "master")
-	Archived          bool   // Whether the project is archived
-	LastActivityAt    string // Last activity timestamp
+	ID                int      // Project ID
+	Name              string   // Project name
+	Path              string   // Project path (URL slug)
+	PathWithNamespace string   // Full path including group
+	Namespace         string   // Full group path, e.g. "org/team/sub" for "org/team/sub/repo"
+	TopLevelGroup     string   // First segment of Namespace, e.g. "org"
+	WebURL            string   // Web URL of the project
+	DefaultBranch     string   // Default branch name (e.g., "main", "master")
+	Archived          bool     // Whether the project is archived
+	IsFork            bool     // Whether the project was forked from another project
+	LastActivityAt    string   // Last activity timestamp
+	Topics            []string // Topics the project is tagged with, e.g. "python-service"
 }
 
 // ListProjectsOptions contains options for listing projects
@@ -235,9 +624,47 @@ type ListProjectsOptions struct {
 	PerPage          int   // Number of results per page (default: 20, max: 100)
 	Archived         *bool // Filter by archived status (nil = all, true = archived only, false = active only)
 	IncludeSubgroups *bool // Include projects from subgroups (nil = default true, explicit true/false to override)
+
+	// BestEffort returns the projects gathered so far, together with a
+	// *PartialListError, when a later page fails instead of discarding them
+	BestEffort bool
+
+	// HeadOnly fetches a single page of up to 100 projects with no retries
+	// and returns it if there are no more pages. If the group is larger or
+	// the request fails, listing carries on normally at 100 per page.
+	HeadOnly bool
+
+	// Topic lists only the projects tagged with this topic ("" = all);
+	// GitLab does the filtering, so other projects are never fetched
+	Topic string
+}
+
+// listPageAttempts is how many times a project listing page is tried
+const listPageAttempts = 3
+
+// maxPerPage is the largest page size GitLab allows
+const maxPerPage = 100
+
+// PartialListError reports that project listing stopped part-way through.
+// In best-effort mode ListProjects returns it alongside the projects
+// fetched before the failing page.
+type PartialListError struct {
+	Page    int   // Page that failed
+	Fetched int   // Projects fetched before the failure
+	Err     error // Underlying error for the failing page
+}
+
+func (e *PartialListError) Error() string {
+	return fmt.Sprintf("project listing incomplete: page %d failed after %d projects: %v", e.Page, e.Fetched, e.Err)
+}
+
+func (e *PartialListError) Unwrap() error {
+	return e.Err
 }
 
-// ListProjects retrieves all projects in the organization/group with pagination
+// ListProjects retrieves all projects in the organization/group with pagination.
+// With a list concurrency above one, the pages after the first are fetched in
+// parallel when GitLab reports the total page count.
 func (c *Client) ListProjects(ctx context.Context, opts *ListProjectsOptions) ([]*Project, error) {
 	if c.client == nil {
 		return nil, fmt.Errorf("GitLab client is not initialized")
@@ -247,40 +674,107 @@ func (c *Client) ListProjects(ctx context.Context, opts *ListProjectsOptions) ([
 	if opts == nil {
 		opts = &ListProjectsOptions{}
 	}
-	
+
 	perPage := opts.PerPage
 	if perPage == 0 {
 		perPage = 20 // GitLab default
 	}
-	if perPage > 100 {
-		perPage = 100 // GitLab maximum
+	if perPage > maxPerPage || opts.HeadOnly {
+		perPage = maxPerPage
 	}
 
-	// Build the list options for the go-gitlab library
-	listOptions := &gitlab.ListGroupProjectsOptions{
-		ListOptions: gitlab.ListOptions{
-			PerPage: perPage,
-			Page:    1,
-		},
+	var allProjects []*Project
+
+	// listFailed reports a page failure, keeping the earlier pages in best-effort mode
+	listFailed := func(page int, err error) ([]*Project, error) {
+		if opts.BestEffort && len(allProjects) > 0 {
+			return allProjects, &PartialListError{
+				Page:    page,
+				Fetched: len(allProjects),
+				Err:     err,
+			}
+		}
+		return nil, err
 	}
-	
-	// Set IncludeSubGroups (default to true if not specified)
-	if opts.IncludeSubgroups != nil {
-		listOptions.IncludeSubGroups = opts.IncludeSubgroups
-	} else {
-		listOptions.IncludeSubGroups = gitlab.Ptr(true)
+
+	var projects []*Project
+	var resp *gitlab.Response
+	var err error
+	if opts.HeadOnly {
+		// A small group is listed completely by this one request
+		projects, resp, err = c.listProjectsPage(ctx, opts, perPage, 1, 1)
+		if err == nil && resp.NextPage == 0 {
+			return projects, nil
+		}
+	}
+	if !opts.HeadOnly || err != nil {
+		projects, resp, err = c.listProjectsPage(ctx, opts, perPage, 1, listPageAttempts)
+		if err != nil {
+			return listFailed(1, err)
+		}
+	}
+	allProjects = append(allProjects, projects...)
+
+	// GitLab omits the total for very large listings, so those stay serial
+	if c.listConcurrency > 1 && resp.NextPage != 0 && resp.TotalPages > 1 {
+		pages := c.listProjectPages(ctx, opts, perPage, 2, resp.TotalPages)
+		for i, p := range pages {
+			if p.err != nil {
+				return listFailed(i+2, p.err)
+			}
+			allProjects = append(allProjects, p.projects...)
+		}
+		return allProjects, nil
 	}
 
-	// Set archived filter if specified
-	if opts.Archived != nil {
-		listOptions.Archived = opts.Archived
+	// Paginate through the remaining projects
+	for resp.NextPage != 0 {
+		page := resp.NextPage
+		projects, resp, err = c.listProjectsPage(ctx, opts, perPage, page, listPageAttempts)
+		if err != nil {
+			return listFailed(page, err)
+		}
+		allProjects = append(allProjects, projects...)
 	}
 
-	var allProjects []*Project
+	return allProjects, nil
+}
+
+// projectPage is one page of a parallel project listing
+type projectPage struct {
+	projects []*Project
+	err      error
+}
+
+// listProjectPages fetches pages first through last with up to
+// c.listConcurrency requests in flight, returning them in page order
+func (c *Client) listProjectPages(ctx context.Context, opts *ListProjectsOptions, perPage, first, last int) []projectPage {
+	pages := make([]projectPage, last-first+1)
+	sem := make(chan struct{}, c.listConcurrency)
+
+	var wg sync.WaitGroup
+	for i := range pages {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			projects, _, err := c.listProjectsPage(ctx, opts, perPage, first+i, listPageAttempts)
+			pages[i] = projectPage{projects: projects, err: err}
+		}(i)
+	}
+	wg.Wait()
+
+	return pages
+}
 
+// listProjectsPage fetches and converts a single page of the project listing,
+// making up to attempts tries when network failures are retryable
+func (c *Client) listProjectsPage(ctx context.Context, opts *ListProjectsOptions, perPage, page, attempts int) ([]*Project, *gitlab.Response, error) {
 	// Configure retry for network failures
 	retryConfig := &apperrors.RetryConfig{
-		MaxAttempts:  3,
+		MaxAttempts:  attempts,
 		InitialDelay: 1 * time.Second,
 		MaxDelay:     10 * time.Second,
 		Multiplier:   2.0,
@@ -289,100 +783,175 @@ func (c *Client) ListProjects(ctx context.Context, opts *ListProjectsOptions) ([
 		},
 	}
 
-	// Determine which API to use based on whether organization is specified
-	isGroupScan := c.organization != ""
-
-	// Paginate through all projects
-	for {
-		var gitlabProjects []*gitlab.Project
-		var resp *gitlab.Response
-		var lastErr error
-
-		// Create a context with timeout for this page
-		pageCtx, cancel := context.WithTimeout(ctx, c.timeout)
-		
-		// Fetch one page with retry logic
-		err := apperrors.RetryWithBackoff(pageCtx, retryConfig, func() error {
-			var projects []*gitlab.Project
-			var response *gitlab.Response
-			var err error
+	// Create a context with timeout for this page
+	pageCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
 
-			if isGroupScan {
-				// List projects in specific group/organization
-				projects, response, err = c.client.Groups.ListGroupProjects(c.organization, listOptions, gitlab.WithContext(pageCtx))
+	var gitlabProjects []*gitlab.Project
+	var resp *gitlab.Response
+
+	// Fetch the page with retry logic
+	err := c.retry(pageCtx, retryConfig, func() error {
+		var projects []*gitlab.Project
+		var response *gitlab.Response
+		var err error
+
+		// Determine which API to use based on whether organization is specified
+		if c.organization != "" {
+			// List projects in specific group/organization
+			listOptions := &gitlab.ListGroupProjectsOptions{
+				ListOptions: gitlab.ListOptions{
+					PerPage: perPage,
+					Page:    page,
+				},
+				Archived: opts.Archived,
+			}
+			if opts.Topic != "" {
+				listOptions.Topic = gitlab.Ptr(opts.Topic)
+			}
+			// Set IncludeSubGroups (default to true if not specified)
+			if opts.IncludeSubgroups != nil {
+				listOptions.IncludeSubGroups = opts.IncludeSubgroups
 			} else {
-				// List all projects user has access to (self-hosted without group)
-				userListOptions := &gitlab.ListProjectsOptions{
-					ListOptions: gitlab.ListOptions{
-						PerPage: perPage,
-						Page:    listOptions.Page,
-					},
-				}
-				if opts.Archived != nil {
-					userListOptions.Archived = opts.Archived
-				}
-				projects, response, err = c.client.Projects.ListProjects(userListOptions, gitlab.WithContext(pageCtx))
+				listOptions.IncludeSubGroups = gitlab.Ptr(true)
 			}
-
-			if err != nil {
-				lastErr = classifyGitLabError(err, response)
-				return lastErr
+			projects, response, err = c.client.Groups.ListGroupProjects(c.organization, listOptions, gitlab.WithContext(pageCtx))
+		} else {
+			// List all projects user has access to (self-hosted without group)
+			userListOptions := &gitlab.ListProjectsOptions{
+				ListOptions: gitlab.ListOptions{
+					PerPage: perPage,
+					Page:    page,
+				},
+				Archived: opts.Archived,
 			}
-			gitlabProjects = projects
-			resp = response
-			return nil
-		})
-		
-		cancel() // Clean up the context
+			if opts.Topic != "" {
+				userListOptions.Topic = gitlab.Ptr(opts.Topic)
+			}
+			projects, response, err = c.client.Projects.ListProjects(userListOptions, gitlab.WithContext(pageCtx))
+		}
 
 		if err != nil {
-			return nil, c.formatUserError(err, resp)
+			return classifyGitLabError(err, response)
 		}
-
-		// Convert GitLab projects to our Project type
-		for _, gp := range gitlabProjects {
-			project := &Project{
-				ID:                gp.ID,
-				Name:              gp.Name,
-				Path:              gp.Path,
-				PathWithNamespace: gp.PathWithNamespace,
-				WebURL:            gp.WebURL,
-				Archived:          gp.Archived,
-			}
-			
-			// Set default branch if available
-			if gp.DefaultBranch != "" {
-				project.DefaultBranch = gp.DefaultBranch
-			}
-			
-			// Set last activity timestamp if available
-			if gp.LastActivityAt != nil {
-				project.LastActivityAt = gp.LastActivityAt.String()
-			}
-			
-			allProjects = append(allProjects, project)
+		gitlabProjects = projects
+		resp = response
+		return nil
+	})
+	if err != nil {
+		return nil, resp, c.formatUserError(err, resp)
+	}
+
+	// Convert GitLab projects to our Project type
+	projects := make([]*Project, 0, len(gitlabProjects))
+	for _, gp := range gitlabProjects {
+		project := &Project{
+			ID:                gp.ID,
+			Name:              gp.Name,
+			Path:              gp.Path,
+			PathWithNamespace: gp.PathWithNamespace,
+			WebURL:            gp.WebURL,
+			Archived:          gp.Archived,
+			IsFork:            gp.ForkedFromProject != nil,
+			DefaultBranch:     gp.DefaultBranch,
+			Topics:            gp.Topics,
 		}
+		project.Namespace, project.TopLevelGroup = SplitNamespace(gp.PathWithNamespace)
 
-		// Check if there are more pages
-		if resp.NextPage == 0 {
-			break
+		// Set last activity timestamp if available
+		if gp.LastActivityAt != nil {
+			project.LastActivityAt = gp.LastActivityAt.String()
 		}
 
-		// Move to next page
-		listOptions.Page = resp.NextPage
+		projects = append(projects, project)
 	}
 
-	return allProjects, nil
+	return projects, resp, nil
+}
+
+// SplitNamespace returns the group path of a project's PathWithNamespace and
+// its top-level group: "org/team/sub/repo" gives "org/team/sub" and "org".
+// A path without a group gives empty strings.
+func SplitNamespace(pathWithNamespace string) (namespace, topLevelGroup string) {
+	i := strings.LastIndex(pathWithNamespace, "/")
+	if i < 0 {
+		return "", ""
+	}
+	namespace = pathWithNamespace[:i]
+	topLevelGroup, _, _ = strings.Cut(namespace, "/")
+	return namespace, topLevelGroup
+}
+
+// SubgroupDepth returns how many subgroup levels below group a project lives:
+// 0 for "group/project", 1 for "group/sub/project", and so on. Projects that
+// are not under group return -1.
+func SubgroupDepth(group string, project *Project) int {
+	group = strings.Trim(group, "/")
+	prefix := group + "/"
+	if len(project.PathWithNamespace) <= len(prefix) || !strings.EqualFold(project.PathWithNamespace[:len(prefix)], prefix) {
+		return -1
+	}
+	return strings.Count(project.PathWithNamespace[len(prefix):], "/")
+}
+
+// FilterBySubgroupDepth keeps the projects at most maxDepth subgroup levels
+// below group. GitLab's API can only include all subgroups or none, so depth
+// is computed from each project's PathWithNamespace. A negative maxDepth or
+// an empty group keeps every project.
+func FilterBySubgroupDepth(projects []*Project, group string, maxDepth int) []*Project {
+	if maxDepth < 0 || group == "" {
+		return projects
+	}
+
+	filtered := make([]*Project, 0, len(projects))
+	for _, p := range projects {
+		if depth := SubgroupDepth(group, p); depth >= 0 && depth <= maxDepth {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// FilterForks keeps the forked projects when forks is true and the rest
+// otherwise
+func FilterForks(projects []*Project, forks bool) []*Project {
+	filtered := make([]*Project, 0, len(projects))
+	for _, p := range projects {
+		if p.IsFork == forks {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
 }
 
 // ListAllProjects is a convenience method that lists all active (non-archived) projects
-// with default pagination settings
-func (c *Client) ListAllProjects(ctx context.Context) ([]*Project, error) {
+// with default pagination settings; a non-empty topic lists only the projects
+// carrying it (see ListProjectsOptions.Topic)
+func (c *Client) ListAllProjects(ctx context.Context, topic string) ([]*Project, error) {
+	archived := false
+	includeSubgroups := true
+	return c.ListProjects(ctx, &ListProjectsOptions{
+		PerPage:          c.listPerPage,
+		Archived:         &archived,
+		IncludeSubgroups: &includeSubgroups,
+		HeadOnly:         c.listHeadOnly,
+		Topic:            topic,
+	})
+}
+
+// ListAllProjectsBestEffort is like ListAllProjects, but if a page fails after
+// earlier pages succeeded it returns the projects fetched so far together with
+// a *PartialListError
+func (c *Client) ListAllProjectsBestEffort(ctx context.Context, topic string) ([]*Project, error) {
 	archived := false
 	includeSubgroups := true
 	return c.ListProjects(ctx, &ListProjectsOptions{
+		PerPage:          c.listPerPage,
 		Archived:         &archived,
 		IncludeSubgroups: &includeSubgroups,
+		BestEffort:       true,
+		HeadOnly:         c.listHeadOnly,
+		Topic:            topic,
 	})
 }
 
@@ -428,10 +997,19 @@ func (c *Client) GetRawFile(ctx context.Context, projectID interface{}, filePath
 
 	// Build the options for the go-gitlab library
 	gitlabOpts := &gitlab.GetRawFileOptions{}
+	var ref string
 	if opts != nil && opts.Ref != "" {
+		ref = opts.Ref
 		gitlabOpts.Ref = gitlab.Ptr(opts.Ref)
 	}
 
+	cache := FileCacheFrom(ctx)
+	if cache != nil {
+		if file, ok := cache.file(projectID, filePath, ref); ok {
+			return file.content, file.err
+		}
+	}
+
 	// Configure retry for network failures
 	retryConfig := &apperrors.RetryConfig{
 		MaxAttempts:  3,
@@ -451,7 +1029,7 @@ func (c *Client) GetRawFile(ctx context.Context, projectID interface{}, filePath
 	defer cancel()
 
 	// Fetch the file with retry logic
-	err := apperrors.RetryWithBackoff(fetchCtx, retryConfig, func() error {
+	err := c.retry(fetchCtx, retryConfig, func() error {
 		content, resp, err := c.client.RepositoryFiles.GetRawFile(
 			projectID,
 			filePath,
@@ -467,9 +1045,16 @@ func (c *Client) GetRawFile(ctx context.Context, projectID interface{}, filePath
 	})
 
 	if err != nil {
-		return nil, c.formatUserError(err, lastResp)
+		err = c.formatUserError(err, lastResp)
+		if cache != nil {
+			cache.storeFile(projectID, filePath, ref, nil, err)
+		}
+		return nil, err
 	}
 
+	if cache != nil {
+		cache.storeFile(projectID, filePath, ref, fileContent, nil)
+	}
 	return fileContent, nil
 }
 
@@ -517,7 +1102,7 @@ func (c *Client) GetFile(ctx context.Context, projectID interface{}, filePath st
 	defer cancel()
 
 	// Fetch the file with retry logic
-	err := apperrors.RetryWithBackoff(fetchCtx, retryConfig, func() error {
+	err := c.retry(fetchCtx, retryConfig, func() error {
 		file, resp, err := c.client.RepositoryFiles.GetFile(
 			projectID,
 			filePath,
@@ -561,9 +1146,12 @@ func (c *Client) GetFile(ctx context.Context, projectID interface{}, filePath st
 
 	// Decode the content if it's base64 encoded
 	if gitlabFile.Encoding == "base64" && gitlabFile.Content != "" {
-		// The go-gitlab library might return Content as a string
-		// We need to handle base64 decoding
-		fileContent.Content = []byte(gitlabFile.Content)
+		// go-gitlab returns Content exactly as the API sent it
+		decoded, err := base64.StdEncoding.DecodeString(gitlabFile.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s content: %w", filePath, err)
+		}
+		fileContent.Content = decoded
 	} else if gitlabFile.Content != "" {
 		fileContent.Content = []byte(gitlabFile.Content)
 	}
@@ -614,7 +1202,7 @@ func (c *Client) GetFileMetadata(ctx context.Context, projectID interface{}, fil
 	defer cancel()
 
 	// Fetch the file metadata with retry logic
-	err := apperrors.RetryWithBackoff(fetchCtx, retryConfig, func() error {
+	err := c.retry(fetchCtx, retryConfig, func() error {
 		file, resp, err := c.client.RepositoryFiles.GetFileMetaData(
 			projectID,
 			filePath,
@@ -739,7 +1327,7 @@ func (c *Client) SearchBlobs(ctx context.Context, projectID interface{}, query s
 
 		pageCtx, cancel := context.WithTimeout(ctx, c.timeout)
 
-		err := apperrors.RetryWithBackoff(pageCtx, retryConfig, func() error {
+		err := c.retry(pageCtx, retryConfig, func() error {
 			var err error
 			blobs, resp, err = c.client.Search.BlobsByProject(projectID, query, searchOpts, gitlab.WithContext(pageCtx))
 			if err != nil {
@@ -821,7 +1409,7 @@ func (c *Client) SearchBlobsByGroup(ctx context.Context, groupID interface{}, qu
 
 		pageCtx, cancel := context.WithTimeout(ctx, c.timeout)
 
-		err := apperrors.RetryWithBackoff(pageCtx, retryConfig, func() error {
+		err := c.retry(pageCtx, retryConfig, func() error {
 			var err error
 			blobs, resp, err = c.client.Search.BlobsByGroup(groupID, query, searchOpts, gitlab.WithContext(pageCtx))
 			if err != nil {
@@ -864,6 +1452,18 @@ func (c *Client) ListRepositoryTree(ctx context.Context, projectID interface{},
 	if opts == nil {
 		opts = &ListTreeOptions{}
 	}
+
+	// Only whole-repository listings are cached
+	var cache *FileCache
+	if opts.Recursive && opts.Path == "" {
+		cache = FileCacheFrom(ctx)
+	}
+	if cache != nil {
+		if files, ok := cache.tree(projectID, opts.Ref); ok {
+			return files, nil
+		}
+	}
+
 	perPage := opts.PerPage
 	if perPage == 0 {
 		perPage = 100
@@ -904,7 +1504,7 @@ func (c *Client) ListRepositoryTree(ctx context.Context, projectID interface{},
 
 		pageCtx, cancel := context.WithTimeout(ctx, c.timeout)
 
-		err := apperrors.RetryWithBackoff(pageCtx, retryConfig, func() error {
+		err := c.retry(pageCtx, retryConfig, func() error {
 			var err error
 			nodes, resp, err = c.client.Repositories.ListTree(projectID, treeOpts, gitlab.WithContext(pageCtx))
 			if err != nil {
@@ -934,5 +1534,208 @@ func (c *Client) ListRepositoryTree(ctx context.Context, projectID interface{},
 		treeOpts.Page = resp.NextPage
 	}
 
+	if cache != nil {
+		cache.storeTree(projectID, opts.Ref, allFiles)
+	}
 	return allFiles, nil
 }
+
+// LatestTag returns the name of a project's most recently updated tag, or ""
+// if the project has no tags
+func (c *Client) LatestTag(ctx context.Context, projectID interface{}) (string, error) {
+	if c.client == nil {
+		return "", fmt.Errorf("GitLab client is not initialized")
+	}
+
+	tagOpts := &gitlab.ListTagsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 1, Page: 1},
+		OrderBy:     gitlab.Ptr("updated"),
+		Sort:        gitlab.Ptr("desc"),
+	}
+
+	retryConfig := &apperrors.RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2.0,
+		ShouldRetry: func(err error) bool {
+			return apperrors.IsRetryable(err)
+		},
+	}
+
+	var tags []*gitlab.Tag
+	var lastResp *gitlab.Response
+
+	listCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.retry(listCtx, retryConfig, func() error {
+		var err error
+		var resp *gitlab.Response
+		tags, resp, err = c.client.Tags.ListTags(projectID, tagOpts, gitlab.WithContext(listCtx))
+		lastResp = resp
+		if err != nil {
+			return classifyGitLabError(err, resp)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return "", c.formatUserError(err, lastResp)
+	}
+
+	if len(tags) == 0 {
+		return "", nil
+	}
+	return tags[0].Name, nil
+}
+
+// WikiPage is one page of a project's wiki
+type WikiPage struct {
+	Slug    string // Page slug, e.g. "deployment/notes"
+	Title   string // Page title
+	Format  string // Markup format, e.g. "markdown"
+	Content string // Page source
+}
+
+// ListWikiPages returns every page of a project's wiki with its content, or
+// nil if the wiki has no pages
+func (c *Client) ListWikiPages(ctx context.Context, projectID interface{}) ([]*WikiPage, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("GitLab client is not initialized")
+	}
+
+	retryConfig := &apperrors.RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2.0,
+		ShouldRetry: func(err error) bool {
+			return apperrors.IsRetryable(err)
+		},
+	}
+
+	var wikis []*gitlab.Wiki
+	var lastResp *gitlab.Response
+
+	listCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.retry(listCtx, retryConfig, func() error {
+		var err error
+		var resp *gitlab.Response
+		wikis, resp, err = c.client.Wikis.ListWikis(projectID, &gitlab.ListWikisOptions{WithContent: gitlab.Ptr(true)}, gitlab.WithContext(listCtx))
+		lastResp = resp
+		if err != nil {
+			return classifyGitLabError(err, resp)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, c.formatUserError(err, lastResp)
+	}
+
+	pages := make([]*WikiPage, 0, len(wikis))
+	for _, w := range wikis {
+		pages = append(pages, &WikiPage{
+			Slug:    w.Slug,
+			Title:   w.Title,
+			Format:  string(w.Format),
+			Content: w.Content,
+		})
+	}
+	return pages, nil
+}
+
+// CommitDate returns when the commit sha was committed, e.g. a file's
+// LastCommitID from GetFile or GetFileMetadata
+func (c *Client) CommitDate(ctx context.Context, projectID interface{}, sha string) (time.Time, error) {
+	if c.client == nil {
+		return time.Time{}, fmt.Errorf("GitLab client is not initialized")
+	}
+
+	if sha == "" {
+		return time.Time{}, fmt.Errorf("commit SHA cannot be empty")
+	}
+
+	retryConfig := &apperrors.RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2.0,
+		ShouldRetry: func(err error) bool {
+			return apperrors.IsRetryable(err)
+		},
+	}
+
+	var commit *gitlab.Commit
+	var lastResp *gitlab.Response
+
+	fetchCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.retry(fetchCtx, retryConfig, func() error {
+		var err error
+		var resp *gitlab.Response
+		commit, resp, err = c.client.Commits.GetCommit(projectID, sha, nil, gitlab.WithContext(fetchCtx))
+		lastResp = resp
+		if err != nil {
+			return classifyGitLabError(err, resp)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return time.Time{}, c.formatUserError(err, lastResp)
+	}
+
+	if commit.CommittedDate == nil {
+		return time.Time{}, fmt.Errorf("commit %s has no committed date", sha)
+	}
+	return *commit.CommittedDate, nil
+}
+
+// ResolveCommit returns the full SHA of the commit ref (a branch, tag, or
+// commit SHA) currently points to
+func (c *Client) ResolveCommit(ctx context.Context, projectID interface{}, ref string) (string, error) {
+	if c.client == nil {
+		return "", fmt.Errorf("GitLab client is not initialized")
+	}
+
+	if ref == "" {
+		return "", fmt.Errorf("ref cannot be empty")
+	}
+
+	retryConfig := &apperrors.RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2.0,
+		ShouldRetry: func(err error) bool {
+			return apperrors.IsRetryable(err)
+		},
+	}
+
+	var commit *gitlab.Commit
+	var lastResp *gitlab.Response
+
+	fetchCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.retry(fetchCtx, retryConfig, func() error {
+		var err error
+		var resp *gitlab.Response
+		commit, resp, err = c.client.Commits.GetCommit(projectID, ref, nil, gitlab.WithContext(fetchCtx))
+		lastResp = resp
+		if err != nil {
+			return classifyGitLabError(err, resp)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return "", c.formatUserError(err, lastResp)
+	}
+	return commit.ID, nil
+}
@@ -1,8 +1,14 @@
 package gitlab
 
 import (
+	"context"
 	stderrors "errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
@@ -229,6 +235,27 @@ func TestClassifyGitLabError(t *testing.T) {
 			expectedType: "RateLimit",
 			shouldRetry:  true,
 		},
+		{
+			name:         "500 Internal Server Error",
+			err:          &mockGitLabError{message: "500 Internal Server Error"},
+			statusCode:   500,
+			expectedType: "Network",
+			shouldRetry:  true,
+		},
+		{
+			name:         "500 rejecting the request",
+			err:          newErrorResponse("/api/v4/projects/1/repository/files/setup.py/raw", "{message: invalid byte sequence in UTF-8}"),
+			statusCode:   500,
+			expectedType: "Unknown",
+			shouldRetry:  false,
+		},
+		{
+			name:         "500 for a file whose path looks like a rejection",
+			err:          newErrorResponse("/api/v4/projects/1/repository/files/tests/invalid_input.py/raw", "500 Internal Server Error"),
+			statusCode:   500,
+			expectedType: "Network",
+			shouldRetry:  true,
+		},
 		{
 			name:         "502 Bad Gateway",
 			err:          &mockGitLabError{message: "bad gateway"},
@@ -283,6 +310,16 @@ func TestClassifyGitLabError(t *testing.T) {
 }
 
 // mockGitLabError simulates a GitLab API error
+// newErrorResponse builds the error go-gitlab returns for a 500 response to
+// a GET of path with message
+func newErrorResponse(path, message string) *gitlab.ErrorResponse {
+	req := httptest.NewRequest(http.MethodGet, "https://gitlab.com"+path, nil)
+	return &gitlab.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusInternalServerError, Request: req},
+		Message:  message,
+	}
+}
+
 type mockGitLabError struct {
 	message string
 }
@@ -1006,3 +1043,608 @@ func TestGetFileOptions(t *testing.T) {
 		})
 	}
 }
+
+// newTestClient creates a Client backed by an httptest server running the given handler
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	gitlabClient, err := gitlab.NewClient("test-token", gitlab.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create go-gitlab client: %v", err)
+	}
+
+	return &Client{
+		client:  gitlabClient,
+		baseURL: server.URL,
+		timeout: 5 * time.Second,
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	tests := []struct {
+		name           string
+		settingsStatus int
+		wantSearch     string
+	}{
+		{
+			name:           "Admin token sees advanced search",
+			settingsStatus: http.StatusOK,
+			wantSearch:     "enabled",
+		},
+		{
+			name:           "Non-admin token cannot read settings",
+			settingsStatus: http.StatusForbidden,
+			wantSearch:     "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v4/user", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"id": 1, "username": "alice", "name": "Alice"}`)
+			})
+			mux.HandleFunc("/api/v4/version", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"version": "16.8.1-ee", "revision": "abc123"}`)
+			})
+			mux.HandleFunc("/api/v4/application/settings", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.settingsStatus)
+				if tt.settingsStatus == http.StatusOK {
+					fmt.Fprint(w, `{"elasticsearch_search": true}`)
+				} else {
+					fmt.Fprint(w, `{"message": "403 Forbidden"}`)
+				}
+			})
+
+			client := newTestClient(t, mux)
+
+			identity, err := client.Identify(context.Background())
+			if err != nil {
+				t.Fatalf("Identify() error = %v", err)
+			}
+
+			if identity.Username != "alice" {
+				t.Errorf("Username = %v, want alice", identity.Username)
+			}
+			if identity.Version != "16.8.1-ee" {
+				t.Errorf("Version = %v, want 16.8.1-ee", identity.Version)
+			}
+			if identity.AdvancedSearchStatus() != tt.wantSearch {
+				t.Errorf("AdvancedSearchStatus() = %v, want %v", identity.AdvancedSearchStatus(), tt.wantSearch)
+			}
+		})
+	}
+}
+
+func TestIdentifyVersionRestricted(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/user", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1, "username": "alice", "name": "Alice"}`)
+	})
+	mux.HandleFunc("/api/v4/version", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "403 Forbidden"}`)
+	})
+
+	client := newTestClient(t, mux)
+
+	identity, err := client.Identify(context.Background())
+	if err != nil {
+		t.Fatalf("Identify() error = %v, want the version treated as optional", err)
+	}
+	if identity.Username != "alice" || identity.Version != "" {
+		t.Errorf("Identify() = %+v, want alice with an unknown version", identity)
+	}
+}
+
+func TestIdentifyAuthenticationFailure(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/user", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message": "401 Unauthorized"}`)
+	})
+
+	client := newTestClient(t, mux)
+
+	_, err := client.Identify(context.Background())
+	if err == nil {
+		t.Fatal("Identify() expected error for unauthorized token")
+	}
+	if !contains(err.Error(), "authentication failed") {
+		t.Errorf("Identify() error = %v, want authentication failure", err)
+	}
+}
+
+func TestIdentifyNilClient(t *testing.T) {
+	c := &Client{}
+	if _, err := c.Identify(context.Background()); err == nil {
+		t.Error("Identify() expected error for uninitialized client")
+	}
+}
+
+func TestGetFileDecodesBase64(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/42/repository/files/.python-version", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"file_name": ".python-version",
+			"file_path": ".python-version",
+			"size": 7,
+			"encoding": "base64",
+			"content": "My4xMS41Cg==",
+			"ref": "main",
+			"commit_id": "c1",
+			"last_commit_id": "c0"
+		}`)
+	})
+
+	client := newTestClient(t, mux)
+
+	file, err := client.GetFile(context.Background(), 42, ".python-version", nil)
+	if err != nil {
+		t.Fatalf("GetFile() error = %v", err)
+	}
+
+	if string(file.Content) != "3.11.5\n" {
+		t.Errorf("Content = %q, want %q", file.Content, "3.11.5\n")
+	}
+	if file.Size != 7 {
+		t.Errorf("Size = %d, want 7", file.Size)
+	}
+	if file.LastCommitID != "c0" {
+		t.Errorf("LastCommitID = %q, want %q", file.LastCommitID, "c0")
+	}
+}
+
+func TestClientAcquireRelease(t *testing.T) {
+	client, err := NewClient(&Config{
+		GitLabURL:   "gitlab.com/myorg",
+		Token:       "test-token",
+		Concurrency: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if client.GetConcurrency() != 1 {
+		t.Errorf("GetConcurrency() = %d, want 1", client.GetConcurrency())
+	}
+
+	ctx := context.Background()
+	if err := client.Acquire(ctx); err != nil {
+		t.Fatalf("first Acquire() error = %v", err)
+	}
+
+	// The only slot is held, so a second Acquire must wait until ctx expires
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := client.Acquire(waitCtx); err == nil {
+		t.Fatal("second Acquire() should block while the slot is held")
+	}
+
+	client.Release()
+	if err := client.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() after Release error = %v", err)
+	}
+	client.Release()
+}
+
+func TestClientAcquireUnbounded(t *testing.T) {
+	client, err := NewClient(&Config{
+		GitLabURL: "gitlab.com/myorg",
+		Token:     "test-token",
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := client.Acquire(context.Background()); err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+	}
+	if client.GetConcurrency() != 0 {
+		t.Errorf("GetConcurrency() = %d, want 0", client.GetConcurrency())
+	}
+}
+
+func TestClientAcquireRampUp(t *testing.T) {
+	client, err := NewClient(&Config{
+		GitLabURL:   "gitlab.com/myorg",
+		Token:       "test-token",
+		Concurrency: 3,
+		RampUp:      100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if client.GetConcurrency() != 3 {
+		t.Errorf("GetConcurrency() = %d, want 3", client.GetConcurrency())
+	}
+
+	// Only one slot is admitted at first
+	ctx := context.Background()
+	start := time.Now()
+	if err := client.Acquire(ctx); err != nil {
+		t.Fatalf("first Acquire() error = %v", err)
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := client.Acquire(waitCtx); err == nil {
+		t.Fatal("second Acquire() should wait for the ramp")
+	}
+
+	// The rest arrive by the end of the ramp
+	for i := 0; i < 2; i++ {
+		if err := client.Acquire(ctx); err != nil {
+			t.Fatalf("Acquire() during the ramp error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("all 3 slots admitted after %v, want about 100ms", elapsed)
+	}
+	for i := 0; i < 3; i++ {
+		client.Release()
+	}
+}
+
+func TestListProjectsBestEffort(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "403 Forbidden"}`)
+			return
+		}
+		w.Header().Set("X-Next-Page", "2")
+		fmt.Fprint(w, `[{"id": 1, "name": "alpha"}, {"id": 2, "name": "beta"}]`)
+	})
+
+	client := newTestClient(t, mux)
+
+	// Without best-effort the whole listing fails
+	projects, err := client.ListProjects(context.Background(), nil)
+	if err == nil {
+		t.Fatal("ListProjects() expected error")
+	}
+	if projects != nil {
+		t.Errorf("ListProjects() returned %d projects, want none", len(projects))
+	}
+
+	projects, err = client.ListProjects(context.Background(), &ListProjectsOptions{BestEffort: true})
+	var partial *PartialListError
+	if !stderrors.As(err, &partial) {
+		t.Fatalf("ListProjects() error = %v, want *PartialListError", err)
+	}
+	if len(projects) != 2 {
+		t.Errorf("ListProjects() returned %d projects, want 2", len(projects))
+	}
+	if partial.Page != 2 || partial.Fetched != 2 {
+		t.Errorf("PartialListError = page %d fetched %d, want page 2 fetched 2", partial.Page, partial.Fetched)
+	}
+}
+
+func TestListProjectsHeadOnly(t *testing.T) {
+	tests := []struct {
+		name         string
+		pages        int  // Pages the group has
+		failFirst    bool // First request fails with a 502
+		wantRequests int
+	}{
+		{name: "small group", pages: 1, wantRequests: 1},
+		{name: "larger group continues", pages: 2, wantRequests: 2},
+		{name: "failed first request still lists", pages: 1, failFirst: true, wantRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v4/projects", func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) == 1 && tt.failFirst {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				if got := r.URL.Query().Get("per_page"); got != "100" {
+					t.Errorf("per_page = %s, want 100", got)
+				}
+				page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+				if page < tt.pages {
+					w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+				}
+				fmt.Fprintf(w, `[{"id": %d, "name": "p%d"}]`, page, page)
+			})
+
+			client := newTestClient(t, mux)
+			projects, err := client.ListProjects(context.Background(), &ListProjectsOptions{HeadOnly: true})
+			if err != nil {
+				t.Fatalf("ListProjects() error = %v", err)
+			}
+			if len(projects) != tt.pages {
+				t.Errorf("ListProjects() returned %d projects, want %d", len(projects), tt.pages)
+			}
+			if got := int(requests.Load()); got != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestListAllProjectsPerPage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("per_page"); got != "50" {
+			t.Errorf("per_page = %s, want 50", got)
+		}
+		fmt.Fprint(w, `[{"id": 1, "name": "alpha"}]`)
+	})
+
+	client := newTestClient(t, mux)
+	client.listPerPage = 50
+
+	if _, err := client.ListAllProjects(context.Background(), ""); err != nil {
+		t.Fatalf("ListAllProjects() error = %v", err)
+	}
+}
+
+func TestListAllProjectsTopic(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("topic"); got != "python-service" {
+			t.Errorf("topic = %q, want python-service", got)
+		}
+		fmt.Fprint(w, `[{"id": 1, "name": "billing", "topics": ["python-service", "payments"]}]`)
+	})
+
+	client := newTestClient(t, mux)
+	projects, err := client.ListAllProjects(context.Background(), "python-service")
+	if err != nil {
+		t.Fatalf("ListAllProjects() error = %v", err)
+	}
+	if len(projects) != 1 || len(projects[0].Topics) != 2 || projects[0].Topics[0] != "python-service" {
+		t.Errorf("ListAllProjects() = %+v, want billing with its topics", projects)
+	}
+}
+
+func TestListProjectsParallelPages(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects", func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if n <= peak || maxInFlight.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 4 {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "403 Forbidden"}`)
+			return
+		}
+		w.Header().Set("X-Total-Pages", "5")
+		if page < 5 {
+			w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+		}
+		fmt.Fprintf(w, `[{"id": %d, "name": "p%d"}]`, page, page)
+	})
+
+	client := newTestClient(t, mux)
+	client.listConcurrency = 2
+
+	projects, err := client.ListProjects(context.Background(), &ListProjectsOptions{BestEffort: true})
+	var partial *PartialListError
+	if !stderrors.As(err, &partial) {
+		t.Fatalf("ListProjects() error = %v, want *PartialListError", err)
+	}
+	if partial.Page != 4 || partial.Fetched != 3 {
+		t.Errorf("PartialListError = page %d fetched %d, want page 4 fetched 3", partial.Page, partial.Fetched)
+	}
+	for i, p := range projects {
+		if p.ID != i+1 {
+			t.Errorf("projects[%d].ID = %d, want pages in order", i, p.ID)
+		}
+	}
+	if peak := maxInFlight.Load(); peak != 2 {
+		t.Errorf("max pages in flight = %d, want 2", peak)
+	}
+}
+
+func TestCheckGroupAccess(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/groups/myorg%2Fsub", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 7, "full_path": "myorg/sub"}`)
+	})
+	mux.HandleFunc("/api/v4/groups/myorg%2Fmissing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "404 Group Not Found"}`)
+	})
+	mux.HandleFunc("/api/v4/groups/myorg%2Fprivate", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "403 Forbidden"}`)
+	})
+
+	client := newTestClient(t, mux)
+
+	tests := []struct {
+		name    string
+		group   string
+		wantErr string
+	}{
+		{name: "accessible group", group: "myorg/sub"},
+		{name: "no group", group: ""},
+		{name: "missing group", group: "myorg/missing", wantErr: "group 'myorg/missing' not found or not accessible with this token"},
+		{name: "forbidden group", group: "myorg/private", wantErr: "group 'myorg/private' not found or not accessible with this token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.CheckGroupAccess(context.Background(), tt.group)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckGroupAccess() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("CheckGroupAccess() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectExists(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/myorg%2Fbilling", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 7, "path_with_namespace": "myorg/billing"}`)
+	})
+	mux.HandleFunc("/api/v4/projects/myorg%2Fbiling", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "404 Project Not Found"}`)
+	})
+
+	client := newTestClient(t, mux)
+
+	for path, want := range map[string]bool{"myorg/billing": true, "myorg/biling": false} {
+		if got, err := client.ProjectExists(context.Background(), path); err != nil || got != want {
+			t.Errorf("ProjectExists(%q) = %v, %v, want %v", path, got, err, want)
+		}
+	}
+}
+
+func TestFilterBySubgroupDepth(t *testing.T) {
+	projects := []*Project{
+		{Name: "direct", PathWithNamespace: "myorg/direct"},
+		{Name: "team", PathWithNamespace: "myorg/team/service"},
+		{Name: "nested", PathWithNamespace: "myorg/team/sub/worker"},
+		{Name: "mixed-case", PathWithNamespace: "MyOrg/Team/app"},
+		{Name: "elsewhere", PathWithNamespace: "otherorg/tool"},
+	}
+
+	tests := []struct {
+		name     string
+		group    string
+		maxDepth int
+		want     []string
+	}{
+		{name: "direct projects only", group: "myorg", maxDepth: 0, want: []string{"direct"}},
+		{name: "one level", group: "myorg", maxDepth: 1, want: []string{"direct", "team", "mixed-case"}},
+		{name: "unlimited", group: "myorg", maxDepth: -1, want: []string{"direct", "team", "nested", "mixed-case", "elsewhere"}},
+		{name: "subgroup target", group: "myorg/team/", maxDepth: 0, want: []string{"team", "mixed-case"}},
+		{name: "no group", group: "", maxDepth: 0, want: []string{"direct", "team", "nested", "mixed-case", "elsewhere"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, p := range FilterBySubgroupDepth(projects, tt.group, tt.maxDepth) {
+				got = append(got, p.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("FilterBySubgroupDepth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitNamespace(t *testing.T) {
+	tests := []struct {
+		path          string
+		wantNamespace string
+		wantTopLevel  string
+	}{
+		{"org/team/sub/repo", "org/team/sub", "org"},
+		{"org/team/repo", "org/team", "org"},
+		{"org/repo", "org", "org"},
+		{"repo", "", ""},
+	}
+
+	for _, tt := range tests {
+		namespace, topLevel := SplitNamespace(tt.path)
+		if namespace != tt.wantNamespace || topLevel != tt.wantTopLevel {
+			t.Errorf("SplitNamespace(%q) = %q, %q; want %q, %q", tt.path, namespace, topLevel, tt.wantNamespace, tt.wantTopLevel)
+		}
+	}
+}
+
+func TestListProjectsNamespace(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": 1, "name": "repo", "path_with_namespace": "org/team/sub/repo"}]`)
+	})
+
+	client := newTestClient(t, mux)
+	projects, err := client.ListProjects(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListProjects() error = %v", err)
+	}
+	if len(projects) != 1 {
+		t.Fatalf("ListProjects() returned %d projects, want 1", len(projects))
+	}
+	if projects[0].Namespace != "org/team/sub" || projects[0].TopLevelGroup != "org" {
+		t.Errorf("Namespace = %q, TopLevelGroup = %q; want org/team/sub, org", projects[0].Namespace, projects[0].TopLevelGroup)
+	}
+}
+
+func TestListProjectsForks(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": 1, "name": "upstream"}, {"id": 2, "name": "fork", "forked_from_project": {"id": 99}}]`)
+	})
+
+	client := newTestClient(t, mux)
+	projects, err := client.ListProjects(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListProjects() error = %v", err)
+	}
+	if len(projects) != 2 || projects[0].IsFork || !projects[1].IsFork {
+		t.Fatalf("ListProjects() = %+v, want upstream then a fork", projects)
+	}
+
+	if got := FilterForks(projects, false); len(got) != 1 || got[0].Name != "upstream" {
+		t.Errorf("FilterForks(false) = %v, want [upstream]", got)
+	}
+	if got := FilterForks(projects, true); len(got) != 1 || got[0].Name != "fork" {
+		t.Errorf("FilterForks(true) = %v, want [fork]", got)
+	}
+}
+
+func TestLatestTag(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "Most recent tag", body: `[{"name": "v2.1.0"}]`, want: "v2.1.0"},
+		{name: "No tags", body: `[]`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v4/projects/42/repository/tags", func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				if q.Get("order_by") != "updated" || q.Get("sort") != "desc" || q.Get("per_page") != "1" {
+					t.Errorf("unexpected query %q", r.URL.RawQuery)
+				}
+				fmt.Fprint(w, tt.body)
+			})
+			c := newTestClient(t, mux)
+
+			got, err := c.LatestTag(context.Background(), 42)
+			if err != nil {
+				t.Fatalf("LatestTag() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("LatestTag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
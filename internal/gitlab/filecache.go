@@ -0,0 +1,106 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	apperrors "github.com/gbjohnso/gitlab-python-scanner/internal/errors"
+)
+
+// FileCache remembers the raw files and recursive tree listings read from a
+// project, so callers that read the same project more than once (e.g. the
+// scan and the searches of --mode both) fetch each file once. Files that
+// don't exist are remembered too; other failures aren't, so they are
+// fetched again. Cached content is shared and must not be modified. It is
+// safe for concurrent use.
+type FileCache struct {
+	mu    sync.Mutex
+	head  string // Commit the default branch points to ("" = unknown)
+	files map[fileCacheKey]cachedFile
+	trees map[fileCacheKey][]*TreeFile
+}
+
+// fileCacheKey identifies a file (or, with an empty path, a tree) at a ref
+type fileCacheKey struct {
+	projectID string
+	path      string
+	ref       string
+}
+
+// cachedFile is a fetched file's content, or the error fetching it returned
+type cachedFile struct {
+	content []byte
+	err     error
+}
+
+// NewFileCache creates an empty cache
+func NewFileCache() *FileCache {
+	return &FileCache{
+		files: make(map[fileCacheKey]cachedFile),
+		trees: make(map[fileCacheKey][]*TreeFile),
+	}
+}
+
+// SetHead records the commit a project's default branch points to, so reads
+// at that commit and reads at the default branch ("" ref) share entries
+func (fc *FileCache) SetHead(sha string) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.head = sha
+}
+
+// key builds the cache key for path at ref, with the default branch written
+// as its head commit when that's known
+func (fc *FileCache) key(projectID interface{}, path, ref string) fileCacheKey {
+	if ref == "" {
+		ref = fc.head
+	}
+	return fileCacheKey{projectID: fmt.Sprint(projectID), path: path, ref: ref}
+}
+
+func (fc *FileCache) file(projectID interface{}, path, ref string) (cachedFile, bool) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	file, ok := fc.files[fc.key(projectID, path, ref)]
+	return file, ok
+}
+
+func (fc *FileCache) storeFile(projectID interface{}, path, ref string, content []byte, err error) {
+	var appErr *apperrors.AppError
+	if err != nil && (!errors.As(err, &appErr) || appErr.Type != apperrors.ErrorTypeNotFound) {
+		return
+	}
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.files[fc.key(projectID, path, ref)] = cachedFile{content: content, err: err}
+}
+
+func (fc *FileCache) tree(projectID interface{}, ref string) ([]*TreeFile, bool) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	files, ok := fc.trees[fc.key(projectID, "", ref)]
+	return files, ok
+}
+
+func (fc *FileCache) storeTree(projectID interface{}, ref string, files []*TreeFile) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.trees[fc.key(projectID, "", ref)] = files
+}
+
+// fileCacheContextKey is the context key WithFileCache stores the cache under
+type fileCacheContextKey struct{}
+
+// WithFileCache returns a context under which the client's GetRawFile and
+// recursive ListRepositoryTree calls are served from, and recorded in, cache
+func WithFileCache(ctx context.Context, cache *FileCache) context.Context {
+	return context.WithValue(ctx, fileCacheContextKey{}, cache)
+}
+
+// FileCacheFrom returns the cache WithFileCache put on ctx, or nil
+func FileCacheFrom(ctx context.Context) *FileCache {
+	cache, _ := ctx.Value(fileCacheContextKey{}).(*FileCache)
+	return cache
+}
@@ -0,0 +1,69 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFileCache(t *testing.T) {
+	const head = "4f2c9e1a7b3d5c8e9f0a1b2c3d4e5f6a7b8c9d0e"
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/repository/tree"):
+			w.Write([]byte(`[{"name": ".python-version", "path": ".python-version", "type": "blob"}]`))
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			w.Write([]byte("3.12\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&Config{GitLabURL: server.URL + "/org", Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	cache := NewFileCache()
+	cache.SetHead(head)
+	ctx := WithFileCache(context.Background(), cache)
+
+	// Reads at the head commit and at the default branch share one fetch
+	for _, ref := range []string{head, ""} {
+		content, err := client.GetRawFile(ctx, 1, ".python-version", &GetFileOptions{Ref: ref})
+		if err != nil || string(content) != "3.12\n" {
+			t.Fatalf("GetRawFile(ref %q) = %q, %v", ref, content, err)
+		}
+		if _, err := client.ListRepositoryTree(ctx, 1, &ListTreeOptions{Recursive: true, Ref: ref}); err != nil {
+			t.Fatalf("ListRepositoryTree(ref %q) error = %v", ref, err)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server saw %d requests, want one file and one tree", got)
+	}
+
+	// Missing files are remembered as missing
+	requests.Store(0)
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetRawFile(ctx, 1, "setup.py", nil); err == nil {
+			t.Fatal("GetRawFile() of a missing file succeeded")
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server saw %d requests for a missing file, want 1", got)
+	}
+
+	// Without a cache every call is sent
+	requests.Store(0)
+	client.GetRawFile(context.Background(), 1, ".python-version", nil)
+	client.GetRawFile(context.Background(), 1, ".python-version", nil)
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server saw %d uncached requests, want 2", got)
+	}
+}
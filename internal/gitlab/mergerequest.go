@@ -0,0 +1,123 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apperrors "github.com/gbjohnso/gitlab-python-scanner/internal/errors"
+	"github.com/xanzy/go-gitlab"
+)
+
+// MergeRequest is a merge request's branches and the files it changes
+type MergeRequest struct {
+	IID          int    // Project-scoped ID, e.g. 42 for !42
+	Title        string // Merge request title
+	WebURL       string // Merge request page
+	SourceBranch string // Branch with the proposed changes
+	TargetBranch string // Branch the changes would be merged into
+
+	// BaseSHA and HeadSHA are the commits the changes are between: the
+	// target branch's merge base and the source branch's latest commit.
+	// Either is "" until GitLab has computed the diff.
+	BaseSHA string
+	HeadSHA string
+
+	Changes []*FileChange
+
+	// Overflow means GitLab left out some changed files because there were
+	// too many to list
+	Overflow bool
+}
+
+// FileChange is one file a merge request changes
+type FileChange struct {
+	OldPath     string // Path on the target branch
+	NewPath     string // Path on the source branch
+	NewFile     bool
+	DeletedFile bool
+	RenamedFile bool
+
+	// Diff is the change as a unified diff without file headers, or ""
+	// when GitLab omits it for being too large
+	Diff string
+}
+
+// BaseRef returns the ref to read files as they were before the merge
+// request: its base commit, or the target branch before one is known
+func (mr *MergeRequest) BaseRef() string {
+	if mr.BaseSHA != "" {
+		return mr.BaseSHA
+	}
+	return mr.TargetBranch
+}
+
+// HeadRef returns the ref to read files as the merge request proposes them:
+// its head commit, or the source branch before one is known
+func (mr *MergeRequest) HeadRef() string {
+	if mr.HeadSHA != "" {
+		return mr.HeadSHA
+	}
+	return mr.SourceBranch
+}
+
+// GetMergeRequestChanges returns merge request iid of a project with the
+// files it changes
+func (c *Client) GetMergeRequestChanges(ctx context.Context, projectID interface{}, iid int) (*MergeRequest, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("GitLab client is not initialized")
+	}
+
+	retryConfig := &apperrors.RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2.0,
+		ShouldRetry: func(err error) bool {
+			return apperrors.IsRetryable(err)
+		},
+	}
+
+	var mr *gitlab.MergeRequest
+	var lastResp *gitlab.Response
+
+	fetchCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.retry(fetchCtx, retryConfig, func() error {
+		var err error
+		var resp *gitlab.Response
+		mr, resp, err = c.client.MergeRequests.GetMergeRequestChanges(projectID, iid, nil, gitlab.WithContext(fetchCtx))
+		lastResp = resp
+		if err != nil {
+			return classifyGitLabError(err, resp)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, c.formatUserError(err, lastResp)
+	}
+
+	result := &MergeRequest{
+		IID:          mr.IID,
+		Title:        mr.Title,
+		WebURL:       mr.WebURL,
+		SourceBranch: mr.SourceBranch,
+		TargetBranch: mr.TargetBranch,
+		BaseSHA:      mr.DiffRefs.BaseSha,
+		HeadSHA:      mr.DiffRefs.HeadSha,
+		Overflow:     mr.Overflow,
+	}
+	for _, change := range mr.Changes {
+		result.Changes = append(result.Changes, &FileChange{
+			OldPath:     change.OldPath,
+			NewPath:     change.NewPath,
+			NewFile:     change.NewFile,
+			DeletedFile: change.DeletedFile,
+			RenamedFile: change.RenamedFile,
+			Diff:        change.Diff,
+		})
+	}
+	return result, nil
+}
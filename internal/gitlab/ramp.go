@@ -0,0 +1,25 @@
+package gitlab
+
+import (
+	"sync"
+	"time"
+)
+
+// rampUp admits a client's concurrency slots gradually. All but one slot
+// are held back when the client is created and released evenly over
+// duration, starting from the first Acquire, so a scan opens with one
+// request in flight rather than a burst of Concurrency.
+type rampUp struct {
+	once     sync.Once
+	duration time.Duration
+	held     int // Slots held back when the client was created
+}
+
+// start schedules the release of the held-back slots, the first time it's called
+func (r *rampUp) start(slots chan struct{}) {
+	r.once.Do(func() {
+		for i := 1; i <= r.held; i++ {
+			time.AfterFunc(r.duration*time.Duration(i)/time.Duration(r.held), func() { <-slots })
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	apperrors "github.com/gbjohnso/gitlab-python-scanner/internal/errors"
+	"github.com/xanzy/go-gitlab"
+)
+
+// Git modes and tree entry types the file API doesn't expose
+const (
+	modeSymlink   = "120000"
+	typeSubmodule = "commit"
+)
+
+// TreeEntry is a path's entry in its directory's listing
+type TreeEntry struct {
+	Name string // Entry name
+	Path string // Full path in the repository
+	Type string // "blob", "tree", or "commit" (a submodule)
+	Mode string // Git file mode, e.g. "100644", or "120000" for a symlink
+}
+
+// IsSymlink reports whether the entry is a symbolic link. The file API
+// serves a symlink's target path as its content.
+func (e *TreeEntry) IsSymlink() bool {
+	return e.Type == "blob" && e.Mode == modeSymlink
+}
+
+// IsSubmodule reports whether the entry is a submodule, whose files the
+// file API can't read
+func (e *TreeEntry) IsSubmodule() bool {
+	return e.Type == typeSubmodule
+}
+
+// StatPath returns filePath's entry at ref ("" for the default branch) by
+// listing its parent directory, to tell symlinks and submodules apart from
+// regular files, which the file API can't. It returns nil if the directory
+// has no such entry.
+func (c *Client) StatPath(ctx context.Context, projectID interface{}, filePath, ref string) (*TreeEntry, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("GitLab client is not initialized")
+	}
+
+	treeOpts := &gitlab.ListTreeOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+			Page:    1,
+		},
+	}
+	if dir := path.Dir(filePath); dir != "." {
+		treeOpts.Path = gitlab.Ptr(dir)
+	}
+	if ref != "" {
+		treeOpts.Ref = gitlab.Ptr(ref)
+	}
+
+	retryConfig := &apperrors.RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2.0,
+		ShouldRetry: func(err error) bool {
+			return apperrors.IsRetryable(err)
+		},
+	}
+
+	for {
+		var nodes []*gitlab.TreeNode
+		var resp *gitlab.Response
+
+		pageCtx, cancel := context.WithTimeout(ctx, c.timeout)
+
+		err := c.retry(pageCtx, retryConfig, func() error {
+			var err error
+			nodes, resp, err = c.client.Repositories.ListTree(projectID, treeOpts, gitlab.WithContext(pageCtx))
+			if err != nil {
+				return classifyGitLabError(err, resp)
+			}
+			return nil
+		})
+		cancel()
+
+		if err != nil {
+			return nil, c.formatUserError(err, resp)
+		}
+
+		for _, node := range nodes {
+			if node.Path == filePath {
+				return &TreeEntry{Name: node.Name, Path: node.Path, Type: node.Type, Mode: node.Mode}, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		treeOpts.Page = resp.NextPage
+	}
+}
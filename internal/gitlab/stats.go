@@ -0,0 +1,101 @@
+package gitlab
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// CallStats is a snapshot of the API traffic a client has sent since it was
+// created (Config.Stats). Take one before and after a scan and Sub them to
+// measure the scan alone.
+type CallStats struct {
+	Requests     int64 // Requests sent, retries included
+	Retries      int64 // Attempts that repeated a failed request
+	FilesFetched int64 // Repository files successfully downloaded
+	Bytes        int64 // Response body bytes read
+}
+
+// Sub returns the traffic in s that isn't in earlier
+func (s CallStats) Sub(earlier CallStats) CallStats {
+	return CallStats{
+		Requests:     s.Requests - earlier.Requests,
+		Retries:      s.Retries - earlier.Retries,
+		FilesFetched: s.FilesFetched - earlier.FilesFetched,
+		Bytes:        s.Bytes - earlier.Bytes,
+	}
+}
+
+// callStats counts a client's API traffic. It is safe for concurrent use.
+type callStats struct {
+	requests     atomic.Int64
+	retries      atomic.Int64
+	filesFetched atomic.Int64
+	bytes        atomic.Int64
+}
+
+// RecordAttempt is a retryablehttp.RequestLogHook that counts go-gitlab's
+// own retries
+func (s *callStats) RecordAttempt(_ retryablehttp.Logger, _ *http.Request, attempt int) {
+	if attempt > 0 {
+		s.retries.Add(1)
+	}
+}
+
+// statsTransport is an http.RoundTripper that counts the requests it sends
+// and the response bytes read back
+type statsTransport struct {
+	base  http.RoundTripper
+	stats *callStats
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *statsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.stats.requests.Add(1)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode/100 == 2 && isFileDownload(req) {
+		t.stats.filesFetched.Add(1)
+	}
+	resp.Body = &countingBody{ReadCloser: resp.Body, bytes: &t.stats.bytes}
+	return resp, nil
+}
+
+// isFileDownload reports whether req fetches a repository file's content,
+// raw or base64-encoded. HEAD requests for a file's metadata (e.g. from
+// GetFileMetadata) don't download it.
+func isFileDownload(req *http.Request) bool {
+	return req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/repository/files/")
+}
+
+// countingBody adds the bytes read from a response body to a counter
+type countingBody struct {
+	io.ReadCloser
+	bytes *atomic.Int64
+}
+
+// Read implements io.Reader
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes.Add(int64(n))
+	return n, err
+}
+
+// Stats returns the API traffic sent so far, or a zero CallStats for a
+// client created without Config.Stats
+func (c *Client) Stats() CallStats {
+	if c.stats == nil {
+		return CallStats{}
+	}
+	return CallStats{
+		Requests:     c.stats.requests.Load(),
+		Retries:      c.stats.retries.Load(),
+		FilesFetched: c.stats.filesFetched.Load(),
+		Bytes:        c.stats.bytes.Load(),
+	}
+}
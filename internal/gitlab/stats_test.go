@@ -0,0 +1,56 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClientStats(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first attempt is unavailable, so go-gitlab retries it itself
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("3.12\n"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&Config{GitLabURL: server.URL + "/org", Token: "test-token", Stats: true})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	before := client.Stats()
+	if _, err := client.GetRawFile(context.Background(), 1, ".python-version", nil); err != nil {
+		t.Fatalf("GetRawFile() error = %v", err)
+	}
+	got := client.Stats().Sub(before)
+	want := CallStats{Requests: 2, Retries: 1, FilesFetched: 1, Bytes: 5}
+	if got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	// Metadata is read with HEAD, which doesn't download the file
+	before = client.Stats()
+	if _, err := client.GetFileMetadata(context.Background(), 1, ".python-version", nil); err != nil {
+		t.Fatalf("GetFileMetadata() error = %v", err)
+	}
+	if got := client.Stats().Sub(before); got.Requests != 1 || got.FilesFetched != 0 {
+		t.Errorf("Stats() after GetFileMetadata() = %+v, want 1 request and no files", got)
+	}
+}
+
+func TestClientWithoutStats(t *testing.T) {
+	client, err := NewClient(&Config{GitLabURL: "gitlab.com/org", Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client.stats != nil || client.Stats() != (CallStats{}) {
+		t.Error("a client without Stats counts its traffic")
+	}
+}
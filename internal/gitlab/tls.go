@@ -0,0 +1,142 @@
+package gitlab
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	apperrors "github.com/gbjohnso/gitlab-python-scanner/internal/errors"
+)
+
+// tlsVersions maps the versions ParseTLSVersion accepts to crypto/tls's
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseTLSVersion returns the crypto/tls version for s, "1.2" or "1.3".
+// The setting only ever raises the bar: 1.0 and 1.1 are below Go's default
+// minimum and are refused rather than re-enabled.
+func ParseTLSVersion(s string) (uint16, error) {
+	version, ok := tlsVersions[s]
+	if !ok {
+		if s == "1.0" || s == "1.1" {
+			return 0, fmt.Errorf("TLS %s is insecure and can't be enabled (want 1.2 or 1.3)", s)
+		}
+		return 0, fmt.Errorf("unknown TLS version %q (want 1.2 or 1.3)", s)
+	}
+	return version, nil
+}
+
+// ParseCipherSuites returns the IDs of the named cipher suites, as Go names
+// them (e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256). Suites Go considers
+// insecure are refused. TLS 1.3 suites aren't configurable and are always
+// enabled, so they're refused too.
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		for _, version := range suite.SupportedVersions {
+			if version != tls.VersionTLS13 {
+				known[suite.Name] = suite.ID
+				break
+			}
+		}
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or unconfigurable cipher suite %q (want one of %s)", name, strings.Join(sortedKeys(known), ", "))
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// sortedKeys returns m's keys in order, for error messages
+func sortedKeys(m map[string]uint16) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tlsTransport sends requests with a minimum TLS version and cipher suites,
+// and says so when a handshake fails, since crypto/tls's own errors (e.g.
+// "protocol version not supported") don't mention the setting behind them
+type tlsTransport struct {
+	base       http.RoundTripper
+	minVersion uint16
+	ciphers    bool // Whether cipher suites were restricted
+}
+
+// newTLSTransport returns a transport requiring minVersion (0 = Go's
+// default) and, if ciphers isn't empty, only those cipher suites. A
+// minVersion older than TLS 1.2 is ignored, so Go's default is never lowered.
+func newTLSTransport(minVersion uint16, ciphers []uint16) *tlsTransport {
+	if minVersion < tls.VersionTLS12 {
+		minVersion = 0
+	}
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.TLSClientConfig = &tls.Config{
+		MinVersion:   minVersion,
+		CipherSuites: ciphers,
+	}
+	return &tlsTransport{base: base, minVersion: minVersion, ciphers: len(ciphers) > 0}
+}
+
+// RoundTrip sends req, explaining handshake failures. They aren't
+// retryable: the server will refuse the same requirement every time.
+func (t *tlsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil && isHandshakeError(err) {
+		return nil, &apperrors.AppError{
+			Type:    apperrors.ErrorTypeUnknown,
+			Message: fmt.Sprintf("TLS handshake with %s failed; the server may not support %s", req.URL.Host, t.requirement()),
+			Err:     err,
+		}
+	}
+	return resp, err
+}
+
+// requirement describes what the transport asks of servers
+func (t *tlsTransport) requirement() string {
+	var parts []string
+	if t.minVersion != 0 {
+		parts = append(parts, tlsVersionName(t.minVersion)+" or later")
+	}
+	if t.ciphers {
+		parts = append(parts, "the configured cipher suites")
+	}
+	return strings.Join(parts, " with ")
+}
+
+// tlsVersionName names a crypto/tls version, e.g. "TLS 1.2"
+func tlsVersionName(version uint16) string {
+	for name, v := range tlsVersions {
+		if v == version {
+			return "TLS " + name
+		}
+	}
+	return fmt.Sprintf("TLS version %#04x", version)
+}
+
+// isHandshakeError reports whether err is crypto/tls failing to negotiate,
+// either locally or by the server's alert
+func isHandshakeError(err error) bool {
+	var alert tls.AlertError
+	if errors.As(err, &alert) {
+		return true
+	}
+	var recordErr tls.RecordHeaderError
+	if errors.As(err, &recordErr) {
+		return false // Not TLS at all, e.g. an http:// server
+	}
+	return strings.Contains(err.Error(), "tls: ")
+}
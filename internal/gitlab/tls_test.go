@@ -0,0 +1,63 @@
+package gitlab
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseTLSVersion(t *testing.T) {
+	if got, err := ParseTLSVersion("1.3"); err != nil || got != tls.VersionTLS13 {
+		t.Errorf("ParseTLSVersion(1.3) = %#x, %v", got, err)
+	}
+	for _, s := range []string{"", "1", "1.0", "1.1", "1.4", "TLS1.2"} {
+		if _, err := ParseTLSVersion(s); err == nil {
+			t.Errorf("ParseTLSVersion(%q) should fail", s)
+		}
+	}
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	got, err := ParseCipherSuites([]string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"})
+	if err != nil || len(got) != 1 || got[0] != tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 {
+		t.Errorf("ParseCipherSuites() = %v, %v", got, err)
+	}
+	for _, name := range []string{"TLS_AES_128_GCM_SHA256", "TLS_RSA_WITH_RC4_128_SHA", "bogus"} {
+		if _, err := ParseCipherSuites([]string{name}); err == nil {
+			t.Errorf("ParseCipherSuites(%q) should fail", name)
+		}
+	}
+}
+
+func TestClientTLSMinVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("3.12\n"))
+	}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	client, err := NewClient(&Config{GitLabURL: server.URL + "/org", Token: "test-token", TLSMinVersion: tls.VersionTLS13})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	// The version is refused before the test server's certificate is checked
+	_, err = client.GetRawFile(context.Background(), 1, ".python-version", nil)
+	if err == nil {
+		t.Fatal("GetRawFile() should fail against a TLS 1.2 server")
+	}
+	if !strings.Contains(err.Error(), "may not support TLS 1.3 or later") {
+		t.Errorf("GetRawFile() error = %v, want it to name the required version", err)
+	}
+}
+
+func TestNewTLSTransportKeepsDefaultMinimum(t *testing.T) {
+	transport := newTLSTransport(tls.VersionTLS10, nil)
+	if got := transport.base.(*http.Transport).TLSClientConfig.MinVersion; got != 0 {
+		t.Errorf("MinVersion = %#x, want Go's default", got)
+	}
+}
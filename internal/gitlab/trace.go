@@ -0,0 +1,148 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// redactedHeaders are request headers that carry credentials
+var redactedHeaders = []string{"Private-Token", "Authorization", "Job-Token"}
+
+// redactedParams are query parameters that carry credentials
+var redactedParams = []string{"private_token", "access_token", "job_token"}
+
+// TraceMiss is the Category of a file probe GitLab answered with 404, i.e.
+// a candidate file that doesn't exist. Scans make thousands of them, so they
+// are only traced when TraceTransport.LogMisses is set.
+const TraceMiss = "miss"
+
+// TraceEntry is one API call recorded by TraceTransport, written as a JSON line
+type TraceEntry struct {
+	Timestamp  time.Time           `json:"timestamp"`
+	Method     string              `json:"method"`
+	URL        string              `json:"url"`
+	Status     int                 `json:"status,omitempty"`
+	DurationMS int64               `json:"duration_ms"`
+	Retry      int                 `json:"retry"`
+	Error      string              `json:"error,omitempty"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Category   string              `json:"category,omitempty"` // TraceMiss, or "" for every other call
+}
+
+// TraceTransport is an http.RoundTripper that records every API call,
+// including each retry attempt, to a writer. Credentials are redacted from
+// the recorded headers and URL. Install RecordAttempt as the retrying
+// client's request hook to number retries. It is safe for concurrent use.
+type TraceTransport struct {
+	base http.RoundTripper
+
+	// LogMisses also records file probes that found no file (see TraceMiss)
+	LogMisses bool
+
+	mu       sync.Mutex
+	w        io.Writer
+	attempts map[*http.Request]int // Retry number of requests about to be sent
+}
+
+// NewTraceTransport wraps base (http.DefaultTransport if nil) so that each
+// request is written to w
+func NewTraceTransport(base http.RoundTripper, w io.Writer) *TraceTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &TraceTransport{
+		base:     base,
+		w:        w,
+		attempts: make(map[*http.Request]int),
+	}
+}
+
+// RecordAttempt is a retryablehttp.RequestLogHook that notes which retry of
+// a request is about to be sent, so RoundTrip can report it
+func (t *TraceTransport) RecordAttempt(_ retryablehttp.Logger, req *http.Request, attempt int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.attempts[req] = attempt
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *TraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	retry := t.attempts[req]
+	delete(t.attempts, req)
+	t.mu.Unlock()
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	entry := TraceEntry{
+		Timestamp:  start,
+		Method:     req.Method,
+		URL:        redactURL(req),
+		DurationMS: time.Since(start).Milliseconds(),
+		Retry:      retry,
+		Headers:    redactHeaders(req.Header),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Status = resp.StatusCode
+		if isMiss(req, resp) {
+			if !t.LogMisses {
+				return resp, err
+			}
+			entry.Category = TraceMiss
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if data, jsonErr := json.Marshal(entry); jsonErr == nil {
+		t.w.Write(append(data, '\n'))
+	}
+
+	return resp, err
+}
+
+// isMiss reports whether resp says a requested repository file doesn't exist
+func isMiss(req *http.Request, resp *http.Response) bool {
+	return resp.StatusCode == http.StatusNotFound && strings.Contains(req.URL.Path, "/repository/files/")
+}
+
+// redactURL returns the request path and query with credential parameters hidden
+func redactURL(req *http.Request) string {
+	u := *req.URL
+	query := u.Query()
+	for _, p := range redactedParams {
+		if query.Has(p) {
+			query.Set(p, "REDACTED")
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
+
+// redactHeaders copies h with credential headers hidden
+func redactHeaders(h http.Header) map[string][]string {
+	if len(h) == 0 {
+		return nil
+	}
+
+	headers := make(map[string][]string, len(h))
+	for k, v := range h {
+		headers[k] = v
+	}
+	for _, k := range redactedHeaders {
+		if _, ok := headers[k]; ok {
+			headers[k] = []string{"REDACTED"}
+		}
+	}
+	return headers
+}
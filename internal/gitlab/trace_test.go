@@ -0,0 +1,113 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTraceTransport(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// Fail the first attempt so the retrying client re-sends the request
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"version": "16.8.1", "revision": "abc"}`))
+	}))
+	defer server.Close()
+
+	trace := &bytes.Buffer{}
+	client, err := NewClient(&Config{GitLabURL: server.URL, Token: "secret-token", Trace: trace})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if _, _, err := client.GetClient().Version.GetVersion(); err != nil {
+		t.Fatalf("GetVersion() error = %v", err)
+	}
+
+	if strings.Contains(trace.String(), "secret-token") {
+		t.Errorf("trace leaked the token:\n%s", trace.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(trace.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d trace lines, want 2:\n%s", len(lines), trace.String())
+	}
+
+	var entries []TraceEntry
+	for _, line := range lines {
+		var e TraceEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid trace line %q: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+
+	if entries[0].Status != http.StatusServiceUnavailable || entries[0].Retry != 0 {
+		t.Errorf("first entry = status %d retry %d, want 503 retry 0", entries[0].Status, entries[0].Retry)
+	}
+	if entries[1].Status != http.StatusOK || entries[1].Retry != 1 {
+		t.Errorf("second entry = status %d retry %d, want 200 retry 1", entries[1].Status, entries[1].Retry)
+	}
+	if entries[1].Method != http.MethodGet || entries[1].URL != "/api/v4/version" {
+		t.Errorf("second entry = %s %s, want GET /api/v4/version", entries[1].Method, entries[1].URL)
+	}
+	if got := entries[1].Headers["Private-Token"]; len(got) != 1 || got[0] != "REDACTED" {
+		t.Errorf("Private-Token header = %v, want [REDACTED]", got)
+	}
+}
+
+func TestTraceTransportMisses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/files/.python-version/raw") {
+			w.Write([]byte("3.11\n"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	for _, logMisses := range []bool{false, true} {
+		trace := &bytes.Buffer{}
+		client, err := NewClient(&Config{GitLabURL: server.URL, Token: "test-token", Trace: trace, TraceMisses: logMisses})
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		client.GetRawFile(context.Background(), 1, ".python-version", nil)
+		client.GetRawFile(context.Background(), 1, "runtime.txt", nil)
+
+		var entries []TraceEntry
+		for _, line := range strings.Split(strings.TrimSpace(trace.String()), "\n") {
+			var e TraceEntry
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				t.Fatalf("invalid trace line %q: %v", line, err)
+			}
+			entries = append(entries, e)
+		}
+
+		if !logMisses {
+			if len(entries) != 1 || entries[0].Status != http.StatusOK || entries[0].Category != "" {
+				t.Errorf("trace without misses = %+v, want only the found file", entries)
+			}
+			continue
+		}
+		if len(entries) != 2 || entries[1].Status != http.StatusNotFound || entries[1].Category != TraceMiss {
+			t.Errorf("trace with misses = %+v, want the 404 as a %q", entries, TraceMiss)
+		}
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v4/projects?private_token=abc&page=2", nil)
+	if got := redactURL(req); got != "/api/v4/projects?page=2&private_token=REDACTED" {
+		t.Errorf("redactURL() = %q", got)
+	}
+}
@@ -0,0 +1,111 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Drift statuses recorded on a ScanResult compared against a Baseline
+const (
+	DriftConforming = "conforming" // Detected version matches the baseline
+	DriftMismatch   = "mismatch"   // Detected version differs from the baseline
+	DriftNoVersion  = "no_version" // In the baseline, but no version was detected
+	DriftUntracked  = "untracked"  // Not in the baseline
+)
+
+// Baseline maps project paths (e.g. "group/api") to the Python version each
+// project is expected to be on. When a scan covers several instances, an
+// entry keyed "instance:path" (see ProjectKey) covers the path on that
+// instance only and takes precedence over the bare path.
+type Baseline map[string]string
+
+// ProjectKey names a project on instance: its path, or "instance:path" when
+// instance isn't "". Mid-migration the same path can exist on two instances;
+// GitLab paths can't contain ":", so the two forms can't be confused.
+func ProjectKey(instance, path string) string {
+	if instance == "" {
+		return path
+	}
+	return instance + ":" + path
+}
+
+// LoadBaseline reads a baseline from a YAML file of "path: version" entries
+func LoadBaseline(path string) (Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var baseline Baseline
+	if err := yaml.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	for project, version := range baseline {
+		if _, err := ParseVersion(version); err != nil {
+			return nil, fmt.Errorf("baseline entry %s: %w", project, err)
+		}
+	}
+	return baseline, nil
+}
+
+// Annotate sets result's ExpectedVersion and Drift. Versions are compared at
+// the baseline's precision, so "3.11" accepts "3.11.5". Failed scans are
+// left unannotated since their version is unknown.
+func (b Baseline) Annotate(result *ScanResult) {
+	if result.Error != nil {
+		return
+	}
+
+	_, expected, tracked := b.entry(result)
+	switch {
+	case !tracked:
+		result.Drift = DriftUntracked
+	case result.PythonVersion == "":
+		result.Drift = DriftNoVersion
+	case VersionsAgree(expected, result.PythonVersion):
+		result.Drift = DriftConforming
+	default:
+		result.Drift = DriftMismatch
+	}
+	result.ExpectedVersion = expected
+}
+
+// entry returns the baseline entry covering result's project and the version
+// it expects: the one for result's instance, else the one for its path
+func (b Baseline) entry(result *ScanResult) (key, expected string, tracked bool) {
+	for _, key := range []string{ProjectKey(result.Instance, result.ProjectPath), result.ProjectPath} {
+		if expected, ok := b[key]; ok {
+			return key, expected, true
+		}
+	}
+	return "", "", false
+}
+
+// Unscanned returns the baseline's project paths missing from scanned, sorted
+func (b Baseline) Unscanned(scanned map[string]bool) []string {
+	missing := []string{}
+	for project := range b {
+		if !scanned[project] {
+			missing = append(missing, project)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// driftSuffix describes a baseline deviation, formatted for appending to a
+// result line, or "" for conforming and unannotated results
+func driftSuffix(drift, expected string) string {
+	switch drift {
+	case DriftMismatch:
+		return fmt.Sprintf(" [drift: expected %s]", expected)
+	case DriftNoVersion:
+		return fmt.Sprintf(" [drift: expected %s, none detected]", expected)
+	case DriftUntracked:
+		return " [untracked]"
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeBaseline(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "baseline.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write baseline: %v", err)
+	}
+	return path
+}
+
+func TestLoadBaseline(t *testing.T) {
+	baseline, err := LoadBaseline(writeBaseline(t, "group/api: \"3.11\"\ngroup/web: 3.12.1\n"))
+	if err != nil {
+		t.Fatalf("LoadBaseline() error = %v", err)
+	}
+	if len(baseline) != 2 || baseline["group/api"] != "3.11" || baseline["group/web"] != "3.12.1" {
+		t.Errorf("baseline = %v", baseline)
+	}
+
+	if _, err := LoadBaseline(writeBaseline(t, "group/api: latest\n")); err == nil {
+		t.Error("expected an error for a non-numeric version")
+	}
+	if _, err := LoadBaseline(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestBaselineAnnotate(t *testing.T) {
+	baseline := Baseline{"group/api": "3.11", "group/web": "3.12", "group/docs": "3.10", "onprem:group/api": "3.9"}
+
+	tests := []struct {
+		name   string
+		result *ScanResult
+		want   string
+	}{
+		{"patch within expected minor", &ScanResult{ProjectPath: "group/api", PythonVersion: "3.11.5"}, DriftConforming},
+		{"different minor", &ScanResult{ProjectPath: "group/web", PythonVersion: "3.9"}, DriftMismatch},
+		{"nothing detected", &ScanResult{ProjectPath: "group/docs"}, DriftNoVersion},
+		{"not in baseline", &ScanResult{ProjectPath: "group/new", PythonVersion: "3.12"}, DriftUntracked},
+		{"failed scan", &ScanResult{ProjectPath: "group/api", Error: errors.New("boom")}, ""},
+		{"entry for the instance", &ScanResult{Instance: "onprem", ProjectPath: "group/api", PythonVersion: "3.9"}, DriftConforming},
+		{"path entry on another instance", &ScanResult{Instance: "saas", ProjectPath: "group/api", PythonVersion: "3.11"}, DriftConforming},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline.Annotate(tt.result)
+			if tt.result.Drift != tt.want {
+				t.Errorf("Drift = %q, want %q", tt.result.Drift, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanStatistics_Baseline(t *testing.T) {
+	baseline := Baseline{"group/api": "3.11", "group/web": "3.12", "group/docs": "3.10", "group/gone": "3.8"}
+	stats := NewScanStatistics()
+	stats.Baseline = baseline
+
+	for _, r := range []*ScanResult{
+		{ProjectPath: "group/api", PythonVersion: "3.11"},
+		{ProjectPath: "group/web", PythonVersion: "3.12.2"},
+		{ProjectPath: "group/docs", PythonVersion: "3.9"},
+		{ProjectPath: "group/new", PythonVersion: "3.12"},
+	} {
+		baseline.Annotate(r)
+		stats.RecordResult(r)
+	}
+
+	if stats.BaselineConforming != 2 || stats.BaselineDrifted != 1 || stats.BaselineUntracked != 1 {
+		t.Errorf("conforming/drifted/untracked = %d/%d/%d, want 2/1/1",
+			stats.BaselineConforming, stats.BaselineDrifted, stats.BaselineUntracked)
+	}
+	if got := stats.BaselineConformance(); got < 66.6 || got > 66.7 {
+		t.Errorf("BaselineConformance() = %v, want 66.7", got)
+	}
+	if got := stats.BaselineUnscanned(); len(got) != 1 || got[0] != "group/gone" {
+		t.Errorf("BaselineUnscanned() = %v, want [group/gone]", got)
+	}
+
+	var buf bytes.Buffer
+	NewConsoleStreamerWithWriter(&buf).PrintSummary(stats)
+	for _, want := range []string{
+		"Baseline: 66.7% conforming (2 conforming, 1 drifted, 0 no version), 1 untracked",
+		"Baseline projects not scanned: 1\n  - group/gone",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("summary missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestConsoleStreamer_StreamResult_Drift(t *testing.T) {
+	var buf bytes.Buffer
+	NewConsoleStreamerWithWriter(&buf).StreamResult(&ScanResult{
+		Index: 1, TotalProjects: 1, ProjectName: "web", PythonVersion: "3.9",
+		DetectionSource: ".python-version", ExpectedVersion: "3.12", Drift: DriftMismatch,
+	})
+	if !strings.Contains(buf.String(), "Python 3.9 (from .python-version) [drift: expected 3.12]") {
+		t.Errorf("got %q", buf.String())
+	}
+}
@@ -0,0 +1,130 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// bucketRecent is how many of each bucket's latest results the live view shows
+const bucketRecent = 5
+
+// Buckets of the live view, in display order
+const (
+	bucketDetected = iota
+	bucketUndetected
+	bucketError
+	bucketCount
+)
+
+// bucketNames and bucketColors label each bucket; colors are ANSI SGR codes
+var (
+	bucketNames  = [bucketCount]string{"Detected", "Undetected", "Errors"}
+	bucketColors = [bucketCount]string{"32", "33", "31"} // Green, yellow, red
+)
+
+// resultBuckets is the live view ConsoleStreamer.Bucketed redraws: a running
+// count and the latest results of each bucket
+type resultBuckets struct {
+	counts [bucketCount]int
+	recent [bucketCount][]string
+	drawn  int // Lines drawn last time, to move back over
+	color  bool
+}
+
+// IsTerminal reports whether f is an interactive terminal rather than a
+// file or pipe
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// resultBucket returns the bucket result belongs in. A project that timed
+// out before anything was detected counts as an error.
+func resultBucket(result *ScanResult) int {
+	switch {
+	case result.Error != nil || (result.TimedOut && result.PythonVersion == ""):
+		return bucketError
+	case result.PythonVersion == "":
+		return bucketUndetected
+	default:
+		return bucketDetected
+	}
+}
+
+// bucketResult adds result to the live view and redraws it over the
+// previous one; cs.mu must be held
+func (cs *ConsoleStreamer) bucketResult(result *ScanResult) error {
+	if cs.buckets == nil {
+		// NO_COLOR (https://no-color.org) turns the headings' colors off
+		cs.buckets = &resultBuckets{color: os.Getenv("NO_COLOR") == ""}
+	}
+
+	var line bytes.Buffer
+	if err := cs.writeResultLine(&line, result); err != nil {
+		return err
+	}
+	cs.buckets.add(resultBucket(result), strings.TrimSuffix(line.String(), "\n"))
+	return cs.buckets.redraw(cs.writer, cs.Width)
+}
+
+// add records a result's line in bucket, keeping only the latest few
+func (b *resultBuckets) add(bucket int, line string) {
+	b.counts[bucket]++
+	b.recent[bucket] = append(b.recent[bucket], line)
+	if len(b.recent[bucket]) > bucketRecent {
+		b.recent[bucket] = b.recent[bucket][1:]
+	}
+}
+
+// redraw moves the cursor back over the previous view, clears it, and
+// writes the current one to w. Rows are cut to width columns (0 = no limit)
+// so none wraps: a wrapped row would take more lines than the next redraw
+// moves back over.
+func (b *resultBuckets) redraw(w io.Writer, width int) error {
+	var view strings.Builder
+	if b.drawn > 0 {
+		fmt.Fprintf(&view, "\033[%dA\033[J", b.drawn)
+	}
+
+	lines := 0
+	row := func(text string) {
+		view.WriteString(truncateColumns(text, width))
+		view.WriteString("\n")
+		lines++
+	}
+	for bucket := 0; bucket < bucketCount; bucket++ {
+		heading := fmt.Sprintf("%s (%d)", bucketNames[bucket], b.counts[bucket])
+		if b.color {
+			heading = "\033[" + bucketColors[bucket] + "m" + heading + "\033[0m"
+		}
+		view.WriteString(heading + "\n")
+		lines++
+		if hidden := b.counts[bucket] - len(b.recent[bucket]); hidden > 0 {
+			row(fmt.Sprintf("  ... %d more", hidden))
+		}
+		// An error message may span several rows
+		for _, line := range b.recent[bucket] {
+			for _, text := range strings.Split(line, "\n") {
+				row("  " + text)
+			}
+		}
+	}
+	b.drawn = lines
+
+	_, err := io.WriteString(w, view.String())
+	return err
+}
+
+// truncateColumns cuts text to fit in width columns, ending it with "…" when
+// anything was cut; width 0 leaves it whole. Each rune counts as a column.
+func truncateColumns(text string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:width-1]) + "…"
+}
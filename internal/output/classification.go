@@ -0,0 +1,118 @@
+package output
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Classifications for projects where no Python version was detected.
+// They separate the remediation list (Python code without a declared
+// version) from projects that are not Python at all.
+const (
+	// ClassPythonNoVersion marks a project with .py or packaging files
+	// but no version our rules could parse
+	ClassPythonNoVersion = "python-no-version"
+
+	// ClassNonPython marks a project with no Python files at all
+	ClassNonPython = "non-python"
+)
+
+// undetectedLabel describes a project with no detected version for result lines
+func undetectedLabel(classification string) string {
+	switch classification {
+	case ClassPythonNoVersion:
+		return "Python version unknown (python-no-version)"
+	case ClassNonPython:
+		return "Python not detected (non-python)"
+	default:
+		return "Python not detected"
+	}
+}
+
+// Confidence buckets for detected versions, from most to least trustworthy
+const (
+	// ConfidenceExplicit is a version file or explicit constraint (>= 0.9)
+	ConfidenceExplicit = "explicit"
+
+	// ConfidenceInferred is a version inferred from CI or container config (0.6 to 0.9)
+	ConfidenceInferred = "inferred"
+
+	// ConfidenceWeak is a guess from comments or loose matches (< 0.6)
+	ConfidenceWeak = "weak"
+)
+
+// confidenceBucketOrder is the order buckets are shown in summaries
+var confidenceBucketOrder = []string{ConfidenceExplicit, ConfidenceInferred, ConfidenceWeak}
+
+// ConfidenceBucket returns the bucket a detection's confidence falls in
+func ConfidenceBucket(confidence float64) string {
+	switch {
+	case confidence >= 0.9:
+		return ConfidenceExplicit
+	case confidence >= 0.6:
+		return ConfidenceInferred
+	default:
+		return ConfidenceWeak
+	}
+}
+
+// ConfidenceTiers are the confidence thresholds for ranking detections
+// strictly by trustworthiness instead of by rule priority
+type ConfidenceTiers struct {
+	Explicit float64 // Lowest confidence in the explicit tier
+	Inferred float64 // Lowest confidence in the inferred tier; lower is weak
+}
+
+// DefaultConfidenceTiers match the summary's confidence buckets
+var DefaultConfidenceTiers = ConfidenceTiers{Explicit: 0.9, Inferred: 0.6}
+
+// Rank returns the tier confidence falls in, 0 (explicit) to 2 (weak).
+// A nil ConfidenceTiers ranks everything 0, leaving rule priority alone.
+func (t *ConfidenceTiers) Rank(confidence float64) int {
+	switch {
+	case t == nil || confidence >= t.Explicit:
+		return 0
+	case confidence >= t.Inferred:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ConfidenceTierName returns the bucket name of a ConfidenceTiers rank,
+// e.g. "inferred" for 1
+func ConfidenceTierName(rank int) string {
+	return confidenceBucketOrder[rank]
+}
+
+// DecayConfidence reduces confidence by half for every halfLife the declaring
+// file has gone unchanged, so a 1.0 detection from a file last touched two
+// half-lives ago becomes 0.25. A non-positive halfLife or age leaves
+// confidence unchanged.
+func DecayConfidence(confidence float64, age, halfLife time.Duration) float64 {
+	if halfLife <= 0 || age <= 0 {
+		return confidence
+	}
+	return confidence * math.Pow(0.5, float64(age)/float64(halfLife))
+}
+
+// confidenceBreakdown renders bucket counts as percentages of detections,
+// e.g. "72% explicit (18), 20% inferred (5), 8% weak (2)"
+func confidenceBreakdown(buckets map[string]int) string {
+	total := 0
+	for _, n := range buckets {
+		total += n
+	}
+	if total == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, bucket := range confidenceBucketOrder {
+		n := buckets[bucket]
+		parts = append(parts, fmt.Sprintf("%d%% %s (%d)", n*100/total, bucket, n))
+	}
+	return strings.Join(parts, ", ")
+}
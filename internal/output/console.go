@@ -4,24 +4,69 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
 // ScanResult represents a single scan result for a project
 type ScanResult struct {
-	ProjectName       string // Name of the project
-	ProjectPath       string // Full path of the project
-	PythonVersion     string // Detected Python version (e.g., "3.11.5")
-	DetectionSource   string // Where the version was detected (e.g., ".python-version")
-	Error             error  // Any error encountered during scanning
-	Index             int    // Sequential index of this result
-	TotalProjects     int    // Total number of projects being scanned
+	ProjectName       string       // Name of the project
+	ProjectPath       string       // Full path of the project
+	Namespace         string       // Full group path of the project (e.g., "org/team/sub")
+	TopLevelGroup     string       // Top-level group of the project (e.g., "org")
+	PythonVersion     string       // Detected Python version (e.g., "3.11.5")
+	DetectionSource   string       // Where the version was detected (e.g., ".python-version")
+	Error             error        // Any error encountered during scanning
+	Index             int          // Sequential index of this result
+	TotalProjects     int          // Total number of projects being scanned
+	CrossChecks       []CrossCheck // Additional detections found in cross-check mode
+	VersionMismatch   bool         // Whether any cross-check disagreed with PythonVersion
+	LastCommitID      string       // Last commit that modified DetectionSource (--with-metadata)
+	SourceSize        int          // Size in bytes of DetectionSource (--with-metadata)
+	Classification    string       // Why no version was found: ClassPythonNoVersion, ClassNonPython, or "" if unknown
+	TimedOut          bool         // Whether the per-project deadline expired (any version is partial)
+	Diagnostics       []string     // Detections discarded as implausible, and why
+	Confidence        float64      // Confidence of the detection (0.0-1.0), 0 if unknown; decayed with --decay-confidence
+	RawConfidence     float64      // Confidence before --decay-confidence was applied (0 without it)
+	SourceUpdated     time.Time    // When DetectionSource was last committed (--decay-confidence)
+	ParseErrors       []string     // Candidate files a rule's parser failed on, with the error (--strict)
+	ExplicitSource    bool         // Whether a rule tagged explicit (e.g. .python-version) detected a version
+	Warnings          []string     // Candidate files a rule failed on (parser or size limit), with the error; parser failures go to ParseErrors instead with --strict; also unsupported lines of the project's .gitlab-seeker-ignore
+	RawValue          string       // Text the version was parsed from, e.g. ">=3.10,<4.0"
+	VersionMax        string       // Upper bound from DetectionSource's constraint, e.g. "<3.11" ("" if open-ended)
+	Dependencies      []Dependency // Packages declared in requirements.txt (--dep-report)
+	CandidatesLimited bool         // Whether probing stopped at --max-candidates with files left untried
+	IsPython2         bool         // Whether PythonVersion has major version 2 (see IsPython2)
+	ExpectedVersion   string       // Version the --baseline expects ("" if untracked or no baseline)
+	Drift             string       // Comparison with the --baseline: a Drift* status, or "" without one
+	LastActivityAt    string       // The project's last activity when it was scanned (see --incremental)
+	Cached            bool         // Whether the result was reused from a previous scan rather than scanned
+	Explanation       *Explanation // How the version was chosen (--explain), nil otherwise
+	Instance          string       // The GitLab instance the project is on, when a scan covers several ("" otherwise)
+	CommitSHA         string       // Commit the scanned ref pointed to, for reproducing the scan ("" if it couldn't be resolved)
+	CIDrift           string       // CI runs a version the declared requires-python rules out, e.g. ".gitlab-ci.yml runs Python 3.9, outside pyproject.toml's >=3.11" ("" if not; --cross-check)
 }
 
 // ConsoleStreamer handles real-time streaming of scan results to console
 type ConsoleStreamer struct {
 	writer io.Writer
 	mu     sync.Mutex // Protects concurrent writes
+
+	// Verbose also prints the raw matched value under each detection
+	Verbose bool
+
+	// Bucketed groups results into detected, undetected, and error buckets
+	// redrawn in place as they arrive, instead of a flat stream. It rewrites
+	// earlier lines, so only set it when writing to a terminal (see IsTerminal).
+	Bucketed bool
+
+	// Width is the terminal's width in columns (see TerminalWidth); the
+	// Bucketed view cuts its rows to it so none wraps (0 = don't cut)
+	Width int
+
+	buckets *resultBuckets // The live view drawn so far (Bucketed)
 }
 
 // NewConsoleStreamer creates a new console streamer that writes to stdout
@@ -45,43 +90,102 @@ func (cs *ConsoleStreamer) StreamResult(result *ScanResult) error {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
+	if cs.Bucketed {
+		return cs.bucketResult(result)
+	}
+	if err := cs.writeResultLine(cs.writer, result); err != nil {
+		return err
+	}
+	if cs.Verbose && result.RawValue != "" && result.Error == nil {
+		if _, err := fmt.Fprintf(cs.writer, "  raw value: %q\n", result.RawValue); err != nil {
+			return err
+		}
+	}
+	// Rule failures are listed under the project they belong to
+	for _, parseErr := range result.ParseErrors {
+		if _, err := fmt.Fprintf(cs.writer, "  parse error: %s\n", parseErr); err != nil {
+			return err
+		}
+	}
+	for _, warning := range result.Warnings {
+		if _, err := fmt.Fprintf(cs.writer, "  warning: %s\n", warning); err != nil {
+			return err
+		}
+	}
+	if result.Explanation != nil {
+		return writeExplanation(cs.writer, result.Explanation)
+	}
+	return nil
+}
+
+// writeResultLine writes result's one-line outcome to w; cs.mu must be held
+func (cs *ConsoleStreamer) writeResultLine(w io.Writer, result *ScanResult) error {
 	// Handle error cases
 	if result.Error != nil {
-		_, err := fmt.Fprintf(cs.writer, "[%d/%d] %s: Error - %v\n",
+		_, err := fmt.Fprintf(w, "[%d/%d] %s: Error - %v\n",
 			result.Index,
 			result.TotalProjects,
-			result.ProjectName,
+			projectLabel(result.ProjectName, result.Instance),
 			result.Error,
 		)
 		return err
 	}
 
+	// Handle projects that ran out of time before anything was detected
+	if result.TimedOut && result.PythonVersion == "" {
+		_, err := fmt.Fprintf(w, "[%d/%d] %s: Timed out\n",
+			result.Index,
+			result.TotalProjects,
+			projectLabel(result.ProjectName, result.Instance),
+		)
+		return err
+	}
+
 	// Handle Python not detected
 	if result.PythonVersion == "" {
-		_, err := fmt.Fprintf(cs.writer, "[%d/%d] %s: Python not detected\n",
+		_, err := fmt.Fprintf(w, "[%d/%d] %s: %s\n",
 			result.Index,
 			result.TotalProjects,
-			result.ProjectName,
+			projectLabel(result.ProjectName, result.Instance),
+			undetectedLabel(result.Classification),
 		)
 		return err
 	}
 
 	// Handle successful detection
-	_, err := fmt.Fprintf(cs.writer, "[%d/%d] %s: Python %s (from %s)\n",
+	source := result.DetectionSource
+	if result.VersionMax != "" {
+		source += ", requires " + result.VersionMax
+	}
+	_, err := fmt.Fprintf(w, "[%d/%d] %s: Python %s (from %s)%s%s%s\n",
 		result.Index,
 		result.TotalProjects,
-		result.ProjectName,
+		projectLabel(result.ProjectName, result.Instance),
 		result.PythonVersion,
-		result.DetectionSource,
+		source,
+		mismatchSuffix(result.PythonVersion, result.CrossChecks),
+		ciDriftSuffix(result.CIDrift),
+		driftSuffix(result.Drift, result.ExpectedVersion),
 	)
 	return err
 }
 
+// projectLabel names a project in result lines, qualified by its instance
+// when a scan covers several, e.g. "billing (onprem)"
+func projectLabel(name, instance string) string {
+	if instance == "" {
+		return name
+	}
+	return name + " (" + instance + ")"
+}
+
 // PrintHeader writes the initial header information to the console
 func (cs *ConsoleStreamer) PrintHeader(gitlabURL string, totalProjects int) error {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
+	// Each --watch run starts a fresh view
+	cs.buckets = nil
 	_, err := fmt.Fprintf(cs.writer, "\nFound %d projects in organization\n\n", totalProjects)
 	return err
 }
@@ -96,43 +200,503 @@ func (cs *ConsoleStreamer) PrintSummary(stats *ScanStatistics) error {
 		stats.PythonProjects,
 		stats.NonPythonProjects,
 	)
+
+	if stats.AtRiskBelow != "" {
+		cs.printAtRisk(stats)
+	}
+
+	if stats.OrgSummary {
+		cs.printOrgSummary(stats)
+	}
+
+	if breakdown := confidenceBreakdown(stats.ConfidenceBuckets); breakdown != "" {
+		fmt.Fprintf(cs.writer, "Confidence: %s\n", breakdown)
+	}
+
+	if stats.RunID != "" {
+		fmt.Fprintf(cs.writer, "Run: %s (started %s)\n", stats.RunID, stats.RunStarted.Format(time.RFC3339))
+	}
 	
 	if stats.ErrorCount > 0 {
 		fmt.Fprintf(cs.writer, "Errors encountered: %d\n", stats.ErrorCount)
 	}
+
+	if stats.MismatchProjects > 0 {
+		fmt.Fprintf(cs.writer, "Version mismatches: %d\n", stats.MismatchProjects)
+	}
+
+	if stats.CIDriftProjects > 0 {
+		fmt.Fprintf(cs.writer, "CI/declaration drift: %d\n", stats.CIDriftProjects)
+		for _, path := range stats.CIDriftPaths {
+			fmt.Fprintf(cs.writer, "  - %s\n", path)
+		}
+	}
+
+	if stats.TimedOutProjects > 0 {
+		fmt.Fprintf(cs.writer, "Timed out: %d\n", stats.TimedOutProjects)
+	}
+
+	if stats.CandidateLimitedProjects > 0 {
+		fmt.Fprintf(cs.writer, "Stopped at candidate limit: %d\n", stats.CandidateLimitedProjects)
+	}
+
+	if stats.ParseErrorCount > 0 {
+		fmt.Fprintf(cs.writer, "Parse errors: %d\n", stats.ParseErrorCount)
+	}
+
+	if stats.CachedProjects > 0 {
+		fmt.Fprintf(cs.writer, "Unchanged since the previous scan (reused): %d\n", stats.CachedProjects)
+	}
+
+	if stats.RequireExplicit {
+		fmt.Fprintf(cs.writer, "Missing explicit version file: %d\n", stats.MissingExplicitProjects)
+		for _, path := range stats.MissingExplicitPaths {
+			fmt.Fprintf(cs.writer, "  - %s\n", path)
+		}
+	}
+
+	if stats.TargetVersion != "" {
+		fmt.Fprintf(cs.writer, "Capped below %s: %d\n", stats.TargetVersion, stats.CappedProjects)
+	}
+
+	if stats.Python2Projects > 0 {
+		fmt.Fprintf(cs.writer, "Python 2 projects: %d\n", stats.Python2Projects)
+		for _, path := range stats.Python2Paths {
+			fmt.Fprintf(cs.writer, "  - %s\n", path)
+		}
+	}
+
+	if stats.ListingError != "" {
+		fmt.Fprintf(cs.writer, "Warning: results are incomplete - %s\n", stats.ListingError)
+	}
+
+	if len(stats.Warnings) > 0 {
+		fmt.Fprintf(cs.writer, "Warnings: %d\n", len(stats.Warnings))
+		for _, warning := range stats.Warnings {
+			fmt.Fprintf(cs.writer, "  - %s\n", warning)
+		}
+	}
+
+	if stats.Baseline != nil {
+		fmt.Fprintf(cs.writer, "Baseline: %.1f%% conforming (%d conforming, %d drifted, %d no version), %d untracked\n",
+			stats.BaselineConformance(),
+			stats.BaselineConforming,
+			stats.BaselineDrifted,
+			stats.BaselineNoVersion,
+			stats.BaselineUntracked,
+		)
+		if unscanned := stats.BaselineUnscanned(); len(unscanned) > 0 {
+			fmt.Fprintf(cs.writer, "Baseline projects not scanned: %d\n", len(unscanned))
+			for _, path := range unscanned {
+				fmt.Fprintf(cs.writer, "  - %s\n", path)
+			}
+		}
+	}
+
+	if len(stats.ApprovedVersions) > 0 {
+		fmt.Fprintf(cs.writer, "Approved versions (%s): %d approved, %d non-approved\n",
+			strings.Join(stats.ApprovedVersions, ", "),
+			stats.ApprovedProjects,
+			stats.NonApprovedProjects,
+		)
+	}
+
+	if stats.PythonNoVersionProjects > 0 || stats.NoPythonFilesProjects > 0 {
+		fmt.Fprintf(cs.writer, "Undetected breakdown: %d python-no-version, %d non-python\n",
+			stats.PythonNoVersionProjects,
+			stats.NoPythonFilesProjects,
+		)
+	}
 	
 	return err
 }
 
-// ScanStatistics holds summary statistics for a scan operation
+// printAtRisk writes the at-risk headline, the projects below the floor,
+// and the count of those on a current version
+func (cs *ConsoleStreamer) printAtRisk(stats *ScanStatistics) {
+	fmt.Fprintf(cs.writer, "\n=== %d of %d Python projects at risk (below %s) ===\n", stats.AtRiskProjects, stats.PythonProjects, stats.AtRiskBelow)
+	for _, path := range stats.AtRiskPaths {
+		fmt.Fprintf(cs.writer, "  - %s\n", path)
+	}
+	fmt.Fprintf(cs.writer, "  Current (%s or later): %d\n", stats.AtRiskBelow, stats.CurrentProjects)
+	if unjudged := stats.PythonProjects - stats.AtRiskProjects - stats.CurrentProjects; unjudged > 0 {
+		fmt.Fprintf(cs.writer, "  Not placed (version too vague): %d\n", unjudged)
+	}
+	fmt.Fprintln(cs.writer)
+}
+
+// printOrgSummary writes the compliance score and the counts behind it
+func (cs *ConsoleStreamer) printOrgSummary(stats *ScanStatistics) {
+	fmt.Fprintf(cs.writer, "\n=== Compliance: %.1f%% of Python projects on a supported version ===\n", stats.ComplianceScore())
+	fmt.Fprintf(cs.writer, "  Supported:        %d\n", stats.SupportedProjects)
+	fmt.Fprintf(cs.writer, "  End of life:      %d\n", stats.EOLProjects)
+	for _, version := range stats.SortedEOLVersions() {
+		fmt.Fprintf(cs.writer, "    %s: %d\n", version, stats.EOLVersionCounts[version])
+	}
+	fmt.Fprintf(cs.writer, "  Unknown support:  %d\n", stats.UnknownEOLProjects)
+	fmt.Fprintf(cs.writer, "  Not counted:      %d undetected, %d errors\n\n", stats.NonPythonProjects, stats.ErrorCount)
+}
+
+// PrintSummaryLine writes the machine-readable summary as a single line
+func (cs *ConsoleStreamer) PrintSummaryLine(stats *ScanStatistics) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	_, err := fmt.Fprintln(cs.writer, stats.SummaryLine())
+	return err
+}
+
+// PrintVersionList writes the distinct detected versions, oldest first, one
+// per line. withCounts appends each version's project count, and
+// includeUndetected adds an "undetected" line when any project had no version.
+func (cs *ConsoleStreamer) PrintVersionList(stats *ScanStatistics, withCounts, includeUndetected bool) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	for _, version := range stats.SortedVersions() {
+		line := version
+		if withCounts {
+			line = fmt.Sprintf("%s %d", version, stats.VersionCounts[version])
+		}
+		if _, err := fmt.Fprintln(cs.writer, line); err != nil {
+			return err
+		}
+	}
+
+	if includeUndetected && stats.NonPythonProjects > 0 {
+		line := "undetected"
+		if withCounts {
+			line = fmt.Sprintf("undetected %d", stats.NonPythonProjects)
+		}
+		if _, err := fmt.Fprintln(cs.writer, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ScanStatistics holds summary statistics for a scan operation.
+// RecordResult is safe for concurrent use; read the fields once recording is done.
 type ScanStatistics struct {
-	TotalProjects      int            // Total number of projects scanned
-	PythonProjects     int            // Number of projects with Python detected
-	NonPythonProjects  int            // Number of projects without Python
-	ErrorCount         int            // Number of errors encountered
-	VersionCounts      map[string]int // Count of each Python version detected
+	mu sync.Mutex
+
+	TotalProjects     int            // Total number of projects scanned
+	PythonProjects    int            // Number of projects with Python detected
+	NonPythonProjects int            // Number of projects without Python
+	ErrorCount        int            // Number of errors encountered
+	VersionCounts     map[string]int // Count of each Python version detected
+	ConfidenceBuckets map[string]int // Count of detections per ConfidenceBucket (unknown confidence is not counted)
+	MismatchProjects  int            // Number of projects whose cross-checks disagreed
+	Normalize         Normalization  // How versions are bucketed in VersionCounts
+
+	// Breakdown of NonPythonProjects by classification; projects that could
+	// not be classified are counted in neither
+	PythonNoVersionProjects int // Python files present but no version declared
+	NoPythonFilesProjects   int // No Python files at all
+
+	// Policy compliance of PythonProjects; only counted when ApprovedVersions is set
+	ApprovedVersions    []string // Versions the organization has approved (see IsApproved)
+	ApprovedProjects    int      // Python projects on an approved version
+	NonApprovedProjects int      // Python projects on any other version
+
+	// ListingError describes why project listing stopped early ("" if the
+	// listing was complete); set when scanning best-effort partial results
+	ListingError string
+
+	// Warnings are run-level problems that didn't stop the scan, such as
+	// results that couldn't be written to an output, in the order they
+	// were recorded (see RecordWarning)
+	Warnings []string
+
+	TimedOutProjects int // Projects that hit the per-project deadline (detected or not)
+
+	// Python2Projects counts Python projects on major version 2, and
+	// Python2Paths lists their paths in the order they were recorded
+	Python2Projects int
+	Python2Paths    []string
+
+	// CIDriftProjects counts Python projects whose CI runs a version their
+	// declared requires-python rules out (see ScanResult.CIDrift), and
+	// CIDriftPaths lists their paths in the order they were recorded
+	CIDriftProjects int
+	CIDriftPaths    []string
+
+	// RequireExplicit enables MissingExplicitProjects, which counts Python
+	// projects without an explicit version file: those detected only by
+	// inferring rules, and those with Python files but no detection.
+	// MissingExplicitPaths lists their paths.
+	RequireExplicit         bool
+	MissingExplicitProjects int
+	MissingExplicitPaths    []string
+
+	// CandidateLimitedProjects stopped probing at --max-candidates, so an
+	// undetected version may exist in a file that was never fetched
+	CandidateLimitedProjects int
+
+	// CachedProjects is the number of results reused from a previous
+	// scan by --incremental; they're counted like scanned ones
+	CachedProjects int
+
+	// ParseErrorCount is the number of candidate files a rule's parser
+	// failed on across all projects (only recorded with --strict)
+	ParseErrorCount int
+
+	// TargetVersion is the version an upgrade is aiming for; Python projects
+	// whose VersionMax excludes it are counted in CappedProjects
+	TargetVersion  string
+	CappedProjects int
+
+	// AtRiskBelow is the policy floor (e.g. "3.10"): Python projects on an
+	// older version are counted in AtRiskProjects and listed in
+	// AtRiskPaths, and those at or above it in CurrentProjects (see
+	// BelowFloor). Projects whose version can't be placed are in neither.
+	AtRiskBelow     string
+	AtRiskProjects  int
+	AtRiskPaths     []string
+	CurrentProjects int
+
+	// OrgSummary enables the compliance roll-up: every Python project is
+	// counted by its version's VersionEOLStatus at EOLAsOf (zero = when
+	// the result is recorded), and EOLVersionCounts breaks EOLProjects
+	// down by major.minor. See ComplianceScore.
+	OrgSummary         bool
+	EOLAsOf            time.Time
+	SupportedProjects  int
+	EOLProjects        int
+	UnknownEOLProjects int
+	EOLVersionCounts   map[string]int
+
+	// RunID and RunStarted identify one scan when --watch repeats it; both
+	// are zero for a single scan
+	RunID      string
+	RunStarted time.Time
+
+	// Baseline is the expected version inventory results are compared
+	// against (nil = none); the counts tally each result's Drift status
+	Baseline           Baseline
+	BaselineConforming int
+	BaselineDrifted    int
+	BaselineNoVersion  int
+	BaselineUntracked  int
+	baselineScanned    map[string]bool // Baseline projects seen, including failed scans
 }
 
 // NewScanStatistics creates a new statistics tracker
 func NewScanStatistics() *ScanStatistics {
 	return &ScanStatistics{
-		VersionCounts: make(map[string]int),
+		VersionCounts:     make(map[string]int),
+		ConfidenceBuckets: make(map[string]int),
 	}
 }
 
+// RecordWarning records a run-level warning; it's safe for concurrent use
+func (ss *ScanStatistics) RecordWarning(warning string) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.Warnings = append(ss.Warnings, warning)
+}
+
 // RecordResult updates statistics based on a scan result
 func (ss *ScanStatistics) RecordResult(result *ScanResult) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
 	ss.TotalProjects++
+	ss.ParseErrorCount += len(result.ParseErrors)
+	if result.Cached {
+		ss.CachedProjects++
+	}
+
+	if ss.Baseline != nil {
+		ss.recordDrift(result)
+	}
 	
 	if result.Error != nil {
 		ss.ErrorCount++
 		return
 	}
+
+	if result.CandidatesLimited {
+		ss.CandidateLimitedProjects++
+	}
+
+	if result.TimedOut {
+		ss.TimedOutProjects++
+		if result.PythonVersion == "" {
+			// Unknown outcome: neither Python nor non-Python
+			return
+		}
+	}
 	
 	if result.PythonVersion == "" {
 		ss.NonPythonProjects++
+		switch result.Classification {
+		case ClassPythonNoVersion:
+			ss.PythonNoVersionProjects++
+			if ss.RequireExplicit {
+				ss.recordMissingExplicit(result)
+			}
+		case ClassNonPython:
+			ss.NoPythonFilesProjects++
+		}
 	} else {
 		ss.PythonProjects++
-		ss.VersionCounts[result.PythonVersion]++
+		ss.VersionCounts[NormalizeVersion(result.PythonVersion, ss.Normalize)]++
+		if result.Confidence > 0 {
+			ss.ConfidenceBuckets[ConfidenceBucket(result.Confidence)]++
+		}
+		if len(ss.ApprovedVersions) > 0 {
+			if IsApproved(result.PythonVersion, ss.ApprovedVersions) {
+				ss.ApprovedProjects++
+			} else {
+				ss.NonApprovedProjects++
+			}
+		}
+		if result.VersionMismatch {
+			ss.MismatchProjects++
+		}
+		if result.CIDrift != "" {
+			ss.CIDriftProjects++
+			ss.CIDriftPaths = append(ss.CIDriftPaths, result.ProjectPath)
+		}
+		if ss.RequireExplicit && !result.ExplicitSource {
+			ss.recordMissingExplicit(result)
+		}
+		if result.IsPython2 {
+			ss.Python2Projects++
+			ss.Python2Paths = append(ss.Python2Paths, result.ProjectPath)
+		}
+		if ss.TargetVersion != "" && ExcludesVersion(result.VersionMax, ss.TargetVersion) {
+			ss.CappedProjects++
+		}
+		if ss.AtRiskBelow != "" {
+			ss.recordAtRisk(result)
+		}
+		if ss.OrgSummary {
+			ss.recordEOL(result)
+		}
+	}
+}
+
+// recordAtRisk counts a Python project against the AtRiskBelow floor
+func (ss *ScanStatistics) recordAtRisk(result *ScanResult) {
+	below, judged := BelowFloor(result.PythonVersion, ss.AtRiskBelow)
+	switch {
+	case below:
+		ss.AtRiskProjects++
+		ss.AtRiskPaths = append(ss.AtRiskPaths, result.ProjectPath)
+	case judged:
+		ss.CurrentProjects++
+	}
+}
+
+// recordEOL counts a Python project by whether its version is supported
+func (ss *ScanStatistics) recordEOL(result *ScanResult) {
+	at := ss.EOLAsOf
+	if at.IsZero() {
+		at = time.Now()
 	}
+
+	switch VersionEOLStatus(result.PythonVersion, at) {
+	case EOLSupported:
+		ss.SupportedProjects++
+	case EOLEnded:
+		ss.EOLProjects++
+		if ss.EOLVersionCounts == nil {
+			ss.EOLVersionCounts = make(map[string]int)
+		}
+		ss.EOLVersionCounts[NormalizeVersion(result.PythonVersion, NormalizeMinor)]++
+	default:
+		ss.UnknownEOLProjects++
+	}
+}
+
+// ComplianceScore returns the percentage of Python projects on a supported
+// version, or 0 if there are none. Projects without a detected version and
+// failed scans aren't counted; projects whose support can't be determined
+// count against the score, so it never overstates compliance.
+func (ss *ScanStatistics) ComplianceScore() float64 {
+	judged := ss.SupportedProjects + ss.EOLProjects + ss.UnknownEOLProjects
+	if judged == 0 {
+		return 0
+	}
+	return float64(ss.SupportedProjects) * 100 / float64(judged)
+}
+
+// SortedEOLVersions returns the keys of EOLVersionCounts, oldest first
+func (ss *ScanStatistics) SortedEOLVersions() []string {
+	versions := make([]string, 0, len(ss.EOLVersionCounts))
+	for version := range ss.EOLVersionCounts {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return CompareVersions(versions[i], versions[j]) < 0
+	})
+	return versions
+}
+
+// recordMissingExplicit notes a Python project without an explicit version file
+func (ss *ScanStatistics) recordMissingExplicit(result *ScanResult) {
+	ss.MissingExplicitProjects++
+	ss.MissingExplicitPaths = append(ss.MissingExplicitPaths, result.ProjectPath)
+}
+
+// recordDrift counts result's Drift status and notes that it was scanned
+func (ss *ScanStatistics) recordDrift(result *ScanResult) {
+	if key, _, tracked := ss.Baseline.entry(result); tracked {
+		if ss.baselineScanned == nil {
+			ss.baselineScanned = make(map[string]bool)
+		}
+		ss.baselineScanned[key] = true
+	}
+
+	switch result.Drift {
+	case DriftConforming:
+		ss.BaselineConforming++
+	case DriftMismatch:
+		ss.BaselineDrifted++
+	case DriftNoVersion:
+		ss.BaselineNoVersion++
+	case DriftUntracked:
+		ss.BaselineUntracked++
+	}
+}
+
+// BaselineConformance returns the percentage of baseline projects scanned
+// successfully that are on their expected version, or 0 if there are none
+func (ss *ScanStatistics) BaselineConformance() float64 {
+	tracked := ss.BaselineConforming + ss.BaselineDrifted + ss.BaselineNoVersion
+	if tracked == 0 {
+		return 0
+	}
+	return float64(ss.BaselineConforming) * 100 / float64(tracked)
+}
+
+// BaselineUnscanned returns the baseline projects that no result was recorded
+// for, e.g. because they were renamed, archived, or are outside the group
+func (ss *ScanStatistics) BaselineUnscanned() []string {
+	return ss.Baseline.Unscanned(ss.baselineScanned)
+}
+
+// SortedVersions returns the keys of VersionCounts ordered by CompareVersions
+func (ss *ScanStatistics) SortedVersions() []string {
+	versions := make([]string, 0, len(ss.VersionCounts))
+	for version := range ss.VersionCounts {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return CompareVersions(versions[i], versions[j]) < 0
+	})
+	return versions
+}
+
+// SummaryLine returns a single key=value line suitable for shell consumption,
+// e.g. "SUMMARY total=2000 python=1400 undetected=500 errors=100"
+func (ss *ScanStatistics) SummaryLine() string {
+	return fmt.Sprintf("SUMMARY total=%d python=%d undetected=%d errors=%d",
+		ss.TotalProjects,
+		ss.PythonProjects,
+		ss.NonPythonProjects,
+		ss.ErrorCount,
+	)
 }
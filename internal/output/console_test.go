@@ -3,9 +3,14 @@ package output
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"math"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 )
 
 func TestNewConsoleStreamer(t *testing.T) {
@@ -42,6 +47,116 @@ func TestConsoleStreamer_StreamResult_Success(t *testing.T) {
 	}
 }
 
+func TestConsoleStreamer_StreamResult_VersionMax(t *testing.T) {
+	buf := &bytes.Buffer{}
+	streamer := NewConsoleStreamerWithWriter(buf)
+
+	result := &ScanResult{
+		ProjectName:     "legacy-api",
+		PythonVersion:   "3.8",
+		DetectionSource: "pyproject.toml",
+		VersionMax:      "<3.11",
+		Index:           2,
+		TotalProjects:   10,
+	}
+
+	if err := streamer.StreamResult(result); err != nil {
+		t.Fatalf("StreamResult() error = %v", err)
+	}
+
+	expected := "[2/10] legacy-api: Python 3.8 (from pyproject.toml, requires <3.11)\n"
+	if buf.String() != expected {
+		t.Errorf("StreamResult() output = %q, want %q", buf.String(), expected)
+	}
+}
+
+func TestConsoleStreamer_StreamResult_Verbose(t *testing.T) {
+	result := &ScanResult{
+		ProjectName:     "legacy-api",
+		PythonVersion:   "3.10",
+		DetectionSource: "pyproject.toml",
+		RawValue:        ">=3.10,<4.0",
+		Index:           2,
+		TotalProjects:   10,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := NewConsoleStreamerWithWriter(buf).StreamResult(result); err != nil {
+		t.Fatalf("StreamResult() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "raw value") {
+		t.Errorf("StreamResult() printed the raw value without Verbose: %q", buf.String())
+	}
+
+	buf.Reset()
+	streamer := NewConsoleStreamerWithWriter(buf)
+	streamer.Verbose = true
+	if err := streamer.StreamResult(result); err != nil {
+		t.Fatalf("StreamResult() error = %v", err)
+	}
+	expected := "[2/10] legacy-api: Python 3.10 (from pyproject.toml)\n" +
+		"  raw value: \">=3.10,<4.0\"\n"
+	if buf.String() != expected {
+		t.Errorf("StreamResult() output = %q, want %q", buf.String(), expected)
+	}
+}
+
+func TestConsoleStreamer_StreamResult_Warnings(t *testing.T) {
+	buf := &bytes.Buffer{}
+	streamer := NewConsoleStreamerWithWriter(buf)
+
+	result := &ScanResult{
+		ProjectName:     "api",
+		PythonVersion:   "3.12",
+		DetectionSource: "runtime.txt",
+		Warnings:        []string{"setup.cfg: parser error in rule setup-cfg: bad section"},
+		Index:           3,
+		TotalProjects:   10,
+	}
+
+	if err := streamer.StreamResult(result); err != nil {
+		t.Fatalf("StreamResult() error = %v", err)
+	}
+
+	expected := "[3/10] api: Python 3.12 (from runtime.txt)\n" +
+		"  warning: setup.cfg: parser error in rule setup-cfg: bad section\n"
+	if buf.String() != expected {
+		t.Errorf("StreamResult() output = %q, want %q", buf.String(), expected)
+	}
+}
+
+func TestConsoleStreamer_StreamResult_Explanation(t *testing.T) {
+	buf := &bytes.Buffer{}
+	streamer := NewConsoleStreamerWithWriter(buf)
+
+	result := &ScanResult{
+		ProjectName:     "billing",
+		PythonVersion:   "3.9",
+		DetectionSource: "setup.py",
+		Index:           3,
+		TotalProjects:   40,
+		Explanation: &Explanation{
+			Steps: []ExplainStep{
+				{Rule: "python-version-file", Priority: 1, File: ".python-version", Outcome: ExplainImplausible, Detail: "major version 7 is not plausible"},
+				{Rule: "setup-py", Priority: 8, File: "setup.py", Outcome: ExplainSelected, Version: "3.9", Confidence: 0.9},
+			},
+			Decision: "Python 3.9 from setup.py",
+		},
+	}
+
+	if err := streamer.StreamResult(result); err != nil {
+		t.Fatalf("StreamResult() error = %v", err)
+	}
+
+	expected := "[3/40] billing: Python 3.9 (from setup.py)\n" +
+		"  explain: .python-version (python-version-file, priority 1): implausible - major version 7 is not plausible\n" +
+		"  explain: setup.py (setup-py, priority 8): selected Python 3.9 (confidence 0.90)\n" +
+		"  explain: decision: Python 3.9 from setup.py\n"
+	if buf.String() != expected {
+		t.Errorf("StreamResult() output = %q, want %q", buf.String(), expected)
+	}
+}
+
 func TestConsoleStreamer_StreamResult_NotDetected(t *testing.T) {
 	buf := &bytes.Buffer{}
 	streamer := NewConsoleStreamerWithWriter(buf)
@@ -97,6 +212,76 @@ func TestConsoleStreamer_StreamResult_Error(t *testing.T) {
 	}
 }
 
+func TestConsoleStreamer_StreamResult_Bucketed(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	buf := &bytes.Buffer{}
+	streamer := NewConsoleStreamerWithWriter(buf)
+	streamer.Bucketed = true
+
+	streamer.StreamResult(&ScanResult{ProjectName: "api", PythonVersion: "3.11", DetectionSource: ".python-version", Index: 1, TotalProjects: 9})
+	first := buf.String()
+	want := "Detected (1)\n  [1/9] api: Python 3.11 (from .python-version)\nUndetected (0)\nErrors (0)\n"
+	if first != want {
+		t.Fatalf("first view = %q, want %q", first, want)
+	}
+
+	// Every later result redraws the view over the previous one
+	buf.Reset()
+	streamer.StreamResult(&ScanResult{ProjectName: "web", Error: errors.New("boom"), Index: 2, TotalProjects: 9})
+	if got := buf.String(); !strings.HasPrefix(got, "\033[4A\033[J") || !strings.Contains(got, "Errors (1)\n  [2/9] web: Error - boom\n") {
+		t.Errorf("second view = %q, want the 4 previous lines cleared and the error bucketed", got)
+	}
+
+	// Only the latest results of a bucket are listed
+	for i := 3; i <= 9; i++ {
+		streamer.StreamResult(&ScanResult{ProjectName: fmt.Sprintf("lib%d", i), Index: i, TotalProjects: 9})
+	}
+	views := strings.Split(buf.String(), "\033[J")
+	last := views[len(views)-1]
+	if !strings.Contains(last, "Undetected (7)\n  ... 2 more\n  [5/9] lib5:") || strings.Contains(last, "lib4") {
+		t.Errorf("last view = %q, want the 5 latest of 7 undetected", last)
+	}
+
+	// A new run starts a fresh view
+	streamer.PrintHeader("gitlab.com/org", 1)
+	buf.Reset()
+	streamer.StreamResult(&ScanResult{ProjectName: "api", PythonVersion: "3.12", DetectionSource: ".python-version", Index: 1, TotalProjects: 1})
+	if got := buf.String(); strings.Contains(got, "\033[") || !strings.HasPrefix(got, "Detected (1)") {
+		t.Errorf("view after PrintHeader = %q, want a fresh view", got)
+	}
+}
+
+func TestConsoleStreamer_StreamResult_BucketedWidth(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	buf := &bytes.Buffer{}
+	streamer := NewConsoleStreamerWithWriter(buf)
+	streamer.Bucketed = true
+	streamer.Width = 24
+
+	streamer.StreamResult(&ScanResult{ProjectName: "a-project-with-a-long-name", PythonVersion: "3.11", DetectionSource: "services/api/pyproject.toml", Index: 1, TotalProjects: 3})
+	streamer.StreamResult(&ScanResult{ProjectName: "broken", Error: errors.New("500 Internal Server Error\nretry later"), Index: 2, TotalProjects: 3})
+	buf.Reset()
+	streamer.buckets.drawn = 0
+	streamer.buckets.redraw(buf, streamer.Width)
+	for _, row := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if n := utf8.RuneCountInString(row); n > streamer.Width {
+			t.Errorf("row %q is %d columns, want at most %d", row, n, streamer.Width)
+		}
+	}
+	rows := strings.Count(buf.String(), "\n")
+
+	if rows != 6 {
+		t.Errorf("view = %q, want 6 rows with the error's two lines", buf.String())
+	}
+
+	// The next redraw moves back over every row drawn
+	buf.Reset()
+	streamer.StreamResult(&ScanResult{ProjectName: "web", Index: 3, TotalProjects: 3})
+	if want := fmt.Sprintf("\033[%dA\033[J", rows); !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("second view = %q, want it to start with %q", buf.String(), want)
+	}
+}
+
 func TestConsoleStreamer_StreamResult_Concurrent(t *testing.T) {
 	buf := &bytes.Buffer{}
 	streamer := NewConsoleStreamerWithWriter(buf)
@@ -309,3 +494,444 @@ func TestScanStatistics_RecordResult_MultipleVersions(t *testing.T) {
 		t.Errorf("VersionCounts[2.7.18] = %d, want 1", stats.VersionCounts["2.7.18"])
 	}
 }
+
+// Run with -race: callers record results from many goroutines without locking
+func TestScanStatistics_RecordResult_Concurrent(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.ApprovedVersions = []string{"3.11"}
+
+	const workers, perWorker = 16, 200
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				switch i % 3 {
+				case 0:
+					stats.RecordResult(&ScanResult{PythonVersion: "3.11", Confidence: 1.0})
+				case 1:
+					stats.RecordResult(&ScanResult{PythonVersion: "3.8"})
+				default:
+					stats.RecordResult(&ScanResult{Error: errors.New("boom")})
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if stats.TotalProjects != workers*perWorker {
+		t.Errorf("TotalProjects = %d, want %d", stats.TotalProjects, workers*perWorker)
+	}
+	if got := stats.PythonProjects + stats.ErrorCount; got != workers*perWorker {
+		t.Errorf("PythonProjects + ErrorCount = %d, want %d", got, workers*perWorker)
+	}
+	if stats.VersionCounts["3.11"] != stats.ApprovedProjects || stats.VersionCounts["3.8"] != stats.NonApprovedProjects {
+		t.Errorf("version counts %v disagree with approved=%d non-approved=%d",
+			stats.VersionCounts, stats.ApprovedProjects, stats.NonApprovedProjects)
+	}
+}
+
+func TestScanStatistics_CandidateLimited(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.RecordResult(&ScanResult{CandidatesLimited: true})
+	stats.RecordResult(&ScanResult{PythonVersion: "3.11", CandidatesLimited: true})
+	stats.RecordResult(&ScanResult{PythonVersion: "3.12"})
+
+	if stats.CandidateLimitedProjects != 2 {
+		t.Errorf("CandidateLimitedProjects = %d, want 2", stats.CandidateLimitedProjects)
+	}
+
+	var buf bytes.Buffer
+	NewConsoleStreamerWithWriter(&buf).PrintSummary(stats)
+	if !strings.Contains(buf.String(), "Stopped at candidate limit: 2") {
+		t.Errorf("summary missing candidate limit line:\n%s", buf.String())
+	}
+}
+
+func TestScanStatistics_ParseErrors(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.RecordResult(&ScanResult{ParseErrors: []string{"setup.cfg: bad", "tox.ini: bad"}})
+	stats.RecordResult(&ScanResult{PythonVersion: "3.12"})
+
+	if stats.ParseErrorCount != 2 {
+		t.Errorf("ParseErrorCount = %d, want 2", stats.ParseErrorCount)
+	}
+
+	var buf bytes.Buffer
+	NewConsoleStreamerWithWriter(&buf).PrintSummary(stats)
+	if !strings.Contains(buf.String(), "Parse errors: 2") {
+		t.Errorf("summary missing parse error line:\n%s", buf.String())
+	}
+}
+
+func TestScanStatistics_MissingExplicit(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.RequireExplicit = true
+	stats.RecordResult(&ScanResult{ProjectPath: "org/pinned", PythonVersion: "3.12", ExplicitSource: true})
+	stats.RecordResult(&ScanResult{ProjectPath: "org/inferred", PythonVersion: "3.11"})
+	stats.RecordResult(&ScanResult{ProjectPath: "org/scripts", Classification: ClassPythonNoVersion})
+	stats.RecordResult(&ScanResult{ProjectPath: "org/frontend", Classification: ClassNonPython})
+	stats.RecordResult(&ScanResult{ProjectPath: "org/broken", Error: errors.New("boom")})
+
+	want := []string{"org/inferred", "org/scripts"}
+	if stats.MissingExplicitProjects != 2 || !reflect.DeepEqual(stats.MissingExplicitPaths, want) {
+		t.Errorf("MissingExplicitProjects = %d, MissingExplicitPaths = %v, want 2 %v",
+			stats.MissingExplicitProjects, stats.MissingExplicitPaths, want)
+	}
+
+	var buf bytes.Buffer
+	NewConsoleStreamerWithWriter(&buf).PrintSummary(stats)
+	if !strings.Contains(buf.String(), "Missing explicit version file: 2\n  - org/inferred\n  - org/scripts\n") {
+		t.Errorf("summary missing explicit version section:\n%s", buf.String())
+	}
+
+	// Off by default
+	stats = NewScanStatistics()
+	stats.RecordResult(&ScanResult{ProjectPath: "org/inferred", PythonVersion: "3.11"})
+	if stats.MissingExplicitProjects != 0 {
+		t.Errorf("MissingExplicitProjects = %d without RequireExplicit, want 0", stats.MissingExplicitProjects)
+	}
+}
+
+func TestScanStatistics_Python2(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.RecordResult(&ScanResult{ProjectPath: "org/legacy", PythonVersion: "2.7", IsPython2: true})
+	stats.RecordResult(&ScanResult{ProjectPath: "org/api", PythonVersion: "3.12"})
+	stats.RecordResult(&ScanResult{ProjectPath: "org/broken", PythonVersion: "2.7", IsPython2: true, Error: errors.New("boom")})
+
+	if stats.Python2Projects != 1 || len(stats.Python2Paths) != 1 || stats.Python2Paths[0] != "org/legacy" {
+		t.Errorf("Python2Projects = %d, Python2Paths = %v, want 1 [org/legacy]", stats.Python2Projects, stats.Python2Paths)
+	}
+
+	var buf bytes.Buffer
+	NewConsoleStreamerWithWriter(&buf).PrintSummary(stats)
+	if !strings.Contains(buf.String(), "Python 2 projects: 1\n  - org/legacy\n") {
+		t.Errorf("summary missing Python 2 section:\n%s", buf.String())
+	}
+}
+
+func TestScanStatistics_SummaryLine(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.RecordResult(&ScanResult{ProjectName: "a", PythonVersion: "3.11"})
+	stats.RecordResult(&ScanResult{ProjectName: "b", PythonVersion: "3.12"})
+	stats.RecordResult(&ScanResult{ProjectName: "c"})
+	stats.RecordResult(&ScanResult{ProjectName: "d", Error: errors.New("boom")})
+
+	want := "SUMMARY total=4 python=2 undetected=1 errors=1"
+	if got := stats.SummaryLine(); got != want {
+		t.Errorf("SummaryLine() = %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	streamer := NewConsoleStreamerWithWriter(&buf)
+	if err := streamer.PrintSummaryLine(stats); err != nil {
+		t.Fatalf("PrintSummaryLine() error = %v", err)
+	}
+	if buf.String() != want+"\n" {
+		t.Errorf("PrintSummaryLine() wrote %q, want a single line %q", buf.String(), want)
+	}
+}
+
+func TestConsoleStreamer_StreamResult_Mismatch(t *testing.T) {
+	var buf bytes.Buffer
+	streamer := NewConsoleStreamerWithWriter(&buf)
+
+	result := &ScanResult{
+		ProjectName:     "my-project",
+		PythonVersion:   "3.11",
+		DetectionSource: ".python-version",
+		Index:           1,
+		TotalProjects:   1,
+		CrossChecks: []CrossCheck{
+			{Source: "pyproject.toml", Version: "3.11.4"},
+			{Source: "Dockerfile", Version: "3.9"},
+		},
+		VersionMismatch: true,
+	}
+
+	if err := streamer.StreamResult(result); err != nil {
+		t.Fatalf("StreamResult failed: %v", err)
+	}
+
+	want := "[1/1] my-project: Python 3.11 (from .python-version) - MISMATCH: Dockerfile=3.9\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	stats := NewScanStatistics()
+	stats.RecordResult(result)
+	if stats.MismatchProjects != 1 {
+		t.Errorf("MismatchProjects = %d, want 1", stats.MismatchProjects)
+	}
+}
+
+func TestConsoleStreamer_StreamResult_Classified(t *testing.T) {
+	tests := []struct {
+		classification string
+		want           string
+	}{
+		{"", "[1/1] my-project: Python not detected\n"},
+		{ClassPythonNoVersion, "[1/1] my-project: Python version unknown (python-no-version)\n"},
+		{ClassNonPython, "[1/1] my-project: Python not detected (non-python)\n"},
+	}
+
+	stats := NewScanStatistics()
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		streamer := NewConsoleStreamerWithWriter(&buf)
+
+		result := &ScanResult{
+			ProjectName:    "my-project",
+			Index:          1,
+			TotalProjects:  1,
+			Classification: tt.classification,
+		}
+		if err := streamer.StreamResult(result); err != nil {
+			t.Fatalf("StreamResult failed: %v", err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("got %q, want %q", buf.String(), tt.want)
+		}
+		stats.RecordResult(result)
+	}
+
+	if stats.NonPythonProjects != 3 {
+		t.Errorf("NonPythonProjects = %d, want 3", stats.NonPythonProjects)
+	}
+	if stats.PythonNoVersionProjects != 1 {
+		t.Errorf("PythonNoVersionProjects = %d, want 1", stats.PythonNoVersionProjects)
+	}
+	if stats.NoPythonFilesProjects != 1 {
+		t.Errorf("NoPythonFilesProjects = %d, want 1", stats.NoPythonFilesProjects)
+	}
+}
+
+func TestScanStatistics_CIDrift(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.RecordResult(&ScanResult{ProjectName: "api", ProjectPath: "org/api", PythonVersion: "3.11", CIDrift: ".gitlab-ci.yml runs Python 3.9, outside pyproject.toml's >=3.11"})
+	stats.RecordResult(&ScanResult{ProjectName: "web", ProjectPath: "org/web", PythonVersion: "3.12"})
+
+	if stats.CIDriftProjects != 1 || len(stats.CIDriftPaths) != 1 || stats.CIDriftPaths[0] != "org/api" {
+		t.Errorf("CIDriftProjects = %d, paths %v; want 1, [org/api]", stats.CIDriftProjects, stats.CIDriftPaths)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := NewConsoleStreamerWithWriter(buf).PrintSummary(stats); err != nil {
+		t.Fatalf("PrintSummary() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "CI/declaration drift: 1\n  - org/api\n") {
+		t.Errorf("summary missing the drift list:\n%s", buf.String())
+	}
+}
+
+func TestScanStatistics_AtRisk(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.AtRiskBelow = "3.10"
+	stats.RecordResult(&ScanResult{ProjectName: "legacy", ProjectPath: "org/legacy", PythonVersion: "3.8"})
+	stats.RecordResult(&ScanResult{ProjectName: "api", ProjectPath: "org/api", PythonVersion: "3.11"})
+	stats.RecordResult(&ScanResult{ProjectName: "vague", ProjectPath: "org/vague", PythonVersion: "3"})
+	stats.RecordResult(&ScanResult{ProjectName: "docs", ProjectPath: "org/docs"})
+
+	if stats.AtRiskProjects != 1 || len(stats.AtRiskPaths) != 1 || stats.AtRiskPaths[0] != "org/legacy" {
+		t.Errorf("AtRiskProjects = %d, paths %v; want 1, [org/legacy]", stats.AtRiskProjects, stats.AtRiskPaths)
+	}
+	if stats.CurrentProjects != 1 {
+		t.Errorf("CurrentProjects = %d, want 1", stats.CurrentProjects)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := NewConsoleStreamerWithWriter(buf).PrintSummary(stats); err != nil {
+		t.Fatalf("PrintSummary() error = %v", err)
+	}
+	want := "=== 1 of 3 Python projects at risk (below 3.10) ===\n  - org/legacy\n  Current (3.10 or later): 1\n  Not placed (version too vague): 1\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("summary missing the at-risk section:\n%s", buf.String())
+	}
+}
+
+func TestScanStatistics_Warnings(t *testing.T) {
+	stats := NewScanStatistics()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			stats.RecordWarning(fmt.Sprintf("failed to write result for org/p%d: disk full", i))
+		}(i)
+	}
+	wg.Wait()
+	if len(stats.Warnings) != 50 {
+		t.Fatalf("len(Warnings) = %d, want 50", len(stats.Warnings))
+	}
+
+	buf := &bytes.Buffer{}
+	if err := NewConsoleStreamerWithWriter(buf).PrintSummary(stats); err != nil {
+		t.Fatalf("PrintSummary() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Warnings: 50\n  - failed to write result for org/p") {
+		t.Errorf("summary missing the warnings section:\n%s", buf.String())
+	}
+
+	clean := &bytes.Buffer{}
+	if err := NewConsoleStreamerWithWriter(clean).PrintSummary(NewScanStatistics()); err != nil {
+		t.Fatalf("PrintSummary() error = %v", err)
+	}
+	if strings.Contains(clean.String(), "Warnings") {
+		t.Errorf("summary of a clean run mentions warnings:\n%s", clean.String())
+	}
+}
+
+func TestScanStatistics_TimedOut(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.RecordResult(&ScanResult{ProjectName: "slow", TimedOut: true})
+	stats.RecordResult(&ScanResult{ProjectName: "slow-partial", PythonVersion: "3.11", TimedOut: true})
+
+	if stats.TimedOutProjects != 2 {
+		t.Errorf("TimedOutProjects = %d, want 2", stats.TimedOutProjects)
+	}
+	if stats.PythonProjects != 1 {
+		t.Errorf("PythonProjects = %d, want 1", stats.PythonProjects)
+	}
+	if stats.NonPythonProjects != 0 {
+		t.Errorf("NonPythonProjects = %d, want 0", stats.NonPythonProjects)
+	}
+
+	var buf bytes.Buffer
+	streamer := NewConsoleStreamerWithWriter(&buf)
+	if err := streamer.StreamResult(&ScanResult{ProjectName: "slow", Index: 1, TotalProjects: 2, TimedOut: true}); err != nil {
+		t.Fatalf("StreamResult failed: %v", err)
+	}
+	if want := "[1/2] slow: Timed out\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestConfidenceBucket(t *testing.T) {
+	tests := []struct {
+		confidence float64
+		want       string
+	}{
+		{1.0, ConfidenceExplicit},
+		{0.9, ConfidenceExplicit},
+		{0.89, ConfidenceInferred},
+		{0.6, ConfidenceInferred},
+		{0.59, ConfidenceWeak},
+		{0.3, ConfidenceWeak},
+	}
+
+	for _, tt := range tests {
+		if got := ConfidenceBucket(tt.confidence); got != tt.want {
+			t.Errorf("ConfidenceBucket(%v) = %q, want %q", tt.confidence, got, tt.want)
+		}
+	}
+}
+
+func TestConfidenceTiersRank(t *testing.T) {
+	tiers := &ConfidenceTiers{Explicit: 0.8, Inferred: 0.5}
+	tests := []struct {
+		confidence float64
+		want       int
+	}{
+		{1.0, 0},
+		{0.8, 0},
+		{0.79, 1},
+		{0.5, 1},
+		{0.49, 2},
+	}
+
+	for _, tt := range tests {
+		if got := tiers.Rank(tt.confidence); got != tt.want {
+			t.Errorf("Rank(%v) = %d, want %d", tt.confidence, got, tt.want)
+		}
+	}
+
+	var none *ConfidenceTiers
+	if got := none.Rank(0.1); got != 0 {
+		t.Errorf("nil Rank(0.1) = %d, want 0 so priority alone decides", got)
+	}
+}
+
+func TestScanStatistics_ConfidenceBuckets(t *testing.T) {
+	stats := NewScanStatistics()
+	for _, c := range []float64{1.0, 0.9, 0.95, 0.75, 0.5, 0} {
+		stats.RecordResult(&ScanResult{ProjectName: "p", PythonVersion: "3.11", Confidence: c})
+	}
+	// Undetected projects have no confidence to bucket
+	stats.RecordResult(&ScanResult{ProjectName: "p"})
+
+	want := map[string]int{ConfidenceExplicit: 3, ConfidenceInferred: 1, ConfidenceWeak: 1}
+	for bucket, n := range want {
+		if stats.ConfidenceBuckets[bucket] != n {
+			t.Errorf("ConfidenceBuckets[%s] = %d, want %d", bucket, stats.ConfidenceBuckets[bucket], n)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := NewConsoleStreamerWithWriter(buf).PrintSummary(stats); err != nil {
+		t.Fatalf("PrintSummary() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Confidence: 60% explicit (3), 20% inferred (1), 20% weak (1)\n") {
+		t.Errorf("summary missing confidence breakdown:\n%s", buf.String())
+	}
+}
+
+func TestDecayConfidence(t *testing.T) {
+	year := 365 * 24 * time.Hour
+	tests := []struct {
+		name       string
+		confidence float64
+		age        time.Duration
+		halfLife   time.Duration
+		want       float64
+	}{
+		{"fresh", 1.0, 0, year, 1.0},
+		{"one half-life", 1.0, year, year, 0.5},
+		{"two half-lives", 0.8, 2 * year, year, 0.2},
+		{"no half-life", 0.8, 2 * year, 0, 0.8},
+		{"future commit", 0.8, -time.Hour, year, 0.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecayConfidence(tt.confidence, tt.age, tt.halfLife)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("DecayConfidence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsoleStreamer_PrintVersionList(t *testing.T) {
+	stats := NewScanStatistics()
+	for _, version := range []string{"3.10", "3.9", "3.10", "2.7", "3.12"} {
+		stats.RecordResult(&ScanResult{ProjectName: "p", PythonVersion: version})
+	}
+	stats.RecordResult(&ScanResult{ProjectName: "p"})
+	stats.RecordResult(&ScanResult{ProjectName: "p", Error: errors.New("boom")})
+
+	tests := []struct {
+		name              string
+		withCounts        bool
+		includeUndetected bool
+		want              string
+	}{
+		{"versions only", false, false, "2.7\n3.9\n3.10\n3.12\n"},
+		{"with counts", true, false, "2.7 1\n3.9 1\n3.10 2\n3.12 1\n"},
+		{"with undetected", false, true, "2.7\n3.9\n3.10\n3.12\nundetected\n"},
+		{"with counts and undetected", true, true, "2.7 1\n3.9 1\n3.10 2\n3.12 1\nundetected 1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			streamer := NewConsoleStreamerWithWriter(buf)
+			if err := streamer.PrintVersionList(stats, tt.withCounts, tt.includeUndetected); err != nil {
+				t.Fatalf("PrintVersionList() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("PrintVersionList() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
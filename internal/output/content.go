@@ -3,16 +3,36 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
 // ContentMatchEntry represents a single string match found in a file
 type ContentMatchEntry struct {
-	FilePath    string // Full path of the file in the repository
-	LineNumber  int    // 1-based line number of the match
-	LineContent string // The full line containing the match
-	MatchedText string // The specific text that matched
+	FilePath      string   // Full path of the file in the repository
+	LineNumber    int      // 1-based line number of the match (0 for path-only matches)
+	LineContent   string   // The full line containing the match
+	MatchedText   string   // The specific text that matched
+	ContextBefore []string // Lines preceding the match (--context)
+	ContextAfter  []string // Lines following the match (--context)
+	Ref           string   // Branch or commit the file was read from ("" if unknown)
+}
+
+// WikiPathPrefix begins the FilePath of a match in a project's wiki
+// (--search-wikis), followed by the page's slug, e.g. "wiki:deployment/notes"
+const WikiPathPrefix = "wiki:"
+
+// matchLine formats a match for text output: "path:line: content", or just
+// the path for path-only matches from --match-files-only
+func matchLine(m ContentMatchEntry) string {
+	if m.LineNumber == 0 {
+		return m.FilePath
+	}
+	return fmt.Sprintf("%s:%d: %s", m.FilePath, m.LineNumber, m.LineContent)
 }
 
 // ContentScanResult represents the content search results for a single project
@@ -21,12 +41,50 @@ type ContentScanResult struct {
 	ProjectPath   string              // Full path of the project
 	Matches       []ContentMatchEntry // All matches found in this project
 	SearchTerm    string              // The string/pattern that was searched for
+	SearchName    string              // Name of the search (the term itself for --search)
+	Severity      string              // Triage label from the search's config entry ("" if unset)
 	Error         error               // Any error encountered during searching
 	Index         int                 // Sequential index of this result
 	TotalProjects int                 // Total number of projects being searched
+
+	// FirstMatchOnly means the search stopped at the project's first match
+	// (--first-match), so Matches shows presence rather than a count
+	FirstMatchOnly bool
+}
+
+// RedactText masks s for --redact. Its first four characters are kept when
+// it's long enough that they don't give it away, so findings can still be
+// told apart, e.g. "AKIA****************".
+func RedactText(s string) string {
+	runes := []rune(s)
+	if len(runes) < 12 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:4]) + strings.Repeat("*", len(runes)-4)
+}
+
+// Redact masks each match's text wherever it appears: the matched text
+// itself, its line, and its context. Path-only matches are left alone.
+func (r *ContentScanResult) Redact() {
+	for i := range r.Matches {
+		m := &r.Matches[i]
+		if m.LineNumber == 0 || m.MatchedText == "" {
+			continue
+		}
+		masked := RedactText(m.MatchedText)
+		m.LineContent = strings.ReplaceAll(m.LineContent, m.MatchedText, masked)
+		for j := range m.ContextBefore {
+			m.ContextBefore[j] = strings.ReplaceAll(m.ContextBefore[j], m.MatchedText, masked)
+		}
+		for j := range m.ContextAfter {
+			m.ContextAfter[j] = strings.ReplaceAll(m.ContextAfter[j], m.MatchedText, masked)
+		}
+		m.MatchedText = masked
+	}
 }
 
-// ContentScanStatistics holds summary statistics for a content search operation
+// ContentScanStatistics holds summary statistics for a content search operation.
+// RecordResult is safe for concurrent use; read the fields once recording is done.
 type ContentScanStatistics struct {
 	mu                sync.Mutex
 	TotalProjects     int            // Total number of projects searched
@@ -35,21 +93,53 @@ type ContentScanStatistics struct {
 	TotalMatches      int            // Total number of matches across all projects
 	ErrorCount        int            // Number of errors encountered
 	MatchesByFile     map[string]int // Match count by filename
+
+	// MatchesByExtension and ProjectsByExtension tally matches, and the
+	// projects with at least one match, per file extension (see FileExtension)
+	MatchesByExtension  map[string]int
+	ProjectsByExtension map[string]int
+
+	// FirstMatchOnly is set when results came from --first-match searches,
+	// whose match totals only reflect one match per project
+	FirstMatchOnly bool
+
+	// Warnings are run-level problems that didn't stop the search, such as
+	// results that couldn't be written to an output (see RecordWarning)
+	Warnings []string
 }
 
 // NewContentScanStatistics creates a new content search statistics tracker
 func NewContentScanStatistics() *ContentScanStatistics {
 	return &ContentScanStatistics{
-		MatchesByFile: make(map[string]int),
+		MatchesByFile:       make(map[string]int),
+		MatchesByExtension:  make(map[string]int),
+		ProjectsByExtension: make(map[string]int),
 	}
 }
 
+// noExtension is the extension bucket for files like Dockerfile or Makefile
+const noExtension = "(none)"
+
+// FileExtension returns the extension a match in filePath is grouped under,
+// lower-cased (".py"), or "(none)" for files without one. Dotfiles such as
+// .env are their own extension.
+func FileExtension(filePath string) string {
+	ext := strings.ToLower(path.Ext(filePath))
+	if ext == "" {
+		return noExtension
+	}
+	return ext
+}
+
 // RecordResult updates statistics based on a content search result
 func (cs *ContentScanStatistics) RecordResult(result *ContentScanResult) {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
 	cs.TotalProjects++
+	if result.FirstMatchOnly {
+		cs.FirstMatchOnly = true
+	}
 
 	if result.Error != nil {
 		cs.ErrorCount++
@@ -61,12 +151,53 @@ func (cs *ContentScanStatistics) RecordResult(result *ContentScanResult) {
 	} else {
 		cs.ProjectsWithHits++
 		cs.TotalMatches += len(result.Matches)
+		seen := make(map[string]bool)
 		for _, m := range result.Matches {
 			cs.MatchesByFile[m.FilePath]++
+			ext := FileExtension(m.FilePath)
+			cs.MatchesByExtension[ext]++
+			if !seen[ext] {
+				seen[ext] = true
+				cs.ProjectsByExtension[ext]++
+			}
 		}
 	}
 }
 
+// RecordWarning records a run-level warning; it's safe for concurrent use
+func (cs *ContentScanStatistics) RecordWarning(warning string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.Warnings = append(cs.Warnings, warning)
+}
+
+// ExtensionCount is one row of the per-extension match breakdown
+type ExtensionCount struct {
+	Extension string
+	Matches   int
+	Projects  int
+}
+
+// ExtensionBreakdown returns the per-extension tallies, most matches first
+// (ties by extension)
+func (cs *ContentScanStatistics) ExtensionBreakdown() []ExtensionCount {
+	counts := make([]ExtensionCount, 0, len(cs.MatchesByExtension))
+	for ext, matches := range cs.MatchesByExtension {
+		counts = append(counts, ExtensionCount{Extension: ext, Matches: matches, Projects: cs.ProjectsByExtension[ext]})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Matches != counts[j].Matches {
+			return counts[i].Matches > counts[j].Matches
+		}
+		return counts[i].Extension < counts[j].Extension
+	})
+	return counts
+}
+
+// firstMatchLabel replaces the match count of --first-match results, which
+// isn't a total
+const firstMatchLabel = "match found (first match only)"
+
 // StreamContentResult writes a single content search result to the console
 func (cs *ConsoleStreamer) StreamContentResult(result *ContentScanResult) error {
 	cs.mu.Lock()
@@ -84,14 +215,18 @@ func (cs *ConsoleStreamer) StreamContentResult(result *ContentScanResult) error
 		return err
 	}
 
-	_, err := fmt.Fprintf(cs.writer, "[%d/%d] %s: %d match(es) found\n",
-		result.Index, result.TotalProjects, result.ProjectName, len(result.Matches))
+	label := fmt.Sprintf("%d match(es) found", len(result.Matches))
+	if result.FirstMatchOnly {
+		label = firstMatchLabel
+	}
+	_, err := fmt.Fprintf(cs.writer, "[%d/%d] %s: %s\n",
+		result.Index, result.TotalProjects, result.ProjectName, label)
 	if err != nil {
 		return err
 	}
 
 	for _, m := range result.Matches {
-		_, err = fmt.Fprintf(cs.writer, "  %s:%d: %s\n", m.FilePath, m.LineNumber, m.LineContent)
+		_, err = fmt.Fprintf(cs.writer, "  %s\n", matchLine(m))
 		if err != nil {
 			return err
 		}
@@ -114,94 +249,191 @@ func (cs *ConsoleStreamer) PrintContentSummary(stats *ContentScanStatistics) err
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
-	_, err := fmt.Fprintf(cs.writer, "\nSearch complete: %d projects scanned, %d with matches (%d total matches)\n",
-		stats.TotalProjects, stats.ProjectsWithHits, stats.TotalMatches)
+	var err error
+	if stats.FirstMatchOnly {
+		_, err = fmt.Fprintf(cs.writer, "\nSearch complete: %d projects scanned, %d with matches (first match only, no match totals)\n",
+			stats.TotalProjects, stats.ProjectsWithHits)
+	} else {
+		_, err = fmt.Fprintf(cs.writer, "\nSearch complete: %d projects scanned, %d with matches (%d total matches)\n",
+			stats.TotalProjects, stats.ProjectsWithHits, stats.TotalMatches)
+	}
 
 	if stats.ErrorCount > 0 {
 		fmt.Fprintf(cs.writer, "Errors encountered: %d\n", stats.ErrorCount)
 	}
 
+	if len(stats.Warnings) > 0 {
+		fmt.Fprintf(cs.writer, "Warnings: %d\n", len(stats.Warnings))
+		for _, warning := range stats.Warnings {
+			fmt.Fprintf(cs.writer, "  - %s\n", warning)
+		}
+	}
+
+	// Shows where matches concentrate, to help narrow --file patterns
+	if breakdown := stats.ExtensionBreakdown(); !stats.FirstMatchOnly && len(breakdown) > 0 {
+		fmt.Fprintf(cs.writer, "Matches by extension:\n")
+		for _, ec := range breakdown {
+			fmt.Fprintf(cs.writer, "  %-10s %d (%.0f%%) in %d project(s)\n",
+				ec.Extension, ec.Matches, float64(ec.Matches)/float64(stats.TotalMatches)*100, ec.Projects)
+		}
+	}
+
 	return err
 }
 
-// ContentLogEntry represents a single content search log entry
+// ContentLogEntry is one line of a JSON content search log. Each match is
+// its own entry, so a project with three matches produces three lines; a
+// project with no matches, or that failed, produces a single entry without
+// match fields.
 type ContentLogEntry struct {
-	Timestamp   time.Time         `json:"timestamp"`
-	ProjectName string            `json:"project_name"`
-	ProjectPath string            `json:"project_path,omitempty"`
-	SearchTerm  string            `json:"search_term"`
-	Matches     []ContentMatchLog `json:"matches,omitempty"`
-	MatchCount  int               `json:"match_count"`
-	Error       string            `json:"error,omitempty"`
-	Index       int               `json:"index"`
-	Total       int               `json:"total_projects"`
-}
-
-// ContentMatchLog is the JSON-serializable form of a content match
-type ContentMatchLog struct {
-	FilePath    string `json:"file_path"`
-	LineNumber  int    `json:"line_number"`
-	LineContent string `json:"line_content"`
-	MatchedText string `json:"matched_text"`
+	Timestamp     time.Time `json:"timestamp"`
+	ProjectName   string    `json:"project_name"`
+	ProjectPath   string    `json:"project_path,omitempty"`
+	SearchName    string    `json:"search_name,omitempty"`
+	Severity      string    `json:"severity,omitempty"`
+	SearchTerm    string    `json:"search_term"`
+	Ref           string    `json:"ref,omitempty"`
+	FilePath      string    `json:"file_path,omitempty"`
+	LineNumber    int       `json:"line_number,omitempty"`
+	LineContent   string    `json:"line_content,omitempty"`
+	MatchedText   string    `json:"matched_text,omitempty"`
+	ContextBefore []string  `json:"context_before,omitempty"`
+	ContextAfter  []string  `json:"context_after,omitempty"`
+	MatchCount    int       `json:"match_count"`
+	FirstMatch    bool      `json:"first_match_only,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	Index         int       `json:"index"`
+	Total         int       `json:"total_projects"`
 }
 
-// LogContentResult writes a content search result to the log file
-func (fl *FileLogger) LogContentResult(result *ContentScanResult) error {
-	fl.mu.Lock()
-	defer fl.mu.Unlock()
-
-	entry := ContentLogEntry{
-		Timestamp:   time.Now(),
+// contentLogEntries converts a result into its JSON log entries
+func contentLogEntries(result *ContentScanResult, now time.Time) []ContentLogEntry {
+	base := ContentLogEntry{
+		Timestamp:   now,
 		ProjectName: result.ProjectName,
 		ProjectPath: result.ProjectPath,
+		SearchName:  result.SearchName,
+		Severity:    result.Severity,
 		SearchTerm:  result.SearchTerm,
 		MatchCount:  len(result.Matches),
+		FirstMatch:  result.FirstMatchOnly,
 		Index:       result.Index,
 		Total:       result.TotalProjects,
 	}
 
 	if result.Error != nil {
-		entry.Error = result.Error.Error()
+		base.Error = result.Error.Error()
+		return []ContentLogEntry{base}
+	}
+	if len(result.Matches) == 0 {
+		return []ContentLogEntry{base}
 	}
 
+	entries := make([]ContentLogEntry, 0, len(result.Matches))
 	for _, m := range result.Matches {
-		entry.Matches = append(entry.Matches, ContentMatchLog{
-			FilePath:    m.FilePath,
-			LineNumber:  m.LineNumber,
-			LineContent: m.LineContent,
-			MatchedText: m.MatchedText,
-		})
+		entry := base
+		entry.Ref = m.Ref
+		entry.FilePath = m.FilePath
+		entry.LineNumber = m.LineNumber
+		entry.LineContent = m.LineContent
+		entry.MatchedText = m.MatchedText
+		entry.ContextBefore = m.ContextBefore
+		entry.ContextAfter = m.ContextAfter
+		entries = append(entries, entry)
 	}
+	return entries
+}
+
+// LogContentResult writes a content search result to the log file
+func (fl *FileLogger) LogContentResult(result *ContentScanResult) error {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	now := time.Now()
 
 	switch fl.format {
 	case FormatJSON:
-		data, err := json.Marshal(&entry)
-		if err != nil {
-			return fmt.Errorf("failed to marshal content log entry: %w", err)
+		for _, entry := range contentLogEntries(result, now) {
+			data, err := json.Marshal(&entry)
+			if err != nil {
+				return fmt.Errorf("failed to marshal content log entry: %w", err)
+			}
+			if _, err := fl.file.Write(append(data, '\n')); err != nil {
+				return err
+			}
 		}
-		_, err = fl.file.Write(append(data, '\n'))
-		return err
+		return nil
 	case FormatText:
-		if entry.Error != "" {
+		if result.Error != nil {
 			_, err := fmt.Fprintf(fl.file, "[%s] [%d/%d] %s: Error - %s\n",
-				entry.Timestamp.Format(time.RFC3339), entry.Index, entry.Total, entry.ProjectName, entry.Error)
+				now.Format(time.RFC3339), result.Index, result.TotalProjects, result.ProjectName, result.Error)
 			return err
 		}
-		if entry.MatchCount == 0 {
+		if len(result.Matches) == 0 {
 			_, err := fmt.Fprintf(fl.file, "[%s] [%d/%d] %s: no matches\n",
-				entry.Timestamp.Format(time.RFC3339), entry.Index, entry.Total, entry.ProjectName)
+				now.Format(time.RFC3339), result.Index, result.TotalProjects, result.ProjectName)
 			return err
 		}
-		_, err := fmt.Fprintf(fl.file, "[%s] [%d/%d] %s: %d match(es)\n",
-			entry.Timestamp.Format(time.RFC3339), entry.Index, entry.Total, entry.ProjectName, entry.MatchCount)
+		label := fmt.Sprintf("%d match(es)", len(result.Matches))
+		if result.FirstMatchOnly {
+			label = firstMatchLabel
+		}
+		_, err := fmt.Fprintf(fl.file, "[%s] [%d/%d] %s: %s\n",
+			now.Format(time.RFC3339), result.Index, result.TotalProjects, result.ProjectName, label)
 		if err != nil {
 			return err
 		}
-		for _, m := range entry.Matches {
-			fmt.Fprintf(fl.file, "  %s:%d: %s\n", m.FilePath, m.LineNumber, m.LineContent)
+		for _, m := range result.Matches {
+			fmt.Fprintf(fl.file, "  %s\n", matchLine(m))
+		}
+		return nil
+	case FormatCSV:
+		for _, entry := range contentLogEntries(result, now) {
+			// One row per match; projects without matches have none
+			if entry.FilePath == "" && entry.Error == "" {
+				continue
+			}
+			if err := fl.writeContentCSV(&entry); err != nil {
+				return err
+			}
 		}
 		return nil
 	default:
 		return fmt.Errorf("unknown log format: %s", fl.format)
 	}
 }
+
+// contentCSVColumns are the column names written as the first row of CSV
+// content search logs
+var contentCSVColumns = []string{
+	"timestamp",
+	"project_name",
+	"project_path",
+	"search_name",
+	"severity",
+	"ref",
+	"file_path",
+	"line_number",
+	"matched_text",
+	"error",
+}
+
+// writeContentCSV writes a content log entry as a single CSV row
+func (fl *FileLogger) writeContentCSV(entry *ContentLogEntry) error {
+	line := ""
+	if entry.LineNumber > 0 {
+		line = strconv.Itoa(entry.LineNumber)
+	}
+	return fl.writeCSVRecord([]string{
+		entry.Timestamp.Format(time.RFC3339),
+		entry.ProjectName,
+		entry.ProjectPath,
+		entry.SearchName,
+		entry.Severity,
+		entry.Ref,
+		entry.FilePath,
+		line,
+		entry.MatchedText,
+		entry.Error,
+	})
+}
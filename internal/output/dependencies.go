@@ -0,0 +1,223 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Dependency is one package requirement declared by a project
+type Dependency struct {
+	Name      string // Package name as written (e.g., "Django")
+	Specifier string // Version specifier (e.g., "==4.2.7"), "" if unpinned
+}
+
+// PackageUsage aggregates one package across every scanned project
+type PackageUsage struct {
+	Name       string         `json:"name"`       // Normalized package name
+	Projects   []string       `json:"projects"`   // Projects declaring the package, sorted
+	Specifiers map[string]int `json:"specifiers"` // Projects per version specifier ("" = unpinned)
+}
+
+// unpinnedSpecifier is how an empty specifier is shown in text and CSV reports
+const unpinnedSpecifier = "(unpinned)"
+
+// packageNameSeparators matches the runs of separators PEP 503 treats as equal
+var packageNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// NormalizePackageName returns the PEP 503 form of a package name, so that
+// "Django", "django", and "python_dateutil"/"python-dateutil" are counted together
+func NormalizePackageName(name string) string {
+	return packageNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+// DependencyInventory tallies which projects use each package, for a
+// cross-project report such as "django is used by 43 projects".
+// It is safe for concurrent use.
+type DependencyInventory struct {
+	mu       sync.Mutex
+	packages map[string]*PackageUsage
+	projects map[string]bool
+}
+
+// NewDependencyInventory creates an empty inventory
+func NewDependencyInventory() *DependencyInventory {
+	return &DependencyInventory{
+		packages: make(map[string]*PackageUsage),
+		projects: make(map[string]bool),
+	}
+}
+
+// Record adds a project's dependencies. A package declared more than once
+// by the same project (e.g., in several requirements files) counts once,
+// with the first specifier seen.
+func (inv *DependencyInventory) Record(project string, deps []Dependency) {
+	if len(deps) == 0 {
+		return
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	inv.projects[project] = true
+	seen := make(map[string]bool, len(deps))
+	for _, dep := range deps {
+		name := NormalizePackageName(dep.Name)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		usage, ok := inv.packages[name]
+		if !ok {
+			usage = &PackageUsage{Name: name, Specifiers: make(map[string]int)}
+			inv.packages[name] = usage
+		}
+		usage.Projects = append(usage.Projects, project)
+		usage.Specifiers[dep.Specifier]++
+	}
+}
+
+// ProjectCount returns the number of projects that declared any dependency
+func (inv *DependencyInventory) ProjectCount() int {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return len(inv.projects)
+}
+
+// Packages returns every package, most widely used first (ties by name)
+func (inv *DependencyInventory) Packages() []*PackageUsage {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	packages := make([]*PackageUsage, 0, len(inv.packages))
+	for _, usage := range inv.packages {
+		sort.Strings(usage.Projects)
+		packages = append(packages, usage)
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		if len(packages[i].Projects) != len(packages[j].Projects) {
+			return len(packages[i].Projects) > len(packages[j].Projects)
+		}
+		return packages[i].Name < packages[j].Name
+	})
+	return packages
+}
+
+// WriteDependencyReport writes the inventory to path, inferring the format
+// from its extension like scan logs do (see FormatFromPath)
+func WriteDependencyReport(path string, inv *DependencyInventory) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create dependency report: %w", err)
+	}
+
+	if err := WriteDependencyReportTo(file, FormatFromPath(path), inv); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteDependencyReportTo writes the inventory to w in the given format
+func WriteDependencyReportTo(w io.Writer, format LogFormat, inv *DependencyInventory) error {
+	packages := inv.Packages()
+
+	switch format {
+	case FormatText:
+		return writeDependencyText(w, inv.ProjectCount(), packages)
+	case FormatCSV:
+		return writeDependencyCSV(w, packages)
+	default:
+		return writeDependencyJSON(w, inv.ProjectCount(), packages)
+	}
+}
+
+// writeDependencyText writes one line per package with its specifier breakdown
+func writeDependencyText(w io.Writer, projectCount int, packages []*PackageUsage) error {
+	if _, err := fmt.Fprintf(w, "=== Dependency Report ===\nProjects with dependencies: %d\nPackages: %d\n\n", projectCount, len(packages)); err != nil {
+		return err
+	}
+	for _, usage := range packages {
+		if _, err := fmt.Fprintf(w, "%s: %d projects (%s)\n", usage.Name, len(usage.Projects), formatSpecifiers(usage.Specifiers)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDependencyCSV writes one row per package and specifier
+func writeDependencyCSV(w io.Writer, packages []*PackageUsage) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"package", "project_count", "specifier", "specifier_count", "projects"})
+	for _, usage := range packages {
+		for _, spec := range sortedSpecifiers(usage.Specifiers) {
+			cw.Write([]string{
+				usage.Name,
+				strconv.Itoa(len(usage.Projects)),
+				displaySpecifier(spec),
+				strconv.Itoa(usage.Specifiers[spec]),
+				strings.Join(usage.Projects, ";"),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeDependencyJSON writes the whole report as one indented JSON document
+func writeDependencyJSON(w io.Writer, projectCount int, packages []*PackageUsage) error {
+	report := struct {
+		ProjectCount int             `json:"project_count"`
+		Packages     []*PackageUsage `json:"packages"`
+	}{projectCount, packages}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode dependency report: %w", err)
+	}
+	return nil
+}
+
+// formatSpecifiers renders a breakdown such as "==4.2.7: 30, (unpinned): 13"
+func formatSpecifiers(specifiers map[string]int) string {
+	parts := make([]string, 0, len(specifiers))
+	for _, spec := range sortedSpecifiers(specifiers) {
+		parts = append(parts, fmt.Sprintf("%s: %d", displaySpecifier(spec), specifiers[spec]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sortedSpecifiers orders specifiers by project count, then text, with
+// unpinned last among equals
+func sortedSpecifiers(specifiers map[string]int) []string {
+	specs := make([]string, 0, len(specifiers))
+	for spec := range specifiers {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		if specifiers[specs[i]] != specifiers[specs[j]] {
+			return specifiers[specs[i]] > specifiers[specs[j]]
+		}
+		if specs[i] == "" || specs[j] == "" {
+			return specs[j] == ""
+		}
+		return specs[i] < specs[j]
+	})
+	return specs
+}
+
+func displaySpecifier(spec string) string {
+	if spec == "" {
+		return unpinnedSpecifier
+	}
+	return spec
+}
@@ -0,0 +1,111 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizePackageName(t *testing.T) {
+	tests := map[string]string{
+		"Django":          "django",
+		"python_dateutil": "python-dateutil",
+		"zope.interface":  "zope-interface",
+		"My__Odd-.Name":   "my-odd-name",
+	}
+	for in, want := range tests {
+		if got := NormalizePackageName(in); got != want {
+			t.Errorf("NormalizePackageName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDependencyInventory(t *testing.T) {
+	inv := NewDependencyInventory()
+	inv.Record("group/api", []Dependency{{"Django", "==4.2.7"}, {"requests", ">=2.28"}, {"django", "==3.2"}})
+	inv.Record("group/web", []Dependency{{"django", "==4.2.7"}})
+	inv.Record("group/tools", []Dependency{{"Django", ""}, {"click", ""}})
+	inv.Record("group/empty", nil)
+
+	if got := inv.ProjectCount(); got != 3 {
+		t.Errorf("ProjectCount() = %d, want 3", got)
+	}
+
+	packages := inv.Packages()
+	if len(packages) != 3 {
+		t.Fatalf("got %d packages, want 3", len(packages))
+	}
+
+	django := packages[0]
+	if django.Name != "django" || len(django.Projects) != 3 {
+		t.Errorf("first package = %s with %d projects, want django with 3", django.Name, len(django.Projects))
+	}
+	// The duplicate "django==3.2" in group/api is ignored
+	if django.Specifiers["==4.2.7"] != 2 || django.Specifiers[""] != 1 || len(django.Specifiers) != 2 {
+		t.Errorf("django specifiers = %v", django.Specifiers)
+	}
+
+	// Ties are ordered by name
+	if packages[1].Name != "click" || packages[2].Name != "requests" {
+		t.Errorf("package order = %s, %s, want click, requests", packages[1].Name, packages[2].Name)
+	}
+}
+
+func TestWriteDependencyReportTo(t *testing.T) {
+	inv := NewDependencyInventory()
+	inv.Record("group/api", []Dependency{{"Django", "==4.2.7"}})
+	inv.Record("group/web", []Dependency{{"django", ""}})
+
+	var text bytes.Buffer
+	if err := WriteDependencyReportTo(&text, FormatText, inv); err != nil {
+		t.Fatalf("text report failed: %v", err)
+	}
+	if !strings.Contains(text.String(), "django: 2 projects (==4.2.7: 1, (unpinned): 1)") {
+		t.Errorf("unexpected text report:\n%s", text.String())
+	}
+
+	var csvOut bytes.Buffer
+	if err := WriteDependencyReportTo(&csvOut, FormatCSV, inv); err != nil {
+		t.Fatalf("csv report failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 3 || lines[1] != "django,2,==4.2.7,1,group/api;group/web" {
+		t.Errorf("unexpected csv report:\n%s", csvOut.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteDependencyReportTo(&jsonOut, FormatJSON, inv); err != nil {
+		t.Fatalf("json report failed: %v", err)
+	}
+	var report struct {
+		ProjectCount int             `json:"project_count"`
+		Packages     []*PackageUsage `json:"packages"`
+	}
+	if err := json.Unmarshal(jsonOut.Bytes(), &report); err != nil {
+		t.Fatalf("invalid json report: %v", err)
+	}
+	if report.ProjectCount != 2 || len(report.Packages) != 1 || report.Packages[0].Name != "django" {
+		t.Errorf("unexpected json report: %+v", report)
+	}
+}
+
+func TestWriteDependencyReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deps.txt")
+
+	inv := NewDependencyInventory()
+	inv.Record("group/api", []Dependency{{"flask", ">=3.0"}})
+	if err := WriteDependencyReport(path, inv); err != nil {
+		t.Fatalf("WriteDependencyReport() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "=== Dependency Report ===") {
+		t.Errorf("expected text report for .txt path, got:\n%s", data)
+	}
+}
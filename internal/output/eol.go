@@ -0,0 +1,80 @@
+package output
+
+import "time"
+
+// EOLStatus is whether a Python version still receives upstream fixes
+type EOLStatus string
+
+const (
+	EOLSupported EOLStatus = "supported" // Before its end-of-life date
+	EOLEnded     EOLStatus = "eol"       // On or after its end-of-life date
+	EOLUnknown   EOLStatus = "unknown"   // No minor version to look up, e.g. "3"
+)
+
+// pythonEOL maps each Python 3 minor release to the day it stops receiving
+// security fixes, from https://devguide.python.org/versions/. Releases that
+// haven't reached end of life use the planned month-end dates.
+var pythonEOL = map[int]string{
+	0:  "2009-06-27",
+	1:  "2012-04-09",
+	2:  "2016-02-20",
+	3:  "2017-09-29",
+	4:  "2019-03-18",
+	5:  "2020-09-30",
+	6:  "2021-12-23",
+	7:  "2023-06-27",
+	8:  "2024-10-07",
+	9:  "2025-10-31",
+	10: "2026-10-31",
+	11: "2027-10-31",
+	12: "2028-10-31",
+	13: "2029-10-31",
+	14: "2030-10-31",
+}
+
+// latestKnownMinor is the newest Python 3 minor release in pythonEOL
+const latestKnownMinor = 14
+
+// PythonEOL returns the end-of-life date of version's major.minor release;
+// ok is false when the table has no date for it. Python 2 ended with 2.7
+// on 2020-01-01, which is returned for every 2.x version.
+func PythonEOL(version string) (date time.Time, ok bool) {
+	nums, err := ParseVersion(version)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var day string
+	switch {
+	case nums[0] == 2:
+		day = "2020-01-01"
+	case nums[0] == 3 && len(nums) >= 2:
+		day, ok = pythonEOL[nums[1]]
+		if !ok {
+			return time.Time{}, false
+		}
+	default:
+		return time.Time{}, false
+	}
+
+	date, err = time.Parse(time.DateOnly, day)
+	return date, err == nil
+}
+
+// VersionEOLStatus reports whether version is supported at t. All of Python
+// 2 has ended; Python 3 releases newer than the table are treated as
+// supported, since a release can't reach end of life before older ones do.
+func VersionEOLStatus(version string, at time.Time) EOLStatus {
+	if date, ok := PythonEOL(version); ok {
+		if at.Before(date) {
+			return EOLSupported
+		}
+		return EOLEnded
+	}
+
+	nums, err := ParseVersion(version)
+	if err == nil && nums[0] == 3 && len(nums) >= 2 && nums[1] > latestKnownMinor {
+		return EOLSupported
+	}
+	return EOLUnknown
+}
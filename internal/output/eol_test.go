@@ -0,0 +1,81 @@
+package output
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVersionEOLStatus(t *testing.T) {
+	at := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		version string
+		want    EOLStatus
+	}{
+		{"3.12", EOLSupported},
+		{"3.10.4", EOLSupported},
+		{"3.9", EOLEnded},
+		{"3.6.15", EOLEnded},
+		{"2.7", EOLEnded},
+		{"2", EOLEnded},
+		{"3.19", EOLSupported}, // Newer than the table
+		{"3", EOLUnknown},
+		{"3.x", EOLUnknown},
+		{"4.0", EOLUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := VersionEOLStatus(tt.version, at); got != tt.want {
+			t.Errorf("VersionEOLStatus(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestVersionEOLStatusOnEOLDate(t *testing.T) {
+	date, ok := PythonEOL("3.9")
+	if !ok {
+		t.Fatal("PythonEOL(3.9) not found")
+	}
+	if got := VersionEOLStatus("3.9", date.Add(-time.Second)); got != EOLSupported {
+		t.Errorf("the second before EOL = %q, want supported", got)
+	}
+	if got := VersionEOLStatus("3.9", date); got != EOLEnded {
+		t.Errorf("on the EOL date = %q, want eol", got)
+	}
+}
+
+func TestScanStatistics_OrgSummary(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.OrgSummary = true
+	stats.EOLAsOf = time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	for _, version := range []string{"3.12", "3.11.4", "3.8", "3.8.10", "2.7", "3"} {
+		stats.RecordResult(&ScanResult{ProjectName: "p", PythonVersion: version})
+	}
+	// Neither undetected projects nor errors count toward the score
+	stats.RecordResult(&ScanResult{ProjectName: "p"})
+	stats.RecordResult(&ScanResult{ProjectName: "p", Error: errors.New("404 Not Found")})
+
+	if stats.SupportedProjects != 2 || stats.EOLProjects != 3 || stats.UnknownEOLProjects != 1 {
+		t.Errorf("supported/eol/unknown = %d/%d/%d, want 2/3/1",
+			stats.SupportedProjects, stats.EOLProjects, stats.UnknownEOLProjects)
+	}
+	if stats.EOLVersionCounts["3.8"] != 2 || stats.EOLVersionCounts["2.7"] != 1 {
+		t.Errorf("EOLVersionCounts = %v", stats.EOLVersionCounts)
+	}
+	if got := stats.SortedEOLVersions(); len(got) != 2 || got[0] != "2.7" || got[1] != "3.8" {
+		t.Errorf("SortedEOLVersions() = %v, want [2.7 3.8]", got)
+	}
+	if got, want := stats.ComplianceScore(), 100*2/6.0; got != want {
+		t.Errorf("ComplianceScore() = %v, want %v", got, want)
+	}
+}
+
+func TestScanStatistics_OrgSummaryOff(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.RecordResult(&ScanResult{ProjectName: "p", PythonVersion: "3.8"})
+
+	if stats.EOLProjects != 0 || stats.ComplianceScore() != 0 {
+		t.Errorf("counted EOL without OrgSummary: %d eol, score %v", stats.EOLProjects, stats.ComplianceScore())
+	}
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"fmt"
+	"io"
+)
+
+// Outcomes of one candidate file in an Explanation
+const (
+	// ExplainIgnored marks a candidate matching an ignore glob, never fetched
+	ExplainIgnored = "ignored"
+
+	// ExplainMissing marks a candidate that isn't in the repository
+	ExplainMissing = "missing"
+
+	// ExplainFetchError marks a candidate that couldn't be fetched for any
+	// other reason (e.g. a timeout)
+	ExplainFetchError = "fetch-error"
+
+	// ExplainNotRegular marks a symlink that leaves the repository or points
+	// at a file that couldn't be read
+	ExplainNotRegular = "not-regular-file"
+
+	// ExplainNoVersion marks a file the rule found no Python version in
+	ExplainNoVersion = "no-version"
+
+	// ExplainRuleError marks a file the rule failed on
+	ExplainRuleError = "rule-error"
+
+	// ExplainImplausible marks a version discarded as outside the version bounds
+	ExplainImplausible = "implausible"
+
+	// ExplainSelected marks the detection that was reported
+	ExplainSelected = "selected"
+
+	// ExplainCrossCheck marks a further detection recorded by --cross-check
+	ExplainCrossCheck = "cross-check"
+
+	// ExplainOutranked marks a detection --authoritative passed over, for
+	// one in a higher confidence tier or an earlier one in the same tier
+	ExplainOutranked = "outranked"
+)
+
+// Explanation is the decision trace behind one project's result (--explain):
+// every candidate file in the order it was considered, what its rule made of
+// it, and why the reported version won
+type Explanation struct {
+	Steps    []ExplainStep `json:"steps"`
+	Decision string        `json:"decision"`
+}
+
+// ExplainStep is one candidate file and its outcome
+type ExplainStep struct {
+	Rule       string  `json:"rule"`
+	Priority   int     `json:"priority"`
+	File       string  `json:"file"`
+	Outcome    string  `json:"outcome"` // One of the Explain* outcomes
+	Version    string  `json:"version,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+	Detail     string  `json:"detail,omitempty"` // The error or reason behind the outcome
+}
+
+// writeExplanation writes e indented under a project's result line
+func writeExplanation(w io.Writer, e *Explanation) error {
+	for _, step := range e.Steps {
+		line := fmt.Sprintf("  explain: %s (%s, priority %d): %s", step.File, step.Rule, step.Priority, step.Outcome)
+		if step.Version != "" {
+			line += fmt.Sprintf(" Python %s (confidence %.2f)", step.Version, step.Confidence)
+		}
+		if step.Detail != "" {
+			line += " - " + step.Detail
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "  explain: decision: %s\n", e.Decision)
+	return err
+}
@@ -1,23 +1,61 @@
 package output
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
 // LogEntry represents a single log entry in the log file
 type LogEntry struct {
-	Timestamp       time.Time `json:"timestamp"`
-	ProjectName     string    `json:"project_name"`
-	ProjectPath     string    `json:"project_path,omitempty"`
-	PythonVersion   string    `json:"python_version,omitempty"`
-	DetectionSource string    `json:"detection_source,omitempty"`
-	Error           string    `json:"error,omitempty"`
-	Index           int       `json:"index"`
-	TotalProjects   int       `json:"total_projects"`
+	Timestamp       time.Time    `json:"timestamp"`
+	ProjectName     string       `json:"project_name"`
+	ProjectPath     string       `json:"project_path,omitempty"`
+	PythonVersion   string       `json:"python_version,omitempty"`
+	DetectionSource string       `json:"detection_source,omitempty"`
+	Error           string       `json:"error,omitempty"`
+	Index           int          `json:"index"`
+	TotalProjects   int          `json:"total_projects"`
+	CrossChecks     []CrossCheck `json:"cross_checks,omitempty"`
+	VersionMismatch bool         `json:"version_mismatch,omitempty"`
+	LastCommitID    string       `json:"last_commit_id,omitempty"`
+	SourceSize      int          `json:"source_size,omitempty"`
+	Classification  string       `json:"classification,omitempty"`
+	TimedOut        bool         `json:"timed_out,omitempty"`
+	Confidence      float64      `json:"confidence,omitempty"`
+	VersionMax      string       `json:"version_max,omitempty"`
+	RawValue        string       `json:"raw_value,omitempty"`
+	Diagnostics     []string     `json:"diagnostics,omitempty"`
+
+	CandidatesLimited bool `json:"candidates_limited,omitempty"`
+	IsPython2         bool `json:"is_python2,omitempty"`
+
+	ExpectedVersion string `json:"expected_version,omitempty"`
+	Drift           string `json:"drift,omitempty"`
+
+	RawConfidence float64    `json:"raw_confidence,omitempty"`
+	SourceUpdated *time.Time `json:"source_updated,omitempty"`
+	ParseErrors   []string   `json:"parse_errors,omitempty"`
+	Warnings      []string   `json:"warnings,omitempty"`
+
+	ExplicitSource bool `json:"explicit_source,omitempty"`
+
+	Namespace     string `json:"namespace,omitempty"`
+	TopLevelGroup string `json:"top_level_group,omitempty"`
+
+	LastActivityAt string `json:"last_activity_at,omitempty"`
+	Cached         bool   `json:"cached,omitempty"`
+
+	Explanation *Explanation `json:"explanation,omitempty"`
+	CIDrift     string       `json:"ci_drift,omitempty"`
+	Instance    string       `json:"instance,omitempty"`
+	CommitSHA   string       `json:"commit_sha,omitempty"`
 }
 
 // LogFormat defines the format for log file output
@@ -28,13 +66,43 @@ const (
 	FormatJSON LogFormat = "json"
 	// FormatText outputs each result as a formatted text line
 	FormatText LogFormat = "text"
+	// FormatCSV outputs each result as a CSV row (with a column header row)
+	FormatCSV LogFormat = "csv"
 )
 
+// csvColumns are the column names written as the first row of CSV scan logs
+var csvColumns = []string{
+	"timestamp",
+	"index",
+	"total_projects",
+	"project_name",
+	"project_path",
+	"python_version",
+	"detection_source",
+	"error",
+}
+
+// FormatFromPath infers the log format from a file extension.
+// ".csv" selects CSV, ".txt" selects text, and anything else (including
+// ".json", ".jsonl", and ".log") selects JSON to match the historical default.
+func FormatFromPath(path string) LogFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".txt":
+		return FormatText
+	default:
+		return FormatJSON
+	}
+}
+
 // FileLogger handles writing scan results to a log file
 type FileLogger struct {
 	file   *os.File
 	format LogFormat
 	mu     sync.Mutex // Protects concurrent writes
+
+	wroteContentHeader bool // Whether the CSV content column row is written
 }
 
 // NewFileLogger creates a new file logger that writes to the specified path
@@ -79,8 +147,38 @@ func (fl *FileLogger) LogResult(result *ScanResult) error {
 		DetectionSource: result.DetectionSource,
 		Index:           result.Index,
 		TotalProjects:   result.TotalProjects,
+		CrossChecks:     result.CrossChecks,
+		VersionMismatch: result.VersionMismatch,
+		LastCommitID:    result.LastCommitID,
+		SourceSize:      result.SourceSize,
+		Classification:  result.Classification,
+		TimedOut:        result.TimedOut,
+		Diagnostics:     result.Diagnostics,
+		VersionMax:      result.VersionMax,
+		RawValue:        result.RawValue,
+		Confidence:      result.Confidence,
+
+		CandidatesLimited: result.CandidatesLimited,
+		IsPython2:         result.IsPython2,
+		ExpectedVersion:   result.ExpectedVersion,
+		Drift:             result.Drift,
+		RawConfidence:     result.RawConfidence,
+		ParseErrors:       result.ParseErrors,
+		Warnings:          result.Warnings,
+		ExplicitSource:    result.ExplicitSource,
+		Namespace:         result.Namespace,
+		TopLevelGroup:     result.TopLevelGroup,
+		LastActivityAt:    result.LastActivityAt,
+		Cached:            result.Cached,
+		Explanation:       result.Explanation,
+		CIDrift:           result.CIDrift,
+		Instance:          result.Instance,
+		CommitSHA:         result.CommitSHA,
 	}
 
+	if !result.SourceUpdated.IsZero() {
+		entry.SourceUpdated = &result.SourceUpdated
+	}
 	if result.Error != nil {
 		entry.Error = result.Error.Error()
 	}
@@ -90,6 +188,8 @@ func (fl *FileLogger) LogResult(result *ScanResult) error {
 		return fl.writeJSON(&entry)
 	case FormatText:
 		return fl.writeText(&entry)
+	case FormatCSV:
+		return fl.writeCSV(&entry)
 	default:
 		return fmt.Errorf("unknown log format: %s", fl.format)
 	}
@@ -119,26 +219,48 @@ func (fl *FileLogger) writeText(entry *LogEntry) error {
 			entry.Timestamp.Format(time.RFC3339),
 			entry.Index,
 			entry.TotalProjects,
-			entry.ProjectName,
+			projectLabel(entry.ProjectName, entry.Instance),
 			entry.Error,
 		)
+	} else if entry.TimedOut && entry.PythonVersion == "" {
+		line = fmt.Sprintf("[%s] [%d/%d] %s: Timed out\n",
+			entry.Timestamp.Format(time.RFC3339),
+			entry.Index,
+			entry.TotalProjects,
+			projectLabel(entry.ProjectName, entry.Instance),
+		)
 	} else if entry.PythonVersion == "" {
-		line = fmt.Sprintf("[%s] [%d/%d] %s: Python not detected\n",
+		line = fmt.Sprintf("[%s] [%d/%d] %s: %s\n",
 			entry.Timestamp.Format(time.RFC3339),
 			entry.Index,
 			entry.TotalProjects,
-			entry.ProjectName,
+			projectLabel(entry.ProjectName, entry.Instance),
+			undetectedLabel(entry.Classification),
 		)
 	} else {
-		line = fmt.Sprintf("[%s] [%d/%d] %s: Python %s (from %s)\n",
+		line = fmt.Sprintf("[%s] [%d/%d] %s: Python %s (from %s)%s\n",
 			entry.Timestamp.Format(time.RFC3339),
 			entry.Index,
 			entry.TotalProjects,
-			entry.ProjectName,
+			projectLabel(entry.ProjectName, entry.Instance),
 			entry.PythonVersion,
 			entry.DetectionSource,
+			mismatchSuffix(entry.PythonVersion, entry.CrossChecks),
 		)
 	}
+	if suffix := ciDriftSuffix(entry.CIDrift); suffix != "" {
+		line = strings.TrimSuffix(line, "\n") + suffix + "\n"
+	}
+	if suffix := driftSuffix(entry.Drift, entry.ExpectedVersion); suffix != "" {
+		line = strings.TrimSuffix(line, "\n") + suffix + "\n"
+	}
+	// Rule failures are listed under the project they belong to, as on the console
+	for _, parseErr := range entry.ParseErrors {
+		line += fmt.Sprintf("  parse error: %s\n", parseErr)
+	}
+	for _, warning := range entry.Warnings {
+		line += fmt.Sprintf("  warning: %s\n", warning)
+	}
 
 	_, err := fl.file.WriteString(line)
 	if err != nil {
@@ -148,6 +270,33 @@ func (fl *FileLogger) writeText(entry *LogEntry) error {
 	return nil
 }
 
+// writeCSV writes a log entry as a single CSV row
+func (fl *FileLogger) writeCSV(entry *LogEntry) error {
+	return fl.writeCSVRecord([]string{
+		entry.Timestamp.Format(time.RFC3339),
+		strconv.Itoa(entry.Index),
+		strconv.Itoa(entry.TotalProjects),
+		entry.ProjectName,
+		entry.ProjectPath,
+		entry.PythonVersion,
+		entry.DetectionSource,
+		entry.Error,
+	})
+}
+
+// writeCSVRecord writes one CSV record with proper quoting and flushes it
+func (fl *FileLogger) writeCSVRecord(record []string) error {
+	w := csv.NewWriter(fl.file)
+	if err := w.Write(record); err != nil {
+		return fmt.Errorf("failed to write to log file: %w", err)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write to log file: %w", err)
+	}
+	return nil
+}
+
 // WriteHeader writes the initial header information to the log file
 func (fl *FileLogger) WriteHeader(gitlabURL string, totalProjects int) error {
 	fl.mu.Lock()
@@ -176,6 +325,9 @@ func (fl *FileLogger) WriteHeader(gitlabURL string, totalProjects int) error {
 		header += fmt.Sprintf("GitLab URL: %s\n", gitlabURL)
 		header += fmt.Sprintf("Total Projects: %d\n", totalProjects)
 		header += fmt.Sprintf("=====================================\n\n")
+	case FormatCSV:
+		// CSV logs carry only the column header row
+		return fl.writeCSVRecord(csvColumns)
 	default:
 		return fmt.Errorf("unknown log format: %s", fl.format)
 	}
@@ -200,13 +352,87 @@ func (fl *FileLogger) WriteSummary(stats *ScanStatistics) error {
 	case FormatJSON:
 		// For JSON, write a summary entry
 		summaryEntry := map[string]interface{}{
-			"type":               "scan_completed",
-			"timestamp":          timestamp,
-			"total_projects":     stats.TotalProjects,
-			"python_projects":    stats.PythonProjects,
-			"non_python_projects": stats.NonPythonProjects,
-			"error_count":        stats.ErrorCount,
-			"version_counts":     stats.VersionCounts,
+			"type":                       "scan_completed",
+			"timestamp":                  timestamp,
+			"total_projects":             stats.TotalProjects,
+			"python_projects":            stats.PythonProjects,
+			"non_python_projects":        stats.NonPythonProjects,
+			"error_count":                stats.ErrorCount,
+			"version_counts":             stats.VersionCounts,
+			"mismatch_projects":          stats.MismatchProjects,
+			"confidence_buckets":         stats.ConfidenceBuckets,
+			"python_no_version_projects": stats.PythonNoVersionProjects,
+			"no_python_files_projects":   stats.NoPythonFilesProjects,
+		}
+		if stats.RunID != "" {
+			summaryEntry["run_id"] = stats.RunID
+			summaryEntry["run_started"] = stats.RunStarted.Format(time.RFC3339)
+		}
+		if stats.TimedOutProjects > 0 {
+			summaryEntry["timed_out_projects"] = stats.TimedOutProjects
+		}
+		if stats.CandidateLimitedProjects > 0 {
+			summaryEntry["candidate_limited_projects"] = stats.CandidateLimitedProjects
+		}
+		if stats.ParseErrorCount > 0 {
+			summaryEntry["parse_errors"] = stats.ParseErrorCount
+		}
+		if stats.CachedProjects > 0 {
+			summaryEntry["cached_projects"] = stats.CachedProjects
+		}
+		if stats.Python2Projects > 0 {
+			summaryEntry["python2_projects"] = stats.Python2Projects
+			summaryEntry["python2_paths"] = stats.Python2Paths
+		}
+		if stats.CIDriftProjects > 0 {
+			summaryEntry["ci_drift_projects"] = stats.CIDriftProjects
+			summaryEntry["ci_drift_paths"] = stats.CIDriftPaths
+		}
+		if stats.RequireExplicit {
+			summaryEntry["require_explicit"] = true
+			summaryEntry["missing_explicit_projects"] = stats.MissingExplicitProjects
+			summaryEntry["missing_explicit_paths"] = stats.MissingExplicitPaths
+		}
+		if stats.TargetVersion != "" {
+			summaryEntry["target_version"] = stats.TargetVersion
+			summaryEntry["capped_projects"] = stats.CappedProjects
+		}
+		if stats.AtRiskBelow != "" {
+			summaryEntry["at_risk_below"] = stats.AtRiskBelow
+			summaryEntry["at_risk_projects"] = stats.AtRiskProjects
+			summaryEntry["at_risk_paths"] = stats.AtRiskPaths
+			summaryEntry["current_projects"] = stats.CurrentProjects
+		}
+		if stats.OrgSummary {
+			summaryEntry["org_summary"] = true
+			summaryEntry["compliance_score"] = stats.ComplianceScore()
+			summaryEntry["supported_projects"] = stats.SupportedProjects
+			summaryEntry["eol_projects"] = stats.EOLProjects
+			summaryEntry["unknown_eol_projects"] = stats.UnknownEOLProjects
+			summaryEntry["eol_version_counts"] = stats.EOLVersionCounts
+			if !stats.EOLAsOf.IsZero() {
+				summaryEntry["eol_as_of"] = stats.EOLAsOf.Format(time.RFC3339)
+			}
+		}
+		if stats.Baseline != nil {
+			summaryEntry["baseline_conformance_pct"] = stats.BaselineConformance()
+			summaryEntry["baseline_conforming"] = stats.BaselineConforming
+			summaryEntry["baseline_drifted"] = stats.BaselineDrifted
+			summaryEntry["baseline_no_version"] = stats.BaselineNoVersion
+			summaryEntry["baseline_untracked"] = stats.BaselineUntracked
+			summaryEntry["baseline_not_scanned"] = stats.BaselineUnscanned()
+		}
+		if stats.ListingError != "" {
+			summaryEntry["listing_incomplete"] = true
+			summaryEntry["listing_error"] = stats.ListingError
+		}
+		if len(stats.Warnings) > 0 {
+			summaryEntry["warnings"] = stats.Warnings
+		}
+		if len(stats.ApprovedVersions) > 0 {
+			summaryEntry["approved_versions"] = stats.ApprovedVersions
+			summaryEntry["approved_projects"] = stats.ApprovedProjects
+			summaryEntry["non_approved_projects"] = stats.NonApprovedProjects
 		}
 		data, err := json.Marshal(summaryEntry)
 		if err != nil {
@@ -216,12 +442,100 @@ func (fl *FileLogger) WriteSummary(stats *ScanStatistics) error {
 	case FormatText:
 		summary = fmt.Sprintf("\n=== Scan Summary ===\n")
 		summary += fmt.Sprintf("Timestamp: %s\n", timestamp)
+		if stats.RunID != "" {
+			summary += fmt.Sprintf("Run ID: %s (started %s)\n", stats.RunID, stats.RunStarted.Format(time.RFC3339))
+		}
+		if stats.OrgSummary {
+			summary += fmt.Sprintf("Compliance Score: %.1f%%\n", stats.ComplianceScore())
+			summary += fmt.Sprintf("  Supported: %d\n", stats.SupportedProjects)
+			summary += fmt.Sprintf("  End of Life: %d\n", stats.EOLProjects)
+			for _, version := range stats.SortedEOLVersions() {
+				summary += fmt.Sprintf("    %s: %d\n", version, stats.EOLVersionCounts[version])
+			}
+			summary += fmt.Sprintf("  Unknown Support: %d\n", stats.UnknownEOLProjects)
+		}
 		summary += fmt.Sprintf("Total Projects: %d\n", stats.TotalProjects)
 		summary += fmt.Sprintf("Python Projects: %d\n", stats.PythonProjects)
 		summary += fmt.Sprintf("Non-Python Projects: %d\n", stats.NonPythonProjects)
+		if stats.PythonNoVersionProjects > 0 || stats.NoPythonFilesProjects > 0 {
+			summary += fmt.Sprintf("  Python, version unknown: %d\n", stats.PythonNoVersionProjects)
+			summary += fmt.Sprintf("  No Python files: %d\n", stats.NoPythonFilesProjects)
+		}
 		if stats.ErrorCount > 0 {
 			summary += fmt.Sprintf("Errors: %d\n", stats.ErrorCount)
 		}
+		if stats.MismatchProjects > 0 {
+			summary += fmt.Sprintf("Version Mismatches: %d\n", stats.MismatchProjects)
+		}
+		if stats.TimedOutProjects > 0 {
+			summary += fmt.Sprintf("Timed Out: %d\n", stats.TimedOutProjects)
+		}
+		if stats.CandidateLimitedProjects > 0 {
+			summary += fmt.Sprintf("Stopped at Candidate Limit: %d\n", stats.CandidateLimitedProjects)
+		}
+		if stats.ParseErrorCount > 0 {
+			summary += fmt.Sprintf("Parse Errors: %d\n", stats.ParseErrorCount)
+		}
+		if stats.CachedProjects > 0 {
+			summary += fmt.Sprintf("Reused From Previous Scan: %d\n", stats.CachedProjects)
+		}
+		if stats.Python2Projects > 0 {
+			summary += fmt.Sprintf("Python 2 Projects: %d\n", stats.Python2Projects)
+			for _, path := range stats.Python2Paths {
+				summary += fmt.Sprintf("  %s\n", path)
+			}
+		}
+		if stats.CIDriftProjects > 0 {
+			summary += fmt.Sprintf("CI/Declaration Drift: %d\n", stats.CIDriftProjects)
+			for _, path := range stats.CIDriftPaths {
+				summary += fmt.Sprintf("  %s\n", path)
+			}
+		}
+		if stats.RequireExplicit {
+			summary += fmt.Sprintf("Missing Explicit Version File: %d\n", stats.MissingExplicitProjects)
+			for _, path := range stats.MissingExplicitPaths {
+				summary += fmt.Sprintf("  %s\n", path)
+			}
+		}
+		if stats.TargetVersion != "" {
+			summary += fmt.Sprintf("Capped Below %s: %d\n", stats.TargetVersion, stats.CappedProjects)
+		}
+		if stats.AtRiskBelow != "" {
+			summary += fmt.Sprintf("At Risk (Below %s): %d\n", stats.AtRiskBelow, stats.AtRiskProjects)
+			for _, path := range stats.AtRiskPaths {
+				summary += fmt.Sprintf("    %s\n", path)
+			}
+			summary += fmt.Sprintf("Current (%s or Later): %d\n", stats.AtRiskBelow, stats.CurrentProjects)
+		}
+		if stats.ListingError != "" {
+			summary += fmt.Sprintf("Incomplete Listing: %s\n", stats.ListingError)
+		}
+		if len(stats.Warnings) > 0 {
+			summary += fmt.Sprintf("Warnings: %d\n", len(stats.Warnings))
+			for _, warning := range stats.Warnings {
+				summary += fmt.Sprintf("  %s\n", warning)
+			}
+		}
+		if stats.Baseline != nil {
+			summary += fmt.Sprintf("Baseline Conformance: %.1f%%\n", stats.BaselineConformance())
+			summary += fmt.Sprintf("  Conforming: %d\n", stats.BaselineConforming)
+			summary += fmt.Sprintf("  Drifted: %d\n", stats.BaselineDrifted)
+			summary += fmt.Sprintf("  No Version: %d\n", stats.BaselineNoVersion)
+			summary += fmt.Sprintf("  Untracked: %d\n", stats.BaselineUntracked)
+			unscanned := stats.BaselineUnscanned()
+			summary += fmt.Sprintf("  Not Scanned: %d\n", len(unscanned))
+			for _, path := range unscanned {
+				summary += fmt.Sprintf("    %s\n", path)
+			}
+		}
+		if len(stats.ApprovedVersions) > 0 {
+			summary += fmt.Sprintf("Approved Versions: %s\n", strings.Join(stats.ApprovedVersions, ", "))
+			summary += fmt.Sprintf("  Approved: %d\n", stats.ApprovedProjects)
+			summary += fmt.Sprintf("  Non-Approved: %d\n", stats.NonApprovedProjects)
+		}
+		if breakdown := confidenceBreakdown(stats.ConfidenceBuckets); breakdown != "" {
+			summary += fmt.Sprintf("Confidence: %s\n", breakdown)
+		}
 		if len(stats.VersionCounts) > 0 {
 			summary += fmt.Sprintf("\nPython Version Distribution:\n")
 			for version, count := range stats.VersionCounts {
@@ -229,6 +543,9 @@ func (fl *FileLogger) WriteSummary(stats *ScanStatistics) error {
 			}
 		}
 		summary += fmt.Sprintf("====================\n")
+	case FormatCSV:
+		// Summaries don't fit the row layout; spreadsheets can aggregate the rows
+		return nil
 	default:
 		return fmt.Errorf("unknown log format: %s", fl.format)
 	}
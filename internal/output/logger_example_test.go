@@ -3,14 +3,29 @@ package output_test
 import (
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 
 	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
 )
 
+// exampleLogPath returns a path for name in a fresh temporary directory so
+// the examples don't leave log files in the source tree. The returned
+// function removes the directory.
+func exampleLogPath(name string) (string, func()) {
+	dir, err := os.MkdirTemp("", "logger-example")
+	if err != nil {
+		log.Fatal(err)
+	}
+	return filepath.Join(dir, name), func() { os.RemoveAll(dir) }
+}
+
 // ExampleFileLogger_text demonstrates basic usage of FileLogger with text format
 func ExampleFileLogger_text() {
 	// Create a text format logger
-	logger, err := output.NewFileLogger("scan_results.log", output.FormatText)
+	path, cleanup := exampleLogPath("scan_results.log")
+	defer cleanup()
+	logger, err := output.NewFileLogger(path, output.FormatText)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -58,7 +73,9 @@ func ExampleFileLogger_text() {
 // ExampleFileLogger_json demonstrates JSON format logging (JSONL/NDJSON)
 func ExampleFileLogger_json() {
 	// Create a JSON format logger
-	logger, err := output.NewFileLogger("scan_results.jsonl", output.FormatJSON)
+	path, cleanup := exampleLogPath("scan_results.jsonl")
+	defer cleanup()
+	logger, err := output.NewFileLogger(path, output.FormatJSON)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -97,7 +114,9 @@ func ExampleFileLogger_json() {
 
 // ExampleFileLogger_concurrent demonstrates concurrent logging
 func ExampleFileLogger_concurrent() {
-	logger, err := output.NewFileLogger("concurrent_scan.log", output.FormatText)
+	path, cleanup := exampleLogPath("concurrent_scan.log")
+	defer cleanup()
+	logger, err := output.NewFileLogger(path, output.FormatText)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -129,7 +148,9 @@ func ExampleFileLogger_concurrent() {
 func ExampleFileLogger_withConsole() {
 	// Create both console streamer and file logger
 	console := output.NewConsoleStreamer()
-	logger, err := output.NewFileLogger("combined_output.log", output.FormatText)
+	path, cleanup := exampleLogPath("combined_output.log")
+	defer cleanup()
+	logger, err := output.NewFileLogger(path, output.FormatText)
 	if err != nil {
 		log.Fatal(err)
 	}
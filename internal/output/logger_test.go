@@ -1,6 +1,7 @@
 package output
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"os"
@@ -8,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewFileLogger(t *testing.T) {
@@ -124,6 +126,41 @@ func TestFileLogger_LogResult_Text_Success(t *testing.T) {
 	}
 }
 
+func TestFileLogger_LogResult_Text_Warnings(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "test.log")
+
+	logger, err := NewFileLogger(logPath, FormatText)
+	if err != nil {
+		t.Fatalf("Failed to create file logger: %v", err)
+	}
+	defer logger.Close()
+
+	result := &ScanResult{
+		ProjectName:     "billing",
+		PythonVersion:   "3.11",
+		DetectionSource: "pyproject.toml",
+		ParseErrors:     []string{"setup.cfg: parser error in rule setup-cfg: bad section"},
+		Warnings:        []string{".gitlab-seeker-ignore: ignored unsupported pattern \"!keep\""},
+		Index:           1,
+		TotalProjects:   1,
+	}
+	if err := logger.LogResult(result); err != nil {
+		t.Fatalf("Failed to log result: %v", err)
+	}
+	logger.Close()
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	want := "Python 3.11 (from pyproject.toml)\n" +
+		"  parse error: setup.cfg: parser error in rule setup-cfg: bad section\n" +
+		"  warning: .gitlab-seeker-ignore: ignored unsupported pattern \"!keep\"\n"
+	if !strings.HasSuffix(string(content), want) {
+		t.Errorf("log = %q, want it to end with %q", content, want)
+	}
+}
+
 func TestFileLogger_LogResult_Text_NotDetected(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, "test.log")
@@ -559,6 +596,48 @@ func TestFileLogger_WriteSummary_JSON(t *testing.T) {
 	}
 }
 
+func TestFileLogger_WriteSummary_RunID(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "test.log")
+
+	logger, err := NewFileLogger(logPath, FormatJSON)
+	if err != nil {
+		t.Fatalf("Failed to create file logger: %v", err)
+	}
+
+	// Two watch runs append two summaries to the same log
+	started := time.Date(2024, 2, 6, 10, 30, 0, 0, time.UTC)
+	for _, runID := range []string{"20240206T103000Z-1", "20240206T104500Z-2"} {
+		stats := NewScanStatistics()
+		stats.RunID = runID
+		stats.RunStarted = started
+		if err := logger.WriteSummary(stats); err != nil {
+			t.Fatalf("Failed to write summary: %v", err)
+		}
+	}
+	logger.Close()
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 summary lines, got %d", len(lines))
+	}
+
+	var summary map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &summary); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if summary["run_id"] != "20240206T104500Z-2" {
+		t.Errorf("Expected run_id '20240206T104500Z-2', got '%v'", summary["run_id"])
+	}
+	if summary["run_started"] != "2024-02-06T10:30:00Z" {
+		t.Errorf("Expected run_started '2024-02-06T10:30:00Z', got '%v'", summary["run_started"])
+	}
+}
+
 func TestFileLogger_Close(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, "test.log")
@@ -683,3 +762,74 @@ func TestFileLogger_MultipleEntries_JSONL(t *testing.T) {
 		}
 	}
 }
+
+func TestFileLogger_LogResult_CSV(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "test.csv")
+
+	logger, err := NewFileLogger(logPath, FormatCSV)
+	if err != nil {
+		t.Fatalf("Failed to create file logger: %v", err)
+	}
+
+	if err := logger.WriteHeader("gitlab.com/myorg", 2); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := logger.LogResult(&ScanResult{
+		ProjectName:     "project, with comma",
+		PythonVersion:   "3.11",
+		DetectionSource: ".python-version",
+		Index:           1,
+		TotalProjects:   2,
+	}); err != nil {
+		t.Fatalf("Failed to log result: %v", err)
+	}
+	if err := logger.WriteSummary(NewScanStatistics()); err != nil {
+		t.Fatalf("Failed to write summary: %v", err)
+	}
+	logger.Close()
+
+	f, err := os.Open(logPath)
+	if err != nil {
+		t.Fatalf("Failed to open log file: %v", err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Expected header + 1 row, got %d records", len(records))
+	}
+	if records[0][0] != "timestamp" {
+		t.Errorf("Expected header row, got %v", records[0])
+	}
+	if records[1][3] != "project, with comma" {
+		t.Errorf("Expected project name column to survive quoting, got %q", records[1][3])
+	}
+	if records[1][5] != "3.11" {
+		t.Errorf("Expected python_version 3.11, got %q", records[1][5])
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want LogFormat
+	}{
+		{"results.json", FormatJSON},
+		{"results.jsonl", FormatJSON},
+		{"results.log", FormatJSON},
+		{"results.txt", FormatText},
+		{"results.CSV", FormatCSV},
+		{"results", FormatJSON},
+	}
+
+	for _, tt := range tests {
+		if got := FormatFromPath(tt.path); got != tt.want {
+			t.Errorf("FormatFromPath(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
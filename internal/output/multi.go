@@ -0,0 +1,90 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MultiLogger fans each result out to several FileLoggers so that a single
+// run can produce, for example, JSON for machines, text for humans, and CSV
+// for spreadsheets at the same time.
+type MultiLogger struct {
+	loggers []*FileLogger
+}
+
+// NewMultiLogger creates a multiplexing logger over the given file loggers
+func NewMultiLogger(loggers ...*FileLogger) *MultiLogger {
+	return &MultiLogger{
+		loggers: loggers,
+	}
+}
+
+// OpenMultiLogger creates one FileLogger per path, inferring each file's
+// format from its extension (see FormatFromPath).
+// If any file cannot be created, the files opened so far are closed.
+func OpenMultiLogger(paths []string) (*MultiLogger, error) {
+	ml := &MultiLogger{}
+
+	for _, path := range paths {
+		logger, err := NewFileLogger(path, FormatFromPath(path))
+		if err != nil {
+			ml.Close()
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		ml.loggers = append(ml.loggers, logger)
+	}
+
+	return ml, nil
+}
+
+// Len returns the number of underlying loggers
+func (ml *MultiLogger) Len() int {
+	return len(ml.loggers)
+}
+
+// LogResult writes a scan result to every logger.
+// All loggers are attempted even if one fails; errors are joined.
+func (ml *MultiLogger) LogResult(result *ScanResult) error {
+	return ml.each(func(fl *FileLogger) error {
+		return fl.LogResult(result)
+	})
+}
+
+// LogContentResult writes a content search result to every logger
+func (ml *MultiLogger) LogContentResult(result *ContentScanResult) error {
+	return ml.each(func(fl *FileLogger) error {
+		return fl.LogContentResult(result)
+	})
+}
+
+// WriteHeader writes the scan header to every logger
+func (ml *MultiLogger) WriteHeader(gitlabURL string, totalProjects int) error {
+	return ml.each(func(fl *FileLogger) error {
+		return fl.WriteHeader(gitlabURL, totalProjects)
+	})
+}
+
+// WriteSummary writes the scan summary to every logger
+func (ml *MultiLogger) WriteSummary(stats *ScanStatistics) error {
+	return ml.each(func(fl *FileLogger) error {
+		return fl.WriteSummary(stats)
+	})
+}
+
+// Close closes every logger
+func (ml *MultiLogger) Close() error {
+	return ml.each(func(fl *FileLogger) error {
+		return fl.Close()
+	})
+}
+
+// each applies fn to every logger and joins any errors
+func (ml *MultiLogger) each(fn func(fl *FileLogger) error) error {
+	var errs []error
+	for _, fl := range ml.loggers {
+		if err := fn(fl); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenMultiLogger(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := []string{
+		filepath.Join(tmpDir, "results.json"),
+		filepath.Join(tmpDir, "results.txt"),
+		filepath.Join(tmpDir, "results.csv"),
+	}
+
+	ml, err := OpenMultiLogger(paths)
+	if err != nil {
+		t.Fatalf("Failed to open multi logger: %v", err)
+	}
+
+	if ml.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", ml.Len())
+	}
+
+	if err := ml.WriteHeader("gitlab.com/myorg", 1); err != nil {
+		t.Fatalf("WriteHeader failed: %v", err)
+	}
+	if err := ml.LogResult(&ScanResult{
+		ProjectName:     "my-project",
+		PythonVersion:   "3.12",
+		DetectionSource: "pyproject.toml",
+		Index:           1,
+		TotalProjects:   1,
+	}); err != nil {
+		t.Fatalf("LogResult failed: %v", err)
+	}
+	if err := ml.WriteSummary(NewScanStatistics()); err != nil {
+		t.Fatalf("WriteSummary failed: %v", err)
+	}
+	if err := ml.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	wantContent := map[string]string{
+		paths[0]: `"python_version":"3.12"`,
+		paths[1]: "my-project: Python 3.12 (from pyproject.toml)",
+		paths[2]: "my-project,,3.12,pyproject.toml",
+	}
+	for path, want := range wantContent {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if !strings.Contains(string(content), want) {
+			t.Errorf("%s: expected to contain %q, got:\n%s", filepath.Base(path), want, content)
+		}
+	}
+}
+
+func TestOpenMultiLogger_InvalidPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := []string{
+		filepath.Join(tmpDir, "ok.json"),
+		"/invalid/path/that/does/not/exist/test.log",
+	}
+
+	if _, err := OpenMultiLogger(paths); err == nil {
+		t.Error("Expected error for invalid path")
+	}
+}
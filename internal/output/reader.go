@@ -0,0 +1,252 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ScanLog is a scan reconstructed from a JSON log written by FileLogger
+type ScanLog struct {
+	GitLabURL string
+	Results   []*ScanResult
+
+	// Summary carries the run details the results alone can't provide
+	// (run ID, approved versions, listing errors); its counts are
+	// recomputed from Results by Statistics
+	Summary ScanLogSummary
+}
+
+// ScanLogSummary is the subset of a "scan_completed" entry that is read back
+type ScanLogSummary struct {
+	RunID            string   `json:"run_id"`
+	RunStarted       string   `json:"run_started"`
+	ListingError     string   `json:"listing_error"`
+	Warnings         []string `json:"warnings"`
+	ApprovedVersions []string `json:"approved_versions"`
+	TargetVersion    string   `json:"target_version"`
+	AtRiskBelow      string   `json:"at_risk_below"`
+	RequireExplicit  bool     `json:"require_explicit"`
+	OrgSummary       bool     `json:"org_summary"`
+	EOLAsOf          string   `json:"eol_as_of"`
+
+	// Counts as the scan wrote them; Statistics recomputes its own
+	Timestamp         string         `json:"timestamp"`
+	TotalProjects     int            `json:"total_projects"`
+	PythonProjects    int            `json:"python_projects"`
+	NonPythonProjects int            `json:"non_python_projects"`
+	ErrorCount        int            `json:"error_count"`
+	VersionCounts     map[string]int `json:"version_counts"`
+}
+
+// LogHeader is the "scan_started" entry written before a run's results
+type LogHeader struct {
+	Timestamp     string `json:"timestamp"`
+	GitLabURL     string `json:"gitlab_url"`
+	TotalProjects int    `json:"total_projects"`
+}
+
+// logRecord holds the fields shared by every kind of JSON log line
+type logRecord struct {
+	Type       string `json:"type"`
+	SearchTerm string `json:"search_term"` // Only set in content search logs
+}
+
+// ReadScanLogFile reads a JSON scan log from path (see ReadScanLog)
+func ReadScanLogFile(path string) (*ScanLog, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open scan log: %w", err)
+	}
+	defer file.Close()
+
+	return ReadScanLog(file)
+}
+
+// ReadScanLog reconstructs a scan from a JSON log, either JSONL as written
+// by FileLogger or a JSON array of the same objects. When several runs were
+// appended to one log (e.g. by --watch), only the last run is returned.
+func ReadScanLog(r io.Reader) (*ScanLog, error) {
+	entries, header, summary, err := ReadLog(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("scan log contains no results")
+	}
+
+	log := &ScanLog{}
+	if header != nil {
+		log.GitLabURL = header.GitLabURL
+	}
+	if summary != nil {
+		log.Summary = *summary
+	}
+	for i := range entries {
+		log.Results = append(log.Results, entries[i].scanResult())
+	}
+	return log, nil
+}
+
+// ReadLog parses a JSON scan log, either JSONL as written by FileLogger or a
+// JSON array of the same objects, into its result entries and the header and
+// summary around them. Like ReadScanLog it returns only the last run of a
+// log several runs were appended to. header and summary are nil if the run
+// has none, e.g. a log from a scan that was interrupted before its summary.
+func ReadLog(r io.Reader) (entries []LogEntry, header *LogHeader, summary *ScanLogSummary, err error) {
+	reader := bufio.NewReader(r)
+	first, err := firstNonSpace(reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var raw []json.RawMessage
+	if first == '[' {
+		if err := json.NewDecoder(reader).Decode(&raw); err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid JSON array in scan log: %w", err)
+		}
+	} else {
+		dec := json.NewDecoder(reader)
+		for {
+			var msg json.RawMessage
+			if err := dec.Decode(&msg); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, nil, nil, fmt.Errorf("invalid JSON in scan log entry %d: %w", len(raw)+1, err)
+			}
+			raw = append(raw, msg)
+		}
+	}
+
+	for i, msg := range raw {
+		var record logRecord
+		if err := json.Unmarshal(msg, &record); err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid scan log entry %d: %w", i+1, err)
+		}
+
+		if record.SearchTerm != "" {
+			return nil, nil, nil, fmt.Errorf("entry %d is a content search match; only Python version scan logs can be read", i+1)
+		}
+
+		switch record.Type {
+		case "scan_started":
+			// A new run starts over
+			header = &LogHeader{}
+			if err := json.Unmarshal(msg, header); err != nil {
+				return nil, nil, nil, fmt.Errorf("invalid scan header at entry %d: %w", i+1, err)
+			}
+			entries, summary = nil, nil
+		case "scan_completed":
+			summary = &ScanLogSummary{}
+			if err := json.Unmarshal(msg, summary); err != nil {
+				return nil, nil, nil, fmt.Errorf("invalid scan summary at entry %d: %w", i+1, err)
+			}
+		case "":
+			var entry LogEntry
+			if err := json.Unmarshal(msg, &entry); err != nil {
+				return nil, nil, nil, fmt.Errorf("invalid scan result at entry %d: %w", i+1, err)
+			}
+			entries = append(entries, entry)
+		default:
+			return nil, nil, nil, fmt.Errorf("unexpected %q entry at %d", record.Type, i+1)
+		}
+	}
+
+	return entries, header, summary, nil
+}
+
+// Statistics recomputes the scan statistics from the log's results, using
+// normalize for the version distribution
+func (l *ScanLog) Statistics(normalize Normalization) *ScanStatistics {
+	stats := NewScanStatistics()
+	stats.Normalize = normalize
+	stats.ApprovedVersions = l.Summary.ApprovedVersions
+	stats.TargetVersion = l.Summary.TargetVersion
+	stats.AtRiskBelow = l.Summary.AtRiskBelow
+	stats.RequireExplicit = l.Summary.RequireExplicit
+	stats.OrgSummary = l.Summary.OrgSummary
+	if asOf, err := time.Parse(time.RFC3339, l.Summary.EOLAsOf); err == nil {
+		// Judge support as of the original scan so the score is reproducible
+		stats.EOLAsOf = asOf
+	}
+	stats.ListingError = l.Summary.ListingError
+	stats.Warnings = l.Summary.Warnings
+	stats.RunID = l.Summary.RunID
+	if started, err := time.Parse(time.RFC3339, l.Summary.RunStarted); err == nil {
+		stats.RunStarted = started
+	}
+
+	for _, result := range l.Results {
+		stats.RecordResult(result)
+	}
+	return stats
+}
+
+// scanResult converts a logged entry back into the result it was written from
+func (e *LogEntry) scanResult() *ScanResult {
+	result := &ScanResult{
+		ProjectName:     e.ProjectName,
+		ProjectPath:     e.ProjectPath,
+		PythonVersion:   e.PythonVersion,
+		DetectionSource: e.DetectionSource,
+		Index:           e.Index,
+		TotalProjects:   e.TotalProjects,
+		CrossChecks:     e.CrossChecks,
+		VersionMismatch: e.VersionMismatch,
+		LastCommitID:    e.LastCommitID,
+		SourceSize:      e.SourceSize,
+		Classification:  e.Classification,
+		TimedOut:        e.TimedOut,
+		Diagnostics:     e.Diagnostics,
+		VersionMax:      e.VersionMax,
+		RawValue:        e.RawValue,
+		Confidence:      e.Confidence,
+
+		CandidatesLimited: e.CandidatesLimited,
+		// Derived rather than read so logs written before the field existed still classify
+		IsPython2: IsPython2(e.PythonVersion),
+
+		ExpectedVersion: e.ExpectedVersion,
+		Drift:           e.Drift,
+		RawConfidence:   e.RawConfidence,
+		ParseErrors:     e.ParseErrors,
+		Warnings:        e.Warnings,
+		ExplicitSource:  e.ExplicitSource,
+		Namespace:       e.Namespace,
+		TopLevelGroup:   e.TopLevelGroup,
+		LastActivityAt:  e.LastActivityAt,
+		Cached:          e.Cached,
+		Explanation:     e.Explanation,
+		CIDrift:         e.CIDrift,
+		Instance:        e.Instance,
+		CommitSHA:       e.CommitSHA,
+	}
+	if e.SourceUpdated != nil {
+		result.SourceUpdated = *e.SourceUpdated
+	}
+	if e.Error != "" {
+		result.Error = errors.New(e.Error)
+	}
+	return result
+}
+
+// firstNonSpace peeks at the first non-whitespace byte without consuming it
+func firstNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			return 0, fmt.Errorf("scan log is empty")
+		}
+		if err != nil {
+			return 0, err
+		}
+		if !strings.ContainsRune(" \t\r\n", rune(b)) {
+			return b, r.UnreadByte()
+		}
+	}
+}
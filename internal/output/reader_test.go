@@ -0,0 +1,172 @@
+package output
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadScanLogRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.json")
+	logger, err := NewFileLogger(path, FormatJSON)
+	if err != nil {
+		t.Fatalf("NewFileLogger() error = %v", err)
+	}
+
+	stats := NewScanStatistics()
+	stats.ApprovedVersions = []string{"3.11"}
+	stats.OrgSummary = true
+	stats.EOLAsOf = time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	results := []*ScanResult{
+		{ProjectName: "api", ProjectPath: "group/team/api", Namespace: "group/team", TopLevelGroup: "group", PythonVersion: "3.11", DetectionSource: ".python-version", Index: 1, TotalProjects: 3, Confidence: 1.0},
+		{ProjectName: "web", ProjectPath: "group/web", PythonVersion: "3.8", DetectionSource: "pyproject.toml", Index: 2, TotalProjects: 3, VersionMax: "<3.9",
+			Explanation: &Explanation{Steps: []ExplainStep{{Rule: "pyproject-toml", Priority: 10, File: "pyproject.toml", Outcome: ExplainSelected, Version: "3.8"}}, Decision: "first match"}},
+		{ProjectName: "docs", ProjectPath: "group/docs", Index: 3, TotalProjects: 3, Error: errors.New("404 Not Found")},
+	}
+	logger.WriteHeader("https://gitlab.com/group", len(results))
+	for _, r := range results {
+		stats.RecordResult(r)
+		logger.WriteResult(r)
+	}
+	stats.RecordWarning("failed to write result for group/web: disk full")
+	logger.WriteSummary(stats)
+	logger.Close()
+
+	scanLog, err := ReadScanLogFile(path)
+	if err != nil {
+		t.Fatalf("ReadScanLogFile() error = %v", err)
+	}
+	if scanLog.GitLabURL != "https://gitlab.com/group" {
+		t.Errorf("GitLabURL = %q", scanLog.GitLabURL)
+	}
+	if len(scanLog.Results) != 3 {
+		t.Fatalf("got %d results, want 3", len(scanLog.Results))
+	}
+	if got := scanLog.Results[0]; got.Namespace != "group/team" || got.TopLevelGroup != "group" {
+		t.Errorf("result[0] namespace = %q, top-level group = %q", got.Namespace, got.TopLevelGroup)
+	}
+	if got := scanLog.Results[1]; got.PythonVersion != "3.8" || got.VersionMax != "<3.9" {
+		t.Errorf("result[1] = %+v", got)
+	}
+	if got := scanLog.Results[1].Explanation; got == nil || len(got.Steps) != 1 || got.Steps[0].Outcome != ExplainSelected || got.Decision != "first match" {
+		t.Errorf("result[1] explanation = %+v, want the logged trace", got)
+	}
+	if got := scanLog.Results[2]; got.Error == nil || got.Error.Error() != "404 Not Found" {
+		t.Errorf("result[2] error = %v, want 404 Not Found", got.Error)
+	}
+
+	rebuilt := scanLog.Statistics(NormalizeNone)
+	if rebuilt.TotalProjects != stats.TotalProjects || rebuilt.PythonProjects != stats.PythonProjects || rebuilt.ErrorCount != stats.ErrorCount {
+		t.Errorf("rebuilt stats = %d/%d/%d, want %d/%d/%d",
+			rebuilt.TotalProjects, rebuilt.PythonProjects, rebuilt.ErrorCount,
+			stats.TotalProjects, stats.PythonProjects, stats.ErrorCount)
+	}
+	if len(rebuilt.ApprovedVersions) != 1 || rebuilt.ApprovedVersions[0] != "3.11" {
+		t.Errorf("ApprovedVersions = %v, want [3.11]", rebuilt.ApprovedVersions)
+	}
+	if !rebuilt.OrgSummary || !rebuilt.EOLAsOf.Equal(stats.EOLAsOf) {
+		t.Errorf("OrgSummary = %v as of %v, want true as of %v", rebuilt.OrgSummary, rebuilt.EOLAsOf, stats.EOLAsOf)
+	}
+	if len(rebuilt.Warnings) != 1 || rebuilt.Warnings[0] != stats.Warnings[0] {
+		t.Errorf("Warnings = %v, want %v", rebuilt.Warnings, stats.Warnings)
+	}
+	if rebuilt.ComplianceScore() != 50 {
+		t.Errorf("ComplianceScore() = %v, want 50", rebuilt.ComplianceScore())
+	}
+}
+
+func TestReadScanLogJSONArray(t *testing.T) {
+	input := `
+[
+  {"type": "scan_started", "gitlab_url": "gitlab.com/org", "total_projects": 2},
+  {"project_name": "a", "project_path": "org/a", "python_version": "3.12"},
+  {"project_name": "b", "project_path": "org/b"}
+]`
+	scanLog, err := ReadScanLog(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadScanLog() error = %v", err)
+	}
+	if scanLog.GitLabURL != "gitlab.com/org" || len(scanLog.Results) != 2 {
+		t.Errorf("got url %q with %d results", scanLog.GitLabURL, len(scanLog.Results))
+	}
+}
+
+func TestReadScanLogKeepsLastRun(t *testing.T) {
+	input := `{"type": "scan_started", "gitlab_url": "gitlab.com/org"}
+{"project_name": "a", "python_version": "3.10"}
+{"type": "scan_completed", "run_id": "run-1"}
+{"type": "scan_started", "gitlab_url": "gitlab.com/org"}
+{"project_name": "a", "python_version": "3.12"}
+{"type": "scan_completed", "run_id": "run-2"}
+`
+	scanLog, err := ReadScanLog(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadScanLog() error = %v", err)
+	}
+	if len(scanLog.Results) != 1 || scanLog.Results[0].PythonVersion != "3.12" {
+		t.Errorf("results = %+v, want only the second run", scanLog.Results)
+	}
+	if scanLog.Summary.RunID != "run-2" {
+		t.Errorf("RunID = %q, want run-2", scanLog.Summary.RunID)
+	}
+}
+
+func TestReadLog(t *testing.T) {
+	input := `{"type": "scan_started", "gitlab_url": "gitlab.com/org", "total_projects": 9}
+{"project_name": "old"}
+{"type": "scan_started", "gitlab_url": "gitlab.com/org", "total_projects": 2}
+{"project_name": "a", "python_version": "3.12", "confidence": 1, "cross_checks": [{"Source": "pyproject.toml", "Version": "3.12"}]}
+{"project_name": "b", "error": "404 Not Found"}
+{"type": "scan_completed", "total_projects": 2, "python_projects": 1, "error_count": 1, "version_counts": {"3.12": 1}}
+`
+	entries, header, summary, err := ReadLog(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadLog() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].ProjectName != "a" || entries[1].Error != "404 Not Found" {
+		t.Fatalf("entries = %+v, want a and b from the last run", entries)
+	}
+	if entries[0].Confidence != 1 || len(entries[0].CrossChecks) != 1 {
+		t.Errorf("entries[0] = %+v, want typed confidence and cross-checks", entries[0])
+	}
+	if header == nil || header.GitLabURL != "gitlab.com/org" || header.TotalProjects != 2 {
+		t.Errorf("header = %+v, want the last run's", header)
+	}
+	if summary == nil || summary.TotalProjects != 2 || summary.ErrorCount != 1 || summary.VersionCounts["3.12"] != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+
+	// A bare list of results, as left by an interrupted scan with no
+	// header, has neither envelope
+	entries, header, summary, err = ReadLog(strings.NewReader(`[{"project_name": "a"}]`))
+	if err != nil || len(entries) != 1 || header != nil || summary != nil {
+		t.Errorf("ReadLog() = %d entries, header %v, summary %v, error %v; want 1 entry only", len(entries), header, summary, err)
+	}
+}
+
+func TestReadScanLogErrors(t *testing.T) {
+	tests := map[string]string{
+		"empty":          "  \n",
+		"no results":     `{"type": "scan_started", "gitlab_url": "gitlab.com/org"}`,
+		"content log":    `{"project_name": "a", "search_term": "API_KEY", "file_path": ".env"}`,
+		"unknown type":   `{"type": "something_else"}`,
+		"malformed line": "{\"project_name\": \"a\"}\n{not json}\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadScanLog(strings.NewReader(input)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestReadScanLogFileMissing(t *testing.T) {
+	_, err := ReadScanLogFile(filepath.Join(t.TempDir(), "missing.json"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("error = %v, want not-exist", err)
+	}
+}
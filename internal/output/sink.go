@@ -0,0 +1,140 @@
+package output
+
+// ResultSink is the common contract for anything that receives Python version
+// scan output: the console, log files, multiplexed loggers, and future outputs.
+// Implementations must be safe for concurrent WriteResult calls.
+type ResultSink interface {
+	// WriteHeader is called once before any results, with the project count
+	WriteHeader(gitlabURL string, totalProjects int) error
+
+	// WriteResult is called once per scanned project as results complete
+	WriteResult(result *ScanResult) error
+
+	// WriteSummary is called once after all results have been written
+	WriteSummary(stats *ScanStatistics) error
+}
+
+// ContentResultSink is the common contract for content search output
+// Implementations must be safe for concurrent WriteContentResult calls.
+type ContentResultSink interface {
+	// WriteContentHeader is called once before any content search results
+	WriteContentHeader(gitlabURL string, totalProjects int, searchTerm string) error
+
+	// WriteContentResult is called once per searched project
+	WriteContentResult(result *ContentScanResult) error
+
+	// WriteContentSummary is called once after all results have been written
+	WriteContentSummary(stats *ContentScanStatistics) error
+}
+
+// Compile-time checks that the built-in outputs satisfy the sink contracts
+var (
+	_ ResultSink        = (*ConsoleStreamer)(nil)
+	_ ResultSink        = (*FileLogger)(nil)
+	_ ResultSink        = (*MultiLogger)(nil)
+	_ ResultSink        = (*SQLiteSink)(nil)
+	_ ContentResultSink = (*ConsoleStreamer)(nil)
+	_ ContentResultSink = (*FileLogger)(nil)
+	_ ContentResultSink = (*MultiLogger)(nil)
+)
+
+// ============================================================================
+// ConsoleStreamer
+// ============================================================================
+
+// WriteHeader implements ResultSink by delegating to PrintHeader
+func (cs *ConsoleStreamer) WriteHeader(gitlabURL string, totalProjects int) error {
+	return cs.PrintHeader(gitlabURL, totalProjects)
+}
+
+// WriteResult implements ResultSink by delegating to StreamResult
+func (cs *ConsoleStreamer) WriteResult(result *ScanResult) error {
+	return cs.StreamResult(result)
+}
+
+// WriteSummary implements ResultSink by delegating to PrintSummary
+func (cs *ConsoleStreamer) WriteSummary(stats *ScanStatistics) error {
+	return cs.PrintSummary(stats)
+}
+
+// WriteContentHeader implements ContentResultSink by delegating to PrintContentHeader
+func (cs *ConsoleStreamer) WriteContentHeader(gitlabURL string, totalProjects int, searchTerm string) error {
+	return cs.PrintContentHeader(gitlabURL, totalProjects, searchTerm)
+}
+
+// WriteContentResult implements ContentResultSink by delegating to StreamContentResult
+func (cs *ConsoleStreamer) WriteContentResult(result *ContentScanResult) error {
+	return cs.StreamContentResult(result)
+}
+
+// WriteContentSummary implements ContentResultSink by delegating to PrintContentSummary
+func (cs *ConsoleStreamer) WriteContentSummary(stats *ContentScanStatistics) error {
+	return cs.PrintContentSummary(stats)
+}
+
+// ============================================================================
+// FileLogger
+// ============================================================================
+
+// WriteResult implements ResultSink by delegating to LogResult
+func (fl *FileLogger) WriteResult(result *ScanResult) error {
+	return fl.LogResult(result)
+}
+
+// WriteContentHeader implements ContentResultSink.
+// Content search logs are a plain stream of results, so only CSV logs get a
+// header: their column row, written once however many searches share the log.
+func (fl *FileLogger) WriteContentHeader(gitlabURL string, totalProjects int, searchTerm string) error {
+	if fl.format != FormatCSV {
+		return nil
+	}
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+	if fl.wroteContentHeader {
+		return nil
+	}
+	if err := fl.writeCSVRecord(contentCSVColumns); err != nil {
+		return err
+	}
+	fl.wroteContentHeader = true
+	return nil
+}
+
+// WriteContentResult implements ContentResultSink by delegating to LogContentResult
+func (fl *FileLogger) WriteContentResult(result *ContentScanResult) error {
+	return fl.LogContentResult(result)
+}
+
+// WriteContentSummary implements ContentResultSink.
+// Content search logs are a plain stream of results, so no summary is written.
+func (fl *FileLogger) WriteContentSummary(stats *ContentScanStatistics) error {
+	return nil
+}
+
+// ============================================================================
+// MultiLogger
+// ============================================================================
+
+// WriteResult implements ResultSink by delegating to LogResult
+func (ml *MultiLogger) WriteResult(result *ScanResult) error {
+	return ml.LogResult(result)
+}
+
+// WriteContentHeader implements ContentResultSink
+func (ml *MultiLogger) WriteContentHeader(gitlabURL string, totalProjects int, searchTerm string) error {
+	return ml.each(func(fl *FileLogger) error {
+		return fl.WriteContentHeader(gitlabURL, totalProjects, searchTerm)
+	})
+}
+
+// WriteContentResult implements ContentResultSink by delegating to LogContentResult
+func (ml *MultiLogger) WriteContentResult(result *ContentScanResult) error {
+	return ml.LogContentResult(result)
+}
+
+// WriteContentSummary implements ContentResultSink
+func (ml *MultiLogger) WriteContentSummary(stats *ContentScanStatistics) error {
+	return ml.each(func(fl *FileLogger) error {
+		return fl.WriteContentSummary(stats)
+	})
+}
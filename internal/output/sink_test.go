@@ -0,0 +1,217 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResultSinks(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logPath := filepath.Join(t.TempDir(), "scan.txt")
+
+	logger, err := NewFileLogger(logPath, FormatText)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sinks := []ResultSink{NewConsoleStreamerWithWriter(buf), logger}
+	result := &ScanResult{
+		ProjectName:     "my-project",
+		PythonVersion:   "3.11",
+		DetectionSource: "pyproject.toml",
+		Index:           1,
+		TotalProjects:   1,
+	}
+	stats := NewScanStatistics()
+	stats.RecordResult(result)
+
+	for _, sink := range sinks {
+		if err := sink.WriteHeader("gitlab.com/myorg", 1); err != nil {
+			t.Fatalf("WriteHeader failed: %v", err)
+		}
+		if err := sink.WriteResult(result); err != nil {
+			t.Fatalf("WriteResult failed: %v", err)
+		}
+		if err := sink.WriteSummary(stats); err != nil {
+			t.Fatalf("WriteSummary failed: %v", err)
+		}
+	}
+	logger.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+
+	for name, out := range map[string]string{"console": buf.String(), "log": string(data)} {
+		if !strings.Contains(out, "my-project") || !strings.Contains(out, "3.11") {
+			t.Errorf("%s output missing result, got:\n%s", name, out)
+		}
+	}
+}
+
+func TestFileLogger_ContentSinkNoHeader(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "content.json")
+
+	logger, err := NewFileLogger(logPath, FormatJSON)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	var sink ContentResultSink = logger
+	if err := sink.WriteContentHeader("gitlab.com/myorg", 1, "python"); err != nil {
+		t.Fatalf("WriteContentHeader failed: %v", err)
+	}
+	if err := sink.WriteContentSummary(NewContentScanStatistics()); err != nil {
+		t.Fatalf("WriteContentSummary failed: %v", err)
+	}
+	logger.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	if len(data) != 0 {
+		t.Errorf("expected empty content log, got %q", data)
+	}
+}
+
+func TestFileLogger_ContentResultJSONPerMatch(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "content.json")
+
+	logger, err := NewFileLogger(logPath, FormatJSON)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	results := []*ContentScanResult{
+		{
+			ProjectName:   "api",
+			ProjectPath:   "group/api",
+			SearchName:    "hardcoded-keys",
+			Severity:      "high",
+			SearchTerm:    "API_KEY",
+			Index:         1,
+			TotalProjects: 2,
+			Matches: []ContentMatchEntry{
+				{FilePath: "settings.py", LineNumber: 3, LineContent: "API_KEY = 'x'", MatchedText: "API_KEY", Ref: "main",
+					ContextBefore: []string{"import os"}, ContextAfter: []string{"DEBUG = True"}},
+				{FilePath: "deploy.py", LineNumber: 9, LineContent: "os.environ['API_KEY']", MatchedText: "API_KEY", Ref: "main"},
+			},
+		},
+		{ProjectName: "web", SearchTerm: "API_KEY", Index: 2, TotalProjects: 2},
+	}
+	for _, result := range results {
+		if err := logger.LogContentResult(result); err != nil {
+			t.Fatalf("LogContentResult failed: %v", err)
+		}
+	}
+	logger.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+
+	var entries []ContentLogEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var entry ContentLogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	// Two matches in one project are two entries; the project without matches is one
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	first := entries[0]
+	if first.FilePath != "settings.py" || first.LineNumber != 3 || first.MatchedText != "API_KEY" ||
+		first.SearchName != "hardcoded-keys" || first.Severity != "high" || first.Ref != "main" || first.MatchCount != 2 {
+		t.Errorf("unexpected first entry: %+v", first)
+	}
+	if len(first.ContextBefore) != 1 || len(first.ContextAfter) != 1 {
+		t.Errorf("expected context lines, got %+v", first)
+	}
+	if entries[1].FilePath != "deploy.py" || entries[1].ProjectPath != "group/api" {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+	if entries[2].ProjectName != "web" || entries[2].FilePath != "" || entries[2].MatchCount != 0 {
+		t.Errorf("unexpected no-match entry: %+v", entries[2])
+	}
+}
+
+func TestFileLogger_ContentResultCSV(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "content.csv")
+
+	logger, err := NewFileLogger(logPath, FormatCSV)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	// Two searches share the log, as in --mode both; the column row is written once
+	var sink ContentResultSink = logger
+	for _, term := range []string{"API_KEY", "SECRET"} {
+		if err := sink.WriteContentHeader("gitlab.com/myorg", 3, term); err != nil {
+			t.Fatalf("WriteContentHeader failed: %v", err)
+		}
+	}
+	results := []*ContentScanResult{
+		{
+			ProjectName: "api",
+			ProjectPath: "group/api",
+			SearchName:  "hardcoded-keys",
+			Severity:    "high",
+			Matches: []ContentMatchEntry{
+				{FilePath: "settings.py", LineNumber: 3, LineContent: "API_KEY = 'x'", MatchedText: "API_KEY = 'x'", Ref: "main"},
+				{FilePath: "deploy.py", LineNumber: 9, LineContent: "key, other", MatchedText: "key, other", Ref: "main"},
+			},
+		},
+		{ProjectName: "web", ProjectPath: "group/web"},
+		{ProjectName: "private", ProjectPath: "group/private", Error: errors.New("403 Forbidden")},
+	}
+	for _, result := range results {
+		if err := sink.WriteContentResult(result); err != nil {
+			t.Fatalf("WriteContentResult failed: %v", err)
+		}
+	}
+	if err := sink.WriteContentSummary(NewContentScanStatistics()); err != nil {
+		t.Fatalf("WriteContentSummary failed: %v", err)
+	}
+	logger.Close()
+
+	file, err := os.Open(logPath)
+	if err != nil {
+		t.Fatalf("Failed to open log: %v", err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+
+	// The column row, a row per match, and the error; no row for "web"
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %d: %q", len(rows), rows)
+	}
+	if strings.Join(rows[0], ",") != strings.Join(contentCSVColumns, ",") {
+		t.Errorf("header = %q, want %q", rows[0], contentCSVColumns)
+	}
+	if got := strings.Join(rows[1][1:], "|"); got != "api|group/api|hardcoded-keys|high|main|settings.py|3|API_KEY = 'x'|" {
+		t.Errorf("first match row = %q", got)
+	}
+	if rows[2][8] != "key, other" {
+		t.Errorf("matched text with a comma = %q, want it quoted intact", rows[2][8])
+	}
+	if rows[3][1] != "private" || rows[3][6] != "" || rows[3][9] != "403 Forbidden" {
+		t.Errorf("error row = %q", rows[3])
+	}
+}
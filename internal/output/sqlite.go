@@ -0,0 +1,262 @@
+package output
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure-Go SQLite driver, registered as "sqlite"
+)
+
+// sqliteSchemaVersion is bumped whenever the tables below change shape.
+// Opening a database written with a different version is an error rather
+// than a silent mix of layouts.
+const sqliteSchemaVersion = 2
+
+// sqliteBatchSize is the number of results inserted per transaction
+const sqliteBatchSize = 100
+
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS results (
+		id               INTEGER PRIMARY KEY AUTOINCREMENT,
+		scanned_at       TEXT NOT NULL,
+		run_id           TEXT,
+		gitlab_url       TEXT,
+		project_name     TEXT NOT NULL,
+		project_path     TEXT,
+		python_version   TEXT,
+		detection_source TEXT,
+		error            TEXT,
+		classification   TEXT,
+		version_mismatch INTEGER NOT NULL DEFAULT 0,
+		timed_out        INTEGER NOT NULL DEFAULT 0,
+		last_commit_id   TEXT,
+		source_size      INTEGER
+	)`,
+	`CREATE TABLE IF NOT EXISTS summary (
+		id                  INTEGER PRIMARY KEY AUTOINCREMENT,
+		completed_at        TEXT NOT NULL,
+		run_id              TEXT,
+		gitlab_url          TEXT,
+		total_projects      INTEGER NOT NULL,
+		python_projects     INTEGER NOT NULL,
+		non_python_projects INTEGER NOT NULL,
+		error_count         INTEGER NOT NULL,
+		mismatch_projects   INTEGER NOT NULL,
+		timed_out_projects  INTEGER NOT NULL,
+		version_counts      TEXT
+	)`,
+}
+
+const sqliteInsertResult = `INSERT INTO results (
+	scanned_at, run_id, gitlab_url, project_name, project_path, python_version,
+	detection_source, error, classification, version_mismatch, timed_out,
+	last_commit_id, source_size
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// SQLiteSink streams scan results into a SQLite database so large scans can
+// be queried with plain SQL, e.g.
+//
+//	SELECT python_version, COUNT(*) FROM results GROUP BY python_version;
+//
+// With --watch every run appends to the same tables; run_id tells the runs'
+// rows apart.
+//
+// Results are inserted in batched transactions; the pending batch is committed
+// by WriteSummary and Close. It is safe for concurrent use.
+type SQLiteSink struct {
+	db        *sql.DB
+	mu        sync.Mutex
+	tx        *sql.Tx
+	stmt      *sql.Stmt
+	pending   int
+	gitlabURL string
+	runID     string
+}
+
+// NewSQLiteSink opens (or creates) the database at path and prepares its
+// tables. Existing results are kept, so several scans can share one file.
+func NewSQLiteSink(path string) (*SQLiteSink, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// SQLite allows a single writer; one connection avoids "database is locked"
+	db.SetMaxOpenConns(1)
+
+	if err := initSQLiteSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteSink{db: db}, nil
+}
+
+// initSQLiteSchema creates the tables and checks the schema version
+func initSQLiteSchema(db *sql.DB) error {
+	for _, stmt := range sqliteSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to create sqlite schema: %w", err)
+		}
+	}
+
+	var version int
+	err := db.QueryRow(`SELECT version FROM schema_version LIMIT 1`).Scan(&version)
+	switch {
+	case err == sql.ErrNoRows:
+		if _, err := db.Exec(`INSERT INTO schema_version (version) VALUES (?)`, sqliteSchemaVersion); err != nil {
+			return fmt.Errorf("failed to record sqlite schema version: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to read sqlite schema version: %w", err)
+	case version != sqliteSchemaVersion:
+		return fmt.Errorf("sqlite schema version %d is not supported (expected %d)", version, sqliteSchemaVersion)
+	}
+
+	return nil
+}
+
+// WriteHeader records the GitLab URL stored alongside each result
+func (s *SQLiteSink) WriteHeader(gitlabURL string, totalProjects int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.gitlabURL = gitlabURL
+	return nil
+}
+
+// SetRunID records the run ID stored with the results that follow, so the
+// rows of one --watch run can be selected
+func (s *SQLiteSink) SetRunID(runID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.runID = runID
+}
+
+// WriteResult implements ResultSink by delegating to LogResult
+func (s *SQLiteSink) WriteResult(result *ScanResult) error {
+	return s.LogResult(result)
+}
+
+// LogResult inserts a scan result, committing every sqliteBatchSize rows
+func (s *SQLiteSink) LogResult(result *ScanResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tx == nil {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin sqlite transaction: %w", err)
+		}
+		stmt, err := tx.Prepare(sqliteInsertResult)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to prepare sqlite insert: %w", err)
+		}
+		s.tx = tx
+		s.stmt = stmt
+	}
+
+	var errMsg string
+	if result.Error != nil {
+		errMsg = result.Error.Error()
+	}
+
+	_, err := s.stmt.Exec(
+		time.Now().Format(time.RFC3339),
+		s.runID,
+		s.gitlabURL,
+		result.ProjectName,
+		result.ProjectPath,
+		result.PythonVersion,
+		result.DetectionSource,
+		errMsg,
+		result.Classification,
+		result.VersionMismatch,
+		result.TimedOut,
+		result.LastCommitID,
+		result.SourceSize,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert sqlite result: %w", err)
+	}
+
+	s.pending++
+	if s.pending >= sqliteBatchSize {
+		return s.commit()
+	}
+	return nil
+}
+
+// WriteSummary commits pending results and inserts a summary row
+func (s *SQLiteSink) WriteSummary(stats *ScanStatistics) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.commit(); err != nil {
+		return err
+	}
+
+	versionCounts, err := json.Marshal(stats.VersionCounts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal version counts: %w", err)
+	}
+
+	_, err = s.db.Exec(`INSERT INTO summary (
+		completed_at, run_id, gitlab_url, total_projects, python_projects, non_python_projects,
+		error_count, mismatch_projects, timed_out_projects, version_counts
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		time.Now().Format(time.RFC3339),
+		stats.RunID,
+		s.gitlabURL,
+		stats.TotalProjects,
+		stats.PythonProjects,
+		stats.NonPythonProjects,
+		stats.ErrorCount,
+		stats.MismatchProjects,
+		stats.TimedOutProjects,
+		string(versionCounts),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert sqlite summary: %w", err)
+	}
+
+	return nil
+}
+
+// Close commits any pending results and closes the database
+func (s *SQLiteSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commitErr := s.commit()
+	if err := s.db.Close(); err != nil {
+		return err
+	}
+	return commitErr
+}
+
+// commit commits the open batch, if any. The caller must hold s.mu.
+func (s *SQLiteSink) commit() error {
+	if s.tx == nil {
+		return nil
+	}
+
+	s.stmt.Close()
+	err := s.tx.Commit()
+	s.tx = nil
+	s.stmt = nil
+	s.pending = 0
+
+	if err != nil {
+		return fmt.Errorf("failed to commit sqlite results: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package output
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestSQLiteSink(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "scan.db")
+
+	sink, err := NewSQLiteSink(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create sqlite sink: %v", err)
+	}
+
+	if err := sink.WriteHeader("gitlab.com/myorg", 250); err != nil {
+		t.Fatalf("WriteHeader failed: %v", err)
+	}
+
+	// More than one batch, written concurrently
+	stats := NewScanStatistics()
+	var wg sync.WaitGroup
+	for i := 0; i < 250; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result := &ScanResult{
+				ProjectName:     fmt.Sprintf("project-%d", i),
+				PythonVersion:   "3.11",
+				DetectionSource: "pyproject.toml",
+				Index:           i + 1,
+				TotalProjects:   250,
+			}
+			if err := sink.WriteResult(result); err != nil {
+				t.Errorf("WriteResult failed: %v", err)
+			}
+			stats.RecordResult(result)
+		}(i)
+	}
+	wg.Wait()
+
+	if err := sink.WriteSummary(stats); err != nil {
+		t.Fatalf("WriteSummary failed: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM results WHERE python_version = '3.11' AND gitlab_url = 'gitlab.com/myorg'`).Scan(&count); err != nil {
+		t.Fatalf("Failed to query results: %v", err)
+	}
+	if count != 250 {
+		t.Errorf("expected 250 results, got %d", count)
+	}
+
+	var total, python int
+	var versionCounts string
+	if err := db.QueryRow(`SELECT total_projects, python_projects, version_counts FROM summary`).Scan(&total, &python, &versionCounts); err != nil {
+		t.Fatalf("Failed to query summary: %v", err)
+	}
+	if total != 250 || python != 250 {
+		t.Errorf("expected summary 250/250, got %d/%d", total, python)
+	}
+	if versionCounts != `{"3.11":250}` {
+		t.Errorf("unexpected version_counts %q", versionCounts)
+	}
+}
+
+func TestSQLiteSink_SchemaVersion(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "scan.db")
+
+	sink, err := NewSQLiteSink(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create sqlite sink: %v", err)
+	}
+	sink.Close()
+
+	// Reopening a database with the current schema is fine
+	sink, err = NewSQLiteSink(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen sqlite sink: %v", err)
+	}
+	if _, err := sink.db.Exec(`UPDATE schema_version SET version = 99`); err != nil {
+		t.Fatalf("Failed to bump schema version: %v", err)
+	}
+	sink.Close()
+
+	if _, err := NewSQLiteSink(dbPath); err == nil {
+		t.Error("expected error for unsupported schema version")
+	}
+}
+
+func TestSQLiteSink_RunID(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "scan.db")
+
+	sink, err := NewSQLiteSink(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create sqlite sink: %v", err)
+	}
+	defer sink.Close()
+
+	// Two --watch runs appending to the same tables
+	for _, run := range []struct {
+		id       string
+		projects int
+	}{{"run-1", 2}, {"run-2", 3}} {
+		sink.SetRunID(run.id)
+		stats := NewScanStatistics()
+		stats.RunID = run.id
+		for i := 0; i < run.projects; i++ {
+			result := &ScanResult{ProjectName: fmt.Sprintf("project-%d", i), PythonVersion: "3.11"}
+			if err := sink.WriteResult(result); err != nil {
+				t.Fatalf("WriteResult failed: %v", err)
+			}
+			stats.RecordResult(result)
+		}
+		if err := sink.WriteSummary(stats); err != nil {
+			t.Fatalf("WriteSummary failed: %v", err)
+		}
+	}
+
+	var count, total int
+	if err := sink.db.QueryRow(`SELECT COUNT(*) FROM results WHERE run_id = 'run-2'`).Scan(&count); err != nil {
+		t.Fatalf("Failed to query results: %v", err)
+	}
+	if err := sink.db.QueryRow(`SELECT total_projects FROM summary WHERE run_id = 'run-2'`).Scan(&total); err != nil {
+		t.Fatalf("Failed to query summary: %v", err)
+	}
+	if count != 3 || total != 3 {
+		t.Errorf("run-2 has %d results and a summary of %d projects, want 3 and 3", count, total)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package output
+
+import (
+	"os"
+	"strconv"
+)
+
+// TerminalWidth returns the terminal's width in columns from $COLUMNS, since
+// the console size isn't queried on this platform, or 0 if it isn't set
+func TerminalWidth(f *os.File) int {
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || width < 0 {
+		return 0
+	}
+	return width
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package output
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// TerminalWidth returns the width in columns of the terminal f writes to,
+// or 0 if f isn't a terminal or its size can't be read
+func TerminalWidth(f *os.File) int {
+	size, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0
+	}
+	return int(size.Col)
+}
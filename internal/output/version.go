@@ -0,0 +1,372 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Normalization controls how detected versions are bucketed in ScanStatistics.
+// The raw detected version is always preserved on each ScanResult.
+type Normalization string
+
+const (
+	NormalizeNone  Normalization = ""      // Count each detected version as-is
+	NormalizeMinor Normalization = "minor" // Bucket by major.minor, e.g. "3.11.5" -> "3.11"
+	NormalizeMajor Normalization = "major" // Bucket by major, e.g. "3.11.5" -> "3"
+)
+
+// ParseNormalization validates a --normalize value
+func ParseNormalization(value string) (Normalization, error) {
+	switch n := Normalization(value); n {
+	case NormalizeNone, NormalizeMinor, NormalizeMajor:
+		return n, nil
+	default:
+		return NormalizeNone, fmt.Errorf("invalid normalization %q (expected \"minor\" or \"major\")", value)
+	}
+}
+
+// NormalizeVersion truncates a version to the components kept by n.
+// Versions that are already shorter than the requested level are returned unchanged.
+func NormalizeVersion(version string, n Normalization) string {
+	keep := 0
+	switch n {
+	case NormalizeMinor:
+		keep = 2
+	case NormalizeMajor:
+		keep = 1
+	default:
+		return version
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) <= keep {
+		return version
+	}
+	return strings.Join(parts[:keep], ".")
+}
+
+// ParseVersion splits a dotted version such as "3.11.5" into its numeric
+// components. Every component must be a non-negative integer.
+func ParseVersion(version string) ([]int, error) {
+	if version == "" {
+		return nil, fmt.Errorf("empty version")
+	}
+
+	parts := strings.Split(version, ".")
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version %q", version)
+		}
+		nums[i] = n
+	}
+	return nums, nil
+}
+
+// VersionBounds are the limits a detected version must fall within to be
+// reported; anything outside them is assumed to be a parser false positive.
+// The zero value only requires the version to parse.
+type VersionBounds struct {
+	Majors   []int // Accepted major versions (empty = any)
+	MaxMinor int   // Largest accepted minor version (0 = no limit)
+}
+
+// DefaultVersionBounds accepts Python 2 and 3 with minor versions up to 30
+var DefaultVersionBounds = VersionBounds{Majors: []int{2, 3}, MaxMinor: 30}
+
+// CheckVersion returns an error describing why version is not a plausible
+// Python version, or nil if it is
+func (b VersionBounds) CheckVersion(version string) error {
+	nums, err := ParseVersion(version)
+	if err != nil {
+		return err
+	}
+
+	if len(b.Majors) > 0 {
+		accepted := false
+		for _, m := range b.Majors {
+			if nums[0] == m {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			return fmt.Errorf("major version %d of %q is not plausible", nums[0], version)
+		}
+	}
+
+	if b.MaxMinor > 0 && len(nums) > 1 && nums[1] > b.MaxMinor {
+		return fmt.Errorf("minor version %d of %q exceeds %d", nums[1], version, b.MaxMinor)
+	}
+
+	return nil
+}
+
+// CompareVersions orders two versions numerically, treating missing
+// components as 0, so "3.9" sorts before "3.10". Versions that don't parse
+// sort after those that do; remaining ties are broken by the strings
+// themselves so the order is stable.
+func CompareVersions(a, b string) int {
+	aNums, aErr := ParseVersion(a)
+	bNums, bErr := ParseVersion(b)
+	switch {
+	case aErr != nil && bErr == nil:
+		return 1
+	case aErr == nil && bErr != nil:
+		return -1
+	case aErr == nil && bErr == nil:
+		if c := compareVersionNums(aNums, bNums); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// compareVersionNums orders two parsed versions, treating missing
+// components as 0
+func compareVersionNums(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// ParseApprovedVersions splits a comma-separated --approved-versions value,
+// e.g. "3.11, 3.12", dropping empty entries
+func ParseApprovedVersions(value string) []string {
+	var versions []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// IsPython2 reports whether version has major version 2, e.g. "2.7.18"
+func IsPython2(version string) bool {
+	nums, err := ParseVersion(version)
+	return err == nil && nums[0] == 2
+}
+
+// IsApproved reports whether version falls within one of the approved
+// versions. Both sides are compared at major.minor, so "3.11.5" is approved
+// by "3.11".
+func IsApproved(version string, approved []string) bool {
+	normalized := NormalizeVersion(version, NormalizeMinor)
+	for _, a := range approved {
+		if NormalizeVersion(a, NormalizeMinor) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// ExcludesVersion reports whether an upper bound such as "<3.11" or "<=3.10"
+// (see ScanResult.VersionMax) rules out target. target is compared at the
+// bound's precision, so "<=3.10" allows "3.10.4" and "<3.11" excludes "3.11.2".
+// An empty or unparseable bound excludes nothing.
+func ExcludesVersion(versionMax, target string) bool {
+	inclusive := strings.HasPrefix(versionMax, "<=")
+	bound, err := ParseVersion(strings.TrimLeft(versionMax, "<="))
+	if err != nil {
+		return false
+	}
+	nums, err := ParseVersion(target)
+	if err != nil {
+		return false
+	}
+
+	for i, b := range bound {
+		var t int
+		if i < len(nums) {
+			t = nums[i]
+		}
+		if t != b {
+			return t > b
+		}
+	}
+	// Equal at the bound's precision
+	return !inclusive
+}
+
+// BelowFloor reports whether version is older than floor, e.g. "3.9.18" is
+// below "3.10" and "3.10.2" isn't. judged is false when the version can't be
+// placed relative to floor: it doesn't parse, or it stops short of floor's
+// precision while agreeing with it, as "3" does with "3.10".
+func BelowFloor(version, floor string) (below, judged bool) {
+	nums, err := ParseVersion(version)
+	if err != nil {
+		return false, false
+	}
+	bound, err := ParseVersion(floor)
+	if err != nil {
+		return false, false
+	}
+
+	for i, b := range bound {
+		if i >= len(nums) {
+			return false, false
+		}
+		if nums[i] != b {
+			return nums[i] < b, true
+		}
+	}
+	return false, true
+}
+
+// VersionFilter is a comparison such as "<4.0" that a version must satisfy.
+// The zero value is no filter and accepts every version.
+type VersionFilter struct {
+	Op      string // One of "<", "<=", ">", ">=", "==", "!="
+	Version string // Threshold compared against with CompareVersions
+}
+
+// versionFilterOps lists the operators, two-character ones first so "<="
+// isn't read as "<" followed by "=4.0"
+var versionFilterOps = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// ParseVersionFilter parses a filter such as "<4.0" or ">= 2.2"
+func ParseVersionFilter(value string) (VersionFilter, error) {
+	value = strings.TrimSpace(value)
+	for _, op := range versionFilterOps {
+		if threshold, ok := strings.CutPrefix(value, op); ok {
+			threshold = strings.TrimSpace(threshold)
+			if _, err := ParseVersion(threshold); err != nil {
+				return VersionFilter{}, fmt.Errorf("invalid version filter %q: %w", value, err)
+			}
+			return VersionFilter{Op: op, Version: threshold}, nil
+		}
+	}
+	return VersionFilter{}, fmt.Errorf("invalid version filter %q: must start with <, <=, >, >=, ==, or !=", value)
+}
+
+// IsZero reports whether f is the zero value, i.e. no filter
+func (f VersionFilter) IsZero() bool {
+	return f.Op == ""
+}
+
+// String returns the filter as it's written, e.g. "<4.0"
+func (f VersionFilter) String() string {
+	return f.Op + f.Version
+}
+
+// Matches reports whether version satisfies f. Missing components count as
+// 0, so "4" == "4.0". A version that doesn't parse never matches a filter.
+func (f VersionFilter) Matches(version string) bool {
+	if f.IsZero() {
+		return true
+	}
+	nums, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+	bound, err := ParseVersion(f.Version)
+	if err != nil {
+		return false
+	}
+
+	cmp := compareVersionNums(nums, bound)
+
+	switch f.Op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	}
+	return false
+}
+
+// CrossCheck records an additional detection found while cross-checking
+// a project's primary detection against lower-priority sources
+type CrossCheck struct {
+	Source  string `json:"source"`  // File the version was detected in
+	Version string `json:"version"` // Version detected in that file
+}
+
+// VersionsAgree reports whether two detected versions are consistent.
+// Only the components both versions specify are compared, so "3.11" agrees
+// with "3.11.5" but "3.10" does not agree with "3.11".
+func VersionsAgree(a, b string) bool {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	n := len(aParts)
+	if len(bParts) < n {
+		n = len(bParts)
+	}
+
+	for i := 0; i < n; i++ {
+		if aParts[i] != bParts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ConstraintExcludes reports whether a declared constraint with the given
+// floor (e.g. "3.11" from ">=3.11") and upper bound (see ExcludesVersion)
+// rules out version. The floor is compared at the precision both share, so
+// "3.11" isn't below a floor of "3.11.2". Unparseable versions exclude nothing.
+func ConstraintExcludes(floor, versionMax, version string) bool {
+	if ExcludesVersion(versionMax, version) {
+		return true
+	}
+	floorNums, err := ParseVersion(floor)
+	if err != nil {
+		return false
+	}
+	nums, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+	n := min(len(floorNums), len(nums))
+	return compareVersionNums(nums[:n], floorNums[:n]) < 0
+}
+
+// ciDriftSuffix flags CI/declaration drift (see ScanResult.CIDrift) for
+// appending to a result line, or returns "" if there is none
+func ciDriftSuffix(drift string) string {
+	if drift == "" {
+		return ""
+	}
+	return " [CI/declaration drift: " + drift + "]"
+}
+
+// mismatchSuffix describes the cross-checks that disagree with version,
+// formatted for appending to a result line, or "" if they all agree
+func mismatchSuffix(version string, checks []CrossCheck) string {
+	var parts []string
+	for _, cc := range checks {
+		if !VersionsAgree(version, cc.Version) {
+			parts = append(parts, fmt.Sprintf("%s=%s", cc.Source, cc.Version))
+		}
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return " - MISMATCH: " + strings.Join(parts, ", ")
+}
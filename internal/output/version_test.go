@@ -0,0 +1,330 @@
+package output
+
+import "testing"
+
+func TestVersionsAgree(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"3.11", "3.11", true},
+		{"3.11", "3.11.5", true},
+		{"3.11.5", "3.11", true},
+		{"3.11.5", "3.11.2", false},
+		{"3.10", "3.11", false},
+		{"3", "3.12", true},
+		{"2.7", "3.7", false},
+	}
+
+	for _, tt := range tests {
+		if got := VersionsAgree(tt.a, tt.b); got != tt.want {
+			t.Errorf("VersionsAgree(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		n       Normalization
+		want    string
+	}{
+		{"3.11.5", NormalizeNone, "3.11.5"},
+		{"3.11.5", NormalizeMinor, "3.11"},
+		{"3.11", NormalizeMinor, "3.11"},
+		{"3", NormalizeMinor, "3"},
+		{"3.11.5", NormalizeMajor, "3"},
+		{"2.7", NormalizeMajor, "2"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeVersion(tt.version, tt.n); got != tt.want {
+			t.Errorf("NormalizeVersion(%q, %q) = %q, want %q", tt.version, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestParseNormalization(t *testing.T) {
+	for _, value := range []string{"", "minor", "major"} {
+		if _, err := ParseNormalization(value); err != nil {
+			t.Errorf("ParseNormalization(%q) unexpected error: %v", value, err)
+		}
+	}
+
+	if _, err := ParseNormalization("patch"); err == nil {
+		t.Error("ParseNormalization(\"patch\") expected error")
+	}
+}
+
+func TestScanStatistics_Normalize(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.Normalize = NormalizeMinor
+
+	for _, v := range []string{"3.11.5", "3.11.2", "3.11", "3.8"} {
+		stats.RecordResult(&ScanResult{ProjectName: "p", PythonVersion: v})
+	}
+
+	if stats.VersionCounts["3.11"] != 3 {
+		t.Errorf("VersionCounts[3.11] = %d, want 3", stats.VersionCounts["3.11"])
+	}
+	if stats.VersionCounts["3.8"] != 1 {
+		t.Errorf("VersionCounts[3.8] = %d, want 1", stats.VersionCounts["3.8"])
+	}
+	if len(stats.VersionCounts) != 2 {
+		t.Errorf("len(VersionCounts) = %d, want 2", len(stats.VersionCounts))
+	}
+}
+
+func TestIsApproved(t *testing.T) {
+	approved := ParseApprovedVersions("3.11,3.12")
+
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"3.11", true},
+		{"3.11.5", true},
+		{"3.12.1", true},
+		{"3.8", false},
+		{"3.1", false},
+		{"3", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsApproved(tt.version, approved); got != tt.want {
+			t.Errorf("IsApproved(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestIsPython2(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"2.7", true},
+		{"2.7.18", true},
+		{"2", true},
+		{"3.11", false},
+		{"12.1", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsPython2(tt.version); got != tt.want {
+			t.Errorf("IsPython2(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestScanStatistics_ApprovedVersions(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.ApprovedVersions = []string{"3.11", "3.12"}
+
+	for _, v := range []string{"3.11.5", "3.12", "3.8", ""} {
+		stats.RecordResult(&ScanResult{ProjectName: "p", PythonVersion: v})
+	}
+
+	if stats.ApprovedProjects != 2 {
+		t.Errorf("ApprovedProjects = %d, want 2", stats.ApprovedProjects)
+	}
+	if stats.NonApprovedProjects != 1 {
+		t.Errorf("NonApprovedProjects = %d, want 1", stats.NonApprovedProjects)
+	}
+}
+
+func TestVersionBounds_CheckVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		bounds  VersionBounds
+		wantErr bool
+	}{
+		{"3.11.5", DefaultVersionBounds, false},
+		{"2.7", DefaultVersionBounds, false},
+		{"3", DefaultVersionBounds, false},
+		{"3.30", DefaultVersionBounds, false},
+		{"3.31", DefaultVersionBounds, true},
+		{"7.4", DefaultVersionBounds, true},
+		{"3.x", DefaultVersionBounds, true},
+		{"", DefaultVersionBounds, true},
+		{"2.7", VersionBounds{Majors: []int{3}}, true},
+		{"3.99", VersionBounds{}, false},
+		{"3.11-slim", VersionBounds{}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.bounds.CheckVersion(tt.version)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckVersion(%q) with %+v error = %v, wantErr %v", tt.version, tt.bounds, err, tt.wantErr)
+		}
+	}
+}
+
+func TestExcludesVersion(t *testing.T) {
+	tests := []struct {
+		versionMax, target string
+		want               bool
+	}{
+		{"<3.11", "3.11", true},
+		{"<3.11", "3.12", true},
+		{"<3.11", "3.11.2", true},
+		{"<3.11", "3.10.9", false},
+		{"<=3.10", "3.10.4", false},
+		{"<=3.10", "3.11", true},
+		{"<4.0", "3.12", false},
+		{"<4", "4.1", true},
+		{"", "3.12", false},
+		{"<3.11", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := ExcludesVersion(tt.versionMax, tt.target); got != tt.want {
+			t.Errorf("ExcludesVersion(%q, %q) = %v, want %v", tt.versionMax, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestBelowFloor(t *testing.T) {
+	tests := []struct {
+		version, floor string
+		below, judged  bool
+	}{
+		{"3.9.18", "3.10", true, true},
+		{"3.10", "3.10", false, true},
+		{"3.10.2", "3.10", false, true},
+		{"3.12", "3.10", false, true},
+		{"2.7", "3.10", true, true},
+		{"2", "3.10", true, true},
+		{"3", "3.10", false, false},
+		{"latest", "3.10", false, false},
+	}
+
+	for _, tt := range tests {
+		below, judged := BelowFloor(tt.version, tt.floor)
+		if below != tt.below || judged != tt.judged {
+			t.Errorf("BelowFloor(%q, %q) = %v, %v, want %v, %v", tt.version, tt.floor, below, judged, tt.below, tt.judged)
+		}
+	}
+}
+
+func TestConstraintExcludes(t *testing.T) {
+	tests := []struct {
+		floor, versionMax, version string
+		want                       bool
+	}{
+		{"3.11", "", "3.9", true},
+		{"3.11", "", "3.11", false},
+		{"3.11", "", "3.12", false},
+		{"3.11.2", "", "3.11", false}, // Compared at the precision both share
+		{"3.11.2", "", "3.11.1", true},
+		{"3.9", "<3.12", "3.12", true},
+		{"3.9", "<3.12", "3.10", false},
+		{"", "", "3.9", false},
+		{"3.11", "", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := ConstraintExcludes(tt.floor, tt.versionMax, tt.version); got != tt.want {
+			t.Errorf("ConstraintExcludes(%q, %q, %q) = %v, want %v", tt.floor, tt.versionMax, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestScanStatistics_TargetVersion(t *testing.T) {
+	stats := NewScanStatistics()
+	stats.TargetVersion = "3.12"
+
+	for _, bound := range []string{"<3.11", "<4.0", "", "<3.12"} {
+		stats.RecordResult(&ScanResult{ProjectName: "p", PythonVersion: "3.8", VersionMax: bound})
+	}
+	// Undetected projects are never counted
+	stats.RecordResult(&ScanResult{ProjectName: "p", VersionMax: "<3.11"})
+
+	if stats.CappedProjects != 2 {
+		t.Errorf("CappedProjects = %d, want 2", stats.CappedProjects)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"3.9", "3.10", -1},
+		{"3.10", "3.9", 1},
+		{"2.7", "3.6", -1},
+		{"3.11", "3.11", 0},
+		{"3.11", "3.11.0", -1}, // numerically equal, ordered by string
+		{"3.11.5", "3.11.10", -1},
+		{"3", "3.0.1", -1},
+		{"3.x", "3.12", 1},
+		{"3.12", "3.x", -1},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersionFilter(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    VersionFilter
+		wantErr bool
+	}{
+		{"<4.0", VersionFilter{Op: "<", Version: "4.0"}, false},
+		{"<= 2.2", VersionFilter{Op: "<=", Version: "2.2"}, false},
+		{" >=3 ", VersionFilter{Op: ">=", Version: "3"}, false},
+		{"!=1.11", VersionFilter{Op: "!=", Version: "1.11"}, false},
+		{"4.0", VersionFilter{}, true},
+		{"<four", VersionFilter{}, true},
+		{"=<4.0", VersionFilter{}, true},
+		{"", VersionFilter{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVersionFilter(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVersionFilter(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersionFilter(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestVersionFilter_Matches(t *testing.T) {
+	tests := []struct {
+		filter  string
+		version string
+		want    bool
+	}{
+		{"<4.0", "3.2.18", true},
+		{"<4.0", "4.0", false},
+		{"<4.0", "4", false}, // Missing components count as 0
+		{"<4.0", "10.1", false},
+		{"<=4.0", "4.0.0", true},
+		{">2.2", "2.10", true},
+		{">=2.2", "2.2", true},
+		{"==3.2", "3.2.0", true},
+		{"!=3.2", "3.2", false},
+		{"<4.0", "4.0rc1", false}, // Doesn't parse
+	}
+
+	for _, tt := range tests {
+		filter, err := ParseVersionFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("ParseVersionFilter(%q) error = %v", tt.filter, err)
+		}
+		if got := filter.Matches(tt.version); got != tt.want {
+			t.Errorf("%s Matches(%q) = %v, want %v", tt.filter, tt.version, got, tt.want)
+		}
+	}
+
+	if !(VersionFilter{}).Matches("anything") {
+		t.Error("the zero filter rejected a version, want every version accepted")
+	}
+}
@@ -0,0 +1,79 @@
+package parsers
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+)
+
+// envrcPythonPattern matches direnv directives that select an interpreter:
+// "layout python python3.11", "layout python /usr/bin/python3.11",
+// "layout python3", and "use python 3.11"
+var envrcPythonPattern = regexp.MustCompile(`^(layout|use)\s+python(?:\s+(?:\S*/)?python|\s+|)(\d+(?:\.\d+)*)(?:\s|$)`)
+
+// ParseEnvrc extracts a Python version from a direnv .envrc.
+//
+// Format examples:
+//
+//	layout python python3.11
+//	use python 3.11
+//
+// Returns:
+// - Confidence: 0.7 for a major.minor interpreter (e.g. python3.11)
+// - Confidence: 0.4 for a major-only interpreter (e.g. layout python3)
+func ParseEnvrc(content []byte, filename string) (*rules.SearchResult, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		matches := envrcPythonPattern.FindStringSubmatch(line)
+		if len(matches) < 3 {
+			continue
+		}
+
+		version := matches[2]
+		confidence := 0.7
+		metadata := map[string]string{
+			"source_type": "direnv",
+			"directive":   matches[1],
+		}
+
+		// "layout python3" only pins the major version
+		if !strings.Contains(version, ".") {
+			confidence = 0.4
+			metadata["major_only"] = "true"
+		}
+
+		return &rules.SearchResult{
+			Found: true,
+			Detection: rules.Detection{
+				Version:    version,
+				Source:     filename,
+				Confidence: confidence,
+			},
+			RawValue: line,
+			Metadata: metadata,
+		}, nil
+	}
+
+	return &rules.SearchResult{Found: false}, nil
+}
+
+// GetEnvrcRule returns a SearchRule for direnv .envrc files
+func GetEnvrcRule() *rules.SearchRule {
+	return rules.NewRuleBuilder("envrc").
+		Description("Extracts Python version from direnv layout python / use python directives in .envrc").
+		Priority(16).
+		FilePattern(".envrc").
+		RequiredContent(`(layout|use)\s+python`).
+		MaxFileSize(1024*1024). // 1MB
+		Parser(ParseEnvrc).
+		Tags("config", "direnv").
+		MustBuild()
+}
@@ -0,0 +1,93 @@
+package parsers
+
+import (
+	"testing"
+)
+
+func TestParseEnvrc(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		wantFound      bool
+		wantVer        string
+		wantConfidence float64
+	}{
+		{
+			name:           "layout python with interpreter",
+			content:        "export DATABASE_URL=postgres://localhost/dev\nlayout python python3.11\n",
+			wantFound:      true,
+			wantVer:        "3.11",
+			wantConfidence: 0.7,
+		},
+		{
+			name:           "layout python with interpreter path",
+			content:        "layout python /usr/local/bin/python3.12\n",
+			wantFound:      true,
+			wantVer:        "3.12",
+			wantConfidence: 0.7,
+		},
+		{
+			name:           "use python",
+			content:        "use python 3.10.4\n",
+			wantFound:      true,
+			wantVer:        "3.10.4",
+			wantConfidence: 0.7,
+		},
+		{
+			name:           "layout python3 is major only",
+			content:        "layout python3\n",
+			wantFound:      true,
+			wantVer:        "3",
+			wantConfidence: 0.4,
+		},
+		{
+			name:      "layout python without version",
+			content:   "layout python\n",
+			wantFound: false,
+		},
+		{
+			name:      "commented out",
+			content:   "# layout python python3.11\n",
+			wantFound: false,
+		},
+		{
+			name:      "other layout",
+			content:   "layout node\nuse nix\n",
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseEnvrc([]byte(tt.content), ".envrc")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Found != tt.wantFound {
+				t.Fatalf("Found = %v, want %v", result.Found, tt.wantFound)
+			}
+			if !tt.wantFound {
+				return
+			}
+
+			if result.Version != tt.wantVer {
+				t.Errorf("Version = %q, want %q", result.Version, tt.wantVer)
+			}
+			if result.Confidence != tt.wantConfidence {
+				t.Errorf("Confidence = %v, want %v", result.Confidence, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestGetEnvrcRule(t *testing.T) {
+	rule := GetEnvrcRule()
+
+	if !rule.Matches(".envrc", ".envrc") {
+		t.Error("expected rule to match .envrc")
+	}
+	if rule.Matches(".env", ".env") {
+		t.Error("expected rule not to match .env")
+	}
+}
@@ -26,6 +26,14 @@ func TestParsePyprojectToml_RealWorldExamples(t *testing.T) {
 			wantConfidence: 0.9,
 			minDeps:        4, // Should have at least 4 dependencies
 		},
+		{
+			filename:       "poetry-table-example.toml",
+			wantFound:      true,
+			wantVersion:    "3.10",
+			wantFormat:     "Poetry",
+			wantConfidence: 0.9,
+			minDeps:        4,
+		},
 		{
 			filename:       "pep621-example.toml",
 			wantFound:      true,
@@ -77,8 +85,8 @@ func TestParsePyprojectToml_RealWorldExamples(t *testing.T) {
 				t.Errorf("Confidence = %v, want %v", result.Confidence, tt.wantConfidence)
 			}
 
-			if format := result.Metadata["format"]; format != tt.wantFormat {
-				t.Errorf("Format = %v, want %v", format, tt.wantFormat)
+			if result.Format != tt.wantFormat {
+				t.Errorf("Format = %v, want %v", result.Format, tt.wantFormat)
 			}
 
 			if result.Source != tt.filename {
@@ -89,8 +97,8 @@ func TestParsePyprojectToml_RealWorldExamples(t *testing.T) {
 				t.Error("RawValue should not be empty")
 			}
 
-			if constraint := result.Metadata["constraint"]; constraint == "" {
-				t.Error("Metadata[constraint] should not be empty")
+			if result.Constraint == "" {
+				t.Error("Constraint should not be empty")
 			}
 
 			// Note: We don't check exact dependency count as it varies by file
@@ -0,0 +1,132 @@
+package parsers
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+	"gopkg.in/yaml.v3"
+)
+
+// PlatformAppConfig represents the parts of a Platform.sh .platform.app.yaml
+// we care about
+type PlatformAppConfig struct {
+	Type string `yaml:"type"`
+}
+
+// HerokuAppJSON represents the parts of a Heroku app.json we care about.
+// Env values are either a plain string or an object with a "value" field.
+type HerokuAppJSON struct {
+	Env map[string]json.RawMessage `json:"env"`
+}
+
+// platformPythonPattern matches a deployment runtime like "python:3.11" or
+// "python:3.11.4"
+var platformPythonPattern = regexp.MustCompile(`^python:(\d+\.\d+(?:\.\d+)?)$`)
+
+// platformVersionPattern matches a bare Python version like "3.11.4"
+var platformVersionPattern = regexp.MustCompile(`^\d+\.\d+(?:\.\d+)?$`)
+
+// ParsePlatformManifest extracts a Python version from a PaaS deployment
+// manifest. These are authoritative for what runs in production, so they
+// cover Django and other apps that declare a version nowhere else.
+//
+// Format examples:
+//
+//	# .platform.app.yaml (Platform.sh)
+//	type: "python:3.11"
+//
+//	// app.json (Heroku)
+//	{"env": {"PYTHON_VERSION": {"value": "3.11.4"}}}
+//
+// A Heroku "stack" such as heroku-22 names the base image, not the Python
+// version, so it's ignored.
+//
+// Returns:
+// - Confidence: 0.7
+func ParsePlatformManifest(content []byte, filename string) (*rules.SearchResult, error) {
+	var version, rawValue, sourceType string
+	if path.Base(filename) == "app.json" {
+		version, rawValue = herokuPythonVersion(content)
+		sourceType = "heroku"
+	} else {
+		var config PlatformAppConfig
+		if err := yaml.Unmarshal(content, &config); err != nil {
+			// Return no match instead of error for malformed YAML
+			return &rules.SearchResult{Found: false}, nil
+		}
+		rawValue = config.Type
+		if matches := platformPythonPattern.FindStringSubmatch(strings.TrimSpace(config.Type)); matches != nil {
+			version = matches[1]
+		}
+		sourceType = "platformsh"
+	}
+
+	if version == "" {
+		return &rules.SearchResult{Found: false}, nil
+	}
+
+	return &rules.SearchResult{
+		Found: true,
+		Detection: rules.Detection{
+			Version:    version,
+			Source:     filename,
+			Confidence: 0.7,
+		},
+		RawValue: rawValue,
+		Metadata: map[string]string{"source_type": sourceType},
+	}, nil
+}
+
+// herokuPythonVersion returns the PYTHON_VERSION set in an app.json's env,
+// or "" if it has none or the file is malformed
+func herokuPythonVersion(content []byte) (version, rawValue string) {
+	var app HerokuAppJSON
+	if err := json.Unmarshal(content, &app); err != nil {
+		return "", ""
+	}
+	raw, ok := app.Env["PYTHON_VERSION"]
+	if !ok {
+		return "", ""
+	}
+
+	if err := json.Unmarshal(raw, &rawValue); err != nil {
+		var entry struct {
+			Value string `json:"value"`
+		}
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return "", ""
+		}
+		rawValue = entry.Value
+	}
+
+	version = strings.TrimPrefix(strings.TrimSpace(rawValue), "python-")
+	if !platformVersionPattern.MatchString(version) {
+		return "", ""
+	}
+	return version, rawValue
+}
+
+// GetPlatformAppYamlRule returns a SearchRule for Platform.sh's .platform.app.yaml
+func GetPlatformAppYamlRule() *rules.SearchRule {
+	return platformManifestRule("platform-app-yaml", ".platform.app.yaml", `python`, "platformsh")
+}
+
+// GetAppJSONRule returns a SearchRule for Heroku's app.json
+func GetAppJSONRule() *rules.SearchRule {
+	return platformManifestRule("app-json", "app.json", `PYTHON_VERSION`, "heroku")
+}
+
+func platformManifestRule(name, filename, requiredContent, platform string) *rules.SearchRule {
+	return rules.NewRuleBuilder(name).
+		Description("Extracts the deployment Python version from "+filename).
+		Priority(18).
+		FilePattern(filename).
+		RequiredContent(requiredContent).
+		MaxFileSize(1024*1024). // 1MB
+		Parser(ParsePlatformManifest).
+		Tags("deployment", "paas", platform).
+		MustBuild()
+}
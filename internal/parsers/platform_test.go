@@ -0,0 +1,113 @@
+package parsers
+
+import (
+	"testing"
+)
+
+func TestParsePlatformManifest(t *testing.T) {
+	tests := []struct {
+		name       string
+		filename   string
+		content    string
+		wantFound  bool
+		wantVer    string
+		wantSource string
+	}{
+		{
+			name:     "platform.sh python runtime",
+			filename: ".platform.app.yaml",
+			content: `name: app
+type: "python:3.11"
+web:
+  commands:
+    start: "gunicorn myproject.wsgi"
+`,
+			wantFound:  true,
+			wantVer:    "3.11",
+			wantSource: "platformsh",
+		},
+		{
+			name:      "platform.sh other runtime",
+			filename:  ".platform.app.yaml",
+			content:   "type: \"nodejs:18\"\n",
+			wantFound: false,
+		},
+		{
+			name:      "malformed yaml",
+			filename:  ".platform.app.yaml",
+			content:   "type: [python:3.11\n",
+			wantFound: false,
+		},
+		{
+			name:     "heroku env object",
+			filename: "app.json",
+			content: `{
+  "name": "django-app",
+  "stack": "heroku-22",
+  "env": {"PYTHON_VERSION": {"description": "Runtime", "value": "3.11.4"}}
+}`,
+			wantFound:  true,
+			wantVer:    "3.11.4",
+			wantSource: "heroku",
+		},
+		{
+			name:       "heroku env string in a subdirectory",
+			filename:   "deploy/app.json",
+			content:    `{"env": {"PYTHON_VERSION": "python-3.10.13"}}`,
+			wantFound:  true,
+			wantVer:    "3.10.13",
+			wantSource: "heroku",
+		},
+		{
+			name:      "heroku stack only",
+			filename:  "app.json",
+			content:   `{"stack": "heroku-22", "buildpacks": [{"url": "heroku/python"}]}`,
+			wantFound: false,
+		},
+		{
+			name:      "heroku non-version value",
+			filename:  "app.json",
+			content:   `{"env": {"PYTHON_VERSION": {"generator": "secret"}}}`,
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParsePlatformManifest([]byte(tt.content), tt.filename)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Found != tt.wantFound {
+				t.Fatalf("Found = %v, want %v", result.Found, tt.wantFound)
+			}
+			if !tt.wantFound {
+				return
+			}
+
+			if result.Version != tt.wantVer {
+				t.Errorf("Version = %q, want %q", result.Version, tt.wantVer)
+			}
+			if result.Confidence != 0.7 {
+				t.Errorf("Confidence = %v, want 0.7", result.Confidence)
+			}
+			if result.Metadata["source_type"] != tt.wantSource {
+				t.Errorf("source_type = %q, want %q", result.Metadata["source_type"], tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestPlatformManifestRules(t *testing.T) {
+	registry := DefaultRegistry()
+	for name, filename := range map[string]string{"platform-app-yaml": ".platform.app.yaml", "app-json": "app.json"} {
+		rule := registry.Get(name)
+		if rule == nil {
+			t.Fatalf("rule %q not registered", name)
+		}
+		if rule.Condition.FilePattern != filename {
+			t.Errorf("rule %q FilePattern = %q, want %q", name, rule.Condition.FilePattern, filename)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package parsers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+	"gopkg.in/yaml.v3"
+)
+
+// PreCommitConfig represents the parts of .pre-commit-config.yaml we care about
+type PreCommitConfig struct {
+	DefaultLanguageVersion map[string]string `yaml:"default_language_version"`
+}
+
+// preCommitPythonPattern matches interpreter names like "python3", "python3.11",
+// or "python3.11.5"
+var preCommitPythonPattern = regexp.MustCompile(`^python(\d+(?:\.\d+)*)$`)
+
+// ParsePreCommitConfig extracts a Python version from the
+// default_language_version section of .pre-commit-config.yaml.
+//
+// Format examples:
+//
+//	default_language_version:
+//	  python: python3.11
+//
+// Returns:
+// - Confidence: 0.75 for a major.minor interpreter (e.g. python3.11)
+// - Confidence: 0.4 for a major-only interpreter (e.g. python3)
+func ParsePreCommitConfig(content []byte, filename string) (*rules.SearchResult, error) {
+	var config PreCommitConfig
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		// Return no match instead of error for malformed YAML
+		return &rules.SearchResult{Found: false}, nil
+	}
+
+	raw := config.DefaultLanguageVersion["python"]
+	matches := preCommitPythonPattern.FindStringSubmatch(raw)
+	if len(matches) < 2 {
+		return &rules.SearchResult{Found: false}, nil
+	}
+
+	version := matches[1]
+	confidence := 0.75
+	metadata := map[string]string{
+		"source_type": "pre_commit",
+		"interpreter": raw,
+	}
+
+	// "python3" only pins the major version
+	if !strings.Contains(version, ".") {
+		confidence = 0.4
+		metadata["major_only"] = "true"
+	}
+
+	return &rules.SearchResult{
+		Found: true,
+		Detection: rules.Detection{
+			Version:    version,
+			Source:     filename,
+			Confidence: confidence,
+		},
+		RawValue: raw,
+		Metadata: metadata,
+	}, nil
+}
+
+// GetPreCommitRule returns a SearchRule for .pre-commit-config.yaml
+func GetPreCommitRule() *rules.SearchRule {
+	return rules.NewRuleBuilder("pre-commit-config").
+		Description("Extracts Python version from .pre-commit-config.yaml default_language_version").
+		Priority(14).
+		FilePattern(".pre-commit-config.yaml").
+		RequiredContent(`default_language_version`).
+		MaxFileSize(1024*1024). // 1MB
+		Parser(ParsePreCommitConfig).
+		Tags("config", "pre-commit").
+		MustBuild()
+}
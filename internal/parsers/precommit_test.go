@@ -0,0 +1,93 @@
+package parsers
+
+import (
+	"testing"
+)
+
+func TestParsePreCommitConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		wantFound      bool
+		wantVer        string
+		wantConfidence float64
+	}{
+		{
+			name: "minor version",
+			content: `default_language_version:
+  python: python3.11
+repos:
+  - repo: https://github.com/psf/black
+    rev: 24.1.0
+    hooks:
+      - id: black
+`,
+			wantFound:      true,
+			wantVer:        "3.11",
+			wantConfidence: 0.75,
+		},
+		{
+			name:           "patch version",
+			content:        "default_language_version:\n  python: python3.11.5\n",
+			wantFound:      true,
+			wantVer:        "3.11.5",
+			wantConfidence: 0.75,
+		},
+		{
+			name:           "major only",
+			content:        "default_language_version:\n  python: python3\n",
+			wantFound:      true,
+			wantVer:        "3",
+			wantConfidence: 0.4,
+		},
+		{
+			name:      "other language only",
+			content:   "default_language_version:\n  node: 18.0.0\n",
+			wantFound: false,
+		},
+		{
+			name:      "no default_language_version",
+			content:   "repos: []\n",
+			wantFound: false,
+		},
+		{
+			name:      "malformed yaml",
+			content:   "default_language_version: [python: \n",
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParsePreCommitConfig([]byte(tt.content), ".pre-commit-config.yaml")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Found != tt.wantFound {
+				t.Fatalf("Found = %v, want %v", result.Found, tt.wantFound)
+			}
+			if !tt.wantFound {
+				return
+			}
+
+			if result.Version != tt.wantVer {
+				t.Errorf("Version = %q, want %q", result.Version, tt.wantVer)
+			}
+			if result.Confidence != tt.wantConfidence {
+				t.Errorf("Confidence = %v, want %v", result.Confidence, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestGetPreCommitRule(t *testing.T) {
+	rule := GetPreCommitRule()
+
+	if !rule.Matches(".pre-commit-config.yaml", ".pre-commit-config.yaml") {
+		t.Error("expected rule to match .pre-commit-config.yaml")
+	}
+	if rule.Matches("config.yaml", "config.yaml") {
+		t.Error("expected rule not to match config.yaml")
+	}
+}
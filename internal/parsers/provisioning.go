@@ -0,0 +1,103 @@
+package parsers
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+)
+
+// ProvisioningTag marks the best-effort provisioning rules so they can be
+// disabled as a group by users who find them too noisy
+const ProvisioningTag = "provisioning"
+
+// provisioningPythonPattern matches interpreter package references like
+// "python3.11", "python3.11-venv", or "/usr/bin/python3.10"
+var provisioningPythonPattern = regexp.MustCompile(`\bpython(3\.\d+)`)
+
+// ParseProvisioning extracts a Python version from infrastructure provisioning
+// files such as Vagrantfiles and Ansible playbooks. These repos often only
+// mention Python as a package to install, so this is a last-resort signal.
+//
+// Format examples:
+//
+//	apt: name=python3.11 state=present
+//	- python3.10-venv
+//	config.vm.provision "shell", inline: "apt-get install -y python3.12"
+//
+// Returns:
+// - Confidence: 0.5 (inferred from provisioning, not a declaration)
+func ParseProvisioning(content []byte, filename string) (*rules.SearchResult, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip comment lines in both Ruby (Vagrantfile) and YAML
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		matches := provisioningPythonPattern.FindStringSubmatch(line)
+		if len(matches) > 1 {
+			return &rules.SearchResult{
+				Found: true,
+				Detection: rules.Detection{
+					Version:    matches[1],
+					Source:     filename,
+					Confidence: 0.5,
+				},
+				RawValue: matches[0],
+				Metadata: map[string]string{
+					"source_type": "provisioning",
+					"context":     line,
+					"inferred":    "true",
+				},
+			}, nil
+		}
+	}
+
+	return &rules.SearchResult{Found: false}, nil
+}
+
+// GetVagrantfileRule returns a SearchRule for Vagrantfile provisioning
+func GetVagrantfileRule() *rules.SearchRule {
+	return rules.NewRuleBuilder("vagrantfile").
+		Description("Infers Python version from packages installed in a Vagrantfile").
+		Priority(20). // Last resort - inferred from provisioning
+		FilePattern("Vagrantfile").
+		RequiredContent(`python3\.\d+`).
+		MaxFileSize(1024*1024). // 1MB
+		Parser(ParseProvisioning).
+		Tags(ProvisioningTag, "vagrant", "inferred").
+		MustBuild()
+}
+
+// GetAnsiblePlaybookRule returns a SearchRule for top-level Ansible playbooks
+func GetAnsiblePlaybookRule() *rules.SearchRule {
+	return rules.NewRuleBuilder("ansible-playbook").
+		Description("Infers Python version from packages installed by an Ansible playbook").
+		Priority(21).
+		FilePattern("playbook*.yml").
+		RequiredContent(`python3\.\d+`).
+		MaxFileSize(1024*1024). // 1MB
+		Parser(ParseProvisioning).
+		Tags(ProvisioningTag, "ansible", "inferred").
+		MustBuild()
+}
+
+// GetAnsibleDirectoryRule returns a SearchRule for YAML files under an ansible/ directory
+func GetAnsibleDirectoryRule() *rules.SearchRule {
+	return rules.NewRuleBuilder("ansible-yaml").
+		Description("Infers Python version from YAML files under ansible/").
+		Priority(22).
+		FilePattern("*.yaml").
+		PathPattern(`(^|/)ansible/`).
+		RequiredContent(`python3\.\d+`).
+		MaxFileSize(1024*1024). // 1MB
+		Parser(ParseProvisioning).
+		Tags(ProvisioningTag, "ansible", "inferred").
+		MustBuild()
+}
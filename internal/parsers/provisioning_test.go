@@ -0,0 +1,129 @@
+package parsers
+
+import (
+	"testing"
+)
+
+func TestParseProvisioning(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantFound   bool
+		wantVer     string
+		wantContext string
+	}{
+		{
+			name: "ansible apt task",
+			content: `- hosts: all
+  tasks:
+    - name: Install Python
+      apt:
+        name: python3.11
+        state: present
+`,
+			wantFound:   true,
+			wantVer:     "3.11",
+			wantContext: "name: python3.11",
+		},
+		{
+			name:        "package with suffix",
+			content:     "    - python3.10-venv\n",
+			wantFound:   true,
+			wantVer:     "3.10",
+			wantContext: "- python3.10-venv",
+		},
+		{
+			name: "vagrant inline shell",
+			content: `Vagrant.configure("2") do |config|
+  config.vm.provision "shell", inline: "apt-get install -y python3.12"
+end
+`,
+			wantFound: true,
+			wantVer:   "3.12",
+		},
+		{
+			name:      "commented out reference ignored",
+			content:   "# apt: name=python3.8\n- python3\n",
+			wantFound: false,
+		},
+		{
+			name:      "unversioned python",
+			content:   "apt: name=python3 state=present\n",
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseProvisioning([]byte(tt.content), "playbook.yml")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Found != tt.wantFound {
+				t.Fatalf("Found = %v, want %v", result.Found, tt.wantFound)
+			}
+
+			if tt.wantFound {
+				if result.Version != tt.wantVer {
+					t.Errorf("Version = %v, want %v", result.Version, tt.wantVer)
+				}
+				if result.Confidence != 0.5 {
+					t.Errorf("Confidence = %v, want 0.5", result.Confidence)
+				}
+				if result.Metadata["inferred"] != "true" {
+					t.Error("expected result to be marked as inferred")
+				}
+				if tt.wantContext != "" && result.Metadata["context"] != tt.wantContext {
+					t.Errorf("context = %q, want %q", result.Metadata["context"], tt.wantContext)
+				}
+			}
+		})
+	}
+}
+
+func TestProvisioningRulesMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		filepath string
+		want     []string
+	}{
+		{"vagrantfile", "Vagrantfile", "Vagrantfile", []string{"vagrantfile"}},
+		{"playbook", "playbook-web.yml", "playbook-web.yml", []string{"ansible-playbook"}},
+		{"ansible dir yaml", "main.yaml", "ansible/roles/web/tasks/main.yaml", []string{"ansible-yaml"}},
+		{"yaml outside ansible", "main.yaml", "deploy/main.yaml", nil},
+	}
+
+	registry := DefaultRegistry()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, rule := range registry.FindMatchingRules(tt.filename, tt.filepath) {
+				got = append(got, rule.Name)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("matching rules = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("matching rules = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestProvisioningRulesDisableByTag(t *testing.T) {
+	registry := DefaultRegistry()
+
+	if n := registry.DisableByTag(ProvisioningTag); n != 3 {
+		t.Errorf("DisableByTag(%q) = %d, want 3", ProvisioningTag, n)
+	}
+
+	if rules := registry.FindMatchingRules("Vagrantfile", "Vagrantfile"); len(rules) != 0 {
+		t.Errorf("expected no enabled rules for Vagrantfile, got %d", len(rules))
+	}
+}
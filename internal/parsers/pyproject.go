@@ -3,6 +3,7 @@ package parsers
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -75,8 +76,10 @@ func ParsePyprojectToml(content []byte, filename string) (*rules.SearchResult, e
 	}
 
 	result := &rules.SearchResult{
-		Found:    false,
-		Source:   filename,
+		Found: false,
+		Detection: rules.Detection{
+			Source: filename,
+		},
 		Metadata: make(map[string]string),
 	}
 
@@ -91,8 +94,9 @@ func ParsePyprojectToml(content []byte, filename string) (*rules.SearchResult, e
 			result.Version = version
 			result.RawValue = pyproject.Project.RequiresPython
 			result.Confidence = 0.9
-			result.Metadata["format"] = "PEP621"
-			result.Metadata["constraint"] = pyproject.Project.RequiresPython
+			result.Format = "PEP621"
+			result.Constraint = pyproject.Project.RequiresPython
+			result.VersionMax = extractUpperBoundFromConstraint(pyproject.Project.RequiresPython)
 			
 			if len(pyproject.Project.Dependencies) > 0 {
 				result.Metadata["dependency_count"] = fmt.Sprintf("%d", len(pyproject.Project.Dependencies))
@@ -105,17 +109,7 @@ func ParsePyprojectToml(content []byte, filename string) (*rules.SearchResult, e
 	// 2. Try Poetry format ([tool.poetry.dependencies])
 	if pyproject.Tool != nil && pyproject.Tool.Poetry != nil {
 		if pythonDep, ok := pyproject.Tool.Poetry.Dependencies["python"]; ok {
-			constraint := ""
-			
-			// Handle different formats: string or map
-			switch v := pythonDep.(type) {
-			case string:
-				constraint = v
-			case map[string]interface{}:
-				if ver, ok := v["version"].(string); ok {
-					constraint = ver
-				}
-			}
+			constraint, markers := poetryPythonConstraint(pythonDep)
 			
 			if constraint != "" {
 				version, err := extractVersionFromConstraint(constraint)
@@ -124,8 +118,12 @@ func ParsePyprojectToml(content []byte, filename string) (*rules.SearchResult, e
 					result.Version = version
 					result.RawValue = constraint
 					result.Confidence = 0.9
-					result.Metadata["format"] = "Poetry"
-					result.Metadata["constraint"] = constraint
+					result.Format = "Poetry"
+					result.Constraint = constraint
+					result.VersionMax = extractUpperBoundFromConstraint(constraint)
+					if markers != "" {
+						result.Metadata["markers"] = markers
+					}
 					
 					// Count dependencies (excluding python itself)
 					depCount := len(pyproject.Tool.Poetry.Dependencies) - 1
@@ -146,6 +144,22 @@ func ParsePyprojectToml(content []byte, filename string) (*rules.SearchResult, e
 	return result, nil
 }
 
+// poetryPythonConstraint returns the constraint from a Poetry python
+// dependency, written either as a string (python = "^3.11") or as a table
+// (python = { version = "^3.11", markers = "..." }), and the table's
+// environment markers if any
+func poetryPythonConstraint(dep interface{}) (constraint, markers string) {
+	switch v := dep.(type) {
+	case string:
+		return v, ""
+	case map[string]interface{}:
+		constraint, _ = v["version"].(string)
+		markers, _ = v["markers"].(string)
+		return constraint, markers
+	}
+	return "", ""
+}
+
 // extractVersionFromConstraint extracts a Python version from a version constraint
 // Handles common formats:
 // - "^3.11" -> "3.11"
@@ -178,6 +192,141 @@ func extractVersionFromConstraint(constraint string) (string, error) {
 	return "", fmt.Errorf("no version found in constraint: %s", constraint)
 }
 
+// constraintClausePattern matches one clause of a version constraint, e.g.
+// "<3.11", "^3.8", or "3.11.*"
+var constraintClausePattern = regexp.MustCompile(`(===|==|!=|~=|<=|>=|<|>|\^|~)?\s*(\d+(?:\.\d+)*)(\.\*)?`)
+
+// versionBound is an upper bound on a version
+type versionBound struct {
+	version   []int
+	inclusive bool
+}
+
+// extractUpperBoundFromConstraint returns the tightest upper bound a version
+// constraint places on Python, as a specifier such as "<3.11" or "<=3.10".
+// Handles PEP 440 and Poetry forms:
+// - ">=3.8,<3.11" -> "<3.11"
+// - "^3.8" -> "<4.0"
+// - "~3.8" -> "<3.9"
+// - "~=3.8.1" -> "<3.9"
+// - "3.11.*" -> "<3.12"
+// Open-ended constraints (">=3.8"), exact pins, and alternatives ("||")
+// return "".
+func extractUpperBoundFromConstraint(constraint string) string {
+	if strings.Contains(constraint, "|") {
+		return ""
+	}
+
+	var tightest *versionBound
+	for _, m := range constraintClausePattern.FindAllStringSubmatch(constraint, -1) {
+		bound := upperBoundForClause(m[1], m[2], m[3] != "")
+		if bound == nil {
+			continue
+		}
+		if tightest == nil || compareVersionParts(bound.version, tightest.version) < 0 ||
+			(compareVersionParts(bound.version, tightest.version) == 0 && !bound.inclusive) {
+			tightest = bound
+		}
+	}
+
+	if tightest == nil {
+		return ""
+	}
+
+	op := "<"
+	if tightest.inclusive {
+		op = "<="
+	}
+	return op + joinVersionParts(tightest.version)
+}
+
+// upperBoundForClause returns the upper bound implied by a single clause, or
+// nil if it has none
+func upperBoundForClause(op, version string, wildcard bool) *versionBound {
+	parts := splitVersionParts(version)
+
+	switch {
+	case op == "<":
+		return &versionBound{version: parts}
+	case op == "<=":
+		return &versionBound{version: parts, inclusive: true}
+	case wildcard && (op == "" || op == "=="):
+		// 3.11.* -> <3.12
+		return &versionBound{version: bumpVersionPart(parts, len(parts)-1)}
+	case op == "^":
+		// Caret allows changes that keep the first non-zero component
+		i := 0
+		for i < len(parts)-1 && parts[i] == 0 {
+			i++
+		}
+		return &versionBound{version: bumpVersionPart(parts, i)}
+	case op == "~":
+		// Poetry tilde allows patch changes, or minor changes if only the major is given
+		if len(parts) == 1 {
+			return &versionBound{version: bumpVersionPart(parts, 0)}
+		}
+		return &versionBound{version: bumpVersionPart(parts, 1)}
+	case op == "~=":
+		// Compatible release drops the last component: ~=3.8 -> <4.0
+		if len(parts) < 2 {
+			return nil
+		}
+		return &versionBound{version: bumpVersionPart(parts, len(parts)-2)}
+	}
+
+	return nil
+}
+
+// splitVersionParts converts "3.11.5" to [3 11 5]; the pattern guarantees digits
+func splitVersionParts(version string) []int {
+	fields := strings.Split(version, ".")
+	parts := make([]int, len(fields))
+	for i, f := range fields {
+		parts[i], _ = strconv.Atoi(f)
+	}
+	return parts
+}
+
+// bumpVersionPart increments parts[i] and drops everything after it, keeping
+// at least major.minor, so bumping the major of 3.8 gives 4.0
+func bumpVersionPart(parts []int, i int) []int {
+	bumped := append([]int(nil), parts[:i+1]...)
+	bumped[i]++
+	for len(bumped) < 2 {
+		bumped = append(bumped, 0)
+	}
+	return bumped
+}
+
+// compareVersionParts compares two versions, treating missing components as 0
+func compareVersionParts(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// joinVersionParts converts [3 11] back to "3.11"
+func joinVersionParts(parts []int) string {
+	fields := make([]string, len(parts))
+	for i, p := range parts {
+		fields[i] = strconv.Itoa(p)
+	}
+	return strings.Join(fields, ".")
+}
+
 // GetPyprojectTomlRule returns a SearchRule for pyproject.toml parsing
 // This is a convenience function for creating the rule
 func GetPyprojectTomlRule() *rules.SearchRule {
@@ -97,6 +97,35 @@ black = "^23.0.0"
 			wantConfidence: 0.9,
 			wantFormat:     "Poetry",
 		},
+		{
+			name: "Poetry with inline table",
+			content: `[tool.poetry.dependencies]
+python = { version = "^3.11" }
+requests = "^2.28.0"
+`,
+			wantFound:      true,
+			wantVersion:    "3.11",
+			wantConfidence: 0.9,
+			wantFormat:     "Poetry",
+		},
+		{
+			name: "Poetry with table and markers",
+			content: `[tool.poetry.dependencies.python]
+version = ">=3.9,<3.13"
+markers = "platform_python_implementation == 'CPython'"
+`,
+			wantFound:      true,
+			wantVersion:    "3.9",
+			wantConfidence: 0.9,
+			wantFormat:     "Poetry",
+		},
+		{
+			name: "Poetry table without version",
+			content: `[tool.poetry.dependencies]
+python = { markers = "sys_platform == 'linux'" }
+`,
+			wantFound: false,
+		},
 		{
 			name: "Poetry without python dependency",
 			content: `[tool.poetry.dependencies]
@@ -134,8 +163,8 @@ django = "^4.2"
 				t.Errorf("Source = %v, want pyproject.toml", result.Source)
 			}
 
-			if format, ok := result.Metadata["format"]; !ok || format != tt.wantFormat {
-				t.Errorf("Metadata[format] = %v, want %v", format, tt.wantFormat)
+			if result.Format != tt.wantFormat {
+				t.Errorf("Format = %v, want %v", result.Format, tt.wantFormat)
 			}
 
 			if result.RawValue == "" {
@@ -251,8 +280,8 @@ docs = ["sphinx"]
 				t.Errorf("Confidence = %v, want %v", result.Confidence, tt.wantConfidence)
 			}
 
-			if format, ok := result.Metadata["format"]; !ok || format != tt.wantFormat {
-				t.Errorf("Metadata[format] = %v, want %v", format, tt.wantFormat)
+			if result.Format != tt.wantFormat {
+				t.Errorf("Format = %v, want %v", result.Format, tt.wantFormat)
 			}
 		})
 	}
@@ -286,8 +315,8 @@ lint = ["black", "ruff"]
 	}
 
 	// PDM uses PEP 621 format
-	if format := result.Metadata["format"]; format != "PEP621" {
-		t.Errorf("Format = %v, want PEP621", format)
+	if result.Format != "PEP621" {
+		t.Errorf("Format = %v, want PEP621", result.Format)
 	}
 }
 
@@ -318,8 +347,8 @@ python = "^3.11"
 		t.Errorf("Version = %v, want 3.12 (PEP621 should take priority)", result.Version)
 	}
 
-	if format := result.Metadata["format"]; format != "PEP621" {
-		t.Errorf("Format = %v, want PEP621", format)
+	if result.Format != "PEP621" {
+		t.Errorf("Format = %v, want PEP621", result.Format)
 	}
 }
 
@@ -473,6 +502,61 @@ func TestExtractVersionFromConstraint(t *testing.T) {
 	}
 }
 
+func TestExtractUpperBoundFromConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{">=3.8,<3.11", "<3.11"},
+		{">=3.8, <=3.10", "<=3.10"},
+		{">=3.8 <3.11", "<3.11"},
+		{"<3.12,<3.11", "<3.11"},
+		{"<=3.11,<3.11", "<3.11"},
+		{"^3.8", "<4.0"},
+		{"^3", "<4.0"},
+		{"^0.2.3", "<0.3"},
+		{"~3.8", "<3.9"},
+		{"~3.8.1", "<3.9"},
+		{"~3", "<4.0"},
+		{"~=3.8", "<4.0"},
+		{"~=3.8.1", "<3.9"},
+		{"3.11.*", "<3.12"},
+		{"==3.11.*", "<3.12"},
+		{">=3.8,!=3.9.*", ""},
+		{">=3.8", ""},
+		{"==3.11.5", ""},
+		{">=3.8,<3.10 || >=3.11", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			if got := extractUpperBoundFromConstraint(tt.constraint); got != tt.want {
+				t.Errorf("extractUpperBoundFromConstraint(%q) = %q, want %q", tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePyprojectTomlVersionMax(t *testing.T) {
+	content := `[project]
+name = "capped"
+requires-python = ">=3.8,<3.11"
+`
+	result, err := ParsePyprojectToml([]byte(content), "pyproject.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Version != "3.8" || result.VersionMax != "<3.11" {
+		t.Errorf("got Version %q, VersionMax %q, want 3.8 and <3.11", result.Version, result.VersionMax)
+	}
+
+	result, _ = ParsePyprojectToml([]byte("[project]\nrequires-python = \">=3.8\"\n"), "pyproject.toml")
+	if result.VersionMax != "" {
+		t.Errorf("VersionMax = %q, want empty for an open-ended constraint", result.VersionMax)
+	}
+}
+
 func TestGetPyprojectTomlRule(t *testing.T) {
 	rule := GetPyprojectTomlRule()
 
@@ -533,13 +617,12 @@ dependencies = [
 		t.Fatal("Expected to find Python version")
 	}
 
-	// Check metadata
-	if constraint, ok := result.Metadata["constraint"]; !ok {
-		t.Error("Metadata should contain 'constraint'")
-	} else if constraint != ">=3.11" {
-		t.Errorf("constraint = %v, want >=3.11", constraint)
+	if result.Constraint != ">=3.11" {
+		t.Errorf("Constraint = %v, want >=3.11", result.Constraint)
 	}
 
+	// Check metadata
+
 	if depCount, ok := result.Metadata["dependency_count"]; !ok {
 		t.Error("Metadata should contain 'dependency_count'")
 	} else if depCount != "3" {
@@ -547,6 +630,25 @@ dependencies = [
 	}
 }
 
+func TestParsePyprojectToml_PoetryTableMarkers(t *testing.T) {
+	content := `[tool.poetry.dependencies]
+python = { version = "^3.11", markers = "sys_platform != 'win32'" }
+`
+
+	result, err := ParsePyprojectToml([]byte(content), "pyproject.toml")
+	if err != nil {
+		t.Fatalf("ParsePyprojectToml() error = %v", err)
+	}
+
+	// The table's version is the constraint; markers are kept as metadata
+	if result.Constraint != "^3.11" || result.VersionMax != "<4.0" {
+		t.Errorf("Constraint = %q, VersionMax = %q, want ^3.11 and <4.0", result.Constraint, result.VersionMax)
+	}
+	if got := result.Metadata["markers"]; got != "sys_platform != 'win32'" {
+		t.Errorf("markers = %q, want sys_platform != 'win32'", got)
+	}
+}
+
 func TestParsePyprojectToml_RawValue(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -9,6 +9,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+	"gopkg.in/yaml.v3"
 )
 
 // ============================================================================
@@ -40,12 +41,14 @@ func ParsePythonVersionFile(content []byte, filename string) (*rules.SearchResul
 	}
 	
 	return &rules.SearchResult{
-		Found:      true,
-		Version:    version,
-		Source:     filename,
-		Confidence: 1.0,
-		RawValue:   versionStr,
-		Metadata:   map[string]string{"source_type": "explicit_version_file"},
+		Found: true,
+		Detection: rules.Detection{
+			Version:    version,
+			Source:     filename,
+			Confidence: 1.0,
+		},
+		RawValue: versionStr,
+		Metadata: map[string]string{"source_type": "explicit_version_file"},
 	}, nil
 }
 
@@ -61,6 +64,37 @@ func GetPythonVersionFileRule() *rules.SearchRule {
 		MustBuild()
 }
 
+// DefaultExplicitVersionFiles are pin files some teams use in place of
+// .python-version. Each is read like .python-version, just after runtime.txt.
+var DefaultExplicitVersionFiles = []string{"python-version", ".python-versions"}
+
+// GetExplicitVersionFileRule returns a SearchRule that reads filename as a
+// .python-version-style pin
+func GetExplicitVersionFileRule(filename string) *rules.SearchRule {
+	return rules.NewRuleBuilder("explicit-version-file:" + filename).
+		Description(fmt.Sprintf("Extracts Python version from %s (a .python-version-style pin)", filename)).
+		Priority(3).
+		FilePattern(filename).
+		MaxFileSize(1024).
+		Parser(ParsePythonVersionFile).
+		Tags("explicit", "version-file").
+		MustBuild()
+}
+
+// RegisterExplicitVersionFiles adds a rule for each extra pin filename.
+// .python-version already has its own rule and is skipped.
+func RegisterExplicitVersionFiles(registry *rules.Registry, filenames []string) error {
+	for _, filename := range filenames {
+		if filename == "" || filename == ".python-version" {
+			continue
+		}
+		if err := registry.Register(GetExplicitVersionFileRule(filename)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ============================================================================
 // runtime.txt Parser (Heroku)
 // ============================================================================
@@ -87,12 +121,15 @@ func ParseRuntimeTxt(content []byte, filename string) (*rules.SearchResult, erro
 	}
 	
 	return &rules.SearchResult{
-		Found:      true,
-		Version:    version,
-		Source:     filename,
-		Confidence: 0.95,
-		RawValue:   string(content),
-		Metadata:   map[string]string{"source_type": "heroku_runtime"},
+		Found: true,
+		Detection: rules.Detection{
+			Version:        version,
+			Source:         filename,
+			Confidence:     0.95,
+			Implementation: "cpython",
+		},
+		RawValue: string(content),
+		Metadata: map[string]string{"source_type": "heroku_runtime"},
 	}, nil
 }
 
@@ -141,14 +178,17 @@ func ParseSetupPy(content []byte, filename string) (*rules.SearchResult, error)
 	}
 	
 	return &rules.SearchResult{
-		Found:      true,
-		Version:    version,
-		Source:     filename,
-		Confidence: 0.9,
-		RawValue:   constraint,
+		Found: true,
+		Detection: rules.Detection{
+			Version:    version,
+			Source:     filename,
+			Confidence: 0.9,
+			Constraint: constraint,
+			VersionMax: extractUpperBoundFromConstraint(constraint),
+		},
+		RawValue: constraint,
 		Metadata: map[string]string{
 			"source_type": "setup_py",
-			"constraint":  constraint,
 		},
 	}, nil
 }
@@ -216,14 +256,16 @@ func ParsePipfile(content []byte, filename string) (*rules.SearchResult, error)
 	}
 	
 	return &rules.SearchResult{
-		Found:      true,
-		Version:    version,
-		Source:     filename,
-		Confidence: 0.9,
-		RawValue:   versionStr,
+		Found: true,
+		Detection: rules.Detection{
+			Version:    version,
+			Source:     filename,
+			Confidence: 0.9,
+			Format:     "pipenv",
+		},
+		RawValue: versionStr,
 		Metadata: map[string]string{
 			"source_type": "pipfile",
-			"format":      "pipenv",
 		},
 	}, nil
 }
@@ -272,11 +314,13 @@ func ParseRequirementsTxt(content []byte, filename string) (*rules.SearchResult,
 			if len(matches) > 1 {
 				version := matches[1]
 				return &rules.SearchResult{
-					Found:      true,
-					Version:    version,
-					Source:     filename,
-					Confidence: 0.6,
-					RawValue:   line,
+					Found: true,
+					Detection: rules.Detection{
+						Version:    version,
+						Source:     filename,
+						Confidence: 0.6,
+					},
+					RawValue: line,
 					Metadata: map[string]string{
 						"source_type": "requirements_comment",
 					},
@@ -313,8 +357,14 @@ func GetRequirementsTxtRule() *rules.SearchRule {
 //   image: python:3.11-slim
 //   image: python:3.11.5-alpine
 //
+// Without a Python image, a PYTHON_VERSION or PY_VERSION entry in the
+// top-level or a job's variables section is used instead:
+//   variables:
+//     PYTHON_VERSION: "3.11"
+//
 // Returns:
 // - Confidence: 0.75 (CI configuration)
+// - Confidence: 0.7 for a version variable
 func ParseGitLabCI(content []byte, filename string) (*rules.SearchResult, error) {
 	contentStr := string(content)
 	
@@ -324,17 +374,20 @@ func ParseGitLabCI(content []byte, filename string) (*rules.SearchResult, error)
 	matches := pattern.FindStringSubmatch(contentStr)
 	
 	if len(matches) < 2 {
-		return &rules.SearchResult{Found: false}, nil
+		return parseGitLabCIVariables(content, filename), nil
 	}
 	
 	version := matches[1]
 	
 	return &rules.SearchResult{
-		Found:      true,
-		Version:    version,
-		Source:     filename,
-		Confidence: 0.75,
-		RawValue:   matches[0],
+		Found: true,
+		Detection: rules.Detection{
+			Version:        version,
+			Source:         filename,
+			Confidence:     0.75,
+			Implementation: "cpython",
+		},
+		RawValue: matches[0],
 		Metadata: map[string]string{
 			"source_type": "gitlab_ci",
 			"image":       matches[0],
@@ -342,13 +395,106 @@ func ParseGitLabCI(content []byte, filename string) (*rules.SearchResult, error)
 	}, nil
 }
 
+// gitLabCIVersionVariables are the CI variable names read as a Python
+// version, in order of preference
+var gitLabCIVersionVariables = []string{"PYTHON_VERSION", "PY_VERSION"}
+
+// parseGitLabCIVariables looks for a Python version variable in the
+// top-level variables section, then in each job's, in document order
+func parseGitLabCIVariables(content []byte, filename string) *rules.SearchResult {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil || len(doc.Content) == 0 {
+		// Malformed YAML is treated as no match
+		return &rules.SearchResult{Found: false}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return &rules.SearchResult{Found: false}
+	}
+
+	if result := gitLabCIVariableResult(yamlMapValue(root, "variables"), "", filename); result != nil {
+		return result
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		job := root.Content[i].Value
+		if job == "variables" || root.Content[i+1].Kind != yaml.MappingNode {
+			continue
+		}
+		if result := gitLabCIVariableResult(yamlMapValue(root.Content[i+1], "variables"), job, filename); result != nil {
+			return result
+		}
+	}
+
+	return &rules.SearchResult{Found: false}
+}
+
+// gitLabCIVariableResult builds a result from a variables mapping, or
+// returns nil if it holds no usable version variable
+func gitLabCIVariableResult(variables *yaml.Node, job, filename string) *rules.SearchResult {
+	if variables == nil || variables.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for _, name := range gitLabCIVersionVariables {
+		value := yamlMapValue(variables, name)
+		// Long form: PYTHON_VERSION: {value: "3.11", description: ...}
+		if value != nil && value.Kind == yaml.MappingNode {
+			value = yamlMapValue(value, "value")
+		}
+		if value == nil || value.Kind != yaml.ScalarNode {
+			continue
+		}
+
+		// Scalars are read as written, so an unquoted 3.10 stays "3.10"
+		version, err := extractPythonVersion(value.Value)
+		if err != nil {
+			continue
+		}
+
+		metadata := map[string]string{
+			"source_type": "gitlab_ci_variable",
+			"variable":    name,
+		}
+		if job != "" {
+			metadata["job"] = job
+		}
+
+		return &rules.SearchResult{
+			Found: true,
+			Detection: rules.Detection{
+				Version:    version,
+				Source:     filename,
+				Confidence: 0.7,
+			},
+			RawValue: name + ": " + value.Value,
+			Metadata: metadata,
+		}
+	}
+
+	return nil
+}
+
+// yamlMapValue returns the value node for key in a mapping node, or nil
+func yamlMapValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
 // GetGitLabCIRule returns a SearchRule for .gitlab-ci.yml
 func GetGitLabCIRule() *rules.SearchRule {
 	return rules.NewRuleBuilder("gitlab-ci").
 		Description("Extracts Python version from .gitlab-ci.yml").
 		Priority(12).
 		FilePattern(".gitlab-ci.yml").
-		RequiredContent(`image:\s*python:`).
+		RequiredContent(`image:\s*python:|PYTHON_VERSION|PY_VERSION`).
 		MaxFileSize(1024 * 1024). // 1MB
 		Parser(ParseGitLabCI).
 		Tags("ci", "gitlab", "docker").
@@ -381,11 +527,14 @@ func ParseDockerfile(content []byte, filename string) (*rules.SearchResult, erro
 		if len(matches) > 1 {
 			version := matches[1]
 			return &rules.SearchResult{
-				Found:      true,
-				Version:    version,
-				Source:     filename,
-				Confidence: 0.8,
-				RawValue:   line,
+				Found: true,
+				Detection: rules.Detection{
+					Version:        version,
+					Source:         filename,
+					Confidence:     0.8,
+					Implementation: "cpython",
+				},
+				RawValue: line,
 				Metadata: map[string]string{
 					"source_type": "dockerfile",
 					"from_image":  line,
@@ -440,11 +589,13 @@ func ParseToxIni(content []byte, filename string) (*rules.SearchResult, error) {
 			version := extractPythonVersionFromToxEnv(toxIni.Tox.EnvList)
 			if version != "" {
 				return &rules.SearchResult{
-					Found:      true,
-					Version:    version,
-					Source:     filename,
-					Confidence: 0.7,
-					RawValue:   toxIni.Tox.EnvList,
+					Found: true,
+					Detection: rules.Detection{
+						Version:    version,
+						Source:     filename,
+						Confidence: 0.7,
+					},
+					RawValue: toxIni.Tox.EnvList,
 					Metadata: map[string]string{
 						"source_type": "tox_ini",
 						"envlist":     toxIni.Tox.EnvList,
@@ -470,11 +621,13 @@ func ParseToxIni(content []byte, filename string) (*rules.SearchResult, error) {
 	}
 	
 	return &rules.SearchResult{
-		Found:      true,
-		Version:    version,
-		Source:     filename,
-		Confidence: 0.7,
-		RawValue:   envlist,
+		Found: true,
+		Detection: rules.Detection{
+			Version:    version,
+			Source:     filename,
+			Confidence: 0.7,
+		},
+		RawValue: envlist,
 		Metadata: map[string]string{
 			"source_type": "tox_ini",
 			"envlist":     envlist,
@@ -1,6 +1,7 @@
 package parsers
 
 import (
+	"context"
 	"testing"
 
 	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
@@ -114,6 +115,33 @@ func TestGetPythonVersionFileRule(t *testing.T) {
 	}
 }
 
+func TestExplicitVersionFiles(t *testing.T) {
+	registry := DefaultRegistry()
+	for _, filename := range DefaultExplicitVersionFiles {
+		rule := registry.Get("explicit-version-file:" + filename)
+		if rule == nil {
+			t.Fatalf("no built-in rule for %s", filename)
+		}
+		if rule.Priority != 3 || !rule.HasTag(rules.TagExplicit) {
+			t.Errorf("%s: priority = %d, tags = %v; want 3 and explicit", filename, rule.Priority, rule.Tags)
+		}
+	}
+
+	before := registry.Count()
+	if err := RegisterExplicitVersionFiles(registry, []string{".python-version", "PYTHON_VERSION"}); err != nil {
+		t.Fatalf("RegisterExplicitVersionFiles() error = %v", err)
+	}
+	if registry.Count() != before+1 {
+		t.Errorf("Count() = %d, want %d (.python-version already has a rule)", registry.Count(), before+1)
+	}
+
+	rule := registry.Get("explicit-version-file:PYTHON_VERSION")
+	result, err := rule.Apply(context.Background(), []byte("3.12.1\n"), "PYTHON_VERSION")
+	if err != nil || !result.Found || result.Version != "3.12.1" {
+		t.Errorf("Apply() = %+v, %v; want 3.12.1", result, err)
+	}
+}
+
 // ============================================================================
 // runtime.txt Tests
 // ============================================================================
@@ -406,6 +434,8 @@ func TestParseGitLabCI(t *testing.T) {
 		wantFound  bool
 		wantVer    string
 		wantConf   float64
+		wantVar    string
+		wantJob    string
 	}{
 		{
 			name: "python image",
@@ -445,6 +475,69 @@ func TestParseGitLabCI(t *testing.T) {
     - pytest`,
 			wantFound: false,
 		},
+		{
+			name: "top-level PYTHON_VERSION",
+			content: `variables:
+  PYTHON_VERSION: "3.11"
+test:
+  image: ubuntu:latest`,
+			wantFound: true,
+			wantVer:   "3.11",
+			wantConf:  0.7,
+			wantVar:   "PYTHON_VERSION",
+		},
+		{
+			name: "unquoted 3.10 is not read as a float",
+			content: `variables:
+  PY_VERSION: 3.10`,
+			wantFound: true,
+			wantVer:   "3.10",
+			wantConf:  0.7,
+			wantVar:   "PY_VERSION",
+		},
+		{
+			name: "job-level variable",
+			content: `build:
+  script:
+    - make
+test:
+  variables:
+    PYTHON_VERSION: "3.12.1"
+  script:
+    - pytest`,
+			wantFound: true,
+			wantVer:   "3.12.1",
+			wantConf:  0.7,
+			wantVar:   "PYTHON_VERSION",
+			wantJob:   "test",
+		},
+		{
+			name: "long-form variable",
+			content: `variables:
+  PYTHON_VERSION:
+    value: "3.9"
+    description: Interpreter used by the test jobs`,
+			wantFound: true,
+			wantVer:   "3.9",
+			wantConf:  0.7,
+			wantVar:   "PYTHON_VERSION",
+		},
+		{
+			name: "image takes precedence over variable",
+			content: `variables:
+  PYTHON_VERSION: "3.9"
+test:
+  image: python:3.11`,
+			wantFound: true,
+			wantVer:   "3.11",
+			wantConf:  0.75,
+		},
+		{
+			name: "variable without a version",
+			content: `variables:
+  PYTHON_VERSION: latest`,
+			wantFound: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -461,6 +554,18 @@ func TestParseGitLabCI(t *testing.T) {
 			if tt.wantFound && result.Version != tt.wantVer {
 				t.Errorf("Version = %v, want %v", result.Version, tt.wantVer)
 			}
+
+			if tt.wantFound && result.Confidence != tt.wantConf {
+				t.Errorf("Confidence = %v, want %v", result.Confidence, tt.wantConf)
+			}
+
+			if result.Metadata["variable"] != tt.wantVar {
+				t.Errorf("variable = %q, want %q", result.Metadata["variable"], tt.wantVar)
+			}
+
+			if result.Metadata["job"] != tt.wantJob {
+				t.Errorf("job = %q, want %q", result.Metadata["job"], tt.wantJob)
+			}
 		})
 	}
 }
@@ -742,3 +847,47 @@ func TestRulePriorities(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectionTypedFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		parse    func([]byte, string) (*rules.SearchResult, error)
+		content  string
+		filename string
+		want     rules.Detection
+	}{
+		{
+			name:     "setup.py constraint",
+			parse:    ParseSetupPy,
+			content:  `setup(name="x", python_requires=">=3.10,<4")`,
+			filename: "setup.py",
+			want:     rules.Detection{Version: "3.10", Source: "setup.py", Confidence: 0.9, Constraint: ">=3.10,<4", VersionMax: "<4"},
+		},
+		{
+			name:     "Pipfile format",
+			parse:    ParsePipfile,
+			content:  "[requires]\npython_version = \"3.11\"\n",
+			filename: "Pipfile",
+			want:     rules.Detection{Version: "3.11", Source: "Pipfile", Confidence: 0.9, Format: "pipenv"},
+		},
+		{
+			name:     "Dockerfile implementation",
+			parse:    ParseDockerfile,
+			content:  "FROM python:3.12-slim\n",
+			filename: "Dockerfile",
+			want:     rules.Detection{Version: "3.12", Source: "Dockerfile", Confidence: 0.8, Implementation: "cpython"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.parse([]byte(tt.content), tt.filename)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Detection != tt.want {
+				t.Errorf("Detection = %+v, want %+v", result.Detection, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package parsers
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+)
+
+// ReadmeBadgeTag marks the README badge rules so they can be disabled as a
+// group by users who don't trust badges to be kept up to date
+const ReadmeBadgeTag = "readme-badge"
+
+// shieldsBadgePattern matches the path of a shields.io static badge,
+// "/badge/<label>-<message>-<color>", up to the end of its URL
+var shieldsBadgePattern = regexp.MustCompile(`img\.shields\.io/badge/([^\s)\]"'<>?#]+)`)
+
+// badgeVersionPattern matches each version in a badge's message, e.g. the
+// three in "3.9 | 3.10 | 3.11"
+var badgeVersionPattern = regexp.MustCompile(`\b\d+\.\d+(?:\.\d+)?\b`)
+
+// ParseReadmeBadge extracts the Python versions a README advertises with
+// shields.io static badges labelled python (or pyversions). The lowest
+// version is reported, and every version found is recorded in the
+// "versions" metadata. Badges are often left behind when a project moves
+// on, so this is a last-resort signal.
+//
+// Dynamic pyversions badges (img.shields.io/pypi/pyversions/<package>)
+// are drawn from PyPI when the badge is viewed; their URL has no versions
+// and they are ignored.
+//
+// Format examples:
+//
+//	![Python](https://img.shields.io/badge/python-3.11-blue)
+//	![pyversions](https://img.shields.io/badge/pyversions-3.9%20%7C%203.10-blue.svg)
+//	.. image:: https://img.shields.io/badge/python-3.10%2B-blue
+//
+// Returns:
+// - Confidence: 0.5 (inferred from documentation, not a declaration)
+func ParseReadmeBadge(content []byte, filename string) (*rules.SearchResult, error) {
+	var versions []string
+	var lowest, badge string
+	seen := make(map[string]bool)
+
+	for _, match := range shieldsBadgePattern.FindAllSubmatch(content, -1) {
+		for _, version := range badgeVersions(string(match[1])) {
+			if seen[version] {
+				continue
+			}
+			seen[version] = true
+			versions = append(versions, version)
+			if lowest == "" || compareVersionParts(splitVersionParts(version), splitVersionParts(lowest)) < 0 {
+				lowest, badge = version, string(match[0])
+			}
+		}
+	}
+
+	if lowest == "" {
+		return &rules.SearchResult{Found: false}, nil
+	}
+
+	return &rules.SearchResult{
+		Found: true,
+		Detection: rules.Detection{
+			Version:    lowest,
+			Source:     filename,
+			Confidence: 0.5,
+		},
+		RawValue: badge,
+		Metadata: map[string]string{
+			"source_type": "readme_badge",
+			"versions":    strings.Join(versions, ","),
+			"inferred":    "true",
+		},
+	}, nil
+}
+
+// badgeVersions returns the versions in a static badge's path if it's a
+// Python badge, e.g. ["3.11"] for "python-3.11-blue". Shields escapes a
+// literal dash as "--" and a space as "_" or "%20".
+func badgeVersions(badge string) []string {
+	fields := strings.Split(strings.ReplaceAll(badge, "--", "\x00"), "-")
+	var label, message string
+	switch len(fields) {
+	case 2:
+		// A badge without a label, e.g. "python 3.11-blue"
+		label, message = fields[0], fields[0]
+	case 3:
+		label, message = fields[0], fields[1]
+	default:
+		return nil
+	}
+
+	label = strings.ToLower(badgeText(label))
+	if !strings.HasPrefix(label, "python") && !strings.HasPrefix(label, "pyversions") {
+		return nil
+	}
+	return badgeVersionPattern.FindAllString(badgeText(message), -1)
+}
+
+// badgeText undoes a badge field's escaping
+func badgeText(field string) string {
+	field = strings.ReplaceAll(strings.ReplaceAll(field, "\x00", "-"), "_", " ")
+	if text, err := url.PathUnescape(field); err == nil {
+		return text
+	}
+	return field
+}
+
+// GetReadmeBadgeRule returns a SearchRule for badges in README.md
+func GetReadmeBadgeRule() *rules.SearchRule {
+	return readmeBadgeRule("readme-badge", "README.md")
+}
+
+// GetReadmeRstBadgeRule returns a SearchRule for badges in README.rst, the
+// usual README of projects documented with Sphinx
+func GetReadmeRstBadgeRule() *rules.SearchRule {
+	return readmeBadgeRule("readme-rst-badge", "README.rst")
+}
+
+func readmeBadgeRule(name, filename string) *rules.SearchRule {
+	return rules.NewRuleBuilder(name).
+		Description("Infers Python version from shields.io python badges in "+filename).
+		Priority(23). // Last resort - inferred from documentation
+		FilePattern(filename).
+		RequiredContent(`img\.shields\.io/badge/`).
+		MaxFileSize(1024*1024). // 1MB
+		Parser(ParseReadmeBadge).
+		Tags(ReadmeBadgeTag, "docs", "inferred").
+		MustBuild()
+}
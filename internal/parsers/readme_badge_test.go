@@ -0,0 +1,115 @@
+package parsers
+
+import (
+	"testing"
+)
+
+func TestParseReadmeBadge(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantFound    bool
+		wantVer      string
+		wantVersions string
+	}{
+		{
+			name:         "markdown badge",
+			content:      "# billing\n\n[![Python](https://img.shields.io/badge/python-3.11-blue.svg)](https://www.python.org/)\n",
+			wantFound:    true,
+			wantVer:      "3.11",
+			wantVersions: "3.11",
+		},
+		{
+			name:         "pyversions list reports the lowest",
+			content:      "![pyversions](https://img.shields.io/badge/pyversions-3.10%20%7C%203.9%20%7C%203.11-blue)\n",
+			wantFound:    true,
+			wantVer:      "3.9",
+			wantVersions: "3.10,3.9,3.11",
+		},
+		{
+			name:         "rst badge with a plus",
+			content:      ".. image:: https://img.shields.io/badge/Python-3.10%2B-blue\n   :alt: Python 3.10+\n",
+			wantFound:    true,
+			wantVer:      "3.10",
+			wantVersions: "3.10",
+		},
+		{
+			name:         "escaped dash and underscores",
+			content:      "![](https://img.shields.io/badge/python_version-3.8--3.12-green)\n",
+			wantFound:    true,
+			wantVer:      "3.8",
+			wantVersions: "3.8,3.12",
+		},
+		{
+			name:         "badge without a label",
+			content:      "![](https://img.shields.io/badge/python%203.12-blue)\n",
+			wantFound:    true,
+			wantVer:      "3.12",
+			wantVersions: "3.12",
+		},
+		{
+			name:         "versions from several badges",
+			content:      "![](https://img.shields.io/badge/python-3.12-blue) ![](https://img.shields.io/badge/python-3.11-blue) ![](https://img.shields.io/badge/python-3.12-blue)\n",
+			wantFound:    true,
+			wantVer:      "3.11",
+			wantVersions: "3.12,3.11",
+		},
+		{
+			name:      "other badges",
+			content:   "![](https://img.shields.io/badge/coverage-95.5%25-green) ![](https://img.shields.io/badge/node-18.2-green)\n",
+			wantFound: false,
+		},
+		{
+			name:      "dynamic pyversions badge has no versions",
+			content:   "![](https://img.shields.io/pypi/pyversions/requests)\n",
+			wantFound: false,
+		},
+		{
+			name:      "version only in prose",
+			content:   "Requires Python 3.11 or later.\n",
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseReadmeBadge([]byte(tt.content), "README.md")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Found != tt.wantFound {
+				t.Fatalf("Found = %v, want %v", result.Found, tt.wantFound)
+			}
+			if !tt.wantFound {
+				return
+			}
+
+			if result.Version != tt.wantVer {
+				t.Errorf("Version = %q, want %q", result.Version, tt.wantVer)
+			}
+			if got := result.Metadata["versions"]; got != tt.wantVersions {
+				t.Errorf("versions = %q, want %q", got, tt.wantVersions)
+			}
+			if result.Confidence != 0.5 {
+				t.Errorf("Confidence = %v, want 0.5", result.Confidence)
+			}
+		})
+	}
+}
+
+func TestReadmeBadgeRulesDisabledByTag(t *testing.T) {
+	registry := DefaultRegistry()
+	if n := registry.DisableByTag(ReadmeBadgeTag); n != 2 {
+		t.Errorf("DisableByTag(%q) disabled %d rules, want 2", ReadmeBadgeTag, n)
+	}
+	for _, rule := range registry.ListEnabled() {
+		if rule.HasTag(ReadmeBadgeTag) {
+			t.Errorf("rule %s still enabled", rule.Name)
+		}
+	}
+
+	if !GetReadmeRstBadgeRule().Matches("README.rst", "docs/README.rst") {
+		t.Error("expected rule to match README.rst")
+	}
+}
@@ -0,0 +1,92 @@
+package parsers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+	"gopkg.in/yaml.v3"
+)
+
+// ReadTheDocsConfig represents the parts of .readthedocs.yaml we care about
+type ReadTheDocsConfig struct {
+	Build struct {
+		Tools struct {
+			Python string `yaml:"python"`
+		} `yaml:"tools"`
+	} `yaml:"build"`
+}
+
+// readTheDocsPythonPattern matches CPython tool versions like "3" or "3.11".
+// Conda-based tools such as "miniconda3-4.7" and "mambaforge-22.9" say
+// nothing about the Python version and are ignored.
+var readTheDocsPythonPattern = regexp.MustCompile(`^\d+(?:\.\d+)*$`)
+
+// ParseReadTheDocs extracts a Python version from build.tools.python in a
+// Read the Docs configuration file.
+//
+// Format examples:
+//
+//	build:
+//	  os: ubuntu-22.04
+//	  tools:
+//	    python: "3.11"
+//
+// Returns:
+// - Confidence: 0.7 for a major.minor version (e.g. "3.11")
+// - Confidence: 0.4 for a major-only version (e.g. "3")
+func ParseReadTheDocs(content []byte, filename string) (*rules.SearchResult, error) {
+	var config ReadTheDocsConfig
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		// Return no match instead of error for malformed YAML
+		return &rules.SearchResult{Found: false}, nil
+	}
+
+	version := strings.TrimSpace(config.Build.Tools.Python)
+	if !readTheDocsPythonPattern.MatchString(version) {
+		return &rules.SearchResult{Found: false}, nil
+	}
+
+	confidence := 0.7
+	metadata := map[string]string{"source_type": "readthedocs"}
+
+	// "3" only pins the major version
+	if !strings.Contains(version, ".") {
+		confidence = 0.4
+		metadata["major_only"] = "true"
+	}
+
+	return &rules.SearchResult{
+		Found: true,
+		Detection: rules.Detection{
+			Version:    version,
+			Source:     filename,
+			Confidence: confidence,
+		},
+		RawValue: config.Build.Tools.Python,
+		Metadata: metadata,
+	}, nil
+}
+
+// GetReadTheDocsRule returns a SearchRule for .readthedocs.yaml
+func GetReadTheDocsRule() *rules.SearchRule {
+	return readTheDocsRule("readthedocs", ".readthedocs.yaml")
+}
+
+// GetReadTheDocsYmlRule returns a SearchRule for .readthedocs.yml, the
+// other file name Read the Docs accepts
+func GetReadTheDocsYmlRule() *rules.SearchRule {
+	return readTheDocsRule("readthedocs-yml", ".readthedocs.yml")
+}
+
+func readTheDocsRule(name, filename string) *rules.SearchRule {
+	return rules.NewRuleBuilder(name).
+		Description("Extracts Python version from build.tools.python in "+filename).
+		Priority(17).
+		FilePattern(filename).
+		RequiredContent(`python`).
+		MaxFileSize(1024*1024). // 1MB
+		Parser(ParseReadTheDocs).
+		Tags("config", "docs", "readthedocs").
+		MustBuild()
+}
@@ -0,0 +1,95 @@
+package parsers
+
+import (
+	"testing"
+)
+
+func TestParseReadTheDocs(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		wantFound      bool
+		wantVer        string
+		wantConfidence float64
+	}{
+		{
+			name: "minor version",
+			content: `version: 2
+build:
+  os: ubuntu-22.04
+  tools:
+    python: "3.11"
+sphinx:
+  configuration: docs/conf.py
+`,
+			wantFound:      true,
+			wantVer:        "3.11",
+			wantConfidence: 0.7,
+		},
+		{
+			name:           "unquoted version keeps its trailing zero",
+			content:        "build:\n  tools:\n    python: 3.10\n",
+			wantFound:      true,
+			wantVer:        "3.10",
+			wantConfidence: 0.7,
+		},
+		{
+			name:           "major only",
+			content:        "build:\n  tools:\n    python: \"3\"\n",
+			wantFound:      true,
+			wantVer:        "3",
+			wantConfidence: 0.4,
+		},
+		{
+			name:      "conda tool",
+			content:   "build:\n  tools:\n    python: \"miniconda3-4.7\"\n",
+			wantFound: false,
+		},
+		{
+			name:      "no build tools",
+			content:   "version: 2\npython:\n  install:\n    - requirements: docs/requirements.txt\n",
+			wantFound: false,
+		},
+		{
+			name:      "malformed yaml",
+			content:   "build: [tools: \n",
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseReadTheDocs([]byte(tt.content), ".readthedocs.yaml")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Found != tt.wantFound {
+				t.Fatalf("Found = %v, want %v", result.Found, tt.wantFound)
+			}
+			if !tt.wantFound {
+				return
+			}
+
+			if result.Version != tt.wantVer {
+				t.Errorf("Version = %q, want %q", result.Version, tt.wantVer)
+			}
+			if result.Confidence != tt.wantConfidence {
+				t.Errorf("Confidence = %v, want %v", result.Confidence, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestReadTheDocsRules(t *testing.T) {
+	registry := DefaultRegistry()
+	for name, filename := range map[string]string{"readthedocs": ".readthedocs.yaml", "readthedocs-yml": ".readthedocs.yml"} {
+		rule := registry.Get(name)
+		if rule == nil {
+			t.Fatalf("rule %q not registered", name)
+		}
+		if rule.Condition.FilePattern != filename {
+			t.Errorf("rule %q FilePattern = %q, want %q", name, rule.Condition.FilePattern, filename)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package parsers
 
 import (
+	"strings"
+
 	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
 )
 
@@ -12,13 +14,28 @@ func DefaultRegistry() *rules.Registry {
 	// Register all built-in parsers (in priority order)
 	registry.MustRegister(GetPythonVersionFileRule())       // Priority 1
 	registry.MustRegister(GetRuntimeTxtRule())              // Priority 2
+	for _, filename := range DefaultExplicitVersionFiles {
+		registry.MustRegister(GetExplicitVersionFileRule(filename)) // Priority 3
+	}
 	registry.MustRegister(GetSetupPyRule())                 // Priority 8
 	registry.MustRegister(GetPipfileRule())                 // Priority 9
 	registry.MustRegister(GetPyprojectTomlRule())           // Priority 10
 	registry.MustRegister(GetDockerfileRule())              // Priority 11
 	registry.MustRegister(GetGitLabCIRule())                // Priority 12
 	registry.MustRegister(GetToxIniRule())                  // Priority 13
+	registry.MustRegister(GetPreCommitRule())               // Priority 14
 	registry.MustRegister(GetRequirementsTxtDependencyRule()) // Priority 15
+	registry.MustRegister(GetEnvrcRule())                   // Priority 16
+	registry.MustRegister(GetReadTheDocsRule())             // Priority 17
+	registry.MustRegister(GetReadTheDocsYmlRule())          // Priority 17
+	registry.MustRegister(GetPlatformAppYamlRule())         // Priority 18
+	registry.MustRegister(GetAppJSONRule())                 // Priority 18
+	registry.MustRegister(GetSdkmanrcRule())                // Priority 19
+	registry.MustRegister(GetVagrantfileRule())             // Priority 20
+	registry.MustRegister(GetAnsiblePlaybookRule())         // Priority 21
+	registry.MustRegister(GetAnsibleDirectoryRule())        // Priority 22
+	registry.MustRegister(GetReadmeBadgeRule())             // Priority 23
+	registry.MustRegister(GetReadmeRstBadgeRule())          // Priority 23
 	
 	return registry
 }
@@ -35,7 +52,19 @@ func RegisterBuiltInParsers(registry *rules.Registry) error {
 		GetDockerfileRule,
 		GetGitLabCIRule,
 		GetToxIniRule,
+		GetPreCommitRule,
 		GetRequirementsTxtDependencyRule,
+		GetEnvrcRule,
+		GetReadTheDocsRule,
+		GetReadTheDocsYmlRule,
+		GetPlatformAppYamlRule,
+		GetAppJSONRule,
+		GetSdkmanrcRule,
+		GetVagrantfileRule,
+		GetAnsiblePlaybookRule,
+		GetAnsibleDirectoryRule,
+		GetReadmeBadgeRule,
+		GetReadmeRstBadgeRule,
 	}
 	
 	for _, getRule := range parsers {
@@ -44,5 +73,37 @@ func RegisterBuiltInParsers(registry *rules.Registry) error {
 		}
 	}
 	
-	return nil
+	return RegisterExplicitVersionFiles(registry, DefaultExplicitVersionFiles)
+}
+
+// staticConfidence is the confidence each built-in rule reports for every
+// detection. Rules whose confidence depends on what they match (e.g. envrc,
+// gitlab-ci) are left out. TestStaticConfidence checks each entry against
+// its parser, so add an example there with every new entry.
+var staticConfidence = map[string]float64{
+	"python-version-file": 1.0,
+	"runtime-txt":         0.95,
+	"setup-py":            0.9,
+	"pipfile":             0.9,
+	"pyproject-toml":      0.9,
+	"dockerfile":          0.8,
+	"tox-ini":             0.7,
+	"platform-app-yaml":   0.7,
+	"app-json":            0.7,
+	"vagrantfile":         0.5,
+	"ansible-playbook":    0.5,
+	"ansible-yaml":        0.5,
+	"readme-badge":        0.5,
+	"readme-rst-badge":    0.5,
+}
+
+// StaticConfidence returns the confidence a built-in rule always reports.
+// ok is false for rules whose confidence varies by match and for rules that
+// aren't built in.
+func StaticConfidence(name string) (confidence float64, ok bool) {
+	if strings.HasPrefix(name, "explicit-version-file:") {
+		return 1.0, true
+	}
+	confidence, ok = staticConfidence[name]
+	return confidence, ok
 }
@@ -0,0 +1,64 @@
+package parsers
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// staticConfidenceExamples is an input each rule with a static confidence
+// detects a version in
+var staticConfidenceExamples = map[string]string{
+	"python-version-file": "3.11.5\n",
+	"runtime-txt":         "python-3.11.5\n",
+	"setup-py":            "setup(\n    name='billing',\n    python_requires='>=3.10',\n)\n",
+	"pipfile":             "[requires]\npython_version = \"3.11\"\n",
+	"pyproject-toml":      "[project]\nname = \"billing\"\nrequires-python = \">=3.10\"\n",
+	"dockerfile":          "FROM python:3.11-slim\n",
+	"tox-ini":             "[tox]\nenvlist = py311,py312\n",
+	"platform-app-yaml":   "type: \"python:3.11\"\n",
+	"app-json":            `{"env": {"PYTHON_VERSION": {"value": "3.11.4"}}}`,
+	"vagrantfile":         "config.vm.provision \"shell\", inline: \"apt-get install -y python3.12\"\n",
+	"ansible-playbook":    "- apt: name=python3.11 state=present\n",
+	"ansible-yaml":        "- python3.10-venv\n",
+	"readme-badge":        "![Python](https://img.shields.io/badge/python-3.11-blue)\n",
+	"readme-rst-badge":    ".. image:: https://img.shields.io/badge/python-3.10%2B-blue\n",
+}
+
+// TestStaticConfidence checks the confidence describe-rules publishes for
+// each rule against what the rule's parser reports
+func TestStaticConfidence(t *testing.T) {
+	registry := DefaultRegistry()
+	for name := range staticConfidence {
+		if registry.Get(name) == nil {
+			t.Errorf("StaticConfidence lists %s, which isn't a built-in rule", name)
+		}
+	}
+
+	for _, rule := range registry.List() {
+		want, ok := StaticConfidence(rule.Name)
+		if !ok {
+			continue
+		}
+		t.Run(rule.Name, func(t *testing.T) {
+			example, ok := staticConfidenceExamples[rule.Name]
+			if strings.HasPrefix(rule.Name, "explicit-version-file:") {
+				example, ok = "3.11\n", true
+			}
+			if !ok {
+				t.Fatal("no example input for a rule with a static confidence")
+			}
+
+			result, err := rule.Apply(context.Background(), []byte(example), rule.Condition.FilePattern)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if !result.Found {
+				t.Fatal("example input not detected")
+			}
+			if result.Confidence != want {
+				t.Errorf("Confidence = %v, StaticConfidence() = %v", result.Confidence, want)
+			}
+		})
+	}
+}
@@ -95,7 +95,9 @@ func ParseRequirementsTxtDependencies(content []byte, filename string) (*rules.S
 	
 	// Build result
 	result := &rules.SearchResult{
-		Source:   filename,
+		Detection: rules.Detection{
+			Source: filename,
+		},
 		Metadata: make(map[string]string),
 	}
 	
@@ -150,6 +152,29 @@ func ParseRequirementsTxtDependencies(content []byte, filename string) (*rules.S
 	return result, nil
 }
 
+// ParseRequirements returns the package requirements declared in a
+// requirements.txt file. Editable installs, nested -r files, options, and
+// lines that cannot be parsed are skipped.
+func ParseRequirements(content []byte) []Requirement {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	var requirements []Requirement
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		req, err := parseRequirementLine(line)
+		if err != nil || req.IsEditable || req.IsRequirementFile {
+			continue
+		}
+		requirements = append(requirements, *req)
+	}
+
+	return requirements
+}
+
 // parseRequirementLine parses a single line from requirements.txt
 func parseRequirementLine(line string) (*Requirement, error) {
 	if line == "" {
@@ -509,3 +509,30 @@ requests>=2.28.0`,
 		})
 	}
 }
+
+func TestParseRequirements(t *testing.T) {
+	content := `# Python 3.11
+--index-url https://pypi.example.com/simple
+-r base.txt
+-e git+https://github.com/example/pkg.git#egg=pkg
+Django==4.2.7  # web framework
+requests[security]>=2.28.0; python_version >= "3.8"
+pytest
+`
+
+	reqs := ParseRequirements([]byte(content))
+
+	want := []struct{ name, specifier string }{
+		{"Django", "==4.2.7"},
+		{"requests", ">=2.28.0"},
+		{"pytest", ""},
+	}
+	if len(reqs) != len(want) {
+		t.Fatalf("got %d requirements, want %d: %+v", len(reqs), len(want), reqs)
+	}
+	for i, w := range want {
+		if reqs[i].Name != w.name || reqs[i].Specifier != w.specifier {
+			t.Errorf("requirement %d = %s%s, want %s%s", i, reqs[i].Name, reqs[i].Specifier, w.name, w.specifier)
+		}
+	}
+}
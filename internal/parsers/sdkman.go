@@ -0,0 +1,85 @@
+package parsers
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+)
+
+// sdkmanVersionPattern matches the numeric part of an SDKMAN version, which
+// may carry a wildcard patch or a vendor suffix: "3.11.x", "3.12.1-cpython"
+var sdkmanVersionPattern = regexp.MustCompile(`^(\d+(?:\.\d+)*)(?:\.x)?(?:-\S*)?$`)
+
+// ParseSdkmanrc extracts a Python version from an SDKMAN .sdkmanrc, which
+// pins one candidate=version per line. Other candidates (java, gradle, ...)
+// are ignored.
+//
+// Format examples:
+//
+//	java=17.0.2-tem
+//	python=3.11.x
+//
+// Returns:
+// - Confidence: 0.75 for a major.minor version (e.g. 3.11.x)
+// - Confidence: 0.4 for a major-only version (e.g. 3)
+func ParseSdkmanrc(content []byte, filename string) (*rules.SearchResult, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		candidate, raw, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(candidate) != "python" {
+			continue
+		}
+		raw = strings.TrimSpace(raw)
+		matches := sdkmanVersionPattern.FindStringSubmatch(raw)
+		if len(matches) < 2 {
+			continue
+		}
+
+		version := matches[1]
+		confidence := 0.75
+		metadata := map[string]string{
+			"source_type": "sdkman",
+			"candidate":   raw,
+		}
+
+		// "python=3" only pins the major version
+		if !strings.Contains(version, ".") {
+			confidence = 0.4
+			metadata["major_only"] = "true"
+		}
+
+		return &rules.SearchResult{
+			Found: true,
+			Detection: rules.Detection{
+				Version:    version,
+				Source:     filename,
+				Confidence: confidence,
+			},
+			RawValue: line,
+			Metadata: metadata,
+		}, nil
+	}
+
+	return &rules.SearchResult{Found: false}, nil
+}
+
+// GetSdkmanrcRule returns a SearchRule for SDKMAN .sdkmanrc files
+func GetSdkmanrcRule() *rules.SearchRule {
+	return rules.NewRuleBuilder("sdkmanrc").
+		Description("Extracts Python version from the python candidate in an SDKMAN .sdkmanrc").
+		Priority(19).
+		FilePattern(".sdkmanrc").
+		RequiredContent(`(?m)^\s*python\s*=`).
+		MaxFileSize(1024*1024). // 1MB
+		Parser(ParseSdkmanrc).
+		Tags("config", "sdkman").
+		MustBuild()
+}
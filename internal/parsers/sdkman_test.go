@@ -0,0 +1,86 @@
+package parsers
+
+import (
+	"testing"
+)
+
+func TestParseSdkmanrc(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		wantFound      bool
+		wantVer        string
+		wantConfidence float64
+	}{
+		{
+			name:           "wildcard patch alongside JVM candidates",
+			content:        "# Enable auto-env through the sdkman_auto_env config\njava=17.0.2-tem\ngradle=8.5\npython=3.11.x\n",
+			wantFound:      true,
+			wantVer:        "3.11",
+			wantConfidence: 0.75,
+		},
+		{
+			name:           "full version with vendor suffix",
+			content:        "python = 3.12.1-cpython\n",
+			wantFound:      true,
+			wantVer:        "3.12.1",
+			wantConfidence: 0.75,
+		},
+		{
+			name:           "major only",
+			content:        "python=3\n",
+			wantFound:      true,
+			wantVer:        "3",
+			wantConfidence: 0.4,
+		},
+		{
+			name:      "no python candidate",
+			content:   "java=21.0.1-tem\nmaven=3.9.6\n",
+			wantFound: false,
+		},
+		{
+			name:      "commented out",
+			content:   "#python=3.11.x\n",
+			wantFound: false,
+		},
+		{
+			name:      "similar candidate name",
+			content:   "jython=2.7.3\n",
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseSdkmanrc([]byte(tt.content), ".sdkmanrc")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Found != tt.wantFound {
+				t.Fatalf("Found = %v, want %v", result.Found, tt.wantFound)
+			}
+			if !tt.wantFound {
+				return
+			}
+
+			if result.Version != tt.wantVer {
+				t.Errorf("Version = %q, want %q", result.Version, tt.wantVer)
+			}
+			if result.Confidence != tt.wantConfidence {
+				t.Errorf("Confidence = %v, want %v", result.Confidence, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestGetSdkmanrcRule(t *testing.T) {
+	rule := GetSdkmanrcRule()
+
+	if !rule.Matches(".sdkmanrc", ".sdkmanrc") {
+		t.Error("expected rule to match .sdkmanrc")
+	}
+	if rule.Matches(".sdkman", ".sdkman") {
+		t.Error("expected rule not to match .sdkman")
+	}
+}
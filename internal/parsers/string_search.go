@@ -1,9 +1,12 @@
 package parsers
 
 import (
+	"bytes"
 	"fmt"
 	"regexp"
+	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
 	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
@@ -17,9 +20,37 @@ type StringSearchParser struct {
 	ContextLines  int    // Number of context lines before/after each match
 	MaxMatches    int    // Maximum matches to return (0 = unlimited)
 
+	// Prefilter is a literal that every regex match must contain. Content
+	// without it is skipped before any line is matched. Literal searches
+	// always prefilter on SearchTerm itself.
+	Prefilter string
+
+	// ParallelThreshold is the content size in bytes from which lines are
+	// matched by several goroutines at once (0 = DefaultParallelThreshold,
+	// negative = never)
+	ParallelThreshold int
+
+	// VersionFilter keeps only regex matches whose captured version
+	// satisfies it, e.g. "<4.0" to find outdated pins (zero = keep all)
+	VersionFilter output.VersionFilter
+
+	// VersionGroup names the capture group holding the version; the first
+	// version-like token in it is compared. Empty means the first group.
+	VersionGroup string
+
 	compiled *regexp.Regexp // Compiled regex (set on first use)
 }
 
+// versionToken finds a dotted version inside a captured group, so groups
+// like "==3.2.1" or "v3.2" still compare as 3.2.1 and 3.2
+var versionToken = regexp.MustCompile(`\d+(?:\.\d+)*`)
+
+// DefaultParallelThreshold is the content size above which Search splits
+// the lines across goroutines; smaller files aren't worth the overhead. It
+// is well under the content search's default 1MB file size limit, so the
+// files a search fetches can reach it.
+const DefaultParallelThreshold = 256 * 1024
+
 // Search finds all occurrences of the search term in the given content
 func (p *StringSearchParser) Search(content []byte, filename string) ([]output.ContentMatchEntry, error) {
 	if p.SearchTerm == "" {
@@ -30,16 +61,68 @@ func (p *StringSearchParser) Search(content []byte, filename string) ([]output.C
 		return nil, err
 	}
 
+	if !p.mayMatch(content) {
+		return nil, nil
+	}
+
 	lines := strings.Split(string(content), "\n")
+
+	threshold := p.ParallelThreshold
+	if threshold == 0 {
+		threshold = DefaultParallelThreshold
+	}
+	if workers := runtime.GOMAXPROCS(0); threshold > 0 && len(content) >= threshold && workers > 1 {
+		return p.searchParallel(lines, filename, workers), nil
+	}
+	return p.searchLines(lines, 0, len(lines), filename), nil
+}
+
+// searchParallel matches lines in contiguous chunks, one goroutine per chunk,
+// and merges the results in line order. Matching is per line, so chunks
+// split at line boundaries need no overlap, and context is read from the
+// whole file.
+func (p *StringSearchParser) searchParallel(lines []string, filename string, workers int) []output.ContentMatchEntry {
+	chunkSize := (len(lines) + workers - 1) / workers
+	chunks := make([][]output.ContentMatchEntry, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * chunkSize
+		end := min(start+chunkSize, len(lines))
+		if start >= end {
+			break
+		}
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			chunks[w] = p.searchLines(lines, start, end, filename)
+		}(w, start, end)
+	}
+	wg.Wait()
+
 	var matches []output.ContentMatchEntry
+	for _, chunk := range chunks {
+		matches = append(matches, chunk...)
+		// Each chunk stops at MaxMatches, so the first chunks fill the limit
+		if p.MaxMatches > 0 && len(matches) >= p.MaxMatches {
+			return matches[:p.MaxMatches]
+		}
+	}
+	return matches
+}
 
-	for i, line := range lines {
+// searchLines matches lines[start:end], stopping at MaxMatches
+func (p *StringSearchParser) searchLines(lines []string, start, end int, filename string) []output.ContentMatchEntry {
+	var matches []output.ContentMatchEntry
+
+	for i := start; i < end; i++ {
+		line := lines[i]
 		var matched bool
 		var matchedText string
 
 		if p.compiled != nil {
-			loc := p.compiled.FindStringIndex(line)
-			if loc != nil {
+			loc := p.compiled.FindStringSubmatchIndex(line)
+			if loc != nil && p.versionMatches(line, loc) {
 				matched = true
 				matchedText = line[loc[0]:loc[1]]
 			}
@@ -58,12 +141,16 @@ func (p *StringSearchParser) Search(content []byte, filename string) ([]output.C
 		}
 
 		if matched {
-			matches = append(matches, output.ContentMatchEntry{
+			match := output.ContentMatchEntry{
 				FilePath:    filename,
 				LineNumber:  i + 1,
 				LineContent: strings.TrimRight(line, "\r"),
 				MatchedText: matchedText,
-			})
+			}
+			if p.ContextLines > 0 {
+				match.ContextBefore, match.ContextAfter = ContextAround(lines, i, p.ContextLines)
+			}
+			matches = append(matches, match)
 
 			if p.MaxMatches > 0 && len(matches) >= p.MaxMatches {
 				break
@@ -71,7 +158,19 @@ func (p *StringSearchParser) Search(content []byte, filename string) ([]output.C
 		}
 	}
 
-	return matches, nil
+	return matches
+}
+
+// ContextAround returns up to n lines on each side of lines[i], with
+// trailing carriage returns removed
+func ContextAround(lines []string, i, n int) (before, after []string) {
+	for _, line := range lines[max(0, i-n):i] {
+		before = append(before, strings.TrimRight(line, "\r"))
+	}
+	for _, line := range lines[i+1 : min(len(lines), i+1+n)] {
+		after = append(after, strings.TrimRight(line, "\r"))
+	}
+	return before, after
 }
 
 // AsParserFunc returns a rules.ParserFunc adapter for use in the existing rule engine
@@ -87,9 +186,11 @@ func (p *StringSearchParser) AsParserFunc() rules.ParserFunc {
 		}
 
 		return &rules.SearchResult{
-			Found:    true,
-			Version:  matches[0].MatchedText,
-			Source:   filename,
+			Found: true,
+			Detection: rules.Detection{
+				Version: matches[0].MatchedText,
+				Source:  filename,
+			},
 			RawValue: matches[0].LineContent,
 			Metadata: map[string]string{
 				"match_count": fmt.Sprintf("%d", len(matches)),
@@ -99,9 +200,49 @@ func (p *StringSearchParser) AsParserFunc() rules.ParserFunc {
 	}
 }
 
+// mayMatch reports whether content could contain a match, checking the raw
+// bytes for a required literal before the per-line search runs
+func (p *StringSearchParser) mayMatch(content []byte) bool {
+	literal := p.Prefilter
+	if !p.IsRegex {
+		literal = p.SearchTerm
+	}
+	if literal == "" {
+		return true
+	}
+
+	if p.CaseSensitive {
+		return bytes.Contains(content, []byte(literal))
+	}
+	return bytes.Contains(bytes.ToLower(content), []byte(strings.ToLower(literal)))
+}
+
+// versionMatches reports whether the version a regex match captured
+// satisfies VersionFilter; loc is the match's FindStringSubmatchIndex. A
+// match whose group captured nothing version-like is dropped.
+func (p *StringSearchParser) versionMatches(line string, loc []int) bool {
+	if p.VersionFilter.IsZero() {
+		return true
+	}
+
+	group := 1
+	if p.VersionGroup != "" {
+		group = p.compiled.SubexpIndex(p.VersionGroup)
+	}
+	start, end := loc[2*group], loc[2*group+1]
+	if start < 0 {
+		return false
+	}
+	token := versionToken.FindString(line[start:end])
+	return token != "" && p.VersionFilter.Matches(token)
+}
+
 // ensureCompiled compiles the regex pattern if needed
 func (p *StringSearchParser) ensureCompiled() error {
 	if !p.IsRegex {
+		if !p.VersionFilter.IsZero() {
+			return fmt.Errorf("a version filter requires a regex search")
+		}
 		return nil
 	}
 	if p.compiled != nil {
@@ -113,10 +254,31 @@ func (p *StringSearchParser) ensureCompiled() error {
 		pattern = "(?i)" + pattern
 	}
 
-	var err error
-	p.compiled, err = regexp.Compile(pattern)
+	compiled, err := regexp.Compile(pattern)
 	if err != nil {
 		return fmt.Errorf("invalid regex pattern %q: %w", p.SearchTerm, err)
 	}
+	if !p.VersionFilter.IsZero() {
+		if err := CheckVersionGroup(compiled, p.VersionGroup); err != nil {
+			return err
+		}
+	}
+	p.compiled = compiled
+	return nil
+}
+
+// CheckVersionGroup returns an error if pattern has no capture group for a
+// version filter to compare: the one named group, or any group when group
+// is empty
+func CheckVersionGroup(pattern *regexp.Regexp, group string) error {
+	if group != "" {
+		if pattern.SubexpIndex(group) < 0 {
+			return fmt.Errorf("regex has no capture group named %q", group)
+		}
+		return nil
+	}
+	if pattern.NumSubexp() == 0 {
+		return fmt.Errorf("regex needs a capture group holding the version to filter on")
+	}
 	return nil
 }
@@ -1,7 +1,13 @@
 package parsers
 
 import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
 )
 
 func TestStringSearchParser_LiteralSearch(t *testing.T) {
@@ -124,6 +130,38 @@ func TestStringSearchParser_MaxMatches(t *testing.T) {
 	}
 }
 
+func TestStringSearchParser_ContextLines(t *testing.T) {
+	parser := &StringSearchParser{
+		SearchTerm:   "needle",
+		ContextLines: 2,
+	}
+
+	content := []byte("one\r\ntwo\nthree\nneedle\nfive\n")
+	matches, err := parser.Search(content, "test.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+
+	m := matches[0]
+	if strings.Join(m.ContextBefore, "|") != "two|three" {
+		t.Errorf("ContextBefore = %q, want [two three]", m.ContextBefore)
+	}
+	// The final empty line after the trailing newline counts as context
+	if strings.Join(m.ContextAfter, "|") != "five|" {
+		t.Errorf("ContextAfter = %q, want [five \"\"]", m.ContextAfter)
+	}
+
+	// No context is collected unless requested
+	parser = &StringSearchParser{SearchTerm: "needle"}
+	matches, _ = parser.Search(content, "test.txt")
+	if matches[0].ContextBefore != nil || matches[0].ContextAfter != nil {
+		t.Errorf("expected no context, got %q / %q", matches[0].ContextBefore, matches[0].ContextAfter)
+	}
+}
+
 func TestStringSearchParser_NoMatch(t *testing.T) {
 	parser := &StringSearchParser{
 		SearchTerm: "nonexistent",
@@ -178,6 +216,56 @@ func TestStringSearchParser_InvalidRegex(t *testing.T) {
 	}
 }
 
+func TestStringSearchParser_VersionFilter(t *testing.T) {
+	content := []byte("Django==3.2.18\ndjango>=4.1\ndjango-filter==2.4.0\nrequests==2.31.0\ndjango  # unpinned\n")
+
+	parser := &StringSearchParser{
+		SearchTerm:    `^django\s*[=<>~!]=?\s*(?P<ver>[\d.]+)`,
+		IsRegex:       true,
+		VersionFilter: output.VersionFilter{Op: "<", Version: "4.0"},
+		VersionGroup:  "ver",
+	}
+	matches, err := parser.Search(content, "requirements.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].LineNumber != 1 {
+		t.Fatalf("matches = %+v, want only line 1 (Django==3.2.18)", matches)
+	}
+
+	// Without a group name the first group is compared
+	parser = &StringSearchParser{
+		SearchTerm:    `^django\s*[=<>~!]=?\s*([\d.]+)`,
+		IsRegex:       true,
+		VersionFilter: output.VersionFilter{Op: ">=", Version: "4.0"},
+	}
+	matches, err = parser.Search(content, "requirements.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].LineNumber != 2 {
+		t.Fatalf("matches = %+v, want only line 2 (django>=4.1)", matches)
+	}
+}
+
+func TestStringSearchParser_VersionFilterErrors(t *testing.T) {
+	filter := output.VersionFilter{Op: "<", Version: "4.0"}
+	tests := []struct {
+		name   string
+		parser *StringSearchParser
+	}{
+		{"literal search", &StringSearchParser{SearchTerm: "django", VersionFilter: filter}},
+		{"no capture group", &StringSearchParser{SearchTerm: `django==[\d.]+`, IsRegex: true, VersionFilter: filter}},
+		{"unknown group", &StringSearchParser{SearchTerm: `django==(?P<v>[\d.]+)`, IsRegex: true, VersionFilter: filter, VersionGroup: "version"}},
+	}
+
+	for _, tt := range tests {
+		if _, err := tt.parser.Search([]byte("django==3.2"), "requirements.txt"); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
 func TestStringSearchParser_AsParserFunc(t *testing.T) {
 	parser := &StringSearchParser{
 		SearchTerm: "found",
@@ -224,3 +312,88 @@ func TestStringSearchParser_FilePath(t *testing.T) {
 		t.Errorf("FilePath = %q, want %q", matches[0].FilePath, "src/main.py")
 	}
 }
+
+func TestStringSearchParser_Prefilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		parser    *StringSearchParser
+		content   string
+		wantMatch int
+	}{
+		{
+			name:      "regex prefilter literal absent",
+			parser:    &StringSearchParser{SearchTerm: `password\s*=`, IsRegex: true, Prefilter: "password"},
+			content:   "user = admin\nPASS = x\n",
+			wantMatch: 0,
+		},
+		{
+			name:      "regex prefilter literal present",
+			parser:    &StringSearchParser{SearchTerm: `password\s*=`, IsRegex: true, Prefilter: "password"},
+			content:   "user = admin\nPassword = x\n",
+			wantMatch: 1,
+		},
+		{
+			name:      "regex prefilter respects case sensitivity",
+			parser:    &StringSearchParser{SearchTerm: `password\s*=`, IsRegex: true, Prefilter: "password", CaseSensitive: true},
+			content:   "Password = x\n",
+			wantMatch: 0,
+		},
+		{
+			name:      "literal search prefilters on the term",
+			parser:    &StringSearchParser{SearchTerm: "API_KEY"},
+			content:   "nothing to see\n",
+			wantMatch: 0,
+		},
+		{
+			name:      "literal search case-insensitive",
+			parser:    &StringSearchParser{SearchTerm: "API_KEY"},
+			content:   "api_key: secret\n",
+			wantMatch: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := tt.parser.Search([]byte(tt.content), "test.txt")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(matches) != tt.wantMatch {
+				t.Errorf("got %d matches, want %d", len(matches), tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestStringSearchParser_ParallelMatchesSerial(t *testing.T) {
+	if runtime.GOMAXPROCS(0) < 2 {
+		t.Skip("parallel search needs GOMAXPROCS >= 2")
+	}
+
+	var b strings.Builder
+	for i := 0; i < 5000; i++ {
+		if i%7 == 0 {
+			fmt.Fprintf(&b, "needle %d\n", i)
+		} else {
+			fmt.Fprintf(&b, "hay %d\n", i)
+		}
+	}
+	content := []byte(b.String())
+
+	for _, maxMatches := range []int{0, 3, 500} {
+		serial := &StringSearchParser{SearchTerm: `needle \d+`, IsRegex: true, ContextLines: 2, MaxMatches: maxMatches, ParallelThreshold: -1}
+		parallel := &StringSearchParser{SearchTerm: `needle \d+`, IsRegex: true, ContextLines: 2, MaxMatches: maxMatches, ParallelThreshold: 1}
+
+		want, err := serial.Search(content, "big.txt")
+		if err != nil {
+			t.Fatalf("serial search: %v", err)
+		}
+		got, err := parallel.Search(content, "big.txt")
+		if err != nil {
+			t.Fatalf("parallel search: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("MaxMatches=%d: parallel search returned %d matches, serial %d; results differ", maxMatches, len(got), len(want))
+		}
+	}
+}
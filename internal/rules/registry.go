@@ -135,6 +135,25 @@ func (r *Registry) Disable(name string) bool {
 	return false
 }
 
+// DisableByTag disables every rule carrying the given tag.
+// Returns the number of rules that were disabled.
+func (r *Registry) DisableByTag(tag string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	disabled := 0
+	for _, rule := range r.rules {
+		for _, ruleTag := range rule.Tags {
+			if ruleTag == tag {
+				rule.Enabled = false
+				disabled++
+				break
+			}
+		}
+	}
+	return disabled
+}
+
 // Count returns the total number of registered rules.
 func (r *Registry) Count() int {
 	r.mu.RLock()
@@ -207,6 +226,10 @@ type ExecutionOptions struct {
 	// Tags filters rules to only those with at least one matching tag
 	// Empty slice means no tag filtering
 	Tags []string
+
+	// RequireVersion skips results that are Found but carry no Version,
+	// such as dependency-only requirements.txt matches
+	RequireVersion bool
 }
 
 // DefaultExecutionOptions returns sensible defaults for rule execution
@@ -261,6 +284,10 @@ func (r *Registry) Execute(ctx context.Context, content []byte, filename, filepa
 			continue
 		}
 
+		if opts.RequireVersion && !searchResult.HasVersion() {
+			continue
+		}
+
 		if opts.MinConfidence > 0 && searchResult.Confidence < opts.MinConfidence {
 			continue
 		}
@@ -13,12 +13,14 @@ func testParser(version string, found bool) ParserFunc {
 			return &SearchResult{Found: false}, nil
 		}
 		return &SearchResult{
-			Found:      true,
-			Version:    version,
-			Source:     filename,
-			Confidence: 0.9,
-			RawValue:   string(content),
-			Metadata:   map[string]string{"test": "data"},
+			Found: true,
+			Detection: Detection{
+				Version:    version,
+				Source:     filename,
+				Confidence: 0.9,
+			},
+			RawValue: string(content),
+			Metadata: map[string]string{"test": "data"},
 		}, nil
 	}
 }
@@ -226,6 +228,27 @@ func TestRegistryEnableDisable(t *testing.T) {
 	}
 }
 
+func TestRegistryDisableByTag(t *testing.T) {
+	reg := NewRegistry()
+	reg.MustRegister(NewRuleBuilder("noisy1").FilePattern("a").Parser(testParser("3.11", true)).Tags("noisy").MustBuild())
+	reg.MustRegister(NewRuleBuilder("noisy2").FilePattern("b").Parser(testParser("3.11", true)).Tags("other", "noisy").MustBuild())
+	reg.MustRegister(NewRuleBuilder("quiet").FilePattern("c").Parser(testParser("3.11", true)).Tags("other").MustBuild())
+
+	if n := reg.DisableByTag("noisy"); n != 2 {
+		t.Errorf("DisableByTag returned %d, want 2", n)
+	}
+	if reg.Get("noisy1").Enabled || reg.Get("noisy2").Enabled {
+		t.Error("Rules tagged noisy should be disabled")
+	}
+	if !reg.Get("quiet").Enabled {
+		t.Error("Rule without the tag should stay enabled")
+	}
+
+	if n := reg.DisableByTag("missing"); n != 0 {
+		t.Errorf("DisableByTag for unknown tag returned %d, want 0", n)
+	}
+}
+
 func TestRegistryCount(t *testing.T) {
 	reg := NewRegistry()
 	if reg.Count() != 0 {
@@ -400,16 +423,20 @@ func TestRegistryExecute(t *testing.T) {
 		
 		highConfParser := func(content []byte, filename string) (*SearchResult, error) {
 			return &SearchResult{
-				Found:      true,
-				Version:    "3.11",
-				Confidence: 0.9,
+				Found: true,
+				Detection: Detection{
+					Version:    "3.11",
+					Confidence: 0.9,
+				},
 			}, nil
 		}
 		lowConfParser := func(content []byte, filename string) (*SearchResult, error) {
 			return &SearchResult{
-				Found:      true,
-				Version:    "3.10",
-				Confidence: 0.5,
+				Found: true,
+				Detection: Detection{
+					Version:    "3.10",
+					Confidence: 0.5,
+				},
 			}, nil
 		}
 
@@ -427,6 +454,40 @@ func TestRegistryExecute(t *testing.T) {
 		}
 	})
 
+	t.Run("require version", func(t *testing.T) {
+		reg := NewRegistry()
+
+		depsOnlyParser := func(content []byte, filename string) (*SearchResult, error) {
+			return &SearchResult{
+				Found:     true,
+				Detection: Detection{Confidence: 0.5},
+				Metadata:  map[string]string{"source_type": "dependencies_only"},
+			}, nil
+		}
+		versionParser := func(content []byte, filename string) (*SearchResult, error) {
+			return &SearchResult{
+				Found:     true,
+				Detection: Detection{Version: "3.10", Confidence: 0.4},
+			}, nil
+		}
+
+		reg.MustRegister(testRule("deps", 10, "*.py", depsOnlyParser))
+		reg.MustRegister(testRule("version", 20, "*.py", versionParser))
+
+		result := reg.Execute(ctx, content, "test.py", "/path/test.py", DefaultExecutionOptions())
+		if len(result.Results) != 2 {
+			t.Errorf("Expected 2 results without RequireVersion, got %d", len(result.Results))
+		}
+
+		result = reg.Execute(ctx, content, "test.py", "/path/test.py", ExecutionOptions{RequireVersion: true})
+		if len(result.Results) != 1 {
+			t.Fatalf("Expected 1 result with RequireVersion, got %d", len(result.Results))
+		}
+		if result.BestResult.Version != "3.10" {
+			t.Errorf("Expected versioned best result, got version '%s'", result.BestResult.Version)
+		}
+	})
+
 	t.Run("tag filtering", func(t *testing.T) {
 		reg := NewRegistry()
 		
@@ -491,16 +552,20 @@ func TestRegistryExecute(t *testing.T) {
 		
 		medConfParser := func(content []byte, filename string) (*SearchResult, error) {
 			return &SearchResult{
-				Found:      true,
-				Version:    "3.11",
-				Confidence: 0.7,
+				Found: true,
+				Detection: Detection{
+					Version:    "3.11",
+					Confidence: 0.7,
+				},
 			}, nil
 		}
 		highConfParser := func(content []byte, filename string) (*SearchResult, error) {
 			return &SearchResult{
-				Found:      true,
-				Version:    "3.10",
-				Confidence: 0.9,
+				Found: true,
+				Detection: Detection{
+					Version:    "3.10",
+					Confidence: 0.9,
+				},
 			}, nil
 		}
 
@@ -546,16 +611,20 @@ func TestRegistryExecuteBestMatch(t *testing.T) {
 	
 	lowConfParser := func(content []byte, filename string) (*SearchResult, error) {
 		return &SearchResult{
-			Found:      true,
-			Version:    "3.11",
-			Confidence: 0.5,
+			Found: true,
+			Detection: Detection{
+				Version:    "3.11",
+				Confidence: 0.5,
+			},
 		}, nil
 	}
 	highConfParser := func(content []byte, filename string) (*SearchResult, error) {
 		return &SearchResult{
-			Found:      true,
-			Version:    "3.10",
-			Confidence: 0.9,
+			Found: true,
+			Detection: Detection{
+				Version:    "3.10",
+				Confidence: 0.9,
+			},
 		}, nil
 	}
 
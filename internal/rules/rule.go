@@ -2,15 +2,20 @@ package rules
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 )
 
-// SearchResult represents the result of applying a search rule
-type SearchResult struct {
-	// Found indicates whether the rule successfully found a match
-	Found bool
+// ErrParse is wrapped by Apply's error when the rule's parser failed on a
+// file that met the rule's conditions, as opposed to the file being skipped
+var ErrParse = errors.New("parser error")
 
+// Detection holds the typed facts a parser extracted about a Python version.
+// Consumers should read these fields rather than parsing values back out of
+// SearchResult.Metadata.
+type Detection struct {
 	// Version is the detected Python version (if found)
 	Version string
 
@@ -21,13 +26,47 @@ type SearchResult struct {
 	// 1.0 = explicit version file, 0.5 = inferred from tool config, etc.
 	Confidence float64
 
+	// Format identifies the declaration style within the file, e.g. "PEP621",
+	// "Poetry", or "pipenv" (empty if the file has only one style)
+	Format string
+
+	// Constraint is the version specifier the version was derived from,
+	// e.g. ">=3.9,<4.0" (empty when the file pins an exact version)
+	Constraint string
+
+	// VersionMax is the tightest upper bound in Constraint, e.g. "<3.11" or
+	// "<=3.10" (empty when the constraint is open-ended)
+	VersionMax string
+
+	// Implementation is the Python implementation, e.g. "cpython" or "pypy"
+	// (empty when the source does not say)
+	Implementation string
+}
+
+// SearchResult represents the result of applying a search rule
+type SearchResult struct {
+	// Found indicates whether the rule successfully found a match
+	Found bool
+
+	// Detection holds the typed version details; its fields are promoted,
+	// so result.Version, result.Source, etc. work directly
+	Detection
+
 	// RawValue is the raw extracted value before parsing (for debugging)
 	RawValue string
 
-	// Metadata contains additional information about the match
+	// Metadata contains genuinely open-ended extras about the match, such as
+	// source_type or dependency_count
 	Metadata map[string]string
 }
 
+// HasVersion reports whether the result is a Python version detection.
+// Some parsers report Found with no Version (e.g. requirements.txt that only
+// lists dependencies); those are inventory data, not version detections.
+func (r *SearchResult) HasVersion() bool {
+	return r != nil && r.Found && r.Version != ""
+}
+
 // ParserFunc is a function that parses file content to extract Python version information
 // Parameters:
 //   - content: The raw file content as bytes
@@ -43,6 +82,11 @@ type MatchCondition struct {
 	// Examples: ".python-version", "*.toml", "pyproject.toml"
 	FilePattern string
 
+	// CaseInsensitive matches FilePattern regardless of case, e.g. so
+	// "Dockerfile" also matches "DOCKERFILE". Off by default, since names
+	// like ".python-version" are case-sensitive by convention.
+	CaseInsensitive bool
+
 	// PathPattern is an optional regex to match full file paths
 	// Examples: "^Dockerfile$", ".*/.gitlab-ci.yml"
 	PathPattern *regexp.Regexp
@@ -83,6 +127,29 @@ type SearchRule struct {
 	// Tags provide categorization for rules
 	// Examples: ["explicit", "config-file"], ["docker", "inferred"]
 	Tags []string
+
+	// Confidence, if set, replaces the confidence the parser reported on
+	// each detection (0 keeps the parser's own)
+	Confidence float64
+}
+
+// TagExplicit marks rules that read a dedicated version declaration (such as
+// .python-version) rather than inferring the version from other files
+const TagExplicit = "explicit"
+
+// TagCI marks rules that read the Python version CI runs on (such as a
+// .gitlab-ci.yml image), which cross-checking compares against the declared
+// requires-python for CI/declaration drift
+const TagCI = "ci"
+
+// HasTag reports whether the rule carries tag
+func (r *SearchRule) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
 }
 
 // Matches checks if this rule should be applied to a given file
@@ -93,7 +160,7 @@ func (r *SearchRule) Matches(filename string, filepath string) bool {
 
 	// Check file pattern (simple glob or exact match)
 	if r.Condition.FilePattern != "" {
-		matched, err := matchPattern(r.Condition.FilePattern, filename)
+		matched, err := matchPattern(r.Condition.FilePattern, filename, r.Condition.CaseInsensitive)
 		if err != nil || !matched {
 			return false
 		}
@@ -129,9 +196,9 @@ func (r *SearchRule) Apply(ctx context.Context, content []byte, filename string)
 	}
 
 	// Execute the parser
-	result, err := r.Parser(content, filename)
+	result, err := r.runParser(content, filename)
 	if err != nil {
-		return nil, fmt.Errorf("parser error in rule %s: %w", r.Name, err)
+		return nil, fmt.Errorf("%w in rule %s: %w", ErrParse, r.Name, err)
 	}
 
 	// Populate source if not already set
@@ -139,9 +206,25 @@ func (r *SearchRule) Apply(ctx context.Context, content []byte, filename string)
 		result.Source = filename
 	}
 
+	// A configured confidence overrides the parser's
+	if result != nil && result.Found && r.Confidence > 0 {
+		result.Confidence = r.Confidence
+	}
+
 	return result, nil
 }
 
+// runParser calls the rule's parser, converting a panic on adversarial
+// input into an error so one bad file can't crash the whole scan
+func (r *SearchRule) runParser(content []byte, filename string) (result *SearchResult, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			result, err = nil, fmt.Errorf("parser panicked: %v", p)
+		}
+	}()
+	return r.Parser(content, filename)
+}
+
 // Validate checks if the rule is properly configured
 func (r *SearchRule) Validate() error {
 	if r.Name == "" {
@@ -181,9 +264,11 @@ func (r *SearchRule) Clone() *SearchRule {
 		Priority:    r.Priority,
 		Enabled:     r.Enabled,
 		Parser:      r.Parser,
+		Confidence:  r.Confidence,
 		Condition: MatchCondition{
-			FilePattern:  r.Condition.FilePattern,
-			MaxFileSize:  r.Condition.MaxFileSize,
+			FilePattern:     r.Condition.FilePattern,
+			CaseInsensitive: r.Condition.CaseInsensitive,
+			MaxFileSize:     r.Condition.MaxFileSize,
 		},
 	}
 
@@ -205,15 +290,17 @@ func (r *SearchRule) Clone() *SearchRule {
 //   - Exact match: "pyproject.toml"
 //   - Wildcard: "*.toml", "Dockerfile*"
 //   - Simple glob patterns
-func matchPattern(pattern, filename string) (bool, error) {
+//
+// caseInsensitive ignores case in both the exact and wildcard comparisons.
+func matchPattern(pattern, filename string, caseInsensitive bool) (bool, error) {
 	// Exact match
-	if pattern == filename {
+	if pattern == filename || (caseInsensitive && strings.EqualFold(pattern, filename)) {
 		return true, nil
 	}
 
 	// Simple wildcard matching
 	// Convert glob pattern to regex
-	regexPattern := globToRegex(pattern)
+	regexPattern := globToRegex(pattern, caseInsensitive)
 	matched, err := regexp.MatchString(regexPattern, filename)
 	if err != nil {
 		return false, fmt.Errorf("invalid pattern %s: %w", pattern, err)
@@ -222,8 +309,9 @@ func matchPattern(pattern, filename string) (bool, error) {
 	return matched, nil
 }
 
-// globToRegex converts a simple glob pattern to a regex pattern
-func globToRegex(glob string) string {
+// globToRegex converts a simple glob pattern to a regex pattern, flagged
+// (?i) when caseInsensitive is set
+func globToRegex(glob string, caseInsensitive bool) string {
 	// Escape special regex characters except * and ?
 	regex := regexp.QuoteMeta(glob)
 	
@@ -232,6 +320,9 @@ func globToRegex(glob string) string {
 	regex = regexp.MustCompile(`\\\?`).ReplaceAllString(regex, ".")
 	
 	// Anchor the pattern
+	if caseInsensitive {
+		return "(?i)^" + regex + "$"
+	}
 	return "^" + regex + "$"
 }
 
@@ -270,6 +361,12 @@ func (b *RuleBuilder) FilePattern(pattern string) *RuleBuilder {
 	return b
 }
 
+// CaseInsensitive sets whether the file pattern ignores case
+func (b *RuleBuilder) CaseInsensitive(caseInsensitive bool) *RuleBuilder {
+	b.rule.Condition.CaseInsensitive = caseInsensitive
+	return b
+}
+
 // PathPattern sets a regex pattern for matching file paths
 func (b *RuleBuilder) PathPattern(pattern string) *RuleBuilder {
 	if b.err != nil {
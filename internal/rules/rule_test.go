@@ -2,19 +2,23 @@ package rules
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 )
 
 // Mock parser that always succeeds
 func mockParserSuccess(content []byte, filename string) (*SearchResult, error) {
 	return &SearchResult{
-		Found:      true,
-		Version:    "3.11.0",
-		Source:     filename,
-		Confidence: 1.0,
-		RawValue:   string(content),
+		Found: true,
+		Detection: Detection{
+			Version:    "3.11.0",
+			Source:     filename,
+			Confidence: 1.0,
+		},
+		RawValue: string(content),
 	}, nil
 }
 
@@ -266,6 +270,28 @@ func TestSearchRuleApply(t *testing.T) {
 	}
 }
 
+func TestSearchRuleApplyRecoversParserPanic(t *testing.T) {
+	rule := &SearchRule{
+		Name:    "panicky",
+		Enabled: true,
+		Parser: func(content []byte, filename string) (*SearchResult, error) {
+			panic("index out of range")
+		},
+		Condition: MatchCondition{FilePattern: "*.toml"},
+	}
+
+	result, err := rule.Apply(context.Background(), []byte("[tool]"), "pyproject.toml")
+	if result != nil {
+		t.Errorf("result = %+v, want nil", result)
+	}
+	if !errors.Is(err, ErrParse) {
+		t.Fatalf("error = %v, want one wrapping ErrParse", err)
+	}
+	if !strings.Contains(err.Error(), "panicky") || !strings.Contains(err.Error(), "index out of range") {
+		t.Errorf("error = %q, want the rule name and panic value", err)
+	}
+}
+
 func TestSearchRuleValidate(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -345,6 +371,33 @@ func TestSearchRuleValidate(t *testing.T) {
 	}
 }
 
+func TestSearchRuleApplyConfidenceOverride(t *testing.T) {
+	rule := &SearchRule{
+		Name:       "tuned",
+		Enabled:    true,
+		Parser:     mockParserSuccess,
+		Confidence: 0.3,
+		Condition:  MatchCondition{FilePattern: "*.py"},
+	}
+
+	result, err := rule.Apply(context.Background(), []byte("python"), "setup.py")
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if result.Confidence != 0.3 {
+		t.Errorf("Confidence = %v, want the override 0.3", result.Confidence)
+	}
+
+	rule.Confidence = 0
+	result, err = rule.Apply(context.Background(), []byte("python"), "setup.py")
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if result.Confidence != 1.0 {
+		t.Errorf("Confidence = %v, want the parser's own 1.0", result.Confidence)
+	}
+}
+
 func TestSearchRuleClone(t *testing.T) {
 	original := &SearchRule{
 		Name:        "original",
@@ -353,8 +406,10 @@ func TestSearchRuleClone(t *testing.T) {
 		Enabled:     true,
 		Parser:      mockParserSuccess,
 		Tags:        []string{"tag1", "tag2"},
+		Confidence:  0.3,
 		Condition: MatchCondition{
 			FilePattern:     "*.py",
+			CaseInsensitive: true,
 			PathPattern:     regexp.MustCompile(".*test.*"),
 			RequiredContent: regexp.MustCompile("python"),
 			MaxFileSize:     1024,
@@ -376,6 +431,9 @@ func TestSearchRuleClone(t *testing.T) {
 	if clone.Enabled != original.Enabled {
 		t.Errorf("Enabled = %v, want %v", clone.Enabled, original.Enabled)
 	}
+	if clone.Confidence != original.Confidence {
+		t.Errorf("Confidence = %v, want %v", clone.Confidence, original.Confidence)
+	}
 
 	// Verify tags are deep copied
 	if len(clone.Tags) != len(original.Tags) {
@@ -391,6 +449,9 @@ func TestSearchRuleClone(t *testing.T) {
 	}
 
 	// Verify condition fields are copied
+	if clone.Condition.CaseInsensitive != original.Condition.CaseInsensitive {
+		t.Errorf("CaseInsensitive = %v, want %v", clone.Condition.CaseInsensitive, original.Condition.CaseInsensitive)
+	}
 	if clone.Condition.FilePattern != original.Condition.FilePattern {
 		t.Error("FilePattern not copied correctly")
 	}
@@ -420,7 +481,7 @@ func TestGlobToRegex(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.glob+" matches "+tt.text, func(t *testing.T) {
-			regex := globToRegex(tt.glob)
+			regex := globToRegex(tt.glob, false)
 			matched, err := regexp.MatchString(regex, tt.text)
 			if err != nil {
 				t.Fatalf("Regex compilation error: %v", err)
@@ -549,25 +610,31 @@ func TestRuleBuilder(t *testing.T) {
 
 func TestMatchPattern(t *testing.T) {
 	tests := []struct {
-		name     string
-		pattern  string
-		filename string
-		expected bool
+		name            string
+		pattern         string
+		filename        string
+		expected        bool
+		caseInsensitive bool
 	}{
-		{"Exact match", "pyproject.toml", "pyproject.toml", true},
-		{"Exact no match", "pyproject.toml", "setup.py", false},
-		{"Wildcard extension", "*.py", "script.py", true},
-		{"Wildcard extension no match", "*.py", "script.txt", false},
-		{"Wildcard prefix", "test_*", "test_feature.py", true},
-		{"Wildcard prefix no match", "test_*", "feature_test.py", false},
-		{"Wildcard both ends", "*file*", "myfile.txt", true},
-		{"Question mark single char", "?.py", "a.py", true},
-		{"Question mark no match", "?.py", "ab.py", false},
+		{"Exact match", "pyproject.toml", "pyproject.toml", true, false},
+		{"Exact no match", "pyproject.toml", "setup.py", false, false},
+		{"Wildcard extension", "*.py", "script.py", true, false},
+		{"Wildcard extension no match", "*.py", "script.txt", false, false},
+		{"Wildcard prefix", "test_*", "test_feature.py", true, false},
+		{"Wildcard prefix no match", "test_*", "feature_test.py", false, false},
+		{"Wildcard both ends", "*file*", "myfile.txt", true, false},
+		{"Question mark single char", "?.py", "a.py", true, false},
+		{"Question mark no match", "?.py", "ab.py", false, false},
+		{"Exact match is case-sensitive", "Dockerfile", "DOCKERFILE", false, false},
+		{"Wildcard is case-sensitive", "*.txt", "requirements.TXT", false, false},
+		{"Case-insensitive exact match", "Dockerfile", "DOCKERFILE", true, true},
+		{"Case-insensitive wildcard", "*.txt", "requirements.TXT", true, true},
+		{"Case-insensitive still anchored", "Dockerfile", "Dockerfile.dev", false, true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			matched, err := matchPattern(tt.pattern, tt.filename)
+			matched, err := matchPattern(tt.pattern, tt.filename, tt.caseInsensitive)
 			if err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			}
@@ -581,11 +648,13 @@ func TestMatchPattern(t *testing.T) {
 
 func TestSearchResultMetadata(t *testing.T) {
 	result := &SearchResult{
-		Found:      true,
-		Version:    "3.11.0",
-		Source:     ".python-version",
-		Confidence: 1.0,
-		RawValue:   "3.11.0",
+		Found: true,
+		Detection: Detection{
+			Version:    "3.11.0",
+			Source:     ".python-version",
+			Confidence: 1.0,
+		},
+		RawValue: "3.11.0",
 		Metadata: map[string]string{
 			"file_size": "8",
 			"encoding":  "utf-8",
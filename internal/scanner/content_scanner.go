@@ -2,11 +2,13 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
 	"sync"
 
+	apperrors "github.com/gbjohnso/gitlab-python-scanner/internal/errors"
 	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
 	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
 	"github.com/gbjohnso/gitlab-python-scanner/internal/parsers"
@@ -14,13 +16,52 @@ import (
 
 // ContentSearchConfig holds configuration for a content search operation
 type ContentSearchConfig struct {
+	SearchName    string   // Name of the search, recorded on results
+	Severity      string   // Triage label from the search's config entry, recorded on results
 	SearchTerm    string   // The string or regex to search for
 	IsRegex       bool     // Whether SearchTerm is a regex
-	FilePatterns  []string // Filename glob patterns to restrict to (empty = all files)
+	FilePatterns  []string // Filename glob patterns to restrict to (empty = all files); "!" prefix excludes
 	CaseSensitive bool     // Case sensitivity
 	ContextLines  int      // Context lines around matches
 	MaxMatches    int      // Max matches per project (0 = unlimited)
 	MaxFileSize   int64    // Skip files larger than this (bytes, 0 = 1MB default)
+	Prefilter     string   // Literal every regex match contains; files without it are skipped
+
+	// VersionFilter reports only regex matches whose version, captured by
+	// VersionGroup (empty = the first group), satisfies it (zero = all)
+	VersionFilter output.VersionFilter
+	VersionGroup  string
+
+	// MetadataPrefilter fetches each candidate file's metadata before its
+	// content and skips files larger than MaxFileSize, trading one cheap
+	// HEAD request for avoiding a large download. Files are never skipped
+	// as unchanged: search results aren't kept between runs, so there is
+	// no earlier LastCommitID to compare against.
+	MetadataPrefilter bool
+
+	// MatchFilesOnly matches SearchTerm and FilePatterns against file paths
+	// from the repository tree instead of file contents. SearchTerm may be
+	// empty, in which case every file matching FilePatterns is reported.
+	MatchFilesOnly bool
+
+	// InFiles lists exact file paths to fetch and search in each project,
+	// skipping the tree listing entirely. Projects without a file simply
+	// have no matches in it.
+	InFiles []string
+
+	// FirstMatch stops searching a project at its first match, for "which
+	// projects contain this at all" inventories. Results are marked
+	// FirstMatchOnly since their match counts are not real totals.
+	FirstMatch bool
+
+	// Redact masks matched text in results (see ContentScanResult.Redact)
+	// before any sink sees them
+	Redact bool
+
+	// SearchWikis also searches each project's wiki pages, reporting their
+	// matches at output.WikiPathPrefix plus the page slug. FilePatterns and
+	// InFiles don't apply to wiki pages.
+	SearchWikis bool
 }
 
 // ContentScanner orchestrates searching across a project's files
@@ -35,6 +76,9 @@ func NewContentScanner(client *gitlab.Client, config ContentSearchConfig) *Conte
 	if config.MaxFileSize == 0 {
 		config.MaxFileSize = 1024 * 1024 // 1MB default
 	}
+	if config.FirstMatch {
+		config.MaxMatches = 1
+	}
 
 	return &ContentScanner{
 		client: client,
@@ -45,6 +89,9 @@ func NewContentScanner(client *gitlab.Client, config ContentSearchConfig) *Conte
 			CaseSensitive: config.CaseSensitive,
 			ContextLines:  config.ContextLines,
 			MaxMatches:    config.MaxMatches,
+			Prefilter:     config.Prefilter,
+			VersionFilter: config.VersionFilter,
+			VersionGroup:  config.VersionGroup,
 		},
 	}
 }
@@ -55,25 +102,42 @@ func (cs *ContentScanner) ScanProject(ctx context.Context, project *gitlab.Proje
 		ProjectName:   project.Name,
 		ProjectPath:   project.PathWithNamespace,
 		SearchTerm:    cs.config.SearchTerm,
+		SearchName:    cs.config.SearchName,
+		Severity:      cs.config.Severity,
 		Index:         index,
 		TotalProjects: total,
+
+		FirstMatchOnly: cs.config.FirstMatch,
 	}
 
 	var matches []output.ContentMatchEntry
 	var err error
 
-	if cs.config.IsRegex {
+	if cs.config.MatchFilesOnly {
+		matches, err = cs.searchPaths(ctx, project)
+	} else if len(cs.config.InFiles) > 0 {
+		matches, err = cs.searchInFiles(ctx, project)
+	} else if cs.config.IsRegex {
 		matches, err = cs.searchLocal(ctx, project)
 	} else {
 		matches, err = cs.searchViaAPI(ctx, project)
 	}
 
+	if err == nil && cs.config.SearchWikis && (cs.config.MaxMatches == 0 || len(matches) < cs.config.MaxMatches) {
+		var wikiMatches []output.ContentMatchEntry
+		wikiMatches, err = cs.searchWikis(ctx, project, cs.config.MaxMatches-len(matches))
+		matches = append(matches, wikiMatches...)
+	}
+
 	if err != nil {
 		result.Error = err
 		return result
 	}
 
 	result.Matches = matches
+	if cs.config.Redact {
+		result.Redact()
+	}
 	return result
 }
 
@@ -103,12 +167,18 @@ func (cs *ContentScanner) searchViaAPI(ctx context.Context, project *gitlab.Proj
 			}
 			if strings.Contains(searchIn, searchFor) {
 				idx := strings.Index(searchIn, searchFor)
-				matches = append(matches, output.ContentMatchEntry{
+				match := output.ContentMatchEntry{
 					FilePath:    blob.Path,
 					LineNumber:  blob.Startline + i,
 					LineContent: line,
 					MatchedText: line[idx : idx+len(cs.config.SearchTerm)],
-				})
+					Ref:         blob.Ref,
+				}
+				// Context is limited to the lines the snippet includes
+				if cs.config.ContextLines > 0 {
+					match.ContextBefore, match.ContextAfter = parsers.ContextAround(lines, i, cs.config.ContextLines)
+				}
+				matches = append(matches, match)
 
 				if cs.config.MaxMatches > 0 && len(matches) >= cs.config.MaxMatches {
 					return matches, nil
@@ -132,8 +202,12 @@ func (cs *ContentScanner) searchLocal(ctx context.Context, project *gitlab.Proje
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, 3) // Limit concurrent file fetches per project
 
+	// Cancelled once MaxMatches is reached so queued fetches are skipped
+	searchCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
 	for _, file := range files {
-		if cs.config.MaxMatches > 0 && len(allMatches) >= cs.config.MaxMatches {
+		if searchCtx.Err() != nil {
 			break
 		}
 
@@ -142,8 +216,15 @@ func (cs *ContentScanner) searchLocal(ctx context.Context, project *gitlab.Proje
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
+			if searchCtx.Err() != nil {
+				return
+			}
 
-			content, err := cs.client.GetRawFile(ctx, project.ID, f.Path, nil)
+			if cs.config.MetadataPrefilter && cs.exceedsMaxSize(searchCtx, project, f.Path) {
+				return
+			}
+
+			content, err := cs.client.GetRawFile(searchCtx, project.ID, f.Path, nil)
 			if err != nil {
 				return
 			}
@@ -158,9 +239,17 @@ func (cs *ContentScanner) searchLocal(ctx context.Context, project *gitlab.Proje
 				return
 			}
 
+			// Raw files are read from the default branch
+			for i := range matches {
+				matches[i].Ref = project.DefaultBranch
+			}
+
 			if len(matches) > 0 {
 				mu.Lock()
 				allMatches = append(allMatches, matches...)
+				if cs.config.MaxMatches > 0 && len(allMatches) >= cs.config.MaxMatches {
+					stop()
+				}
 				mu.Unlock()
 			}
 		}(file)
@@ -176,6 +265,134 @@ func (cs *ContentScanner) searchLocal(ctx context.Context, project *gitlab.Proje
 	return allMatches, nil
 }
 
+// searchInFiles fetches only the configured file paths and searches them
+// locally, which is far cheaper than listing the tree for single-file checks
+func (cs *ContentScanner) searchInFiles(ctx context.Context, project *gitlab.Project) ([]output.ContentMatchEntry, error) {
+	var allMatches []output.ContentMatchEntry
+	for _, path := range cs.config.InFiles {
+		if cs.config.MetadataPrefilter && cs.exceedsMaxSize(ctx, project, path) {
+			continue
+		}
+
+		content, err := cs.client.GetRawFile(ctx, project.ID, path, nil)
+		if err != nil {
+			// A missing file isn't an error, the project just doesn't have it
+			var appErr *apperrors.AppError
+			if errors.As(err, &appErr) && appErr.Type == apperrors.ErrorTypeNotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed to fetch %s: %w", path, err)
+		}
+
+		if int64(len(content)) > cs.config.MaxFileSize {
+			continue
+		}
+
+		matches, err := cs.parser.Search(content, path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Raw files are read from the default branch
+		for i := range matches {
+			matches[i].Ref = project.DefaultBranch
+		}
+		allMatches = append(allMatches, matches...)
+
+		if cs.config.MaxMatches > 0 && len(allMatches) >= cs.config.MaxMatches {
+			return allMatches[:cs.config.MaxMatches], nil
+		}
+	}
+
+	return allMatches, nil
+}
+
+// searchWikis searches a project's wiki pages with the same matcher as its
+// files, returning at most limit matches when MaxMatches is set. A project
+// whose wiki is disabled or that the token can't read has no matches there.
+func (cs *ContentScanner) searchWikis(ctx context.Context, project *gitlab.Project, limit int) ([]output.ContentMatchEntry, error) {
+	pages, err := cs.client.ListWikiPages(ctx, project.ID)
+	if err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) && (appErr.Type == apperrors.ErrorTypeNotFound || appErr.Type == apperrors.ErrorTypePermission) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list wiki pages: %w", err)
+	}
+
+	var allMatches []output.ContentMatchEntry
+	for _, page := range pages {
+		if int64(len(page.Content)) > cs.config.MaxFileSize {
+			continue
+		}
+
+		matches, err := cs.parser.Search([]byte(page.Content), output.WikiPathPrefix+page.Slug)
+		if err != nil {
+			return nil, err
+		}
+		allMatches = append(allMatches, matches...)
+
+		if cs.config.MaxMatches > 0 && len(allMatches) >= limit {
+			return allMatches[:limit], nil
+		}
+	}
+
+	return allMatches, nil
+}
+
+// exceedsMaxSize reports whether a file's metadata shows it is larger than
+// MaxFileSize. If the metadata cannot be fetched the file is not skipped, so
+// the content fetch still decides.
+func (cs *ContentScanner) exceedsMaxSize(ctx context.Context, project *gitlab.Project, filePath string) bool {
+	// The metadata endpoint requires a ref; the raw endpoint defaults to this one
+	ref := project.DefaultBranch
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	meta, err := cs.client.GetFileMetadata(ctx, project.ID, filePath, &gitlab.GetFileOptions{Ref: ref})
+	if err != nil {
+		return false
+	}
+	return int64(meta.Size) > cs.config.MaxFileSize
+}
+
+// searchPaths matches file paths from the repository tree without fetching
+// any file contents, which is far cheaper for filename-based inventory
+func (cs *ContentScanner) searchPaths(ctx context.Context, project *gitlab.Project) ([]output.ContentMatchEntry, error) {
+	files, err := cs.getFilesToSearch(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []output.ContentMatchEntry
+	for _, f := range files {
+		if cs.config.SearchTerm != "" {
+			// Paths are single lines, so the content parser's literal/regex
+			// and case handling applies unchanged
+			found, err := cs.parser.Search([]byte(f.Path), f.Path)
+			if err != nil {
+				return nil, err
+			}
+			if len(found) == 0 {
+				continue
+			}
+		}
+
+		matches = append(matches, output.ContentMatchEntry{
+			FilePath:    f.Path,
+			MatchedText: f.Path,
+			Ref:         project.DefaultBranch,
+		})
+
+		if cs.config.MaxMatches > 0 && len(matches) >= cs.config.MaxMatches {
+			break
+		}
+	}
+
+	return matches, nil
+}
+
 // getFilesToSearch determines which files to fetch and search
 func (cs *ContentScanner) getFilesToSearch(ctx context.Context, project *gitlab.Project) ([]*gitlab.TreeFile, error) {
 	if len(cs.config.FilePatterns) > 0 {
@@ -207,13 +424,22 @@ func (cs *ContentScanner) getFilesToSearch(ctx context.Context, project *gitlab.
 	return allFiles, nil
 }
 
-// matchesFilePattern checks if a filename matches any of the configured file patterns
+// matchesFilePattern checks if a filename is selected by the configured file
+// patterns. Patterns prefixed with "!" exclude, as in .gitignore, and win over
+// inclusions; with only exclusions every other file is selected.
 func (cs *ContentScanner) matchesFilePattern(filename string) bool {
+	included, hasInclusions := false, false
 	for _, pattern := range cs.config.FilePatterns {
-		matched, err := filepath.Match(pattern, filename)
-		if err == nil && matched {
-			return true
+		if negated, ok := strings.CutPrefix(pattern, "!"); ok {
+			if matched, err := filepath.Match(negated, filename); err == nil && matched {
+				return false
+			}
+			continue
+		}
+		hasInclusions = true
+		if matched, err := filepath.Match(pattern, filename); err == nil && matched {
+			included = true
 		}
 	}
-	return false
+	return included || !hasInclusions
 }
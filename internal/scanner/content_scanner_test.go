@@ -0,0 +1,137 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/parsers"
+)
+
+func TestMatchesFilePattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		filename string
+		want     bool
+	}{
+		{"no patterns", nil, "main.py", true},
+		{"inclusion matches", []string{"*.py"}, "main.py", true},
+		{"inclusion misses", []string{"*.py"}, "go.mod", false},
+		{"exclusion only", []string{"!*.lock"}, "main.py", true},
+		{"exclusion only matches", []string{"!*.lock"}, "poetry.lock", false},
+		{"exclusion wins over inclusion", []string{"*.py", "!*_test.py"}, "scanner_test.py", false},
+		{"exclusion wins regardless of order", []string{"!*_test.py", "*.py"}, "scanner_test.py", false},
+		{"included and not excluded", []string{"*.py", "!*_test.py"}, "scanner.py", true},
+		{"excluded file outside inclusions", []string{"*.py", "!*.lock"}, "Pipfile.lock", false},
+		{"neither included nor excluded", []string{"*.py", "!*.lock"}, "Dockerfile", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &ContentScanner{config: ContentSearchConfig{FilePatterns: tt.patterns}}
+			if got := cs.matchesFilePattern(tt.filename); got != tt.want {
+				t.Errorf("matchesFilePattern(%q) with %v = %v, want %v", tt.filename, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentScannerMetadataPrefilter(t *testing.T) {
+	var rawFetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/repository/tree"):
+			w.Write([]byte(`[{"name": "bundle.js", "path": "bundle.js", "type": "blob"}, {"name": "app.py", "path": "app.py", "type": "blob"}]`))
+		case strings.HasSuffix(r.URL.Path, "/raw"):
+			rawFetches.Add(1)
+			if strings.HasSuffix(r.URL.Path, "/files/bundle.js/raw") {
+				t.Error("GetRawFile() fetched a file its metadata showed was oversized")
+			}
+			w.Write([]byte("import os  # needle\n"))
+		case r.Method == http.MethodHead && strings.HasSuffix(r.URL.Path, "/files/bundle.js"):
+			w.Header().Set("X-Gitlab-Size", "52428800")
+		case r.Method == http.MethodHead && strings.HasSuffix(r.URL.Path, "/files/app.py"):
+			w.Header().Set("X-Gitlab-Size", "21")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	cs := NewContentScanner(client, ContentSearchConfig{SearchTerm: "needle", IsRegex: true, MetadataPrefilter: true, MaxFileSize: 1024})
+	result := cs.ScanProject(context.Background(), &gitlab.Project{ID: 1, Name: "web", DefaultBranch: "main"}, 1, 1)
+	if result.Error != nil {
+		t.Fatalf("ScanProject() error = %v", result.Error)
+	}
+	if len(result.Matches) != 1 || result.Matches[0].FilePath != "app.py" {
+		t.Errorf("matches = %+v, want one in app.py", result.Matches)
+	}
+	if got := rawFetches.Load(); got != 1 {
+		t.Errorf("fetched %d files, want only app.py", got)
+	}
+}
+
+func TestContentScannerSearchesLargeFilesInParallel(t *testing.T) {
+	// Large enough to be split across goroutines, small enough to be fetched
+	var content strings.Builder
+	for i := 0; content.Len() < 2*parsers.DefaultParallelThreshold; i++ {
+		if i%1000 == 0 {
+			fmt.Fprintf(&content, "needle %d\n", i)
+		} else {
+			fmt.Fprintf(&content, "line %d of a generated fixture\n", i)
+		}
+	}
+	want := 0
+	for _, line := range strings.Split(content.String(), "\n") {
+		if strings.HasPrefix(line, "needle ") {
+			want++
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/repository/tree"):
+			w.Write([]byte(`[{"name": "fixture.txt", "path": "fixture.txt", "type": "blob"}]`))
+		case strings.HasSuffix(r.URL.Path, "/files/fixture.txt/raw"):
+			w.Write([]byte(content.String()))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	cs := NewContentScanner(client, ContentSearchConfig{SearchTerm: `needle \d+`, IsRegex: true, ContextLines: 1})
+	if size := int64(content.Len()); size < parsers.DefaultParallelThreshold || size > cs.config.MaxFileSize {
+		t.Fatalf("fixture is %d bytes, want it between the parallel threshold and the file size limit", size)
+	}
+
+	result := cs.ScanProject(context.Background(), &gitlab.Project{ID: 1, Name: "fixtures"}, 1, 1)
+	if result.Error != nil {
+		t.Fatalf("ScanProject() error = %v", result.Error)
+	}
+	if len(result.Matches) != want {
+		t.Fatalf("got %d matches, want %d", len(result.Matches), want)
+	}
+	for i, match := range result.Matches {
+		if match.LineNumber != i*1000+1 || match.MatchedText != fmt.Sprintf("needle %d", i*1000) {
+			t.Errorf("match %d = line %d %q, want needle %d on line %d", i, match.LineNumber, match.MatchedText, i*1000, i*1000+1)
+			break
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"sync"
+
+	apperrors "github.com/gbjohnso/gitlab-python-scanner/internal/errors"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+)
+
+// IgnoreFileName is the file a project can commit at its root to keep paths
+// out of its own scan, one glob per line (see ParseIgnoreFile)
+const IgnoreFileName = ".gitlab-seeker-ignore"
+
+// ParseIgnoreFile turns an ignore file into IgnorePaths globs. Blank lines
+// and lines starting with "#" are skipped. As in .gitignore, a pattern with
+// no "/" except at the end matches at any depth, a leading "/" anchors it to
+// the repository root, a trailing "/" matches only directories, and a
+// matched directory ignores everything under it. Negations ("!") aren't
+// supported; they and malformed globs are returned as invalid.
+func ParseIgnoreFile(content []byte) (patterns, invalid []string) {
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		dirOnly := strings.HasSuffix(line, "/")
+		pattern := strings.Trim(line, "/")
+		if pattern == "" || strings.HasPrefix(pattern, "!") || !validGlob(pattern) {
+			invalid = append(invalid, line)
+			continue
+		}
+		if !strings.HasPrefix(line, "/") && !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+
+		if !dirOnly {
+			patterns = append(patterns, pattern)
+		}
+		patterns = append(patterns, pattern+"/**")
+	}
+	return patterns, invalid
+}
+
+// validGlob reports whether every segment of pattern is a valid path.Match pattern
+func validGlob(pattern string) bool {
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// IgnoreFileCache remembers each project's ignore file, including that it
+// has none, until the project's last activity changes, so repeated scans
+// (e.g. --watch) don't probe every project for it again. It is safe for
+// concurrent use.
+type IgnoreFileCache struct {
+	mu      sync.Mutex
+	entries map[ignoreFileKey]ignoreFile
+}
+
+// ignoreFileKey identifies a project's ignore file as of one activity at one ref
+type ignoreFileKey struct {
+	projectID    int
+	ref          string
+	lastActivity string
+}
+
+// ignoreFile is a parsed ignore file; both fields are nil when there is none
+type ignoreFile struct {
+	patterns []string
+	invalid  []string
+}
+
+// NewIgnoreFileCache creates an empty cache
+func NewIgnoreFileCache() *IgnoreFileCache {
+	return &IgnoreFileCache{entries: make(map[ignoreFileKey]ignoreFile)}
+}
+
+// loadIgnoreFile returns the parsed IgnoreFileName of project at ref ("" for
+// the default branch), from cache when it's known (cache may be nil). A file
+// that couldn't be fetched for any reason other than not existing is treated
+// as absent and fetched again next time.
+func loadIgnoreFile(ctx context.Context, client *gitlab.Client, project *gitlab.Project, ref string, cache *IgnoreFileCache) ignoreFile {
+	key := ignoreFileKey{projectID: project.ID, ref: ref, lastActivity: project.LastActivityAt}
+	cacheable := cache != nil && key.lastActivity != ""
+	if cacheable {
+		cache.mu.Lock()
+		file, ok := cache.entries[key]
+		cache.mu.Unlock()
+		if ok {
+			return file
+		}
+	}
+
+	var file ignoreFile
+	content, err := client.GetRawFile(ctx, project.ID, IgnoreFileName, &gitlab.GetFileOptions{Ref: ref})
+	if err != nil {
+		var appErr *apperrors.AppError
+		if !errors.As(err, &appErr) || appErr.Type != apperrors.ErrorTypeNotFound {
+			return file
+		}
+	} else {
+		file.patterns, file.invalid = ParseIgnoreFile(content)
+	}
+
+	if cacheable {
+		cache.mu.Lock()
+		cache.entries[key] = file
+		cache.mu.Unlock()
+	}
+	return file
+}
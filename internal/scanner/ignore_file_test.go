@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/parsers"
+)
+
+func TestParseIgnoreFile(t *testing.T) {
+	content := "# Generated clients\n\nclients/\n/setup.py\nlegacy/app/*.toml\n*.cfg\n!keep.cfg\n/\nbad[\n"
+	patterns, invalid := ParseIgnoreFile([]byte(content))
+
+	if strings.Join(invalid, ",") != "!keep.cfg,/,bad[" {
+		t.Errorf("invalid = %q, want [!keep.cfg / bad[]", invalid)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"clients/python/setup.py", true},
+		{"services/clients/pyproject.toml", true},
+		{"setup.py", true},
+		{"services/api/setup.py", false}, // Anchored to the root
+		{"legacy/app/pyproject.toml", true},
+		{"legacy/app/sub/pyproject.toml", false},
+		{"setup.cfg", true},
+		{"services/api/setup.cfg", true},
+		{"pyproject.toml", false},
+	}
+	for _, tt := range tests {
+		if got := ignoredPath(tt.path, patterns); got != tt.want {
+			t.Errorf("ignoredPath(%q) = %v, want %v (patterns %v)", tt.path, got, tt.want, patterns)
+		}
+	}
+}
+
+func TestScanProjectIgnoreFile(t *testing.T) {
+	var ignoreFetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/files/"+IgnoreFileName+"/raw"):
+			ignoreFetches.Add(1)
+			if strings.Contains(r.URL.Path, "/projects/1/") {
+				w.Write([]byte("# Old layout\n/.python-version\n!keep\n"))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			w.Write([]byte("2.7\n"))
+		case strings.HasSuffix(r.URL.Path, "/files/runtime.txt/raw"):
+			w.Write([]byte("python-3.12.1\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	withFile := &gitlab.Project{ID: 1, Name: "api", LastActivityAt: "2026-10-01T10:00:00Z"}
+	without := &gitlab.Project{ID: 2, Name: "web", LastActivityAt: "2026-10-01T10:00:00Z"}
+	opts := VersionScanOptions{IgnoreFile: true, IgnoreFiles: NewIgnoreFileCache()}
+
+	result := ScanProject(context.Background(), client, parsers.DefaultRegistry(), withFile, 1, 2, opts)
+	if result.PythonVersion != "3.12.1" || result.DetectionSource != "runtime.txt" {
+		t.Errorf("with an ignore file = %q from %q, want 3.12.1 from runtime.txt", result.PythonVersion, result.DetectionSource)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], `"!keep"`) {
+		t.Errorf("Warnings = %q, want the unsupported negation", result.Warnings)
+	}
+
+	result = ScanProject(context.Background(), client, parsers.DefaultRegistry(), without, 2, 2, opts)
+	if result.PythonVersion != "2.7" {
+		t.Errorf("without an ignore file PythonVersion = %q, want 2.7", result.PythonVersion)
+	}
+
+	// Both the file and its absence are remembered until the project changes
+	ScanProject(context.Background(), client, parsers.DefaultRegistry(), withFile, 1, 2, opts)
+	ScanProject(context.Background(), client, parsers.DefaultRegistry(), without, 2, 2, opts)
+	if got := ignoreFetches.Load(); got != 2 {
+		t.Errorf("fetched the ignore file %d times over two scans, want 2", got)
+	}
+
+	changed := *withFile
+	changed.LastActivityAt = "2026-10-02T08:30:00Z"
+	ScanProject(context.Background(), client, parsers.DefaultRegistry(), &changed, 1, 2, opts)
+	if got := ignoreFetches.Load(); got != 3 {
+		t.Errorf("fetched the ignore file %d times after the project changed, want 3", got)
+	}
+
+	// Without IgnoreFile the project's own file isn't consulted
+	result = ScanProject(context.Background(), client, parsers.DefaultRegistry(), withFile, 1, 2, VersionScanOptions{})
+	if result.PythonVersion != "2.7" || ignoreFetches.Load() != 3 {
+		t.Errorf("without IgnoreFile PythonVersion = %q after %d fetches, want 2.7 and no fetch", result.PythonVersion, ignoreFetches.Load())
+	}
+}
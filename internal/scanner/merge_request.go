@@ -0,0 +1,159 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/gbjohnso/gitlab-python-scanner/internal/errors"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+)
+
+// VersionChange is a file a merge request changes whose detected Python
+// version differs between the target and source branches
+type VersionChange struct {
+	Path   string // The file on the source branch, or the target's if deleted
+	Before string // Version on the target branch ("" if none, e.g. a new file)
+	After  string // Version on the source branch ("" if none, e.g. a deleted file)
+}
+
+// MergeRequestVersionChanges returns the version changes mr makes. Only the
+// changed files an enabled rule reads are fetched, once at mr.BaseRef and
+// once at mr.HeadRef, and each is judged on its own with DetectVersion.
+func MergeRequestVersionChanges(ctx context.Context, client *gitlab.Client, registry *rules.Registry, projectID interface{}, mr *gitlab.MergeRequest) ([]VersionChange, error) {
+	var changes []VersionChange
+	for _, change := range mr.Changes {
+		if !readByRule(registry, change.OldPath) && !readByRule(registry, change.NewPath) {
+			continue
+		}
+
+		var before, after string
+		var err error
+		if !change.NewFile {
+			if before, err = versionAt(ctx, client, registry, projectID, change.OldPath, mr.BaseRef()); err != nil {
+				return nil, err
+			}
+		}
+		if !change.DeletedFile {
+			if after, err = versionAt(ctx, client, registry, projectID, change.NewPath, mr.HeadRef()); err != nil {
+				return nil, err
+			}
+		}
+
+		if before != after {
+			changePath := change.NewPath
+			if change.DeletedFile {
+				changePath = change.OldPath
+			}
+			changes = append(changes, VersionChange{Path: changePath, Before: before, After: after})
+		}
+	}
+	return changes, nil
+}
+
+// readByRule reports whether an enabled rule reads the file at filePath
+func readByRule(registry *rules.Registry, filePath string) bool {
+	return filePath != "" && len(registry.FindMatchingRules(path.Base(filePath), filePath)) > 0
+}
+
+// versionAt returns the version detected in filePath at ref, or "" if the
+// file is missing or declares none
+func versionAt(ctx context.Context, client *gitlab.Client, registry *rules.Registry, projectID interface{}, filePath, ref string) (string, error) {
+	content, err := client.GetRawFile(ctx, projectID, filePath, &gitlab.GetFileOptions{Ref: ref})
+	if err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) && appErr.Type == apperrors.ErrorTypeNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read %s at %s: %w", filePath, ref, err)
+	}
+
+	// A file that no longer parses has no version; that's the change
+	detection, _ := DetectVersion(ctx, content, filePath, registry)
+	if detection == nil {
+		return "", nil
+	}
+	return detection.Version, nil
+}
+
+// SearchAddedLines searches the lines mr adds, in the changed files the
+// configured file patterns select, reporting each match at its line in the
+// source branch's file. Context lines aren't available from a diff. It
+// also returns the paths of changed files whose diff GitLab left out for
+// being too large, which weren't searched.
+func (cs *ContentScanner) SearchAddedLines(mr *gitlab.MergeRequest) (matches []output.ContentMatchEntry, skipped []string, err error) {
+	for _, change := range mr.Changes {
+		if change.DeletedFile || !cs.matchesFilePattern(path.Base(change.NewPath)) {
+			continue
+		}
+		if change.Diff == "" {
+			if !change.RenamedFile {
+				skipped = append(skipped, change.NewPath)
+			}
+			continue
+		}
+
+		for _, line := range addedLines(change.Diff) {
+			found, err := cs.parser.Search([]byte(line.text), change.NewPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, match := range found {
+				match.FilePath = change.NewPath
+				match.LineNumber = line.number
+				match.Ref = mr.SourceBranch
+				matches = append(matches, match)
+				if cs.config.MaxMatches > 0 && len(matches) >= cs.config.MaxMatches {
+					return matches, skipped, nil
+				}
+			}
+		}
+	}
+	return matches, skipped, nil
+}
+
+// addedLine is a line a diff adds, numbered as in the new file
+type addedLine struct {
+	number int
+	text   string
+}
+
+// addedLines returns the lines a unified diff without file headers (as
+// GitLab serves it) adds. Lines are numbered from each hunk header's
+// new-file start, counting added and unchanged lines.
+func addedLines(diff string) []addedLine {
+	var added []addedLine
+	number := 0
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			number = hunkStart(line)
+		case strings.HasPrefix(line, "+"):
+			added = append(added, addedLine{number: number, text: line[1:]})
+			number++
+		case strings.HasPrefix(line, " "):
+			number++
+		}
+	}
+	return added
+}
+
+// hunkStart returns the new-file start line of a hunk header such as
+// "@@ -12,4 +12,6 @@ def main():", or 0 if it can't be read
+func hunkStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0
+	}
+	start, _, _ := strings.Cut(fields[2][1:], ",")
+	n, err := strconv.Atoi(start)
+	if err != nil {
+		return 0
+	}
+	return n
+}
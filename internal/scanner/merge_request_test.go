@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/parsers"
+)
+
+// mergeRequestServer serves merge request !7, which bumps .python-version,
+// adds runtime.txt, and edits app.py; files are served from the "base" and
+// "head" commits
+func mergeRequestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	files := map[string]string{
+		"base:.python-version": "3.11\n",
+		"head:.python-version": "3.12\n",
+		"head:runtime.txt":     "python-3.12.1\n",
+		"base:app.py":          "print('hi')\n",
+		"head:app.py":          "import os\nprint('hi')\n",
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/merge_requests/7/changes"):
+			w.Write([]byte(`{
+				"iid": 7, "title": "Move to 3.12", "source_branch": "py312", "target_branch": "main",
+				"diff_refs": {"base_sha": "base", "head_sha": "head"},
+				"changes": [
+					{"old_path": ".python-version", "new_path": ".python-version", "diff": "@@ -1 +1 @@\n-3.11\n+3.12\n"},
+					{"old_path": "runtime.txt", "new_path": "runtime.txt", "new_file": true, "diff": "@@ -0,0 +1 @@\n+python-3.12.1\n"},
+					{"old_path": "app.py", "new_path": "app.py", "diff": "@@ -1,1 +1,2 @@\n+import os # TODO drop\n print('hi')\n"}
+				]
+			}`))
+		case strings.HasSuffix(r.URL.Path, "/raw"):
+			name := strings.TrimSuffix(r.URL.Path[strings.Index(r.URL.Path, "/files/")+len("/files/"):], "/raw")
+			content, ok := files[r.URL.Query().Get("ref")+":"+name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(content))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestMergeRequestVersionChanges(t *testing.T) {
+	server := mergeRequestServer(t)
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	mr, err := client.GetMergeRequestChanges(context.Background(), "org/app", 7)
+	if err != nil {
+		t.Fatalf("GetMergeRequestChanges() error = %v", err)
+	}
+	if mr.BaseRef() != "base" || mr.HeadRef() != "head" || len(mr.Changes) != 3 {
+		t.Fatalf("merge request = %+v, want base..head with 3 changes", mr)
+	}
+
+	changes, err := MergeRequestVersionChanges(context.Background(), client, parsers.DefaultRegistry(), "org/app", mr)
+	if err != nil {
+		t.Fatalf("MergeRequestVersionChanges() error = %v", err)
+	}
+	want := []VersionChange{
+		{Path: ".python-version", Before: "3.11", After: "3.12"},
+		{Path: "runtime.txt", Before: "", After: "3.12.1"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("changes[%d] = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+}
+
+func TestSearchAddedLines(t *testing.T) {
+	mr := &gitlab.MergeRequest{
+		SourceBranch: "feature",
+		Changes: []*gitlab.FileChange{
+			{OldPath: "app.py", NewPath: "app.py", Diff: "@@ -10,3 +10,4 @@ def main():\n     run()\n-    # TODO old\n+    # TODO new\n+    done()\n     return\n"},
+			{OldPath: "old.py", NewPath: "old.py", DeletedFile: true, Diff: "@@ -1 +0,0 @@\n-# TODO gone\n"},
+			{OldPath: "big.py", NewPath: "big.py"},
+			{OldPath: "notes.md", NewPath: "notes.md", Diff: "@@ -0,0 +1 @@\n+TODO in docs\n"},
+		},
+	}
+
+	cs := NewContentScanner(nil, ContentSearchConfig{SearchTerm: "TODO", FilePatterns: []string{"*.py"}})
+	matches, skipped, err := cs.SearchAddedLines(mr)
+	if err != nil {
+		t.Fatalf("SearchAddedLines() error = %v", err)
+	}
+	if len(matches) != 1 || matches[0].FilePath != "app.py" || matches[0].LineNumber != 11 || matches[0].Ref != "feature" {
+		t.Errorf("matches = %+v, want one at app.py:11 on feature", matches)
+	}
+	if len(skipped) != 1 || skipped[0] != "big.py" {
+		t.Errorf("skipped = %v, want [big.py]", skipped)
+	}
+}
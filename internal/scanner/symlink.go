@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+)
+
+// maxSymlinkTarget is the longest content taken for a symlink's target
+const maxSymlinkTarget = 1024
+
+// symlinkTarget returns the path content would name if it were a symlink
+// read through the file API, which serves the link's target rather than
+// the file it points to: a single line without a trailing newline or
+// spaces. It returns "" for content that can't be a symlink.
+func symlinkTarget(content []byte) string {
+	if len(content) == 0 || len(content) > maxSymlinkTarget || bytes.ContainsAny(content, " \t\r\n\x00") {
+		return ""
+	}
+	return string(content)
+}
+
+// resolveLink returns the repository path a symlink at link points to, or
+// false if target leaves the repository
+func resolveLink(link, target string) (string, bool) {
+	if path.IsAbs(target) {
+		return "", false
+	}
+	resolved := path.Join(path.Dir(link), target)
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return "", false
+	}
+	return resolved, true
+}
+
+// followSymlink checks whether filename, whose content looked like a link
+// target, is a symlink, and if so fetches the file it points to within the
+// repository. It returns that file's content, or a diagnostic saying why the
+// link can't be followed; both are empty if filename is a regular file (or
+// can't be checked), so its content stands.
+func followSymlink(ctx context.Context, client *gitlab.Client, projectID interface{}, filename, target, ref string, opts VersionScanOptions) ([]byte, *gitlab.FileContent, string) {
+	entry, err := client.StatPath(ctx, projectID, filename, ref)
+	if err != nil || entry == nil || !entry.IsSymlink() {
+		return nil, nil, ""
+	}
+
+	resolved, ok := resolveLink(filename, target)
+	if !ok {
+		return nil, nil, fmt.Sprintf("%s is a symlink to %s outside the repository, not a regular file", filename, target)
+	}
+	content, metadata, err := fetchFile(ctx, client, projectID, resolved, ref, opts)
+	if err != nil {
+		return nil, nil, fmt.Sprintf("%s is a symlink to %s, which couldn't be read: %v", filename, resolved, err)
+	}
+	return content, metadata, ""
+}
+
+// checkSubdirs returns the subdirs that can be probed for files. The file
+// API can't read into a submodule, so submodules are dropped with a
+// diagnostic; symlinked directories are replaced by their target when it's
+// within the repository.
+func checkSubdirs(ctx context.Context, client *gitlab.Client, projectID interface{}, subdirs []string, ref string, result *output.ScanResult) []string {
+	checked := make([]string, 0, len(subdirs))
+	for _, dir := range subdirs {
+		entry, err := client.StatPath(ctx, projectID, path.Clean(dir), ref)
+		switch {
+		case err != nil || entry == nil:
+			// Missing subdirs are simply misses, as before
+			checked = append(checked, dir)
+		case entry.IsSubmodule():
+			result.Diagnostics = append(result.Diagnostics,
+				fmt.Sprintf("%s is a submodule, not a regular directory; its files can't be read", dir))
+		case entry.IsSymlink():
+			content, err := client.GetRawFile(ctx, projectID, entry.Path, &gitlab.GetFileOptions{Ref: ref})
+			if err != nil {
+				result.Diagnostics = append(result.Diagnostics,
+					fmt.Sprintf("%s is a symlink whose target couldn't be read: %v; its files weren't probed", dir, err))
+				continue
+			}
+			target := symlinkTarget(content)
+			resolved, ok := resolveLink(entry.Path, target)
+			if target == "" || !ok {
+				result.Diagnostics = append(result.Diagnostics,
+					fmt.Sprintf("%s is a symlink to %s outside the repository, not a regular directory; its files weren't probed", dir, target))
+				continue
+			}
+			checked = append(checked, resolved)
+		default:
+			checked = append(checked, dir)
+		}
+	}
+	return checked
+}
@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/parsers"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+)
+
+func TestSymlinkTarget(t *testing.T) {
+	tests := map[string]string{
+		"../shared/pyproject.toml": "../shared/pyproject.toml",
+		"pyproject.shared.toml":    "pyproject.shared.toml",
+		"3.11\n":                   "",
+		"[project]\nname = \"x\"":  "",
+		"":                         "",
+	}
+	for content, want := range tests {
+		if got := symlinkTarget([]byte(content)); got != want {
+			t.Errorf("symlinkTarget(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
+
+func TestResolveLink(t *testing.T) {
+	tests := []struct {
+		link, target, want string
+		ok                 bool
+	}{
+		{"pyproject.toml", "shared/pyproject.toml", "shared/pyproject.toml", true},
+		{"services/api/pyproject.toml", "../../shared/pyproject.toml", "shared/pyproject.toml", true},
+		{"pyproject.toml", "../common/pyproject.toml", "", false},
+		{"pyproject.toml", "/etc/pyproject.toml", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := resolveLink(tt.link, tt.target)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("resolveLink(%q, %q) = %q, %v, want %q, %v", tt.link, tt.target, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestScanProjectSymlinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := r.URL.Path
+		switch {
+		// Project 1 symlinks pyproject.toml to a shared copy
+		case strings.HasSuffix(p, "/projects/1/repository/files/pyproject.toml/raw"):
+			w.Write([]byte("shared/pyproject.toml"))
+		case strings.HasSuffix(p, "/projects/1/repository/files/shared/pyproject.toml/raw"):
+			w.Write([]byte("[project]\nrequires-python = \">=3.11\"\n"))
+		case strings.HasSuffix(p, "/projects/1/repository/tree"):
+			w.Write([]byte(`[{"name": "pyproject.toml", "path": "pyproject.toml", "type": "blob", "mode": "120000"}]`))
+
+		// Project 2's link leaves the repository
+		case strings.HasSuffix(p, "/projects/2/repository/files/pyproject.toml/raw"):
+			w.Write([]byte("../common/pyproject.toml"))
+		case strings.HasSuffix(p, "/projects/2/repository/tree"):
+			w.Write([]byte(`[{"name": "pyproject.toml", "path": "pyproject.toml", "type": "blob", "mode": "120000"}]`))
+
+		// Project 3 has a submodule and a symlinked directory as subdirs
+		case strings.HasSuffix(p, "/projects/3/repository/tree"):
+			w.Write([]byte(`[{"name": "api", "path": "services/api", "type": "commit", "mode": "160000"},
+				{"name": "web", "path": "services/web", "type": "blob", "mode": "120000"}]`))
+		case strings.HasSuffix(p, "/projects/3/repository/files/services/web/raw"):
+			w.Write([]byte("../apps/web"))
+		case strings.HasSuffix(p, "/projects/3/repository/files/apps/web/.python-version/raw"):
+			w.Write([]byte("3.12\n"))
+		case strings.Contains(p, "/projects/3/repository/files/services/api/"):
+			t.Errorf("probed %s inside a submodule", p)
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	registry := rules.NewRegistry()
+	registry.MustRegister(parsers.GetPythonVersionFileRule())
+	registry.MustRegister(parsers.GetPyprojectTomlRule())
+
+	result := ScanProject(context.Background(), client, registry, &gitlab.Project{ID: 1, Name: "billing"}, 1, 3, VersionScanOptions{})
+	if result.PythonVersion != "3.11" || result.DetectionSource != "pyproject.toml" {
+		t.Errorf("got %q from %q, want 3.11 from the symlinked pyproject.toml", result.PythonVersion, result.DetectionSource)
+	}
+
+	result = ScanProject(context.Background(), client, registry, &gitlab.Project{ID: 2, Name: "search"}, 2, 3, VersionScanOptions{})
+	if result.PythonVersion != "" || !strings.Contains(strings.Join(result.Diagnostics, "\n"), "pyproject.toml is a symlink to ../common/pyproject.toml outside the repository") {
+		t.Errorf("got %q with diagnostics %q, want no version and the escaping symlink reported", result.PythonVersion, result.Diagnostics)
+	}
+
+	opts := VersionScanOptions{Subdirs: []string{"services/api", "services/web"}}
+	result = ScanProject(context.Background(), client, registry, &gitlab.Project{ID: 3, Name: "platform"}, 3, 3, opts)
+	if result.PythonVersion != "3.12" || result.DetectionSource != "apps/web/.python-version" {
+		t.Errorf("got %q from %q, want 3.12 from the symlinked directory's target", result.PythonVersion, result.DetectionSource)
+	}
+	if !strings.Contains(strings.Join(result.Diagnostics, "\n"), "services/api is a submodule") {
+		t.Errorf("Diagnostics = %q, want the submodule reported", result.Diagnostics)
+	}
+}
@@ -0,0 +1,721 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	apperrors "github.com/gbjohnso/gitlab-python-scanner/internal/errors"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/parsers"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+)
+
+// VersionScanOptions controls how ScanProject probes a project's files
+type VersionScanOptions struct {
+	// CrossCheck keeps probing lower-priority sources after the first
+	// detection and records whether they agree with it
+	CrossCheck bool
+
+	// WithMetadata fetches files via the metadata-bearing file API so the
+	// detecting file's last commit and size are recorded on the result
+	WithMetadata bool
+
+	// ProjectTimeout bounds the total time spent on one project across all
+	// of its file operations (0 = no limit)
+	ProjectTimeout time.Duration
+
+	// Subdirs are extra directories to probe for each rule's file, in order,
+	// before the repository root (e.g. "services/api" in a monorepo)
+	Subdirs []string
+
+	// IgnorePaths are globs for candidate files never to read, so vendored
+	// or generated copies of a packaging file can't hijack detection
+	IgnorePaths []string
+
+	// OnlyPaths, if set, are globs a candidate file must match to be read,
+	// for scoping a large monorepo to the paths that matter; IgnorePaths
+	// still apply to the files they allow
+	OnlyPaths []string
+
+	// Bounds rejects implausible detected versions, which are recorded as
+	// diagnostics instead of reported
+	Bounds output.VersionBounds
+
+	// Dependencies collects the packages declared in requirements.txt (at
+	// the root and each subdir) for the dependency report
+	Dependencies bool
+
+	// AtLatestTag scans each project's most recently updated tag instead
+	// of its default branch; projects without tags are reported as errors
+	AtLatestTag bool
+
+	// Refs maps project paths (with namespace) to the ref to scan instead of
+	// the default branch; a project listed here isn't affected by AtLatestTag
+	Refs map[string]string
+
+	// MaxCandidates caps the files fetched per project, highest-priority
+	// rules first (0 = no limit); hitting it marks the result CandidatesLimited
+	MaxCandidates int
+
+	// DecayHalfLife halves a detection's confidence for every half-life its
+	// file has gone without a commit (0 = no decay)
+	DecayHalfLife time.Duration
+
+	// Strict records every candidate file a rule's parser failed on in
+	// ParseErrors instead of silently trying the next candidate
+	Strict bool
+
+	// IgnoreFile fetches each project's IgnoreFileName and skips its globs
+	// along with IgnorePaths
+	IgnoreFile bool
+
+	// IgnoreFiles remembers which projects have an ignore file so unchanged
+	// projects aren't probed for it again (nil = probe every scan)
+	IgnoreFiles *IgnoreFileCache
+
+	// Explain records every candidate file's outcome and why the reported
+	// version won as the result's Explanation
+	Explain bool
+
+	// Tiers, if set, reports the first detection in the highest confidence
+	// tier found instead of the first detection: lower tiers are only
+	// fallen back to when no higher-tier file declares a version
+	Tiers *output.ConfidenceTiers
+}
+
+// DefaultIgnorePaths are the vendored and generated directories whose files
+// never count toward detection; callers add their own to them
+var DefaultIgnorePaths = []string{
+	"**/vendor/**",
+	"**/node_modules/**",
+	"**/.tox/**",
+	"**/site-packages/**",
+}
+
+// candidatePaths returns the paths to probe for a rule's file: each subdir
+// in order, then the repository root, leaving out paths matching ignore
+func candidatePaths(filename string, subdirs, ignore []string) []string {
+	paths := make([]string, 0, len(subdirs)+1)
+	for _, dir := range subdirs {
+		if p := path.Join(dir, filename); !ignoredPath(p, ignore) {
+			paths = append(paths, p)
+		}
+	}
+	if ignoredPath(filename, ignore) {
+		return paths
+	}
+	return append(paths, filename)
+}
+
+// ruleCandidates returns the paths to fetch for rule: its file in each of
+// subdirs and then at the root or, when its file pattern is a glob such as
+// "playbook*.yml", every file in the repository tree the rule matches
+func ruleCandidates(rule *rules.SearchRule, subdirs []string, listTree func() ([]*gitlab.TreeFile, error)) []string {
+	if !strings.ContainsAny(rule.Condition.FilePattern, "*?[") {
+		return candidatePaths(rule.Condition.FilePattern, subdirs, nil)
+	}
+
+	// A project whose tree can't be listed (e.g. an empty repository)
+	// has nothing to match
+	files, err := listTree()
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, file := range files {
+		if rule.Matches(file.Name, file.Path) {
+			paths = append(paths, file.Path)
+		}
+	}
+	return paths
+}
+
+// ignoredPath reports whether p matches any of the ignore globs
+func ignoredPath(p string, ignore []string) bool {
+	for _, pattern := range ignore {
+		if matchPathGlob(strings.Split(pattern, "/"), strings.Split(p, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedPath reports whether p matches any of the allow globs, or there are
+// none
+func allowedPath(p string, allow []string) bool {
+	return len(allow) == 0 || ignoredPath(p, allow)
+}
+
+// matchPathGlob matches path segments against pattern segments, where a "**"
+// segment matches zero or more whole segments and any other segment is a
+// path.Match pattern
+func matchPathGlob(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchPathGlob(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchPathGlob(pattern[1:], segments[1:])
+}
+
+// fetchFile retrieves a project file at ref ("" for the default branch),
+// using the metadata-bearing API when opts.WithMetadata is set (metadata is
+// nil otherwise)
+func fetchFile(ctx context.Context, client *gitlab.Client, projectID interface{}, filename, ref string, opts VersionScanOptions) ([]byte, *gitlab.FileContent, error) {
+	fileOpts := &gitlab.GetFileOptions{Ref: ref}
+	if !opts.WithMetadata {
+		content, err := client.GetRawFile(ctx, projectID, filename, fileOpts)
+		return content, nil, err
+	}
+
+	file, err := client.GetFile(ctx, projectID, filename, fileOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file.Content, file, nil
+}
+
+// ScanProject scans a single project for Python version information: each
+// enabled rule's file is fetched in priority order (see DetectVersion for
+// how one file is judged) until a version is found. Failures are reported on
+// the result rather than returned.
+func ScanProject(ctx context.Context, client *gitlab.Client, registry *rules.Registry, project *gitlab.Project, index, total int, opts VersionScanOptions) *output.ScanResult {
+	result := &output.ScanResult{
+		ProjectName:    project.Name,
+		ProjectPath:    project.PathWithNamespace,
+		Namespace:      project.Namespace,
+		TopLevelGroup:  project.TopLevelGroup,
+		Index:          index,
+		TotalProjects:  total,
+		LastActivityAt: project.LastActivityAt,
+	}
+
+	// selectedRank is the confidence tier of the reported detection
+	selectedRank := 0
+
+	if opts.Explain {
+		result.Explanation = &output.Explanation{}
+		defer func() { result.Explanation.Decision = explainDecision(result, opts, selectedRank) }()
+	}
+
+	if opts.ProjectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.ProjectTimeout)
+		defer cancel()
+	}
+
+	// Get all enabled rules to determine which files to check
+	enabledRules := registry.ListEnabled()
+	if len(enabledRules) == 0 {
+		result.Error = fmt.Errorf("no enabled rules found")
+		return result
+	}
+
+	// AtLatestTag scans what was last released rather than what's on the default branch
+	ref, pinned := opts.Refs[project.PathWithNamespace]
+	if opts.AtLatestTag && !pinned {
+		tag, err := client.LatestTag(ctx, project.ID)
+		if err != nil {
+			result.Error = fmt.Errorf("failed to find latest tag: %w", err)
+			return result
+		}
+		if tag == "" {
+			result.Error = fmt.Errorf("project has no tags")
+			return result
+		}
+		ref = tag
+	}
+
+	// Record the commit behind the ref so the scan can be reproduced after
+	// the branch moves, and read every file at that commit so a push during
+	// the scan can't mix two revisions. Resolving costs one request per
+	// project unless the ref is already a full SHA; a project whose ref
+	// can't be resolved is still scanned at the ref itself.
+	fetchRef := ref
+	if isCommitSHA(ref) {
+		result.CommitSHA = ref
+	} else {
+		commitRef := ref
+		if commitRef == "" {
+			commitRef = project.DefaultBranch
+		}
+		if commitRef != "" {
+			if sha, err := client.ResolveCommit(ctx, project.ID, commitRef); err == nil {
+				result.CommitSHA = sha
+				fetchRef = sha
+				// Later reads of the default branch share cached files with this scan
+				if cache := gitlab.FileCacheFrom(ctx); cache != nil && ref == "" {
+					cache.SetHead(sha)
+				}
+			}
+		}
+	}
+
+	// The file API can't see into submodules or through symlinked directories
+	subdirs := opts.Subdirs
+	if len(subdirs) > 0 {
+		subdirs = checkSubdirs(ctx, client, project.ID, subdirs, fetchRef, result)
+	}
+
+	// The project's own ignore file adds to the caller's globs
+	ignorePaths := opts.IgnorePaths
+	if opts.IgnoreFile {
+		file := loadIgnoreFile(ctx, client, project, fetchRef, opts.IgnoreFiles)
+		ignorePaths = append(ignorePaths[:len(ignorePaths):len(ignorePaths)], file.patterns...)
+		for _, line := range file.invalid {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: ignored unsupported pattern %q", IgnoreFileName, line))
+		}
+	}
+
+	if opts.Dependencies {
+		result.Dependencies = collectDependencies(ctx, client, project.ID, fetchRef, subdirs, ignorePaths)
+	}
+
+	// The repository tree is listed at most once per project: for rules
+	// with a glob file pattern and to classify an undetected project
+	var tree []*gitlab.TreeFile
+	var treeErr error
+	treeListed := false
+	listTree := func() ([]*gitlab.TreeFile, error) {
+		if !treeListed {
+			treeListed = true
+			tree, treeErr = client.ListRepositoryTree(ctx, project.ID, &gitlab.ListTreeOptions{Recursive: true, Ref: fetchRef})
+		}
+		return tree, treeErr
+	}
+
+	// Try each rule's file pattern until we find a match
+	// Rules are already sorted by priority (highest first)
+	fetched := 0
+	// Cross-checking compares the declared constraint against CI's versions
+	var declared *rules.SearchResult
+	var ciDetections []*rules.SearchResult
+probe:
+	for _, rule := range enabledRules {
+		// Stop probing once the project's deadline has passed, before a
+		// glob rule lists the tree
+		if ctx.Err() != nil {
+			break
+		}
+		for _, filename := range ruleCandidates(rule, subdirs, listTree) {
+			if ctx.Err() != nil {
+				break probe
+			}
+			if ignoredPath(filename, ignorePaths) || !allowedPath(filename, opts.OnlyPaths) {
+				explainStep(result, rule, filename, output.ExplainIgnored, "", nil)
+				continue
+			}
+
+			if opts.MaxCandidates > 0 && fetched >= opts.MaxCandidates {
+				result.CandidatesLimited = true
+				result.Diagnostics = append(result.Diagnostics,
+					fmt.Sprintf("stopped after %d candidate files (--max-candidates); lower-priority files were not checked", fetched))
+				break probe
+			}
+			fetched++
+
+			// Try to fetch the file from the project
+			content, metadata, err := fetchFile(ctx, client, project.ID, filename, fetchRef, opts)
+			if err != nil {
+				// File not found or other error - try next candidate
+				var appErr *apperrors.AppError
+				if errors.As(err, &appErr) && appErr.Type == apperrors.ErrorTypeNotFound {
+					explainStep(result, rule, filename, output.ExplainMissing, "", nil)
+				} else {
+					explainStep(result, rule, filename, output.ExplainFetchError, err.Error(), nil)
+				}
+				continue
+			}
+
+			searchResult, err := detectFile(ctx, rule, content, filename, opts.Bounds)
+			// A symlink's content is its target path, which no parser reads
+			if searchResult == nil {
+				if target := symlinkTarget(content); target != "" {
+					linked, linkedMetadata, diagnostic := followSymlink(ctx, client, project.ID, filename, target, fetchRef, opts)
+					if diagnostic != "" {
+						result.Diagnostics = append(result.Diagnostics, diagnostic)
+						explainStep(result, rule, filename, output.ExplainNotRegular, diagnostic, nil)
+						continue
+					}
+					if linked != nil {
+						metadata = linkedMetadata
+						searchResult, err = detectFile(ctx, rule, linked, filename, opts.Bounds)
+					}
+				}
+			}
+			var implausible *ImplausibleVersionError
+			switch {
+			case errors.As(err, &implausible):
+				result.Diagnostics = append(result.Diagnostics, implausible.Error())
+				explainStep(result, rule, filename, output.ExplainImplausible, implausible.Err.Error(), nil)
+				continue
+			case err != nil:
+				// The file exists, so this is the rule failing rather than a
+				// missing file; record it and try the next candidate
+				if opts.Strict && errors.Is(err, rules.ErrParse) {
+					result.ParseErrors = append(result.ParseErrors, fmt.Sprintf("%s: %v", filename, err))
+				} else {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", filename, err))
+				}
+				explainStep(result, rule, filename, output.ExplainRuleError, err.Error(), nil)
+				continue
+			case searchResult == nil:
+				explainStep(result, rule, filename, output.ExplainNoVersion, "", nil)
+				continue
+			}
+
+			// Policy wants every project to declare its version explicitly
+			if rule.HasTag(rules.TagExplicit) {
+				result.ExplicitSource = true
+			}
+			if declared == nil && searchResult.Constraint != "" {
+				declared = searchResult
+			}
+			if rule.HasTag(rules.TagCI) {
+				ciDetections = append(ciDetections, searchResult)
+			}
+
+			// The first (highest-priority) detection is authoritative, unless
+			// a later one is in a higher confidence tier
+			rank := opts.Tiers.Rank(searchResult.Confidence)
+			if result.PythonVersion == "" || rank < selectedRank {
+				if result.PythonVersion != "" {
+					outrank(result, opts.CrossCheck)
+				}
+				selectedRank = rank
+				explainStep(result, rule, filename, output.ExplainSelected, "", searchResult)
+				result.PythonVersion = searchResult.Version
+				result.DetectionSource = sourceAtRef(searchResult.Source, ref)
+				result.VersionMax = searchResult.VersionMax
+				result.RawValue = searchResult.RawValue
+				result.Confidence = searchResult.Confidence
+				result.IsPython2 = output.IsPython2(searchResult.Version)
+				result.LastCommitID, result.SourceSize = "", 0
+				if metadata != nil {
+					result.LastCommitID = metadata.LastCommitID
+					result.SourceSize = metadata.Size
+				}
+				if opts.DecayHalfLife > 0 {
+					result.RawConfidence, result.SourceUpdated = 0, time.Time{}
+					decayConfidence(ctx, client, project.ID, filename, fetchRef, result, opts.DecayHalfLife)
+				}
+				result.VersionMismatch = crossChecksDisagree(result)
+				// Nothing can outrank the top tier
+				if !opts.CrossCheck && rank == 0 {
+					return result
+				}
+				continue
+			}
+			if !opts.CrossCheck {
+				explainStep(result, rule, filename, output.ExplainOutranked, "", searchResult)
+				continue
+			}
+
+			// Cross-check mode: record every further detection and flag disagreement
+			explainStep(result, rule, filename, output.ExplainCrossCheck, "", searchResult)
+			result.CrossChecks = append(result.CrossChecks, output.CrossCheck{
+				Source:  sourceAtRef(searchResult.Source, ref),
+				Version: searchResult.Version,
+			})
+			if !output.VersionsAgree(result.PythonVersion, searchResult.Version) {
+				result.VersionMismatch = true
+			}
+		}
+	}
+
+	if declared != nil {
+		result.CIDrift = ciDrift(declared, ciDetections, ref)
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		// Keep whatever was detected before the deadline
+		result.TimedOut = true
+		return result
+	}
+
+	if result.PythonVersion == "" {
+		files, err := listTree()
+		if err == nil {
+			// Listing failures (e.g. empty repositories) leave the project unclassified
+			result.Classification = classifyUndetected(files, ignorePaths)
+		}
+	}
+
+	return result
+}
+
+// ciDrift describes each CI detection whose version the declared constraint
+// rules out, e.g. ".gitlab-ci.yml runs Python 3.9, outside pyproject.toml's
+// >=3.11", or returns "" if CI only runs supported versions
+func ciDrift(declared *rules.SearchResult, ci []*rules.SearchResult, ref string) string {
+	var parts []string
+	for _, detection := range ci {
+		if output.ConstraintExcludes(declared.Version, declared.VersionMax, detection.Version) {
+			parts = append(parts, fmt.Sprintf("%s runs Python %s, outside %s's %s",
+				sourceAtRef(detection.Source, ref), detection.Version, sourceAtRef(declared.Source, ref), declared.Constraint))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// explainStep records one candidate file's outcome on result's Explanation,
+// if it has one; found is the detection, if any
+func explainStep(result *output.ScanResult, rule *rules.SearchRule, filename, outcome, detail string, found *rules.SearchResult) {
+	if result.Explanation == nil {
+		return
+	}
+	step := output.ExplainStep{Rule: rule.Name, Priority: rule.Priority, File: filename, Outcome: outcome, Detail: detail}
+	if found != nil {
+		step.Version = found.Version
+		step.Confidence = found.Confidence
+	}
+	result.Explanation.Steps = append(result.Explanation.Steps, step)
+}
+
+// outrank demotes result's reported detection, which a detection in a
+// higher confidence tier is replacing, to a cross-check when crossCheck is
+// set; disagreement is judged again against the new version
+func outrank(result *output.ScanResult, crossCheck bool) {
+	if result.Explanation != nil {
+		steps := result.Explanation.Steps
+		for i := len(steps) - 1; i >= 0; i-- {
+			if steps[i].Outcome == output.ExplainSelected {
+				steps[i].Outcome = output.ExplainOutranked
+				break
+			}
+		}
+	}
+	if !crossCheck {
+		return
+	}
+	result.CrossChecks = append(result.CrossChecks, output.CrossCheck{
+		Source:  result.DetectionSource,
+		Version: result.PythonVersion,
+	})
+}
+
+// crossChecksDisagree reports whether any of result's cross-checks
+// disagrees with its version
+func crossChecksDisagree(result *output.ScanResult) bool {
+	for _, cc := range result.CrossChecks {
+		if !output.VersionsAgree(result.PythonVersion, cc.Version) {
+			return true
+		}
+	}
+	return false
+}
+
+// explainDecision says why result ended up with its version, or without
+// one; selectedRank is the confidence tier of the version under opts.Tiers
+func explainDecision(result *output.ScanResult, opts VersionScanOptions, selectedRank int) string {
+	crossCheck := opts.CrossCheck
+	var decision string
+	switch {
+	case result.Error != nil:
+		return fmt.Sprintf("no files were checked: %v", result.Error)
+	case result.PythonVersion != "" && opts.Tiers != nil:
+		decision = fmt.Sprintf("Python %s from %s, the first detection in the highest confidence tier found (%s; --authoritative ranks confidence tiers above priority)",
+			result.PythonVersion, result.DetectionSource, output.ConfidenceTierName(selectedRank))
+		switch {
+		case !crossCheck && selectedRank == 0:
+			decision += "; lower-priority files were not checked"
+		case len(result.CrossChecks) == 0:
+			decision += "; no other file detected a version"
+		case result.VersionMismatch:
+			decision += fmt.Sprintf("; %d other detection(s) were only cross-checked, and some disagree", len(result.CrossChecks))
+		default:
+			decision += fmt.Sprintf("; %d other detection(s) were only cross-checked, and all agree", len(result.CrossChecks))
+		}
+	case result.PythonVersion != "":
+		decision = fmt.Sprintf("Python %s from %s, the first detection in rule priority order (first match wins; confidence doesn't outrank priority)",
+			result.PythonVersion, result.DetectionSource)
+		switch {
+		case !crossCheck:
+			decision += "; lower-priority files were not checked"
+		case len(result.CrossChecks) == 0:
+			decision += "; no lower-priority file detected a version"
+		case result.VersionMismatch:
+			decision += fmt.Sprintf("; %d lower-priority detection(s) were only cross-checked, and some disagree", len(result.CrossChecks))
+		default:
+			decision += fmt.Sprintf("; %d lower-priority detection(s) were only cross-checked, and all agree", len(result.CrossChecks))
+		}
+	default:
+		decision = "no candidate file yielded a plausible version"
+	}
+	if result.TimedOut {
+		decision += "; the project's deadline expired before every file was checked"
+	}
+	if result.CandidatesLimited {
+		decision += "; probing stopped at --max-candidates"
+	}
+	return decision
+}
+
+// DetectVersion runs registry's enabled rules that match filename, a path
+// within a repository such as "services/api/pyproject.toml", over content in
+// priority order and returns the first Python version detected, or nil if
+// there is none. Versions outside output.DefaultVersionBounds are discarded
+// as they are by ScanProject. The rules' errors are only returned when
+// nothing was detected.
+func DetectVersion(ctx context.Context, content []byte, filename string, registry *rules.Registry) (*rules.SearchResult, error) {
+	var errs []error
+	for _, rule := range registry.FindMatchingRules(path.Base(filename), filename) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		searchResult, err := detectFile(ctx, rule, content, filename, output.DefaultVersionBounds)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if searchResult != nil {
+			return searchResult, nil
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// ImplausibleVersionError reports a detection discarded because its version
+// is outside the VersionBounds; it's assumed to be a parser false positive
+type ImplausibleVersionError struct {
+	Source string // Where the version was read from
+	Err    error  // Why the version was rejected
+}
+
+func (e *ImplausibleVersionError) Error() string {
+	return fmt.Sprintf("discarded version from %s: %v", e.Source, e.Err)
+}
+
+func (e *ImplausibleVersionError) Unwrap() error {
+	return e.Err
+}
+
+// detectFile applies rule to one candidate file's content. It returns nil
+// without an error when the rule finds no version there, including
+// dependency-only matches (Found with no Version), and an
+// *ImplausibleVersionError when the version is outside bounds.
+func detectFile(ctx context.Context, rule *rules.SearchRule, content []byte, filename string, bounds output.VersionBounds) (*rules.SearchResult, error) {
+	searchResult, err := rule.Apply(ctx, content, filename)
+	if err != nil {
+		return nil, err
+	}
+	if !searchResult.HasVersion() {
+		return nil, nil
+	}
+
+	// Discard garbage from the looser parsers rather than report it
+	if err := bounds.CheckVersion(searchResult.Version); err != nil {
+		return nil, &ImplausibleVersionError{Source: searchResult.Source, Err: err}
+	}
+	return searchResult, nil
+}
+
+// decayConfidence lowers result's confidence by the age of filename's last
+// commit (see output.DecayConfidence), keeping the original as RawConfidence.
+// If the commit date can't be found the confidence is left as is and the
+// failure is recorded as a diagnostic.
+func decayConfidence(ctx context.Context, client *gitlab.Client, projectID interface{}, filename, ref string, result *output.ScanResult, halfLife time.Duration) {
+	commitID := result.LastCommitID
+	if commitID == "" {
+		metadata, err := client.GetFileMetadata(ctx, projectID, filename, &gitlab.GetFileOptions{Ref: ref})
+		if err != nil {
+			result.Diagnostics = append(result.Diagnostics, fmt.Sprintf("confidence not decayed: %v", err))
+			return
+		}
+		commitID = metadata.LastCommitID
+	}
+
+	updated, err := client.CommitDate(ctx, projectID, commitID)
+	if err != nil {
+		result.Diagnostics = append(result.Diagnostics, fmt.Sprintf("confidence not decayed: %v", err))
+		return
+	}
+
+	result.SourceUpdated = updated
+	result.RawConfidence = result.Confidence
+	result.Confidence = output.DecayConfidence(result.Confidence, time.Since(updated), halfLife)
+}
+
+// sourceAtRef qualifies a detection source with the ref it was read from,
+// e.g. "pyproject.toml@v2.1.0"; sources from the default branch are unchanged
+func sourceAtRef(source, ref string) string {
+	if ref == "" {
+		return source
+	}
+	return source + "@" + ref
+}
+
+// collectDependencies returns the packages declared in every requirements.txt
+// found at the repository root or under one of subdirs, read at ref ("" for
+// the default branch); paths matching ignore are skipped
+func collectDependencies(ctx context.Context, client *gitlab.Client, projectID interface{}, ref string, subdirs, ignore []string) []output.Dependency {
+	var deps []output.Dependency
+	for _, filename := range candidatePaths("requirements.txt", subdirs, ignore) {
+		content, err := client.GetRawFile(ctx, projectID, filename, &gitlab.GetFileOptions{Ref: ref})
+		if err != nil {
+			continue
+		}
+		for _, req := range parsers.ParseRequirements(content) {
+			deps = append(deps, output.Dependency{Name: req.Name, Specifier: req.Specifier})
+		}
+	}
+	return deps
+}
+
+// pythonPackagingFiles are file names that mark a repository as Python even
+// when it declares no version our rules can parse
+var pythonPackagingFiles = map[string]bool{
+	"setup.py":         true,
+	"setup.cfg":        true,
+	"pyproject.toml":   true,
+	"Pipfile":          true,
+	"requirements.txt": true,
+	"tox.ini":          true,
+	"environment.yml":  true,
+}
+
+// classifyUndetected decides whether a project with no detected version
+// still contains Python code or packaging files outside the ignored paths
+func classifyUndetected(files []*gitlab.TreeFile, ignore []string) string {
+	for _, f := range files {
+		if ignoredPath(f.Path, ignore) {
+			continue
+		}
+		if strings.HasSuffix(f.Name, ".py") || pythonPackagingFiles[f.Name] {
+			return output.ClassPythonNoVersion
+		}
+	}
+	return output.ClassNonPython
+}
+
+// isCommitSHA reports whether ref is a full SHA-1 or SHA-256 commit ID,
+// which needs no resolving
+func isCommitSHA(ref string) bool {
+	if len(ref) != 40 && len(ref) != 64 {
+		return false
+	}
+	for _, c := range ref {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,886 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/gitlab"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/output"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/parsers"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/rules"
+)
+
+func TestClassifyUndetected(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{
+			name:  "Python source without version",
+			files: []string{"README.md", "src/app/main.py"},
+			want:  output.ClassPythonNoVersion,
+		},
+		{
+			name:  "Packaging file only",
+			files: []string{"setup.cfg"},
+			want:  output.ClassPythonNoVersion,
+		},
+		{
+			name:  "Go project",
+			files: []string{"go.mod", "main.go", "README.md"},
+			want:  output.ClassNonPython,
+		},
+		{
+			name:  "Empty repository",
+			files: nil,
+			want:  output.ClassNonPython,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var files []*gitlab.TreeFile
+			for _, path := range tt.files {
+				files = append(files, &gitlab.TreeFile{Name: filepath.Base(path), Path: path})
+			}
+
+			if got := classifyUndetected(files, nil); got != tt.want {
+				t.Errorf("classifyUndetected() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyUndetectedIgnoresVendored(t *testing.T) {
+	files := []*gitlab.TreeFile{
+		{Name: "main.go", Path: "main.go"},
+		{Name: "setup.py", Path: "vendor/github.com/x/setup.py"},
+		{Name: "util.py", Path: "node_modules/pkg/util.py"},
+	}
+
+	if got := classifyUndetected(files, DefaultIgnorePaths); got != output.ClassNonPython {
+		t.Errorf("classifyUndetected() = %q, want %q", got, output.ClassNonPython)
+	}
+}
+
+func TestScanProjectTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every file fetch is slower than the project deadline
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token", Stats: true})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	project := &gitlab.Project{ID: 1, Name: "monorepo"}
+	// Every other candidate is outside --only-path, which --explain records
+	opts := VersionScanOptions{ProjectTimeout: 50 * time.Millisecond, Explain: true, OnlyPaths: []string{".python-version"}}
+
+	start := time.Now()
+	result := ScanProject(context.Background(), client, parsers.DefaultRegistry(), project, 1, 1, opts)
+
+	if !result.TimedOut {
+		t.Error("TimedOut = false, want true")
+	}
+	if result.PythonVersion != "" {
+		t.Errorf("PythonVersion = %q, want empty", result.PythonVersion)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("scanProject took %v, want it bounded by the project timeout", elapsed)
+	}
+
+	// Probing stops at the fetch the deadline cut short
+	if got := client.Stats().Requests; got != 1 {
+		t.Errorf("client sent %d requests, want only the one the deadline interrupted", got)
+	}
+	if steps := result.Explanation.Steps; len(steps) != 1 {
+		t.Errorf("explanation has %d steps, want 1: %+v", len(steps), steps)
+	}
+}
+
+func TestCandidatePaths(t *testing.T) {
+	got := candidatePaths("pyproject.toml", []string{"services/api", "services/worker/"}, nil)
+	want := []string{"services/api/pyproject.toml", "services/worker/pyproject.toml", "pyproject.toml"}
+
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("candidatePaths() = %v, want %v", got, want)
+	}
+
+	if got := candidatePaths("Pipfile", nil, nil); len(got) != 1 || got[0] != "Pipfile" {
+		t.Errorf("candidatePaths() without subdirs = %v, want [Pipfile]", got)
+	}
+}
+
+func TestCandidatePathsIgnored(t *testing.T) {
+	got := candidatePaths("setup.py", []string{"vendor/requests", "services/api"}, DefaultIgnorePaths)
+	want := []string{"services/api/setup.py", "setup.py"}
+
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("candidatePaths() = %v, want %v", got, want)
+	}
+}
+
+func TestIgnoredPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		ignore []string
+		want   bool
+	}{
+		{"vendor/lib/setup.py", DefaultIgnorePaths, true},
+		{"services/api/node_modules/pkg/setup.py", DefaultIgnorePaths, true},
+		{".tox/py311/lib/python3.11/site-packages/x/setup.py", DefaultIgnorePaths, true},
+		{"services/api/setup.py", DefaultIgnorePaths, false},
+		{"setup.py", DefaultIgnorePaths, false},
+		{"vendored/setup.py", DefaultIgnorePaths, false},
+		{"third_party/lib/setup.py", []string{"third_party/**"}, true},
+		{"lib/third_party/setup.py", []string{"third_party/**"}, false},
+		{"build/gen/pyproject.toml", []string{"**/gen/*.toml"}, true},
+		{"setup.py", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := ignoredPath(tt.path, tt.ignore); got != tt.want {
+			t.Errorf("ignoredPath(%q, %v) = %v, want %v", tt.path, tt.ignore, got, tt.want)
+		}
+	}
+}
+
+func TestScanProjectSubdir(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/files/services/api/.python-version/raw") {
+			w.Write([]byte("3.12\n"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	project := &gitlab.Project{ID: 1, Name: "monorepo"}
+	opts := VersionScanOptions{Subdirs: []string{"services/api"}}
+
+	result := ScanProject(context.Background(), client, parsers.DefaultRegistry(), project, 1, 1, opts)
+
+	if result.PythonVersion != "3.12" {
+		t.Errorf("PythonVersion = %q, want 3.12", result.PythonVersion)
+	}
+	if result.DetectionSource != "services/api/.python-version" {
+		t.Errorf("DetectionSource = %q, want services/api/.python-version", result.DetectionSource)
+	}
+	if result.Confidence != 1.0 {
+		t.Errorf("Confidence = %v, want 1.0 for .python-version", result.Confidence)
+	}
+	if !result.ExplicitSource {
+		t.Error("ExplicitSource = false, want true for .python-version")
+	}
+	if result.RawValue != "3.12" {
+		t.Errorf("RawValue = %q, want 3.12", result.RawValue)
+	}
+}
+
+func TestScanProjectDecayConfidence(t *testing.T) {
+	halfLife := 365 * 24 * time.Hour
+	committed := time.Now().Add(-2 * halfLife).UTC().Truncate(time.Second)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			w.Write([]byte("3.8\n"))
+		case r.Method == http.MethodHead && strings.HasSuffix(r.URL.Path, "/files/.python-version"):
+			w.Header().Set("X-Gitlab-Last-Commit-Id", "abc123")
+		case strings.HasSuffix(r.URL.Path, "/repository/commits/abc123"):
+			fmt.Fprintf(w, `{"id": "abc123", "committed_date": %q}`, committed.Format(time.RFC3339))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	project := &gitlab.Project{ID: 1, Name: "legacy"}
+	result := ScanProject(context.Background(), client, parsers.DefaultRegistry(), project, 1, 1, VersionScanOptions{DecayHalfLife: halfLife})
+
+	if result.RawConfidence != 1.0 {
+		t.Errorf("RawConfidence = %v, want 1.0", result.RawConfidence)
+	}
+	if result.Confidence < 0.24 || result.Confidence > 0.26 {
+		t.Errorf("Confidence = %v, want about 0.25 after two half-lives", result.Confidence)
+	}
+	if !result.SourceUpdated.Equal(committed) {
+		t.Errorf("SourceUpdated = %v, want %v", result.SourceUpdated, committed)
+	}
+}
+
+func TestScanProjectIgnoresDependencyOnlyRequirements(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/files/requirements.txt/raw") {
+			w.Write([]byte("requests==2.31.0\nflask>=3.0\n"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	project := &gitlab.Project{ID: 1, Name: "service"}
+	result := ScanProject(context.Background(), client, parsers.DefaultRegistry(), project, 1, 1, VersionScanOptions{})
+
+	if result.PythonVersion != "" || result.DetectionSource != "" {
+		t.Errorf("got Python %q from %q, want no detection for dependency-only requirements.txt",
+			result.PythonVersion, result.DetectionSource)
+	}
+}
+
+func TestScanProjectCollectsDependencies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/files/requirements.txt/raw"):
+			w.Write([]byte("Django==4.2.7\n-e .\n"))
+		case strings.HasSuffix(r.URL.Path, "/files/services/api/requirements.txt/raw"):
+			w.Write([]byte("requests>=2.28\n"))
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			w.Write([]byte("3.12\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	project := &gitlab.Project{ID: 1, Name: "monorepo"}
+	opts := VersionScanOptions{Subdirs: []string{"services/api"}, Dependencies: true}
+	result := ScanProject(context.Background(), client, parsers.DefaultRegistry(), project, 1, 1, opts)
+
+	// Dependencies are collected even though .python-version ends the detection early
+	want := []output.Dependency{{Name: "requests", Specifier: ">=2.28"}, {Name: "Django", Specifier: "==4.2.7"}}
+	if fmt.Sprint(result.Dependencies) != fmt.Sprint(want) {
+		t.Errorf("Dependencies = %v, want %v", result.Dependencies, want)
+	}
+	if result.PythonVersion != "3.12" {
+		t.Errorf("PythonVersion = %q, want 3.12", result.PythonVersion)
+	}
+}
+
+func TestScanProjectDiscardsImplausibleVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			w.Write([]byte("7.4\n"))
+		case strings.HasSuffix(r.URL.Path, "/files/runtime.txt/raw"):
+			w.Write([]byte("python-3.11.5\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	project := &gitlab.Project{ID: 1, Name: "service"}
+	opts := VersionScanOptions{Bounds: output.DefaultVersionBounds}
+	result := ScanProject(context.Background(), client, parsers.DefaultRegistry(), project, 1, 1, opts)
+
+	// The implausible .python-version is skipped in favour of the next source
+	if result.PythonVersion != "3.11.5" || result.DetectionSource != "runtime.txt" {
+		t.Errorf("got Python %q from %q, want 3.11.5 from runtime.txt", result.PythonVersion, result.DetectionSource)
+	}
+	if len(result.Diagnostics) != 1 || !strings.Contains(result.Diagnostics[0], ".python-version") {
+		t.Errorf("Diagnostics = %v, want one entry for .python-version", result.Diagnostics)
+	}
+}
+
+func TestScanProjectAtLatestTag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/projects/1/repository/tags"):
+			w.Write([]byte(`[{"name": "v1.4.0"}]`))
+		case strings.HasSuffix(r.URL.Path, "/projects/2/repository/tags"):
+			w.Write([]byte(`[]`))
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			// The default branch has moved on since the release
+			if r.URL.Query().Get("ref") == "v1.4.0" {
+				w.Write([]byte("3.10\n"))
+			} else {
+				w.Write([]byte("3.12\n"))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	opts := VersionScanOptions{AtLatestTag: true}
+	result := ScanProject(context.Background(), client, parsers.DefaultRegistry(), &gitlab.Project{ID: 1, Name: "service"}, 1, 2, opts)
+	if result.PythonVersion != "3.10" || result.DetectionSource != ".python-version@v1.4.0" {
+		t.Errorf("got %q from %q, want 3.10 from .python-version@v1.4.0", result.PythonVersion, result.DetectionSource)
+	}
+
+	untagged := ScanProject(context.Background(), client, parsers.DefaultRegistry(), &gitlab.Project{ID: 2, Name: "scratch"}, 2, 2, opts)
+	if untagged.Error == nil {
+		t.Error("expected an error for a project without tags")
+	}
+}
+
+func TestScanProjectRefs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/repository/tags"):
+			w.Write([]byte(`[{"name": "v1.4.0"}]`))
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			switch r.URL.Query().Get("ref") {
+			case "develop":
+				w.Write([]byte("3.12\n"))
+			case "v1.4.0":
+				w.Write([]byte("3.10\n"))
+			default:
+				w.Write([]byte("3.11\n"))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	pinned := &gitlab.Project{ID: 1, Name: "billing", PathWithNamespace: "org/billing"}
+	other := &gitlab.Project{ID: 2, Name: "search", PathWithNamespace: "org/search"}
+
+	opts := VersionScanOptions{Refs: map[string]string{"org/billing": "develop"}}
+	result := ScanProject(context.Background(), client, parsers.DefaultRegistry(), pinned, 1, 2, opts)
+	if result.PythonVersion != "3.12" || result.DetectionSource != ".python-version@develop" {
+		t.Errorf("got %q from %q, want 3.12 from .python-version@develop", result.PythonVersion, result.DetectionSource)
+	}
+	result = ScanProject(context.Background(), client, parsers.DefaultRegistry(), other, 2, 2, opts)
+	if result.PythonVersion != "3.11" || result.DetectionSource != ".python-version" {
+		t.Errorf("got %q from %q, want 3.11 from the default branch's .python-version", result.PythonVersion, result.DetectionSource)
+	}
+
+	// A configured ref wins over --at-latest-tag
+	opts.AtLatestTag = true
+	result = ScanProject(context.Background(), client, parsers.DefaultRegistry(), pinned, 1, 2, opts)
+	if result.PythonVersion != "3.12" {
+		t.Errorf("got %q with AtLatestTag, want the configured ref's 3.12", result.PythonVersion)
+	}
+	result = ScanProject(context.Background(), client, parsers.DefaultRegistry(), other, 2, 2, opts)
+	if result.PythonVersion != "3.10" {
+		t.Errorf("got %q with AtLatestTag, want the tag's 3.10", result.PythonVersion)
+	}
+}
+
+func TestScanProjectCommitSHA(t *testing.T) {
+	const sha = "4f2c9e1a7b3d5c8e9f0a1b2c3d4e5f6a7b8c9d0e"
+	var resolves atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/repository/commits/"):
+			resolves.Add(1)
+			if strings.HasSuffix(r.URL.Path, "/repository/commits/main") {
+				w.Write([]byte(`{"id": "` + sha + `"}`))
+			} else {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			// Files are read at the resolved commit, not the moving branch
+			if r.URL.Query().Get("ref") == sha || r.URL.Query().Get("ref") == "develop" {
+				w.Write([]byte("3.12\n"))
+			} else {
+				w.Write([]byte("3.11\n"))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	project := &gitlab.Project{ID: 1, Name: "billing", PathWithNamespace: "org/billing", DefaultBranch: "main"}
+	result := ScanProject(context.Background(), client, parsers.DefaultRegistry(), project, 1, 1, VersionScanOptions{})
+	if result.CommitSHA != sha {
+		t.Errorf("CommitSHA = %q, want the default branch's %s", result.CommitSHA, sha)
+	}
+	if result.PythonVersion != "3.12" {
+		t.Errorf("PythonVersion = %q, want 3.12", result.PythonVersion)
+	}
+
+	// A ref that can't be resolved is still scanned, without a SHA
+	result = ScanProject(context.Background(), client, parsers.DefaultRegistry(), project, 1, 1, VersionScanOptions{Refs: map[string]string{"org/billing": "develop"}})
+	if result.CommitSHA != "" || result.PythonVersion != "3.12" {
+		t.Errorf("got %q with CommitSHA %q, want 3.12 and no SHA", result.PythonVersion, result.CommitSHA)
+	}
+
+	// A ref that's already a full SHA is scanned without resolving it
+	resolves.Store(0)
+	result = ScanProject(context.Background(), client, parsers.DefaultRegistry(), project, 1, 1, VersionScanOptions{Refs: map[string]string{"org/billing": sha}})
+	if result.CommitSHA != sha || result.PythonVersion != "3.12" {
+		t.Errorf("got %q with CommitSHA %q, want 3.12 at %s", result.PythonVersion, result.CommitSHA, sha)
+	}
+	if n := resolves.Load(); n != 0 {
+		t.Errorf("resolved a pinned SHA with %d requests, want none", n)
+	}
+}
+
+func TestScanProjectGlobRules(t *testing.T) {
+	var listings atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := r.URL.Path
+		switch {
+		case strings.HasSuffix(p, "/projects/1/repository/tree"):
+			listings.Add(1)
+			w.Write([]byte(`[{"name": "README.md", "path": "README.md", "type": "blob"},
+				{"name": "site.yaml", "path": "deploy/ansible/site.yaml", "type": "blob"}]`))
+		case strings.HasSuffix(p, "/projects/1/repository/files/deploy/ansible/site.yaml/raw"):
+			w.Write([]byte("- apt: name=python3.10 state=present\n"))
+		case strings.HasSuffix(p, "/projects/2/repository/tree"):
+			listings.Add(1)
+			w.Write([]byte(`[{"name": "main.go", "path": "main.go", "type": "blob"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	result := ScanProject(context.Background(), client, parsers.DefaultRegistry(), &gitlab.Project{ID: 1, Name: "infra"}, 1, 2, VersionScanOptions{})
+	if result.PythonVersion != "3.10" || result.DetectionSource != "deploy/ansible/site.yaml" {
+		t.Errorf("got %q from %q, want 3.10 from deploy/ansible/site.yaml", result.PythonVersion, result.DetectionSource)
+	}
+
+	// The listing used for glob rules is reused to classify an undetected project
+	listings.Store(0)
+	result = ScanProject(context.Background(), client, parsers.DefaultRegistry(), &gitlab.Project{ID: 2, Name: "api"}, 2, 2, VersionScanOptions{})
+	if result.Classification != output.ClassNonPython {
+		t.Errorf("Classification = %q, want %q", result.Classification, output.ClassNonPython)
+	}
+	if n := listings.Load(); n != 1 {
+		t.Errorf("listed the tree %d times, want 1", n)
+	}
+}
+
+func TestScanProjectOnlyPaths(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := r.URL.Path
+		switch {
+		case strings.HasSuffix(p, "/repository/tree"):
+			w.Write([]byte(`[{"name": "site.yaml", "path": "legacy/site.yaml", "type": "blob"},
+				{"name": "site.yaml", "path": "deploy/ansible/site.yaml", "type": "blob"}]`))
+		case strings.HasSuffix(p, "/files/legacy/site.yaml/raw"):
+			t.Error("read legacy/site.yaml, which --only-path leaves out")
+			w.Write([]byte("- apt: name=python2.7 state=present\n"))
+		case strings.HasSuffix(p, "/files/deploy/ansible/site.yaml/raw"):
+			w.Write([]byte("- apt: name=python3.10 state=present\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	opts := VersionScanOptions{OnlyPaths: []string{"deploy/**"}, Explain: true}
+	result := ScanProject(context.Background(), client, parsers.DefaultRegistry(), &gitlab.Project{ID: 1, Name: "infra"}, 1, 1, opts)
+	if result.PythonVersion != "3.10" || result.DetectionSource != "deploy/ansible/site.yaml" {
+		t.Errorf("got %q from %q, want 3.10 from deploy/ansible/site.yaml", result.PythonVersion, result.DetectionSource)
+	}
+	if step := result.Explanation.Steps[0]; step.File != ".python-version" || step.Outcome != output.ExplainIgnored {
+		t.Errorf("first step = %+v, want .python-version ignored outside --only-path", step)
+	}
+}
+
+func TestScanProjectMaxCandidates(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/files/") {
+			fetches.Add(1)
+		}
+		// Only the second-priority file declares a version
+		if strings.HasSuffix(r.URL.Path, "/files/runtime.txt/raw") {
+			w.Write([]byte("python-3.11.4\n"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	project := &gitlab.Project{ID: 1, Name: "service"}
+
+	limited := ScanProject(context.Background(), client, parsers.DefaultRegistry(), project, 1, 1, VersionScanOptions{MaxCandidates: 1})
+	if !limited.CandidatesLimited || limited.PythonVersion != "" {
+		t.Errorf("got limited=%v version=%q, want limited with no detection", limited.CandidatesLimited, limited.PythonVersion)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("fetched %d files, want 1", n)
+	}
+	if len(limited.Diagnostics) != 1 {
+		t.Errorf("Diagnostics = %v, want one note about the limit", limited.Diagnostics)
+	}
+
+	found := ScanProject(context.Background(), client, parsers.DefaultRegistry(), project, 1, 1, VersionScanOptions{MaxCandidates: 2})
+	if found.CandidatesLimited || found.PythonVersion != "3.11.4" {
+		t.Errorf("got limited=%v version=%q, want 3.11.4 within the limit", found.CandidatesLimited, found.PythonVersion)
+	}
+}
+
+func TestScanProjectStrict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/files/broken.cfg/raw") {
+			w.Write([]byte("garbage"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	registry := rules.NewRegistry()
+	registry.MustRegister(&rules.SearchRule{
+		Name:      "broken",
+		Priority:  10,
+		Enabled:   true,
+		Condition: rules.MatchCondition{FilePattern: "broken.cfg"},
+		Parser: func(content []byte, filename string) (*rules.SearchResult, error) {
+			return nil, fmt.Errorf("unexpected token")
+		},
+	})
+	project := &gitlab.Project{ID: 1, Name: "service"}
+
+	lenient := ScanProject(context.Background(), client, registry, project, 1, 1, VersionScanOptions{})
+	if len(lenient.ParseErrors) != 0 {
+		t.Errorf("ParseErrors = %v without --strict, want none", lenient.ParseErrors)
+	}
+	if len(lenient.Warnings) != 1 || !strings.HasPrefix(lenient.Warnings[0], "broken.cfg: ") {
+		t.Errorf("Warnings = %v, want one warning for broken.cfg", lenient.Warnings)
+	}
+
+	strict := ScanProject(context.Background(), client, registry, project, 1, 1, VersionScanOptions{Strict: true})
+	if len(strict.ParseErrors) != 1 || !strings.HasPrefix(strict.ParseErrors[0], "broken.cfg: ") {
+		t.Errorf("ParseErrors = %v, want one error for broken.cfg", strict.ParseErrors)
+	}
+	if len(strict.Warnings) != 0 {
+		t.Errorf("Warnings = %v with --strict, want the failure only in ParseErrors", strict.Warnings)
+	}
+}
+
+func TestScanProjectExplain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			w.Write([]byte("7.4\n"))
+		case strings.HasSuffix(r.URL.Path, "/files/setup.py/raw"):
+			w.Write([]byte("setup(python_requires='>=3.9')\n"))
+		case strings.HasSuffix(r.URL.Path, "/files/.gitlab-ci.yml/raw"):
+			w.Write([]byte("image: python:3.11-slim\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	registry := rules.NewRegistry()
+	registry.MustRegister(parsers.GetPythonVersionFileRule())
+	registry.MustRegister(parsers.GetRuntimeTxtRule())
+	registry.MustRegister(parsers.GetSetupPyRule())
+	registry.MustRegister(parsers.GetPipfileRule())
+	registry.MustRegister(parsers.GetGitLabCIRule())
+	project := &gitlab.Project{ID: 1, Name: "billing"}
+
+	opts := VersionScanOptions{Bounds: output.DefaultVersionBounds, IgnorePaths: []string{"Pipfile"}, CrossCheck: true, Explain: true}
+	result := ScanProject(context.Background(), client, registry, project, 1, 1, opts)
+	if result.Explanation == nil {
+		t.Fatal("Explanation = nil with Explain set")
+	}
+
+	var got []string
+	for _, step := range result.Explanation.Steps {
+		got = append(got, step.File+"="+step.Outcome)
+	}
+	want := ".python-version=implausible,runtime.txt=missing,setup.py=selected,Pipfile=ignored,.gitlab-ci.yml=cross-check"
+	if strings.Join(got, ",") != want {
+		t.Errorf("steps = %s, want %s", strings.Join(got, ","), want)
+	}
+	if step := result.Explanation.Steps[2]; step.Version != "3.9" || step.Rule != "setup-py" {
+		t.Errorf("selected step = %+v, want Python 3.9 via setup-py", step)
+	}
+	if decision := result.Explanation.Decision; !strings.Contains(decision, "Python 3.9 from setup.py") || !strings.Contains(decision, "some disagree") {
+		t.Errorf("Decision = %q, want setup.py's 3.9 winning over a disagreeing cross-check", decision)
+	}
+
+	if plain := ScanProject(context.Background(), client, registry, project, 1, 1, VersionScanOptions{}); plain.Explanation != nil {
+		t.Error("Explanation set without Explain")
+	}
+}
+
+func TestScanProjectTiers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/files/guess.cfg/raw"):
+			w.Write([]byte("3.8"))
+		case strings.HasSuffix(r.URL.Path, "/files/ci.cfg/raw"):
+			w.Write([]byte("3.10"))
+		case strings.HasSuffix(r.URL.Path, "/files/declared.cfg/raw"):
+			w.Write([]byte("3.12"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	// Priority order is the reverse of confidence order
+	rule := func(name string, priority int, confidence float64) *rules.SearchRule {
+		return &rules.SearchRule{
+			Name:      name,
+			Priority:  priority,
+			Enabled:   true,
+			Condition: rules.MatchCondition{FilePattern: name + ".cfg"},
+			Parser: func(content []byte, filename string) (*rules.SearchResult, error) {
+				return &rules.SearchResult{Found: true, Detection: rules.Detection{Version: string(content), Source: filename, Confidence: confidence}}, nil
+			},
+		}
+	}
+	registry := rules.NewRegistry()
+	registry.MustRegister(rule("guess", 1, 0.4))
+	registry.MustRegister(rule("ci", 2, 0.7))
+	registry.MustRegister(rule("declared", 3, 0.95))
+	project := &gitlab.Project{ID: 1, Name: "billing"}
+
+	if plain := ScanProject(context.Background(), client, registry, project, 1, 1, VersionScanOptions{}); plain.PythonVersion != "3.8" {
+		t.Errorf("PythonVersion = %q without Tiers, want the highest-priority 3.8", plain.PythonVersion)
+	}
+
+	tiers := output.DefaultConfidenceTiers
+	opts := VersionScanOptions{Tiers: &tiers, Explain: true}
+	result := ScanProject(context.Background(), client, registry, project, 1, 1, opts)
+	if result.PythonVersion != "3.12" || result.DetectionSource != "declared.cfg" || result.Confidence != 0.95 {
+		t.Errorf("result = %s from %s (%v), want the explicit 3.12 from declared.cfg", result.PythonVersion, result.DetectionSource, result.Confidence)
+	}
+	var got []string
+	for _, step := range result.Explanation.Steps {
+		got = append(got, step.File+"="+step.Outcome)
+	}
+	if want := "guess.cfg=outranked,ci.cfg=outranked,declared.cfg=selected"; strings.Join(got, ",") != want {
+		t.Errorf("steps = %s, want %s", strings.Join(got, ","), want)
+	}
+	if !strings.Contains(result.Explanation.Decision, "highest confidence tier found (explicit") {
+		t.Errorf("Decision = %q, want the explicit tier named", result.Explanation.Decision)
+	}
+
+	// Outranked detections become cross-checks of the new version
+	opts = VersionScanOptions{Tiers: &tiers, CrossCheck: true}
+	checked := ScanProject(context.Background(), client, registry, project, 1, 1, opts)
+	if checked.PythonVersion != "3.12" || len(checked.CrossChecks) != 2 || !checked.VersionMismatch {
+		t.Errorf("result = %s with cross-checks %+v (mismatch %v), want 3.12 disagreeing with 3.8 and 3.10",
+			checked.PythonVersion, checked.CrossChecks, checked.VersionMismatch)
+	}
+
+	// Without an explicit source the inferred tier is fallen back to, still
+	// in priority order
+	raised := output.ConfidenceTiers{Explicit: 0.99, Inferred: 0.6}
+	fallback := ScanProject(context.Background(), client, registry, project, 1, 1, VersionScanOptions{Tiers: &raised})
+	if fallback.PythonVersion != "3.10" {
+		t.Errorf("PythonVersion = %q with nothing explicit, want the first inferred-tier detection, 3.10", fallback.PythonVersion)
+	}
+}
+
+func TestScanProjectCIDrift(t *testing.T) {
+	ciImage := "python:3.9"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/files/pyproject.toml/raw"):
+			w.Write([]byte("[project]\nrequires-python = \">=3.11\"\n"))
+		case strings.HasSuffix(r.URL.Path, "/files/.gitlab-ci.yml/raw"):
+			w.Write([]byte("image: " + ciImage + "\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	registry := rules.NewRegistry()
+	registry.MustRegister(parsers.GetPyprojectTomlRule())
+	registry.MustRegister(parsers.GetGitLabCIRule())
+	project := &gitlab.Project{ID: 1, Name: "billing"}
+
+	result := ScanProject(context.Background(), client, registry, project, 1, 1, VersionScanOptions{CrossCheck: true})
+	if !strings.Contains(result.CIDrift, ".gitlab-ci.yml runs Python 3.9") || !strings.Contains(result.CIDrift, ">=3.11") {
+		t.Errorf("CIDrift = %q, want .gitlab-ci.yml's 3.9 flagged against >=3.11", result.CIDrift)
+	}
+
+	// Without cross-checking the CI file is never read
+	if plain := ScanProject(context.Background(), client, registry, project, 1, 1, VersionScanOptions{}); plain.CIDrift != "" {
+		t.Errorf("CIDrift = %q without CrossCheck, want none", plain.CIDrift)
+	}
+
+	ciImage = "python:3.12-slim"
+	if ok := ScanProject(context.Background(), client, registry, project, 1, 1, VersionScanOptions{CrossCheck: true}); ok.CIDrift != "" {
+		t.Errorf("CIDrift = %q for a supported CI version, want none", ok.CIDrift)
+	}
+}
+
+func TestScanProjectSurvivesParserPanic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/files/evil.cfg/raw"):
+			w.Write([]byte("adversarial"))
+		case strings.HasSuffix(r.URL.Path, "/files/.python-version/raw"):
+			w.Write([]byte("3.11\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(&gitlab.Config{GitLabURL: server.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	registry := parsers.DefaultRegistry()
+	registry.MustRegister(&rules.SearchRule{
+		Name:      "evil",
+		Priority:  0,
+		Enabled:   true,
+		Condition: rules.MatchCondition{FilePattern: "evil.cfg"},
+		Parser: func(content []byte, filename string) (*rules.SearchResult, error) {
+			panic("boom")
+		},
+	})
+	project := &gitlab.Project{ID: 1, Name: "service"}
+
+	result := ScanProject(context.Background(), client, registry, project, 1, 1, VersionScanOptions{})
+	if result.PythonVersion != "3.11" {
+		t.Errorf("PythonVersion = %q, want 3.11 from the next rule", result.PythonVersion)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "boom") {
+		t.Errorf("Warnings = %v, want the recovered panic", result.Warnings)
+	}
+}
+
+func TestDetectVersion(t *testing.T) {
+	registry := parsers.DefaultRegistry()
+
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		want     string
+	}{
+		{"python-version", ".python-version", "3.11.4\n", "3.11.4"},
+		{"pyproject in a subdirectory", "services/api/pyproject.toml", "[project]\nrequires-python = \">=3.10\"\n", "3.10"},
+		{"dependency-only requirements", "requirements.txt", "requests==2.31.0\n", ""},
+		{"implausible version", ".python-version", "42.0\n", ""},
+		{"no matching rule", "README.md", "Python 3.11\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DetectVersion(context.Background(), []byte(tt.content), tt.filename, registry)
+			if tt.want == "" {
+				if result != nil {
+					t.Errorf("DetectVersion() = %+v, want no detection", result)
+				}
+				return
+			}
+			if err != nil || result == nil || result.Version != tt.want {
+				t.Fatalf("DetectVersion() = %+v, %v; want version %s", result, err, tt.want)
+			}
+			if result.Source != tt.filename {
+				t.Errorf("Source = %q, want %q", result.Source, tt.filename)
+			}
+		})
+	}
+}
+
+func TestDetectVersionErrors(t *testing.T) {
+	registry := rules.NewRegistry()
+	registry.MustRegister(&rules.SearchRule{
+		Name:      "broken",
+		Priority:  10,
+		Enabled:   true,
+		Condition: rules.MatchCondition{FilePattern: "broken.cfg"},
+		Parser: func(content []byte, filename string) (*rules.SearchResult, error) {
+			return nil, fmt.Errorf("unexpected token")
+		},
+	})
+
+	result, err := DetectVersion(context.Background(), []byte("x"), "broken.cfg", registry)
+	if result != nil || !errors.Is(err, rules.ErrParse) {
+		t.Errorf("DetectVersion() = %+v, %v; want a parse error", result, err)
+	}
+
+	_, err = DetectVersion(context.Background(), []byte("42.0"), ".python-version", parsers.DefaultRegistry())
+	var implausible *ImplausibleVersionError
+	if !errors.As(err, &implausible) {
+		t.Errorf("DetectVersion() error = %v, want an ImplausibleVersionError", err)
+	}
+}
@@ -0,0 +1,68 @@
+// Package detect finds the Python version a file declares, for Go programs
+// that embed the scanner's detection engine instead of running the CLI. It
+// uses the built-in rules, the same ones a scan without --config uses.
+package detect
+
+import (
+	"context"
+
+	"github.com/gbjohnso/gitlab-python-scanner/internal/parsers"
+	"github.com/gbjohnso/gitlab-python-scanner/internal/scanner"
+)
+
+// Result is a Python version detected in a file
+type Result struct {
+	// Version is the detected Python version, e.g. "3.11"
+	Version string
+
+	// Source is the file the version was read from
+	Source string
+
+	// Confidence is how reliable the detection is, from 0.0 to 1.0: 1.0
+	// for an explicit version file, lower for inferred versions
+	Confidence float64
+
+	// Format identifies the declaration style within the file, e.g.
+	// "PEP621" or "Poetry" (empty if the file has only one style)
+	Format string
+
+	// Constraint is the version specifier the version was derived from,
+	// e.g. ">=3.9,<4.0" (empty when the file pins an exact version)
+	Constraint string
+
+	// VersionMax is the tightest upper bound in Constraint, e.g. "<3.11"
+	VersionMax string
+
+	// Implementation is the Python implementation, e.g. "cpython" or
+	// "pypy" (empty when the file does not say)
+	Implementation string
+
+	// RawValue is the text the version was extracted from
+	RawValue string
+
+	// Metadata holds extras about the match, such as source_type
+	Metadata map[string]string
+}
+
+// Version runs the built-in rules that match filename, a path within a
+// repository such as "services/api/pyproject.toml", over content in
+// priority order and returns the first Python version detected, or nil if
+// there is none. The rules' errors are only returned when nothing was
+// detected.
+func Version(ctx context.Context, content []byte, filename string) (*Result, error) {
+	found, err := scanner.DetectVersion(ctx, content, filename, parsers.DefaultRegistry())
+	if found == nil {
+		return nil, err
+	}
+	return &Result{
+		Version:        found.Version,
+		Source:         found.Source,
+		Confidence:     found.Confidence,
+		Format:         found.Format,
+		Constraint:     found.Constraint,
+		VersionMax:     found.VersionMax,
+		Implementation: found.Implementation,
+		RawValue:       found.RawValue,
+		Metadata:       found.Metadata,
+	}, nil
+}
@@ -0,0 +1,23 @@
+package detect_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gbjohnso/gitlab-python-scanner/pkg/detect"
+)
+
+func TestVersion(t *testing.T) {
+	result, err := detect.Version(context.Background(), []byte("[project]\nrequires-python = \">=3.10,<3.13\"\n"), "services/api/pyproject.toml")
+	if err != nil || result == nil {
+		t.Fatalf("Version() = %+v, %v; want a detection", result, err)
+	}
+	if result.Version != "3.10" || result.Constraint != ">=3.10,<3.13" || result.Source != "services/api/pyproject.toml" {
+		t.Errorf("Version() = %+v, want 3.10 from services/api/pyproject.toml with its constraint", result)
+	}
+
+	result, err = detect.Version(context.Background(), []byte("Python 3.11\n"), "NOTES.txt")
+	if result != nil || err != nil {
+		t.Errorf("Version() of a file no rule matches = %+v, %v; want nil, nil", result, err)
+	}
+}